@@ -1,17 +1,25 @@
 package main
 
 import (
+	"context"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
+	"projeto_livros/internal/config"
+	grpcserver "projeto_livros/internal/delivery/grpc"
 	handlers "projeto_livros/internal/delivery/http"
-	"projeto_livros/internal/delivery/middleware"
+	"projeto_livros/internal/events"
+	"projeto_livros/internal/metadata"
+	"projeto_livros/internal/notify"
+	repositories "projeto_livros/internal/repository"
 	"projeto_livros/internal/repository/database"
+	"projeto_livros/internal/trash"
+	"projeto_livros/internal/webhooks"
 	"strings"
 
 	"github.com/go-chi/chi/v5"
-	chimiddleware "github.com/go-chi/chi/v5/middleware"
 )
 
 func main() {
@@ -20,39 +28,83 @@ func main() {
 		log.Fatal("Não foi possível conectar ao banco após várias tentativas")
 	}
 	defer db.Close()
-	bookHandler := handlers.NewBookHandler(db)
-	genreHandler := handlers.NewGenreHandler(db)
-	r := chi.NewRouter()
-	r.Use(chimiddleware.Logger)
-	r.Use(chimiddleware.Recoverer)
-	r.Use(middleware.CorsMiddleware)
-	// Temporarily comment out the auth middleware for testing
-	// r.Use(middleware.AuthMiddleware)
-
-	// Health check endpoint
-	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.Write([]byte("OK"))
-	})
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Erro ao carregar configurações: %v", err)
+	}
+
+	// Provedor de metadados offline, habilitado quando há um dump configurado
+	var metadataProvider metadata.Provider
+	if cfg.MetadataDumpPath != "" {
+		fileProvider, err := metadata.NewFileProvider(cfg.MetadataDumpPath)
+		if err != nil {
+			log.Printf("Aviso: metadados por ISBN desabilitados: %v", err)
+		} else {
+			defer fileProvider.Close()
+			metadataProvider = fileProvider
+		}
+	}
 
-	// Endpoint direto para atualizar quantidade via query params
-	r.Get("/update-quantity", bookHandler.UpdateQuantityDirect)
-
-	// API routes using RESTful conventions
-	r.Route("/api/books", func(r chi.Router) {
-		r.Get("/", bookHandler.GetAllBooks)                        // Lista todos os livros
-		r.Post("/", bookHandler.CreateBook)                        // Cria um livro
-		r.Post("/batch", bookHandler.CreateAllBooks)               // Cria vários livros
-		r.Get("/{id}", bookHandler.GetBook)                        // Busca um livro pelo ID
-		r.Put("/{id}", bookHandler.UpdateBook)                     // Atualiza um livro
-		r.Delete("/{id}", bookHandler.DeleteBook)                  // Remove um livro
-		r.Post("/update-quantity", bookHandler.UpdateBookQuantity) // Endpoint para atualização de quantidade
+	// Resumo diário dos alertas de estoque baixo
+	var digestJob *notify.DigestJob
+	notifier, err := notify.New(cfg.AlertNotifier, notify.Options{
+		WebhookURL:   cfg.AlertWebhookURL,
+		SMTPAddr:     cfg.SMTPAddr,
+		SMTPUser:     cfg.SMTPUser,
+		SMTPPassword: cfg.SMTPPassword,
+		SMTPFrom:     cfg.SMTPFrom,
+		SMTPTo:       cfg.AlertEmailTo,
 	})
+	if err == nil {
+		digestJob, err = notify.NewDigestJob(repositories.NewPostgresAlertRepository(db), notifier, cfg.AlertDigestTime)
+	}
+	if err != nil {
+		log.Printf("Aviso: resumo de estoque baixo desabilitado: %v", err)
+	} else {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go digestJob.Run(ctx)
+	}
+
+	// Limpeza periódica da lixeira
+	purgeJob, err := trash.NewPurgeJob(repositories.NewPostgresTrashRepository(db), cfg.TrashRetention, cfg.TrashPurgeInterval)
+	if err != nil {
+		log.Printf("Aviso: limpeza da lixeira desabilitada: %v", err)
+	} else {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go purgeJob.Run(ctx)
+	}
 
-	r.Route("/api/genres", func(r chi.Router) {
-		r.Get("/", genreHandler.GetAllGenres)              // Lista todos os gêneros
-		r.Post("/", genreHandler.CreateGenre)              // Cria um gênero
-		r.Get("/{id}/books", genreHandler.GetBooksByGenre) // Livros de um gênero
+	// Entrega dos eventos de domínio gravados no outbox: ao destino
+	// configurado e às assinaturas de webhook
+	webhookDeliverer := webhooks.NewDeliverer(repositories.NewPostgresWebhookRepository(db),
+		cfg.WebhookPollInterval, cfg.WebhookMaxAttempts, cfg.WebhookDisableAfter)
+	sinks := events.MultiSink{webhookDeliverer}
+	sink, err := events.New(cfg.EventsSink, events.Options{
+		FilePath:    cfg.EventsFilePath,
+		WebhookURL:  cfg.EventsWebhookURL,
+		NATSURL:     cfg.EventsNATSURL,
+		NATSSubject: cfg.EventsNATSSubject,
 	})
+	if err != nil {
+		log.Printf("Aviso: destino de eventos desabilitado, apenas webhooks serão notificados: %v", err)
+	} else {
+		sinks = append(sinks, sink)
+	}
+	eventsCtx, cancelEvents := context.WithCancel(context.Background())
+	defer cancelEvents()
+	go events.NewDispatcher(db, sinks, cfg.EventsPollInterval, cfg.EventsRetention).Run(eventsCtx)
+	go webhookDeliverer.Run(eventsCtx)
+
+	// Feed SSE: cada instância recebe os eventos confirmados via LISTEN/NOTIFY
+	broker := events.NewBroker(cfg.StreamReplaySize)
+	go events.Listen(eventsCtx, cfg.GetDSN(), repositories.NewPostgresOutboxRepository(db), broker)
+
+	r, err := handlers.NewRouter(db, cfg, handlers.RouterDeps{Metadata: metadataProvider, DigestJob: digestJob, Broker: broker})
+	if err != nil {
+		log.Fatalf("Erro ao montar as rotas: %v", err)
+	}
 
 	// Servir arquivos estáticos do frontend
 	workDir, _ := os.Getwd()
@@ -86,6 +138,18 @@ func main() {
 		http.ServeFile(w, r, filepath.Join(frontendDir, "index.html"))
 	})
 
+	// API gRPC para os serviços internos, na própria porta
+	listener, err := net.Listen("tcp", cfg.GRPCAddr)
+	if err != nil {
+		log.Fatalf("Erro ao abrir a porta do gRPC: %v", err)
+	}
+	go func() {
+		log.Printf("Servidor gRPC rodando em %s", cfg.GRPCAddr)
+		if err := grpcserver.NewServer(db, broker).Serve(listener); err != nil {
+			log.Fatalf("Erro no servidor gRPC: %v", err)
+		}
+	}()
+
 	log.Println("Servidor rodando na porta 3001")
 	log.Fatal(http.ListenAndServe(":3001", r))
 }
@@ -0,0 +1,75 @@
+// Comando audit-verify: percorre o log de auditoria em ordem, recalcula a
+// cadeia de hashes e aponta lacunas na numeração e entradas alteradas.
+// A cadeia sozinha não acusa entradas removidas do fim do log; para isso, o
+// comando imprime o ponto de controle atual (seq:hash), que deve ser guardado
+// fora do banco e informado na próxima execução com -head. Com -head-file, o
+// ponto de controle é lido do arquivo e, se a cadeia estiver íntegra,
+// substituído pelo atual.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"projeto_livros/internal/audit"
+	"projeto_livros/internal/domain/models"
+	repositories "projeto_livros/internal/repository"
+	"projeto_livros/internal/repository/database"
+)
+
+func main() {
+	head := flag.String("head", "", "ponto de controle seq:hash que deve estar presente no log")
+	headFile := flag.String("head-file", "", "arquivo com o ponto de controle esperado, atualizado ao fim de uma verificação sem problemas")
+	flag.Parse()
+
+	verifier := &audit.Verifier{}
+	expected := *head
+	if *headFile != "" && expected == "" {
+		data, err := os.ReadFile(*headFile)
+		if err != nil && !os.IsNotExist(err) {
+			log.Fatalf("Erro ao ler %s: %v", *headFile, err)
+		}
+		expected = string(data)
+	}
+	if expected != "" {
+		checkpoint, err := audit.ParseCheckpoint(expected)
+		if err != nil {
+			log.Fatal(err)
+		}
+		verifier.Expect = &checkpoint
+	}
+
+	db, err := database.ConnectDB()
+	if err != nil {
+		log.Fatalf("Não foi possível conectar ao banco: %v", err)
+	}
+	defer db.Close()
+
+	err = repositories.NewPostgresAuditRepository(db).Each(func(entry *models.AuditEntry) error {
+		verifier.Add(entry)
+		return nil
+	})
+	if err != nil {
+		log.Fatalf("Erro ao ler o log de auditoria: %v", err)
+	}
+	verifier.Finish()
+
+	fmt.Printf("Entradas verificadas: %d\n", verifier.Count)
+	if verifier.Count > 0 {
+		fmt.Printf("Ponto de controle atual: %s\n", verifier.Head())
+	}
+	if len(verifier.Problems) > 0 {
+		fmt.Printf("%d problema(s) encontrado(s):\n", len(verifier.Problems))
+		for _, p := range verifier.Problems {
+			fmt.Println("  " + p.String())
+		}
+		os.Exit(1)
+	}
+	if *headFile != "" && verifier.Count > 0 {
+		if err := os.WriteFile(*headFile, []byte(verifier.Head().String()+"\n"), 0o600); err != nil {
+			log.Fatalf("Erro ao gravar %s: %v", *headFile, err)
+		}
+	}
+	fmt.Println("Cadeia íntegra")
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"projeto_livros/pkg/client"
+	"strings"
+)
+
+func runBooksList(e *env, args []string) error {
+	fs := e.flagSet("books list")
+	page := fs.Int("page", 1, "página")
+	perPage := fs.Int("per-page", 20, "livros por página")
+	all := fs.Bool("all", false, "percorre todas as páginas a partir de -page")
+	sortField := fs.String("sort", "", "ordenação: name ou quantity")
+	desc := fs.Bool("desc", false, "ordem decrescente")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	opts := client.ListBooksOptions{Page: *page, PerPage: *perPage, SortField: *sortField, SortDesc: *desc}
+
+	if !*all {
+		result, err := c.ListBooks(e.ctx, opts)
+		if err != nil {
+			return err
+		}
+		if err := e.render(result, booksTable(result.Data)); err != nil {
+			return err
+		}
+		if e.format == "table" {
+			fmt.Fprintf(e.errOut, "Página %d de %d (%d livros)\n", result.Page, result.TotalPages, result.TotalBooks)
+		}
+		return nil
+	}
+
+	books := []client.Book{}
+	it := c.Books(opts)
+	for it.Next(e.ctx) {
+		books = append(books, it.Item())
+	}
+	if err := it.Err(); err != nil {
+		return err
+	}
+	return e.render(books, booksTable(books))
+}
+
+func runBooksGet(e *env, args []string) error {
+	fs := e.flagSet("books get")
+	isbn := fs.String("isbn", "", "busca pelo ISBN-10 ou ISBN-13 em vez do id")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if (len(positional) == 1) == (*isbn != "") {
+		return usagef("informe o id do livro ou -isbn")
+	}
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	var book *client.Book
+	if *isbn != "" {
+		book, err = c.GetBookByISBN(e.ctx, *isbn)
+	} else {
+		book, err = c.GetBook(e.ctx, positional[0])
+	}
+	if err != nil {
+		return err
+	}
+	return e.render(book, bookDetails(book))
+}
+
+// bookFlags são os campos de livro aceitos por create e update
+type bookFlags struct {
+	fs          *flag.FlagSet
+	name        *string
+	author      *string
+	quantity    *int
+	minQuantity *int
+	isbn        *string
+	publisher   *string
+	year        *int
+	edition     *string
+	subjects    *string
+	genres      *string
+}
+
+func addBookFlags(fs *flag.FlagSet) *bookFlags {
+	return &bookFlags{
+		fs:          fs,
+		name:        fs.String("name", "", "nome do livro"),
+		author:      fs.String("author", "", "autores, separados por vírgula"),
+		quantity:    fs.Int("quantity", 0, "quantidade em estoque"),
+		minQuantity: fs.Int("min-quantity", 0, "estoque mínimo"),
+		isbn:        fs.String("isbn", "", "ISBN-10 ou ISBN-13"),
+		publisher:   fs.String("publisher", "", "editora"),
+		year:        fs.Int("year", 0, "ano de publicação"),
+		edition:     fs.String("edition", "", "edição"),
+		subjects:    fs.String("subjects", "", "assuntos, separados por ponto e vírgula"),
+		genres:      fs.String("genres", "", "ids dos gêneros, separados por vírgula; o primeiro é o principal"),
+	}
+}
+
+// set devolve os nomes das flags informadas na linha de comando
+func (f *bookFlags) set() map[string]bool {
+	set := map[string]bool{}
+	f.fs.Visit(func(fl *flag.Flag) { set[fl.Name] = true })
+	return set
+}
+
+// splitList separa a lista, descartando os espaços e os itens vazios
+func splitList(value, sep string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, sep) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func genreRefs(ids []string) []client.BookGenre {
+	genres := make([]client.BookGenre, len(ids))
+	for i, id := range ids {
+		genres[i] = client.BookGenre{ID: id}
+	}
+	return genres
+}
+
+func runBooksCreate(e *env, args []string) error {
+	fs := e.flagSet("books create")
+	f := addBookFlags(fs)
+	enrich := fs.Bool("enrich", false, "completa os campos vazios com os metadados do ISBN")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if *f.name == "" || *f.quantity <= 0 {
+		return usagef("informe -name e -quantity (maior que zero)")
+	}
+	set := f.set()
+	book := client.Book{
+		Name:      *f.name,
+		Author:    *f.author,
+		Quantity:  *f.quantity,
+		ISBN:      *f.isbn,
+		Publisher: *f.publisher,
+		Edition:   *f.edition,
+		Subjects:  splitList(*f.subjects, ";"),
+		Genres:    genreRefs(splitList(*f.genres, ",")),
+	}
+	if set["min-quantity"] {
+		book.MinQuantity = f.minQuantity
+	}
+	if set["year"] {
+		book.PublicationYear = f.year
+	}
+
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	created, err := c.CreateBook(e.ctx, book, *enrich)
+	if err != nil {
+		return err
+	}
+	return e.render(created, bookDetails(created))
+}
+
+func runBooksUpdate(e *env, args []string) error {
+	fs := e.flagSet("books update")
+	f := addBookFlags(fs)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return usagef("informe o id do livro")
+	}
+	set := f.set()
+	var update client.BookUpdate
+	if set["name"] {
+		update.Name = f.name
+	}
+	if set["author"] {
+		update.Author = f.author
+	}
+	if set["quantity"] {
+		update.Quantity = f.quantity
+	}
+	if set["min-quantity"] {
+		update.MinQuantity = f.minQuantity
+	}
+	if set["isbn"] {
+		update.ISBN = f.isbn
+	}
+	if set["publisher"] {
+		update.Publisher = f.publisher
+	}
+	if set["year"] {
+		update.PublicationYear = f.year
+	}
+	if set["edition"] {
+		update.Edition = f.edition
+	}
+	if set["subjects"] {
+		subjects := splitList(*f.subjects, ";")
+		update.Subjects = &subjects
+	}
+	if set["genres"] {
+		genres := genreRefs(splitList(*f.genres, ","))
+		update.Genres = &genres
+	}
+	if update == (client.BookUpdate{}) {
+		return usagef("informe ao menos um campo a alterar")
+	}
+
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	book, err := c.UpdateBook(e.ctx, positional[0], update)
+	if err != nil {
+		return err
+	}
+	return e.render(book, bookDetails(book))
+}
+
+func runBooksDelete(e *env, args []string) error {
+	fs := e.flagSet("books delete")
+	hard := fs.Bool("hard", false, "apaga definitivamente (exige token de administrador)")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return usagef("informe o id do livro")
+	}
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	if err := c.DeleteBook(e.ctx, positional[0], *hard); err != nil {
+		return err
+	}
+	if *hard {
+		fmt.Fprintf(e.errOut, "Livro %s apagado\n", positional[0])
+	} else {
+		fmt.Fprintf(e.errOut, "Livro %s movido para a lixeira\n", positional[0])
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// commandFlags devolve as flags do subcomando, executando-o com -h: as flags
+// são todas definidas antes da leitura dos argumentos
+func commandFlags(cmd command) []string {
+	e := &env{ctx: context.Background(), out: io.Discard, errOut: io.Discard, config: &configFile{}}
+	cmd.Run(e, []string{"-h"})
+	var names []string
+	if e.lastFlags != nil {
+		e.lastFlags.VisitAll(func(f *flag.Flag) { names = append(names, "-"+f.Name) })
+	}
+	return names
+}
+
+var globalFlags = []string{"-profile", "-server", "-token", "-o"}
+
+func groupNames() []string {
+	names := make([]string, len(groups))
+	for i, g := range groups {
+		names[i] = g.Name
+	}
+	return names
+}
+
+// writeCompletion imprime o script de autocompletar do shell. Os grupos,
+// subcomandos e flags vêm da própria árvore de comandos.
+func writeCompletion(w io.Writer, shell string) error {
+	switch shell {
+	case "bash":
+		writeBashCompletion(w)
+	case "zsh":
+		// O zsh carrega o script do bash pela camada de compatibilidade
+		fmt.Fprintln(w, "#compdef livrosctl")
+		fmt.Fprintln(w, "autoload -U +X bashcompinit && bashcompinit")
+		writeBashCompletion(w)
+	case "fish":
+		writeFishCompletion(w)
+	default:
+		return fmt.Errorf("shell %q não suportado: use bash, zsh ou fish", shell)
+	}
+	return nil
+}
+
+func writeBashCompletion(w io.Writer) {
+	fmt.Fprintln(w, "# Autocompletar do livrosctl: source <(livrosctl completion bash)")
+	fmt.Fprintln(w, "_livrosctl() {")
+	fmt.Fprintln(w, `    local cur="${COMP_WORDS[COMP_CWORD]}" prev="${COMP_WORDS[COMP_CWORD-1]}"`)
+	fmt.Fprintln(w, `    case "$prev" in`)
+	fmt.Fprintf(w, "        -o) COMPREPLY=($(compgen -W %q -- \"$cur\")); return ;;\n", strings.Join(outputFormats, " "))
+	fmt.Fprintln(w, `        -profile|-server|-token) return ;;`)
+	fmt.Fprintln(w, `    esac`)
+	// Palavras que não são flags nem valores de flags: grupo e subcomando
+	fmt.Fprintln(w, `    local words=() i`)
+	fmt.Fprintln(w, `    for ((i = 1; i < COMP_CWORD; i++)); do`)
+	fmt.Fprintln(w, `        case "${COMP_WORDS[i]}" in`)
+	fmt.Fprintln(w, `            -o|-profile|-server|-token) ((i++)) ;;`)
+	fmt.Fprintln(w, `            -*) ;;`)
+	fmt.Fprintln(w, `            *) words+=("${COMP_WORDS[i]}") ;;`)
+	fmt.Fprintln(w, `        esac`)
+	fmt.Fprintln(w, `    done`)
+	fmt.Fprintln(w, `    case "${words[0]} ${words[1]}" in`)
+	for _, g := range groups {
+		if g.Name == "completion" {
+			fmt.Fprintln(w, `        "completion ") COMPREPLY=($(compgen -W "bash zsh fish" -- "$cur")) ;;`)
+			continue
+		}
+		names := make([]string, len(g.Commands))
+		for i, cmd := range g.Commands {
+			names[i] = cmd.Name
+			// As flags globais só valem antes do grupo
+			fmt.Fprintf(w, "        %q) COMPREPLY=($(compgen -W %q -- \"$cur\")) ;;\n", g.Name+" "+cmd.Name, strings.Join(commandFlags(cmd), " "))
+		}
+		fmt.Fprintf(w, "        %q) COMPREPLY=($(compgen -W %q -- \"$cur\")) ;;\n", g.Name+" ", strings.Join(names, " "))
+	}
+	fmt.Fprintf(w, "        \" \") COMPREPLY=($(compgen -W %q -- \"$cur\")) ;;\n", strings.Join(append(groupNames(), globalFlags...), " "))
+	fmt.Fprintln(w, `    esac`)
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w, "complete -o default -F _livrosctl livrosctl")
+}
+
+func writeFishCompletion(w io.Writer) {
+	fmt.Fprintln(w, "# Autocompletar do livrosctl: livrosctl completion fish | source")
+	fmt.Fprintln(w, "complete -c livrosctl -f")
+	fmt.Fprintln(w, "complete -c livrosctl -o profile -r -d 'Perfil com o servidor e o token'")
+	fmt.Fprintln(w, "complete -c livrosctl -o server -r -d 'URL da API'")
+	fmt.Fprintln(w, "complete -c livrosctl -o token -r -d 'Token JWT'")
+	fmt.Fprintf(w, "complete -c livrosctl -o o -x -a %q -d 'Formato da saída'\n", strings.Join(outputFormats, " "))
+	for _, g := range groups {
+		fmt.Fprintf(w, "complete -c livrosctl -n '__fish_use_subcommand' -a %s -d %q\n", g.Name, g.Summary)
+		if g.Name == "completion" {
+			fmt.Fprintln(w, "complete -c livrosctl -n '__fish_seen_subcommand_from completion' -a 'bash zsh fish'")
+			continue
+		}
+		var names []string
+		for _, cmd := range g.Commands {
+			names = append(names, cmd.Name)
+		}
+		for _, cmd := range g.Commands {
+			fmt.Fprintf(w, "complete -c livrosctl -n '__fish_seen_subcommand_from %s; and not __fish_seen_subcommand_from %s' -a %s -d %q\n",
+				g.Name, strings.Join(names, " "), cmd.Name, cmd.Summary)
+			for _, name := range commandFlags(cmd) {
+				fmt.Fprintf(w, "complete -c livrosctl -n '__fish_seen_subcommand_from %s; and __fish_seen_subcommand_from %s' -o %s\n",
+					g.Name, cmd.Name, strings.TrimPrefix(name, "-"))
+			}
+		}
+	}
+}
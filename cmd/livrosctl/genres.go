@@ -0,0 +1,78 @@
+package main
+
+import (
+	"projeto_livros/pkg/client"
+	"strings"
+)
+
+func genreTable(genres []client.Genre) table {
+	t := table{headers: []string{"ID", "NOME", "PAI", "DESCRIÇÃO"}}
+	for _, genre := range genres {
+		parent := ""
+		if genre.ParentID != nil {
+			parent = *genre.ParentID
+		}
+		t.add(genre.ID, genre.Name, parent, genre.Description)
+	}
+	return t
+}
+
+// treeTable mostra a árvore com os nomes recuados conforme o nível
+func treeTable(t *table, nodes []client.GenreNode, depth int) {
+	for _, node := range nodes {
+		t.add(node.ID, strings.Repeat("  ", depth)+node.Name)
+		treeTable(t, node.Children, depth+1)
+	}
+}
+
+func runGenresList(e *env, args []string) error {
+	fs := e.flagSet("genres list")
+	tree := fs.Bool("tree", false, "mostra a hierarquia de gêneros")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	if *tree {
+		nodes, err := c.GenreTree(e.ctx)
+		if err != nil {
+			return err
+		}
+		t := table{headers: []string{"ID", "NOME"}}
+		treeTable(&t, nodes, 0)
+		return e.render(nodes, t)
+	}
+	genres, err := c.ListGenres(e.ctx)
+	if err != nil {
+		return err
+	}
+	return e.render(genres, genreTable(genres))
+}
+
+func runGenresCreate(e *env, args []string) error {
+	fs := e.flagSet("genres create")
+	name := fs.String("name", "", "nome do gênero")
+	description := fs.String("description", "", "descrição")
+	parent := fs.String("parent", "", "id do gênero pai")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if strings.TrimSpace(*name) == "" {
+		return usagef("informe -name")
+	}
+	genre := client.Genre{Name: *name, Description: *description}
+	if *parent != "" {
+		genre.ParentID = parent
+	}
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	created, err := c.CreateGenre(e.ctx, genre)
+	if err != nil {
+		return err
+	}
+	return e.render(created, genreTable([]client.Genre{*created}))
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"projeto_livros/pkg/client"
+	"strconv"
+	"strings"
+)
+
+// csvRow é um livro lido do CSV com a linha de origem, para as mensagens
+type csvRow struct {
+	line int
+	book client.Book
+}
+
+// readBooksCSV lê um CSV com cabeçalho. As colunas são as da exportação
+// (GET /api/books/export?format=csv), em qualquer ordem: name (ou title) e
+// quantity são obrigatórias; author, genre_id, isbn, publisher,
+// publication_year, edition, subjects (separados por "; ") e min_quantity são
+// opcionais; as demais, como id e genre_names, são ignoradas.
+func readBooksCSV(r io.Reader) ([]csvRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("arquivo vazio")
+	} else if err != nil {
+		return nil, err
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	_, hasName := columns["name"]
+	_, hasTitle := columns["title"]
+	if _, ok := columns["quantity"]; !ok || (!hasName && !hasTitle) {
+		return nil, errors.New("o cabeçalho precisa das colunas name e quantity")
+	}
+
+	var rows []csvRow
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		} else if err != nil {
+			return nil, err
+		}
+		get := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		optionalInt := func(column string) (*int, error) {
+			value := get(column)
+			if value == "" {
+				return nil, nil
+			}
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("linha %d: %s inválido: %q", line, column, value)
+			}
+			return &n, nil
+		}
+
+		book := client.Book{
+			Name:      firstNonEmpty(get("name"), get("title")),
+			Author:    get("author"),
+			ISBN:      get("isbn"),
+			Publisher: get("publisher"),
+			Edition:   get("edition"),
+			Subjects:  splitList(get("subjects"), ";"),
+		}
+		if book.Name == "" {
+			return nil, fmt.Errorf("linha %d: nome vazio", line)
+		}
+		if book.Quantity, err = strconv.Atoi(get("quantity")); err != nil || book.Quantity <= 0 {
+			return nil, fmt.Errorf("linha %d: quantity deve ser um número maior que zero", line)
+		}
+		if genreID := get("genre_id"); genreID != "" {
+			book.Genres = genreRefs([]string{genreID})
+		}
+		if book.PublicationYear, err = optionalInt("publication_year"); err != nil {
+			return nil, err
+		}
+		if book.MinQuantity, err = optionalInt("min_quantity"); err != nil {
+			return nil, err
+		}
+		rows = append(rows, csvRow{line: line, book: book})
+	}
+}
+
+func runBooksImport(e *env, args []string) error {
+	fs := e.flagSet("books import")
+	batchSize := fs.Int("batch-size", 100, "livros enviados por requisição")
+	skipDuplicates := fs.Bool("skip-duplicates", false, "ignora os livros com ISBN já cadastrado em vez de parar")
+	enrich := fs.Bool("enrich", false, "completa os campos vazios com os metadados do ISBN")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return usagef("informe o arquivo CSV")
+	}
+	if *batchSize <= 0 {
+		return usagef("-batch-size deve ser maior que zero")
+	}
+
+	file, err := os.Open(positional[0])
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	rows, err := readBooksCSV(file)
+	if err != nil {
+		return fmt.Errorf("%s: %w", positional[0], err)
+	}
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+
+	created := []client.Book{}
+	sent, duplicates := 0, 0
+	for start := 0; start < len(rows); start += *batchSize {
+		batch := rows[start:min(start+*batchSize, len(rows))]
+		result, err := importBatch(e, c, batch, *skipDuplicates, *enrich, &duplicates)
+		if err != nil {
+			if len(created) > 0 {
+				fmt.Fprintf(e.errOut, "%d livro(s) das linhas anteriores já foram criados\n", len(created))
+			}
+			return err
+		}
+		sent += len(batch)
+		created = append(created, result...)
+	}
+
+	if err := e.render(created, booksTable(created)); err != nil {
+		return err
+	}
+	fmt.Fprintf(e.errOut, "%d livro(s) criado(s) de %d linha(s)", len(created), len(rows))
+	if duplicates > 0 {
+		fmt.Fprintf(e.errOut, ", %d com ISBN duplicado", duplicates)
+	}
+	if ignored := sent - duplicates - len(created); ignored > 0 {
+		fmt.Fprintf(e.errOut, ", %d recusado(s) pela API (ISBN, autor ou gênero inválido)", ignored)
+	}
+	fmt.Fprintln(e.errOut)
+	return nil
+}
+
+// importBatch envia um lote. A API recusa o lote inteiro se algum ISBN já
+// existir ou se repetir; com skipDuplicates, esses livros são retirados e o
+// restante é reenviado.
+func importBatch(e *env, c *client.Client, batch []csvRow, skipDuplicates, enrich bool, duplicates *int) ([]client.Book, error) {
+	books := make([]client.Book, len(batch))
+	for i, row := range batch {
+		books[i] = row.book
+	}
+	result, err := c.CreateBooks(e.ctx, books, enrich)
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) || len(apiErr.Duplicates) == 0 {
+		if err != nil {
+			return nil, fmt.Errorf("linhas %d a %d: %w", batch[0].line, batch[len(batch)-1].line, err)
+		}
+		return result.Books, nil
+	}
+
+	rejected := map[int]bool{}
+	for _, d := range apiErr.Duplicates {
+		rejected[d.Index] = true
+		if d.ExistingID != "" {
+			fmt.Fprintf(e.errOut, "Linha %d: ISBN %s já cadastrado (livro %s)\n", batch[d.Index].line, d.ISBN, d.ExistingID)
+		} else {
+			fmt.Fprintf(e.errOut, "Linha %d: ISBN %s repetido no arquivo\n", batch[d.Index].line, d.ISBN)
+		}
+	}
+	if !skipDuplicates {
+		return nil, fmt.Errorf("linhas %d a %d não importadas por ISBN duplicado; use -skip-duplicates para ignorá-los",
+			batch[0].line, batch[len(batch)-1].line)
+	}
+	*duplicates += len(rejected)
+	remaining := []csvRow{}
+	for i, row := range batch {
+		if !rejected[i] {
+			remaining = append(remaining, row)
+		}
+	}
+	if len(remaining) == 0 {
+		return nil, nil
+	}
+	return importBatch(e, c, remaining, skipDuplicates, enrich, duplicates)
+}
@@ -0,0 +1,238 @@
+// Comando livrosctl: cliente de linha de comando para administrar o catálogo
+// pela API REST, sem precisar montar chamadas com curl.
+//
+//	livrosctl [-profile nome] [-server url] [-token jwt] [-o table|json|csv] <grupo> <comando> [argumentos]
+//
+// O servidor e o token vêm, nesta ordem, das flags, das variáveis
+// LIVROS_SERVER e LIVROS_TOKEN ou do perfil (veja "livrosctl profile").
+// "livrosctl completion bash|zsh|fish" imprime o script de autocompletar.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"projeto_livros/pkg/client"
+	"strings"
+)
+
+// command é um subcomando, como "books list"
+type command struct {
+	Name    string
+	Usage   string // Argumentos depois do nome
+	Summary string
+	Run     func(env *env, args []string) error
+}
+
+// group reúne os subcomandos de um recurso
+type group struct {
+	Name     string
+	Summary  string
+	Commands []command
+}
+
+// groups é a árvore de comandos, também usada nos scripts de autocompletar
+var groups = []group{
+	{Name: "books", Summary: "Livros do catálogo", Commands: []command{
+		{Name: "list", Usage: "[-page n] [-per-page n] [-all] [-sort name|quantity] [-desc]", Summary: "Lista os livros", Run: runBooksList},
+		{Name: "get", Usage: "<id> | -isbn <isbn>", Summary: "Mostra um livro", Run: runBooksGet},
+		{Name: "create", Usage: "-name <nome> -quantity <n> [campos]", Summary: "Cadastra um livro", Run: runBooksCreate},
+		{Name: "update", Usage: "<id> [campos]", Summary: "Altera os campos informados de um livro", Run: runBooksUpdate},
+		{Name: "delete", Usage: "<id> [-hard]", Summary: "Move um livro para a lixeira", Run: runBooksDelete},
+		{Name: "import", Usage: "<arquivo.csv> [-batch-size n] [-skip-duplicates] [-enrich]", Summary: "Cadastra os livros de um CSV", Run: runBooksImport},
+	}},
+	{Name: "stock", Summary: "Estoque dos livros", Commands: []command{
+		{Name: "adjust", Usage: "<id> (-delta n | -set n)", Summary: "Soma ao estoque ou define o valor", Run: runStockAdjust},
+	}},
+	{Name: "genres", Summary: "Gêneros", Commands: []command{
+		{Name: "list", Usage: "[-tree]", Summary: "Lista os gêneros", Run: runGenresList},
+		{Name: "create", Usage: "-name <nome> [-description texto] [-parent id]", Summary: "Cadastra um gênero", Run: runGenresCreate},
+	}},
+	{Name: "profile", Summary: "Perfis de servidor e token", Commands: []command{
+		{Name: "list", Summary: "Lista os perfis", Run: runProfileList},
+		{Name: "set", Usage: "<nome> [-server url] [-token jwt]", Summary: "Cria ou altera um perfil", Run: runProfileSet},
+		{Name: "use", Usage: "<nome>", Summary: "Define o perfil padrão", Run: runProfileUse},
+		{Name: "delete", Usage: "<nome>", Summary: "Remove um perfil", Run: runProfileDelete},
+	}},
+	{Name: "completion", Summary: "Script de autocompletar (bash, zsh ou fish)"},
+}
+
+// outputFormats são os valores aceitos por -o
+var outputFormats = []string{"table", "json", "csv"}
+
+// env é o estado compartilhado pelos subcomandos
+type env struct {
+	ctx     context.Context
+	out     io.Writer
+	errOut  io.Writer
+	format  string
+	profile string
+	server  string
+	token   string
+	config  *configFile
+
+	lastFlags *flag.FlagSet // Flags do último subcomando, para o autocompletar
+}
+
+// client cria o cliente da API com o servidor e o token resolvidos
+func (e *env) client() (*client.Client, error) {
+	server, token, err := e.config.resolve(e.profile, e.server, e.token)
+	if err != nil {
+		return nil, err
+	}
+	return client.New(server, client.WithToken(token), client.WithUserAgent("livrosctl")), nil
+}
+
+// flagSet cria as flags de um subcomando, já com -o
+func (e *env) flagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(e.errOut)
+	fs.StringVar(&e.format, "o", e.format, "formato da saída: table, json ou csv")
+	e.lastFlags = fs
+	return fs
+}
+
+// parseArgs lê as flags em qualquer posição, como em "books get <id> -o json",
+// e devolve os argumentos posicionais
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, out, errOut io.Writer) int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	e := &env{ctx: ctx, out: out, errOut: errOut}
+	global := flag.NewFlagSet("livrosctl", flag.ContinueOnError)
+	global.SetOutput(errOut)
+	global.StringVar(&e.profile, "profile", os.Getenv("LIVROS_PROFILE"), "perfil com o servidor e o token")
+	global.StringVar(&e.server, "server", "", "URL da API (padrão: LIVROS_SERVER ou o perfil)")
+	global.StringVar(&e.token, "token", "", "token JWT (padrão: LIVROS_TOKEN ou o perfil)")
+	global.StringVar(&e.format, "o", "table", "formato da saída: table, json ou csv")
+	global.Usage = func() { printUsage(errOut) }
+	if err := global.Parse(args); err != nil {
+		return 2
+	}
+
+	args = global.Args()
+	if len(args) == 0 {
+		printUsage(errOut)
+		return 2
+	}
+	if args[0] == "completion" {
+		if len(args) != 2 {
+			fmt.Fprintln(errOut, "Uso: livrosctl completion bash|zsh|fish")
+			return 2
+		}
+		if err := writeCompletion(out, args[1]); err != nil {
+			fmt.Fprintln(errOut, "Erro:", err)
+			return 2
+		}
+		return 0
+	}
+
+	cmd, ok := findCommand(args)
+	if !ok {
+		printUsage(errOut)
+		return 2
+	}
+	config, err := loadConfig()
+	if err != nil {
+		fmt.Fprintln(errOut, "Erro:", err)
+		return 1
+	}
+	e.config = config
+
+	if err := cmd.Run(e, args[2:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		var usage usageError
+		if errors.As(err, &usage) {
+			fmt.Fprintf(errOut, "Erro: %s\nUso: livrosctl %s %s %s\n", usage.message, args[0], cmd.Name, cmd.Usage)
+			return 2
+		}
+		fmt.Fprintln(errOut, "Erro:", describeError(err))
+		return 1
+	}
+	return 0
+}
+
+func findCommand(args []string) (command, bool) {
+	if len(args) < 2 {
+		return command{}, false
+	}
+	for _, g := range groups {
+		if g.Name != args[0] {
+			continue
+		}
+		for _, cmd := range g.Commands {
+			if cmd.Name == args[1] {
+				return cmd, true
+			}
+		}
+	}
+	return command{}, false
+}
+
+// usageError é um erro nos argumentos do subcomando
+type usageError struct {
+	message string
+}
+
+func (e usageError) Error() string {
+	return e.message
+}
+
+func usagef(format string, args ...interface{}) error {
+	return usageError{fmt.Sprintf(format, args...)}
+}
+
+// describeError mostra os erros da API com o status e os detalhes úteis
+func describeError(err error) string {
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) {
+		return err.Error()
+	}
+	msg := fmt.Sprintf("%s (HTTP %d)", apiErr.Message, apiErr.StatusCode)
+	if apiErr.ExistingID != "" {
+		msg += "; livro existente: " + apiErr.ExistingID
+	}
+	if apiErr.TotalBooks > 0 {
+		msg += fmt.Sprintf("; o gênero tem %d livro(s)", apiErr.TotalBooks)
+	}
+	return msg
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Uso: livrosctl [-profile nome] [-server url] [-token jwt] [-o table|json|csv] <grupo> <comando> [argumentos]")
+	fmt.Fprintln(w)
+	for _, g := range groups {
+		fmt.Fprintf(w, "%s: %s\n", g.Name, g.Summary)
+		if g.Name == "completion" {
+			fmt.Fprintln(w, "  completion bash|zsh|fish")
+		}
+		for _, cmd := range g.Commands {
+			fmt.Fprintf(w, "  %s %s %s\n      %s\n", g.Name, cmd.Name, strings.TrimSpace(cmd.Usage), cmd.Summary)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadBooksCSV(t *testing.T) {
+	// Mesmo cabeçalho da exportação em CSV, com BOM
+	data := "\ufeffid,name,author,quantity,genre_id,genre_names,isbn,publisher,publication_year,edition,subjects\n" +
+		"1,Dom Casmurro,Machado de Assis,3,g1,Romance,9788535910667,Penguin,2016,,Romance; Literatura brasileira\n" +
+		"2,Vidas Secas,Graciliano Ramos,1,,,,,,,\n"
+	rows, err := readBooksCSV(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("esperava 2 livros, obteve %d", len(rows))
+	}
+	book := rows[0].book
+	if book.ID != "" || book.Name != "Dom Casmurro" || book.Quantity != 3 || book.Genres[0].ID != "g1" ||
+		*book.PublicationYear != 2016 || len(book.Subjects) != 2 {
+		t.Errorf("livro lido incorretamente: %+v", book)
+	}
+	if rows[1].line != 3 || rows[1].book.PublicationYear != nil || rows[1].book.Genres != nil {
+		t.Errorf("segunda linha lida incorretamente: %+v", rows[1])
+	}
+
+	_, err = readBooksCSV(strings.NewReader("name,quantity\nDom Casmurro,0\n"))
+	if err == nil || !strings.Contains(err.Error(), "linha 2") {
+		t.Errorf("esperava erro na linha 2, obteve %v", err)
+	}
+	if _, err := readBooksCSV(strings.NewReader("titulo,qtd\n")); err == nil {
+		t.Error("esperava erro de cabeçalho sem name e quantity")
+	}
+}
+
+func TestBooksImportSkipsDuplicates(t *testing.T) {
+	var batches [][]map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var books []map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&books)
+		batches = append(batches, books)
+		if len(batches) == 1 {
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"type":"/api/problems/batch-isbn-conflict","title":"ISBN duplicado no lote","status":409,
+				"detail":"Livros com ISBN já cadastrado ou repetido no lote","instance":"/api/books/batch","code":"BATCH_ISBN_CONFLICT",
+				"duplicates":[{"index":0,"isbn":"9788535910667","existing_id":"existente"}]}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		books[0]["id"] = "novo"
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]interface{}{"message": "1 livros criados com sucesso", "books": books})
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	t.Setenv("LIVROSCTL_CONFIG", filepath.Join(dir, "config.json"))
+	file := filepath.Join(dir, "livros.csv")
+	os.WriteFile(file, []byte("name,quantity,isbn\nDom Casmurro,1,85-359-1066-2\nVidas Secas,2,\n"), 0o600)
+
+	var out, errOut bytes.Buffer
+	code := run([]string{"-server", server.URL, "-o", "csv", "books", "import", file, "-skip-duplicates"}, &out, &errOut)
+	if code != 0 {
+		t.Fatalf("código de saída %d: %s", code, errOut.String())
+	}
+	if len(batches) != 2 || len(batches[1]) != 1 || batches[1][0]["name"] != "Vidas Secas" {
+		t.Errorf("esperava o lote reenviado sem o duplicado, obteve %v", batches)
+	}
+	if !strings.Contains(errOut.String(), "Linha 2: ISBN 9788535910667 já cadastrado (livro existente)") {
+		t.Errorf("duplicado não informado: %s", errOut.String())
+	}
+	if !strings.Contains(out.String(), "novo,Vidas Secas,,2,,") {
+		t.Errorf("saída CSV incorreta: %s", out.String())
+	}
+}
+
+func TestProfileResolution(t *testing.T) {
+	t.Setenv("LIVROSCTL_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+	t.Setenv("LIVROS_SERVER", "")
+	t.Setenv("LIVROS_TOKEN", "")
+	var out, errOut bytes.Buffer
+	if code := run([]string{"profile", "set", "prod", "-server", "https://livros.exemplo.com", "-token", "abc"}, &out, &errOut); code != 0 {
+		t.Fatalf("profile set falhou: %s", errOut.String())
+	}
+	config, err := loadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// O primeiro perfil salvo vira o padrão; as flags têm prioridade
+	server, token, _ := config.resolve("", "", "")
+	if server != "https://livros.exemplo.com" || token != "abc" {
+		t.Errorf("perfil padrão não aplicado: %s %s", server, token)
+	}
+	if server, _, _ := config.resolve("", "http://localhost:3001", ""); server != "http://localhost:3001" {
+		t.Errorf("a flag -server deveria prevalecer, obteve %s", server)
+	}
+	if _, _, err := config.resolve("homologacao", "", ""); err == nil {
+		t.Error("esperava erro para perfil inexistente")
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"projeto_livros/pkg/client"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// table é a forma tabular de uma resposta, usada nas saídas table e csv
+type table struct {
+	headers []string
+	rows    [][]string
+}
+
+func (t *table) add(values ...string) {
+	t.rows = append(t.rows, values)
+}
+
+// render imprime a resposta no formato de -o. A saída json usa o valor como a
+// API o devolveu; table e csv usam t.
+func (e *env) render(value interface{}, t table) error {
+	switch e.format {
+	case "json":
+		enc := json.NewEncoder(e.out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(value)
+	case "csv":
+		w := csv.NewWriter(e.out)
+		w.Write(t.headers)
+		w.WriteAll(t.rows)
+		return w.Error()
+	case "table", "":
+		w := tabwriter.NewWriter(e.out, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, strings.Join(t.headers, "\t"))
+		for _, row := range t.rows {
+			fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+		return w.Flush()
+	}
+	return usagef("formato de saída inválido %q: use %s", e.format, strings.Join(outputFormats, ", "))
+}
+
+var bookHeaders = []string{"ID", "NOME", "AUTOR", "QUANTIDADE", "ISBN", "GÊNEROS"}
+
+func bookRow(book client.Book) []string {
+	genres := make([]string, 0, len(book.Genres))
+	for _, genre := range book.Genres {
+		genres = append(genres, firstNonEmpty(genre.Name, genre.ID))
+	}
+	if len(genres) == 0 && book.GenreID != nil {
+		genres = append(genres, *book.GenreID)
+	}
+	return []string{book.ID, book.Name, book.Author, strconv.Itoa(book.Quantity), book.ISBN, strings.Join(genres, ", ")}
+}
+
+func booksTable(books []client.Book) table {
+	t := table{headers: bookHeaders}
+	for _, book := range books {
+		t.add(bookRow(book)...)
+	}
+	return t
+}
+
+// bookDetails mostra um livro campo a campo, uma linha por campo
+func bookDetails(book *client.Book) table {
+	t := table{headers: []string{"CAMPO", "VALOR"}}
+	t.add("id", book.ID)
+	t.add("nome", book.Name)
+	t.add("autor", book.Author)
+	t.add("quantidade", strconv.Itoa(book.Quantity))
+	if book.MinQuantity != nil {
+		t.add("estoque mínimo", strconv.Itoa(*book.MinQuantity))
+	}
+	t.add("isbn", book.ISBN)
+	t.add("editora", book.Publisher)
+	if book.PublicationYear != nil {
+		t.add("ano", strconv.Itoa(*book.PublicationYear))
+	}
+	t.add("edição", book.Edition)
+	t.add("assuntos", strings.Join(book.Subjects, "; "))
+	t.add("gêneros", bookRow(*book)[5])
+	return t
+}
+
+func yesNo(v bool) string {
+	if v {
+		return "sim"
+	}
+	return "não"
+}
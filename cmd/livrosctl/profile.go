@@ -0,0 +1,195 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// defaultServer é usado quando nenhuma flag, variável ou perfil indica a API
+const defaultServer = "http://localhost:3001"
+
+// profile guarda o servidor e o token de um ambiente (local, homologação...)
+type profile struct {
+	Server string `json:"server"`
+	Token  string `json:"token,omitempty"`
+}
+
+// configFile é o arquivo de perfis, em ~/.config/livrosctl/config.json ou no
+// caminho de LIVROSCTL_CONFIG. Guarda tokens, por isso é gravado só para o dono.
+type configFile struct {
+	Current  string             `json:"current,omitempty"`
+	Profiles map[string]profile `json:"profiles"`
+
+	path string
+}
+
+func configPath() (string, error) {
+	if path := os.Getenv("LIVROSCTL_CONFIG"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("não foi possível achar o diretório de configuração: %w", err)
+	}
+	return filepath.Join(dir, "livrosctl", "config.json"), nil
+}
+
+// loadConfig lê o arquivo de perfis; um arquivo ausente equivale a nenhum perfil
+func loadConfig() (*configFile, error) {
+	path, err := configPath()
+	if err != nil {
+		return nil, err
+	}
+	config := &configFile{Profiles: map[string]profile{}, path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("arquivo de perfis inválido (%s): %w", path, err)
+	}
+	if config.Profiles == nil {
+		config.Profiles = map[string]profile{}
+	}
+	return config, nil
+}
+
+func (c *configFile) save() error {
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, append(data, '\n'), 0o600)
+}
+
+// resolve escolhe o servidor e o token: flags, depois LIVROS_SERVER e
+// LIVROS_TOKEN, depois o perfil (o informado ou o padrão)
+func (c *configFile) resolve(name, server, token string) (string, string, error) {
+	if name == "" {
+		name = c.Current
+	}
+	var p profile
+	if name != "" {
+		var ok bool
+		if p, ok = c.Profiles[name]; !ok {
+			return "", "", fmt.Errorf("perfil %q não encontrado", name)
+		}
+	}
+	server = firstNonEmpty(server, os.Getenv("LIVROS_SERVER"), p.Server, defaultServer)
+	token = firstNonEmpty(token, os.Getenv("LIVROS_TOKEN"), p.Token)
+	return server, token, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func runProfileList(e *env, args []string) error {
+	fs := e.flagSet("profile list")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	names := make([]string, 0, len(e.config.Profiles))
+	for name := range e.config.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	type profileView struct {
+		Name     string `json:"name"`
+		Server   string `json:"server"`
+		HasToken bool   `json:"has_token"`
+		Current  bool   `json:"current"`
+	}
+	views := []profileView{}
+	t := table{headers: []string{"PERFIL", "SERVIDOR", "TOKEN", "PADRÃO"}}
+	for _, name := range names {
+		p := e.config.Profiles[name]
+		view := profileView{Name: name, Server: p.Server, HasToken: p.Token != "", Current: name == e.config.Current}
+		views = append(views, view)
+		t.add(name, p.Server, yesNo(view.HasToken), yesNo(view.Current))
+	}
+	return e.render(views, t)
+}
+
+func runProfileSet(e *env, args []string) error {
+	fs := e.flagSet("profile set")
+	server := fs.String("server", "", "URL da API")
+	token := fs.String("token", "", "token JWT; vazio mantém o atual")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return usagef("informe o nome do perfil")
+	}
+	name := positional[0]
+	p := e.config.Profiles[name]
+	if *server != "" {
+		p.Server = *server
+	}
+	if *token != "" {
+		p.Token = *token
+	}
+	if p.Server == "" {
+		return usagef("informe -server para o novo perfil")
+	}
+	e.config.Profiles[name] = p
+	if e.config.Current == "" {
+		e.config.Current = name
+	}
+	if err := e.config.save(); err != nil {
+		return err
+	}
+	fmt.Fprintf(e.errOut, "Perfil %q salvo em %s\n", name, e.config.path)
+	return nil
+}
+
+func runProfileUse(e *env, args []string) error {
+	fs := e.flagSet("profile use")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return usagef("informe o nome do perfil")
+	}
+	if _, ok := e.config.Profiles[positional[0]]; !ok {
+		return fmt.Errorf("perfil %q não encontrado", positional[0])
+	}
+	e.config.Current = positional[0]
+	return e.config.save()
+}
+
+func runProfileDelete(e *env, args []string) error {
+	fs := e.flagSet("profile delete")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return usagef("informe o nome do perfil")
+	}
+	if _, ok := e.config.Profiles[positional[0]]; !ok {
+		return fmt.Errorf("perfil %q não encontrado", positional[0])
+	}
+	delete(e.config.Profiles, positional[0])
+	if e.config.Current == positional[0] {
+		e.config.Current = ""
+	}
+	return e.config.save()
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+)
+
+// runStockAdjust soma -delta ao estoque ou o define com -set. A API REST só
+// define o valor absoluto, então -delta lê o estoque atual antes; duas
+// alterações simultâneas no mesmo livro podem se sobrepor (o AdjustStock do
+// gRPC faz a soma no banco).
+func runStockAdjust(e *env, args []string) error {
+	fs := e.flagSet("stock adjust")
+	delta := fs.Int("delta", 0, "unidades a somar (negativo para saídas)")
+	setTo := fs.Int("set", 0, "novo estoque")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return usagef("informe o id do livro")
+	}
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	if set["delta"] == set["set"] {
+		return usagef("informe -delta ou -set")
+	}
+	if set["delta"] && *delta == 0 {
+		return usagef("-delta não pode ser zero")
+	}
+
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	id := positional[0]
+	quantity := *setTo
+	if set["delta"] {
+		book, err := c.GetBook(e.ctx, id)
+		if err != nil {
+			return err
+		}
+		quantity = book.Quantity + *delta
+	}
+	if quantity < 0 {
+		return fmt.Errorf("o estoque não pode ficar negativo (resultado: %d)", quantity)
+	}
+
+	result, err := c.UpdateQuantity(e.ctx, id, quantity)
+	if err != nil {
+		return err
+	}
+	t := table{headers: []string{"ID", "ANTERIOR", "ATUAL"}}
+	t.add(result.ID, strconv.Itoa(result.OriginalQuantity), strconv.Itoa(result.FinalQuantity))
+	return e.render(result, t)
+}
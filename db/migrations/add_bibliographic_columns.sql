@@ -0,0 +1,20 @@
+-- Script para adicionar os campos bibliográficos usados na importação e exportação MARC21
+ALTER TABLE livros ADD COLUMN IF NOT EXISTS isbn VARCHAR(20);
+ALTER TABLE livros ADD COLUMN IF NOT EXISTS publisher VARCHAR(255);
+ALTER TABLE livros ADD COLUMN IF NOT EXISTS publication_year INTEGER;
+ALTER TABLE livros ADD COLUMN IF NOT EXISTS edition VARCHAR(100);
+ALTER TABLE livros ADD COLUMN IF NOT EXISTS subjects TEXT[] NOT NULL DEFAULT '{}';
+
+-- Índice para buscas por ISBN
+CREATE INDEX IF NOT EXISTS idx_livros_isbn ON livros(isbn);
+
+-- Confirmar a alteração
+SELECT
+    column_name,
+    data_type,
+    is_nullable
+FROM
+    information_schema.columns
+WHERE
+    table_name = 'livros'
+    AND column_name IN ('isbn', 'publisher', 'publication_year', 'edition', 'subjects');
@@ -0,0 +1,64 @@
+-- Script para normalizar os ISBNs existentes para ISBN-13 e garantir que sejam únicos
+
+-- Converte um ISBN-10 ou ISBN-13 (com ou sem hífens) para ISBN-13.
+-- Devolve NULL para valores vazios ou com dígito verificador inválido.
+CREATE OR REPLACE FUNCTION isbn_to_13(raw TEXT) RETURNS TEXT AS $$
+DECLARE
+    clean TEXT := regexp_replace(upper(coalesce(raw, '')), '[^0-9X]', '', 'g');
+    total INTEGER := 0;
+    digit INTEGER;
+    i INTEGER;
+BEGIN
+    IF length(clean) = 10 THEN
+        FOR i IN 1..10 LOOP
+            IF substr(clean, i, 1) = 'X' THEN
+                IF i <> 10 THEN
+                    RETURN NULL;
+                END IF;
+                digit := 10;
+            ELSE
+                digit := substr(clean, i, 1)::INTEGER;
+            END IF;
+            total := total + digit * (11 - i);
+        END LOOP;
+        IF total % 11 <> 0 THEN
+            RETURN NULL;
+        END IF;
+        clean := '978' || substr(clean, 1, 9);
+        total := 0;
+        FOR i IN 1..12 LOOP
+            total := total + substr(clean, i, 1)::INTEGER * (CASE WHEN i % 2 = 0 THEN 3 ELSE 1 END);
+        END LOOP;
+        RETURN clean || ((10 - total % 10) % 10)::TEXT;
+    END IF;
+
+    IF length(clean) = 13 AND clean !~ 'X' AND left(clean, 3) IN ('978', '979') THEN
+        FOR i IN 1..12 LOOP
+            total := total + substr(clean, i, 1)::INTEGER * (CASE WHEN i % 2 = 0 THEN 3 ELSE 1 END);
+        END LOOP;
+        IF (10 - total % 10) % 10 = substr(clean, 13, 1)::INTEGER THEN
+            RETURN clean;
+        END IF;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+-- Listar os ISBNs inválidos antes de descartá-los, para conferência manual
+SELECT id, name, isbn AS isbn_invalido
+FROM livros
+WHERE isbn IS NOT NULL AND isbn <> '' AND isbn_to_13(isbn) IS NULL;
+
+UPDATE livros SET isbn = isbn_to_13(isbn) WHERE isbn IS NOT NULL;
+
+-- Listar ISBNs repetidos: precisam ser resolvidos antes de criar o índice único
+SELECT isbn, array_agg(id) AS livros
+FROM livros
+WHERE isbn IS NOT NULL
+GROUP BY isbn
+HAVING COUNT(*) > 1;
+
+-- O índice único substitui o índice simples criado em add_bibliographic_columns.sql
+DROP INDEX IF EXISTS idx_livros_isbn;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_livros_isbn_unique ON livros(isbn) WHERE isbn IS NOT NULL;
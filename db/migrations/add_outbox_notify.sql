@@ -0,0 +1,16 @@
+-- Script para o feed de alterações em tempo real (/api/events/stream).
+-- Cada evento gravado no outbox é anunciado no canal outbox_events com o seu
+-- ID; o Postgres só entrega o NOTIFY no commit da transação. Todas as
+-- instâncias da API escutam o canal e repassam o evento aos seus clientes.
+
+CREATE OR REPLACE FUNCTION notify_outbox_event() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('outbox_events', NEW.id::text);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_outbox_events_notify ON outbox_events;
+CREATE TRIGGER trg_outbox_events_notify
+    AFTER INSERT ON outbox_events
+    FOR EACH ROW EXECUTE FUNCTION notify_outbox_event();
@@ -0,0 +1,15 @@
+-- Script para a lixeira de livros e gêneros. Remover um livro ou gênero apenas
+-- preenche deleted_at; o item some das listagens e pode ser restaurado até a
+-- limpeza periódica (TRASH_RETENTION) apagá-lo definitivamente.
+
+ALTER TABLE livros ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE genres ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_livros_deleted_at ON livros(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_genres_deleted_at ON genres(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Um livro na lixeira não bloqueia o seu ISBN; ao restaurá-lo, o índice acusa
+-- o conflito se outro livro passou a usar o mesmo ISBN. O nome dos gêneros
+-- continua único também entre os removidos.
+DROP INDEX IF EXISTS idx_livros_isbn_unique;
+CREATE UNIQUE INDEX idx_livros_isbn_unique ON livros(isbn) WHERE isbn IS NOT NULL AND deleted_at IS NULL;
@@ -0,0 +1,48 @@
+-- Script para o log de auditoria. Cada requisição de escrita (POST, PUT,
+-- PATCH e DELETE) grava quem a fez, a rota, a entidade afetada com o estado
+-- antes e depois, o IP, o id da requisição e o resultado.
+-- As entradas são numeradas sem lacunas (seq) e cada uma guarda o hash da
+-- anterior; o comando audit-verify recalcula a cadeia e aponta alterações.
+-- before_state e after_state usam JSON (e não JSONB) para preservar o texto
+-- exato que entrou no hash. Os gatilhos impedem UPDATE, DELETE e TRUNCATE na
+-- tabela; a remoção de entradas do fim do log por outros meios é acusada pelo
+-- ponto de controle do audit-verify (-head ou -head-file).
+
+CREATE TABLE IF NOT EXISTS audit_log (
+    seq BIGINT PRIMARY KEY CHECK (seq > 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    actor VARCHAR(255),
+    method VARCHAR(10) NOT NULL,
+    route VARCHAR(255) NOT NULL,
+    path TEXT NOT NULL,
+    entity VARCHAR(50),
+    entity_id VARCHAR(255),
+    before_state JSON,
+    after_state JSON,
+    client_ip VARCHAR(64) NOT NULL,
+    request_id VARCHAR(255),
+    status_code INTEGER NOT NULL,
+    outcome VARCHAR(10) NOT NULL CHECK (outcome IN ('success', 'failure')),
+    prev_hash CHAR(64) NOT NULL,
+    hash CHAR(64) NOT NULL UNIQUE
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor ON audit_log(actor);
+CREATE INDEX IF NOT EXISTS idx_audit_log_entity ON audit_log(entity, entity_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at);
+
+CREATE OR REPLACE FUNCTION reject_audit_log_change() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log é somente de inclusão';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_audit_log_append_only ON audit_log;
+CREATE TRIGGER trg_audit_log_append_only
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION reject_audit_log_change();
+
+DROP TRIGGER IF EXISTS trg_audit_log_no_truncate ON audit_log;
+CREATE TRIGGER trg_audit_log_no_truncate
+    BEFORE TRUNCATE ON audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION reject_audit_log_change();
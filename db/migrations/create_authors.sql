@@ -0,0 +1,61 @@
+-- Script para transformar o autor dos livros em entidade própria.
+-- livros.author continua existindo como campo calculado (nomes dos autores
+-- com papel "author"), mantido pela aplicação.
+
+CREATE TABLE IF NOT EXISTS authors (
+    id VARCHAR(27) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    normalized_name VARCHAR(255) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS book_authors (
+    book_id VARCHAR(27) NOT NULL REFERENCES livros(id) ON DELETE CASCADE,
+    author_id VARCHAR(27) NOT NULL REFERENCES authors(id),
+    role VARCHAR(20) NOT NULL DEFAULT 'author'
+        CHECK (role IN ('author', 'translator', 'illustrator')),
+    position INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (book_id, author_id, role)
+);
+
+CREATE INDEX IF NOT EXISTS idx_book_authors_author ON book_authors(author_id);
+
+-- Deduplicar os autores existentes: nomes que diferem apenas em espaços ou
+-- maiúsculas/minúsculas viram um único autor. O nome exibido é o mais usado.
+WITH cleaned AS (
+    SELECT regexp_replace(trim(author), '\s+', ' ', 'g') AS name
+    FROM livros
+    WHERE author IS NOT NULL AND trim(author) <> ''
+),
+ranked AS (
+    SELECT name, lower(name) AS normalized_name,
+           ROW_NUMBER() OVER (PARTITION BY lower(name) ORDER BY COUNT(*) DESC, name) AS rank
+    FROM cleaned
+    GROUP BY name
+)
+INSERT INTO authors (id, name, normalized_name)
+SELECT left(replace(gen_random_uuid()::text, '-', ''), 27), name, normalized_name
+FROM ranked
+WHERE rank = 1
+ON CONFLICT (normalized_name) DO NOTHING;
+
+-- Vincular cada livro ao seu autor
+INSERT INTO book_authors (book_id, author_id, role, position)
+SELECT l.id, a.id, 'author', 0
+FROM livros l
+JOIN authors a ON a.normalized_name = lower(regexp_replace(trim(l.author), '\s+', ' ', 'g'))
+ON CONFLICT DO NOTHING;
+
+-- Reescrever livros.author com o nome deduplicado
+UPDATE livros l
+SET author = a.name
+FROM book_authors ba
+JOIN authors a ON a.id = ba.author_id
+WHERE ba.book_id = l.id AND ba.role = 'author' AND l.author IS DISTINCT FROM a.name;
+
+-- Conferir o resultado
+SELECT
+    (SELECT COUNT(*) FROM authors) AS total_autores,
+    (SELECT COUNT(DISTINCT book_id) FROM book_authors) AS livros_vinculados,
+    (SELECT COUNT(*) FROM livros WHERE author IS NOT NULL AND trim(author) <> '') AS livros_com_autor;
@@ -0,0 +1,29 @@
+-- Script para permitir vários gêneros por livro e gêneros hierárquicos.
+-- livros.genre_id continua existindo como o gênero principal (o primeiro de
+-- book_genres), para compatibilidade com clientes que usam um único gênero.
+
+ALTER TABLE genres ADD COLUMN IF NOT EXISTS parent_id VARCHAR(27) REFERENCES genres(id);
+ALTER TABLE genres DROP CONSTRAINT IF EXISTS genres_parent_not_self;
+ALTER TABLE genres ADD CONSTRAINT genres_parent_not_self CHECK (parent_id <> id);
+CREATE INDEX IF NOT EXISTS idx_genres_parent ON genres(parent_id);
+
+CREATE TABLE IF NOT EXISTS book_genres (
+    book_id VARCHAR(27) NOT NULL REFERENCES livros(id) ON DELETE CASCADE,
+    genre_id VARCHAR(27) NOT NULL REFERENCES genres(id),
+    position INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (book_id, genre_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_book_genres_genre ON book_genres(genre_id);
+
+-- Copiar o gênero atual de cada livro como seu gênero principal
+INSERT INTO book_genres (book_id, genre_id, position)
+SELECT id, genre_id, 0
+FROM livros
+WHERE genre_id IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+-- Conferir o resultado
+SELECT
+    (SELECT COUNT(*) FROM livros WHERE genre_id IS NOT NULL) AS livros_com_genero,
+    (SELECT COUNT(DISTINCT book_id) FROM book_genres) AS livros_em_book_genres;
@@ -0,0 +1,20 @@
+-- Script para o histórico de revisões dos livros. Cada criação, alteração,
+-- remoção, restauração ou reversão grava o estado completo do livro (com
+-- autores e gêneros), os campos alterados e o usuário responsável.
+-- book_id não tem chave estrangeira: o histórico sobrevive à remoção
+-- definitiva do livro. Livros já existentes ganham a primeira revisão na
+-- próxima alteração.
+
+CREATE TABLE IF NOT EXISTS book_revisions (
+    id VARCHAR(27) PRIMARY KEY,
+    book_id VARCHAR(27) NOT NULL,
+    rev INTEGER NOT NULL CHECK (rev > 0),
+    action VARCHAR(20) NOT NULL
+        CHECK (action IN ('create', 'update', 'delete', 'restore', 'revert')),
+    actor VARCHAR(255),
+    changed_fields TEXT[] NOT NULL DEFAULT '{}',
+    reverted_from INTEGER,
+    snapshot JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (book_id, rev)
+);
@@ -0,0 +1,43 @@
+-- Script para alertas de estoque baixo. Cada livro pode ter um estoque mínimo
+-- (min_quantity); quando a quantidade fica abaixo dele, um alerta é
+-- registrado. O registro é feito por trigger para valer em todos os caminhos
+-- que alteram o estoque (inclusive atualizações diretas no banco).
+
+ALTER TABLE livros ADD COLUMN IF NOT EXISTS min_quantity INTEGER CHECK (min_quantity >= 0);
+
+CREATE TABLE IF NOT EXISTS low_stock_alerts (
+    id VARCHAR(27) PRIMARY KEY DEFAULT left(replace(gen_random_uuid()::text, '-', ''), 27),
+    book_id VARCHAR(27) NOT NULL REFERENCES livros(id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL,
+    min_quantity INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    acknowledged_at TIMESTAMP WITH TIME ZONE,
+    acknowledged_by VARCHAR(255),
+    resolved_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_low_stock_alerts_book ON low_stock_alerts(book_id);
+-- No máximo um alerta em aberto (não resolvido) por livro
+CREATE UNIQUE INDEX IF NOT EXISTS idx_low_stock_alerts_open
+    ON low_stock_alerts(book_id) WHERE resolved_at IS NULL;
+
+-- Registra o alerta quando o estoque fica abaixo do mínimo e o resolve quando
+-- o estoque volta ao mínimo (ou o mínimo é removido)
+CREATE OR REPLACE FUNCTION record_low_stock_alert() RETURNS trigger AS $$
+BEGIN
+    IF NEW.min_quantity IS NOT NULL AND NEW.quantity < NEW.min_quantity THEN
+        INSERT INTO low_stock_alerts (book_id, quantity, min_quantity)
+        VALUES (NEW.id, NEW.quantity, NEW.min_quantity)
+        ON CONFLICT (book_id) WHERE resolved_at IS NULL DO NOTHING;
+    ELSE
+        UPDATE low_stock_alerts SET resolved_at = CURRENT_TIMESTAMP
+        WHERE book_id = NEW.id AND resolved_at IS NULL;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_livros_low_stock ON livros;
+CREATE TRIGGER trg_livros_low_stock
+    AFTER INSERT OR UPDATE OF quantity, min_quantity ON livros
+    FOR EACH ROW EXECUTE FUNCTION record_low_stock_alert();
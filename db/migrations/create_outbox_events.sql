@@ -0,0 +1,22 @@
+-- Script para o outbox de eventos de domínio (BookCreated, StockChanged,
+-- GenreCreated...). Os eventos são gravados na mesma transação da alteração
+-- e entregues em ordem de id pelo despachante, que marca dispatched_at após
+-- a confirmação do destino (entrega pelo menos uma vez). Eventos entregues
+-- são apagados depois do prazo de retenção.
+
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id BIGSERIAL PRIMARY KEY,
+    event_type VARCHAR(50) NOT NULL,
+    aggregate_type VARCHAR(20) NOT NULL,
+    aggregate_id VARCHAR(27) NOT NULL,
+    actor VARCHAR(255),
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT,
+    dispatched_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_events_pending ON outbox_events(id) WHERE dispatched_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_events_dispatched ON outbox_events(dispatched_at) WHERE dispatched_at IS NOT NULL;
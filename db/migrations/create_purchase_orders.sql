@@ -0,0 +1,52 @@
+-- Script para fornecedores e pedidos de compra. O recebimento de um pedido
+-- aumenta o estoque dos livros e grava uma linha em stock_receipts, que liga
+-- cada entrada de estoque ao pedido de origem.
+
+CREATE TABLE IF NOT EXISTS suppliers (
+    id VARCHAR(27) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    email VARCHAR(255),
+    phone VARCHAR(50),
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS purchase_orders (
+    id VARCHAR(27) PRIMARY KEY,
+    supplier_id VARCHAR(27) NOT NULL REFERENCES suppliers(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'draft'
+        CHECK (status IN ('draft', 'sent', 'partially_received', 'received')),
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    sent_at TIMESTAMP WITH TIME ZONE,
+    received_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE TABLE IF NOT EXISTS purchase_order_lines (
+    id VARCHAR(27) PRIMARY KEY,
+    order_id VARCHAR(27) NOT NULL REFERENCES purchase_orders(id) ON DELETE CASCADE,
+    book_id VARCHAR(27) NOT NULL REFERENCES livros(id),
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    unit_cost NUMERIC(12, 2) NOT NULL DEFAULT 0 CHECK (unit_cost >= 0),
+    received_quantity INTEGER NOT NULL DEFAULT 0
+        CHECK (received_quantity >= 0 AND received_quantity <= quantity),
+    UNIQUE (order_id, book_id)
+);
+
+CREATE TABLE IF NOT EXISTS stock_receipts (
+    id VARCHAR(27) PRIMARY KEY,
+    order_id VARCHAR(27) NOT NULL REFERENCES purchase_orders(id),
+    line_id VARCHAR(27) NOT NULL REFERENCES purchase_order_lines(id),
+    book_id VARCHAR(27) NOT NULL REFERENCES livros(id),
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    received_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    received_by VARCHAR(255)
+);
+
+CREATE INDEX IF NOT EXISTS idx_purchase_orders_supplier ON purchase_orders(supplier_id);
+CREATE INDEX IF NOT EXISTS idx_purchase_orders_status ON purchase_orders(status);
+CREATE INDEX IF NOT EXISTS idx_purchase_order_lines_order ON purchase_order_lines(order_id);
+CREATE INDEX IF NOT EXISTS idx_stock_receipts_order ON stock_receipts(order_id);
+CREATE INDEX IF NOT EXISTS idx_stock_receipts_book ON stock_receipts(book_id);
@@ -11,14 +11,258 @@ CREATE TABLE IF NOT EXISTS genres (
     id VARCHAR(27) PRIMARY KEY,
     name VARCHAR(100) NOT NULL UNIQUE,
     description TEXT,
+    parent_id VARCHAR(27) REFERENCES genres(id) CHECK (parent_id <> id),
+    deleted_at TIMESTAMP WITH TIME ZONE,
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 );
 
 ALTER TABLE livros ADD COLUMN IF NOT EXISTS genre_id VARCHAR(27) REFERENCES genres(id);
+ALTER TABLE livros ADD COLUMN IF NOT EXISTS isbn VARCHAR(20);
+ALTER TABLE livros ADD COLUMN IF NOT EXISTS publisher VARCHAR(255);
+ALTER TABLE livros ADD COLUMN IF NOT EXISTS publication_year INTEGER;
+ALTER TABLE livros ADD COLUMN IF NOT EXISTS edition VARCHAR(100);
+ALTER TABLE livros ADD COLUMN IF NOT EXISTS subjects TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE livros ADD COLUMN IF NOT EXISTS min_quantity INTEGER CHECK (min_quantity >= 0);
+ALTER TABLE livros ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE TABLE IF NOT EXISTS authors (
+    id VARCHAR(27) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    normalized_name VARCHAR(255) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS book_authors (
+    book_id VARCHAR(27) NOT NULL REFERENCES livros(id) ON DELETE CASCADE,
+    author_id VARCHAR(27) NOT NULL REFERENCES authors(id),
+    role VARCHAR(20) NOT NULL DEFAULT 'author'
+        CHECK (role IN ('author', 'translator', 'illustrator')),
+    position INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (book_id, author_id, role)
+);
+
+CREATE TABLE IF NOT EXISTS book_genres (
+    book_id VARCHAR(27) NOT NULL REFERENCES livros(id) ON DELETE CASCADE,
+    genre_id VARCHAR(27) NOT NULL REFERENCES genres(id),
+    position INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (book_id, genre_id)
+);
+
+CREATE TABLE IF NOT EXISTS low_stock_alerts (
+    id VARCHAR(27) PRIMARY KEY DEFAULT left(replace(gen_random_uuid()::text, '-', ''), 27),
+    book_id VARCHAR(27) NOT NULL REFERENCES livros(id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL,
+    min_quantity INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    acknowledged_at TIMESTAMP WITH TIME ZONE,
+    acknowledged_by VARCHAR(255),
+    resolved_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE TABLE IF NOT EXISTS suppliers (
+    id VARCHAR(27) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    email VARCHAR(255),
+    phone VARCHAR(50),
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS purchase_orders (
+    id VARCHAR(27) PRIMARY KEY,
+    supplier_id VARCHAR(27) NOT NULL REFERENCES suppliers(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'draft'
+        CHECK (status IN ('draft', 'sent', 'partially_received', 'received')),
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    sent_at TIMESTAMP WITH TIME ZONE,
+    received_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE TABLE IF NOT EXISTS purchase_order_lines (
+    id VARCHAR(27) PRIMARY KEY,
+    order_id VARCHAR(27) NOT NULL REFERENCES purchase_orders(id) ON DELETE CASCADE,
+    book_id VARCHAR(27) NOT NULL REFERENCES livros(id),
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    unit_cost NUMERIC(12, 2) NOT NULL DEFAULT 0 CHECK (unit_cost >= 0),
+    received_quantity INTEGER NOT NULL DEFAULT 0
+        CHECK (received_quantity >= 0 AND received_quantity <= quantity),
+    UNIQUE (order_id, book_id)
+);
+
+CREATE TABLE IF NOT EXISTS stock_receipts (
+    id VARCHAR(27) PRIMARY KEY,
+    order_id VARCHAR(27) NOT NULL REFERENCES purchase_orders(id),
+    line_id VARCHAR(27) NOT NULL REFERENCES purchase_order_lines(id),
+    book_id VARCHAR(27) NOT NULL REFERENCES livros(id),
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    received_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    received_by VARCHAR(255)
+);
+
+CREATE TABLE IF NOT EXISTS book_revisions (
+    id VARCHAR(27) PRIMARY KEY,
+    book_id VARCHAR(27) NOT NULL,
+    rev INTEGER NOT NULL CHECK (rev > 0),
+    action VARCHAR(20) NOT NULL
+        CHECK (action IN ('create', 'update', 'delete', 'restore', 'revert')),
+    actor VARCHAR(255),
+    changed_fields TEXT[] NOT NULL DEFAULT '{}',
+    reverted_from INTEGER,
+    snapshot JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (book_id, rev)
+);
+
+CREATE TABLE IF NOT EXISTS audit_log (
+    seq BIGINT PRIMARY KEY CHECK (seq > 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    actor VARCHAR(255),
+    method VARCHAR(10) NOT NULL,
+    route VARCHAR(255) NOT NULL,
+    path TEXT NOT NULL,
+    entity VARCHAR(50),
+    entity_id VARCHAR(255),
+    before_state JSON,
+    after_state JSON,
+    client_ip VARCHAR(64) NOT NULL,
+    request_id VARCHAR(255),
+    status_code INTEGER NOT NULL,
+    outcome VARCHAR(10) NOT NULL CHECK (outcome IN ('success', 'failure')),
+    prev_hash CHAR(64) NOT NULL,
+    hash CHAR(64) NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id BIGSERIAL PRIMARY KEY,
+    event_type VARCHAR(50) NOT NULL,
+    aggregate_type VARCHAR(20) NOT NULL,
+    aggregate_id VARCHAR(27) NOT NULL,
+    actor VARCHAR(255),
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT,
+    dispatched_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id VARCHAR(27) PRIMARY KEY,
+    url TEXT NOT NULL,
+    description VARCHAR(255),
+    event_types TEXT[] NOT NULL DEFAULT '{}',
+    secret VARCHAR(255) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    failure_count INTEGER NOT NULL DEFAULT 0,
+    disabled_at TIMESTAMP WITH TIME ZONE,
+    disabled_reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id VARCHAR(27) PRIMARY KEY,
+    subscription_id VARCHAR(27) NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    event_id BIGINT,
+    event_type VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'succeeded', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    last_status_code INTEGER,
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    delivered_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE (subscription_id, event_id)
+);
+
+CREATE TABLE IF NOT EXISTS webhook_attempts (
+    id VARCHAR(27) PRIMARY KEY,
+    delivery_id VARCHAR(27) NOT NULL REFERENCES webhook_deliveries(id) ON DELETE CASCADE,
+    attempted_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    status_code INTEGER,
+    error TEXT,
+    response_body TEXT,
+    duration_ms BIGINT NOT NULL DEFAULT 0
+);
 
 CREATE INDEX IF NOT EXISTS idx_livros_name ON livros(name);
 CREATE INDEX IF NOT EXISTS idx_genres_name ON genres(name);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_livros_isbn_unique ON livros(isbn) WHERE isbn IS NOT NULL AND deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_livros_deleted_at ON livros(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_genres_deleted_at ON genres(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_book_authors_author ON book_authors(author_id);
+CREATE INDEX IF NOT EXISTS idx_genres_parent ON genres(parent_id);
+CREATE INDEX IF NOT EXISTS idx_book_genres_genre ON book_genres(genre_id);
+CREATE INDEX IF NOT EXISTS idx_low_stock_alerts_book ON low_stock_alerts(book_id);
+CREATE INDEX IF NOT EXISTS idx_purchase_orders_supplier ON purchase_orders(supplier_id);
+CREATE INDEX IF NOT EXISTS idx_purchase_orders_status ON purchase_orders(status);
+CREATE INDEX IF NOT EXISTS idx_purchase_order_lines_order ON purchase_order_lines(order_id);
+CREATE INDEX IF NOT EXISTS idx_stock_receipts_order ON stock_receipts(order_id);
+CREATE INDEX IF NOT EXISTS idx_stock_receipts_book ON stock_receipts(book_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor ON audit_log(actor);
+CREATE INDEX IF NOT EXISTS idx_audit_log_entity ON audit_log(entity, entity_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at);
+CREATE INDEX IF NOT EXISTS idx_outbox_events_pending ON outbox_events(id) WHERE dispatched_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_events_dispatched ON outbox_events(dispatched_at) WHERE dispatched_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription ON webhook_deliveries(subscription_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_webhook_attempts_delivery ON webhook_attempts(delivery_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_low_stock_alerts_open
+    ON low_stock_alerts(book_id) WHERE resolved_at IS NULL;
+
+CREATE OR REPLACE FUNCTION record_low_stock_alert() RETURNS trigger AS $$
+BEGIN
+    IF NEW.min_quantity IS NOT NULL AND NEW.quantity < NEW.min_quantity THEN
+        INSERT INTO low_stock_alerts (book_id, quantity, min_quantity)
+        VALUES (NEW.id, NEW.quantity, NEW.min_quantity)
+        ON CONFLICT (book_id) WHERE resolved_at IS NULL DO NOTHING;
+    ELSE
+        UPDATE low_stock_alerts SET resolved_at = CURRENT_TIMESTAMP
+        WHERE book_id = NEW.id AND resolved_at IS NULL;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_livros_low_stock ON livros;
+CREATE TRIGGER trg_livros_low_stock
+    AFTER INSERT OR UPDATE OF quantity, min_quantity ON livros
+    FOR EACH ROW EXECUTE FUNCTION record_low_stock_alert();
+
+CREATE OR REPLACE FUNCTION reject_audit_log_change() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log é somente de inclusão';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_audit_log_append_only ON audit_log;
+CREATE TRIGGER trg_audit_log_append_only
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION reject_audit_log_change();
+
+DROP TRIGGER IF EXISTS trg_audit_log_no_truncate ON audit_log;
+CREATE TRIGGER trg_audit_log_no_truncate
+    BEFORE TRUNCATE ON audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION reject_audit_log_change();
+
+CREATE OR REPLACE FUNCTION notify_outbox_event() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('outbox_events', NEW.id::text);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_outbox_events_notify ON outbox_events;
+CREATE TRIGGER trg_outbox_events_notify
+    AFTER INSERT ON outbox_events
+    FOR EACH ROW EXECUTE FUNCTION notify_outbox_event();
 
 INSERT INTO genres (id, name, description) VALUES
     (gen_random_uuid(), 'Romance', 'Obras que focam em relacionamentos e emoções'),
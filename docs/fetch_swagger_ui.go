@@ -0,0 +1,70 @@
+//go:build ignore
+
+// Baixa o pacote swagger-ui-dist do registro do npm e copia para swagger-ui/
+// os arquivos usados por /api/docs, que passam a ser embutidos no binário.
+// Uso: go generate ./docs
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+const version = "5.17.14"
+
+// files são os arquivos do pacote copiados para swagger-ui/
+var files = map[string]bool{
+	"package/swagger-ui.css":       true,
+	"package/swagger-ui-bundle.js": true,
+	"package/LICENSE":              true,
+}
+
+func main() {
+	url := fmt.Sprintf("https://registry.npmjs.org/swagger-ui-dist/-/swagger-ui-dist-%s.tgz", version)
+	resp, err := http.Get(url)
+	if err != nil {
+		log.Fatalf("Erro ao baixar %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		log.Fatalf("Erro ao baixar %s: status %d", url, resp.StatusCode)
+	}
+	gz, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		log.Fatalf("Pacote inválido: %v", err)
+	}
+	archive := tar.NewReader(gz)
+	copied := 0
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Fatalf("Pacote inválido: %v", err)
+		}
+		if !files[header.Name] {
+			continue
+		}
+		target := filepath.Join("swagger-ui", filepath.Base(header.Name))
+		out, err := os.Create(target)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if _, err := io.Copy(out, archive); err != nil {
+			log.Fatalf("Erro ao gravar %s: %v", target, err)
+		}
+		out.Close()
+		copied++
+	}
+	if copied != len(files) {
+		log.Fatalf("Esperava %d arquivos no pacote, copiou %d", len(files), copied)
+	}
+	log.Printf("swagger-ui-dist %s copiado para swagger-ui/", version)
+}
@@ -0,0 +1,258 @@
+package docs
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Tipos do documento OpenAPI 3.0. Só os campos usados pela API estão aqui.
+
+type Document struct {
+	OpenAPI    string                `json:"openapi"`
+	Info       Info                  `json:"info"`
+	Servers    []Server              `json:"servers"`
+	Tags       []Tag                 `json:"tags"`
+	Paths      map[string]PathItem   `json:"paths"`
+	Components Components            `json:"components"`
+	Security   []map[string][]string `json:"security,omitempty"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type Server struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem reúne as operações de um caminho, indexadas pelo método em minúsculas
+type PathItem map[string]*Operation
+
+type Operation struct {
+	Tags        []string             `json:"tags,omitempty"`
+	Summary     string               `json:"summary"`
+	Description string               `json:"description,omitempty"`
+	OperationID string               `json:"operationId"`
+	Parameters  []Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"` // path, query ou header
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Description string               `json:"description,omitempty"`
+	Required    bool                 `json:"required,omitempty"`
+	Content     map[string]MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+type Response struct {
+	Ref         string               `json:"$ref,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// Schema é um JSON Schema no dialeto do OpenAPI 3.0
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	Responses       map[string]*Response       `json:"responses"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+// pathParam encontra os parâmetros {nome} dos padrões do chi
+// problemMediaType é o tipo de mídia das respostas de erro
+const problemMediaType = "application/problem+json"
+
+var pathParam = regexp.MustCompile(`\{([^}/:]+)(:[^}]*)?\}`)
+
+// RouteKey é a chave de uma rota na tabela de operações, como
+// "GET /api/books/{id}". A barra final dos subroteadores é removida.
+func RouteKey(method, pattern string) string {
+	if len(pattern) > 1 {
+		pattern = strings.TrimSuffix(pattern, "/")
+	}
+	return method + " " + pattern
+}
+
+// Generate monta o documento a partir das rotas registradas no roteador: cada
+// rota recebe a operação da tabela operations, com os parâmetros de caminho
+// lidos do padrão. Devolve também as rotas sem operação documentada.
+func Generate(routes chi.Routes) (*Document, []string) {
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info: Info{
+			Title:   "Book Management API",
+			Version: "1.0",
+			Description: "API para gerenciar o catálogo de livros, gêneros, autores, estoque e compras. " +
+				"Os erros são enviados como application/problem+json (Problem), com o código do catálogo em code; " +
+				"o catálogo completo está em /api/problems. Os títulos e as mensagens seguem o cabeçalho " +
+				"Accept-Language (pt-BR, o padrão, ou en).",
+		},
+		Servers: []Server{{URL: "http://localhost:3001", Description: "Servidor local"}},
+		Tags:    tags,
+		Paths:   map[string]PathItem{},
+		Components: Components{
+			Schemas:   componentSchemas(),
+			Responses: errorResponses,
+			SecuritySchemes: map[string]*SecurityScheme{
+				"bearerAuth": {
+					Type: "http", Scheme: "bearer", BearerFormat: "JWT",
+					Description: "Opcional na maioria das rotas; identifica o usuário e o papel (admin) nas alterações",
+				},
+			},
+		},
+		// Sem token ou com token: a autenticação ainda não é obrigatória
+		Security: []map[string][]string{{}, {"bearerAuth": {}}},
+	}
+
+	var undocumented []string
+	chi.Walk(routes, func(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		key := RouteKey(method, route)
+		op, ok := operations[key]
+		if !ok {
+			undocumented = append(undocumented, key)
+			return nil
+		}
+		path := key[len(method)+1:]
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = PathItem{}
+		}
+		doc.Paths[path][strings.ToLower(method)] = complete(op, path)
+		return nil
+	})
+	sort.Strings(undocumented)
+	return doc, undocumented
+}
+
+// complete copia a operação acrescentando os parâmetros de caminho, a
+// resposta 400 da validação nas operações com parâmetros ou corpo e a 413 do
+// limite de tamanho nas operações com corpo JSON
+func complete(op *Operation, path string) *Operation {
+	result := *op
+	var params []Parameter
+	for _, match := range pathParam.FindAllStringSubmatch(path, -1) {
+		param, ok := pathParams[match[1]]
+		if !ok {
+			param = Parameter{Schema: &Schema{Type: "string"}}
+		}
+		param.Name = match[1]
+		param.In = "path"
+		param.Required = true
+		params = append(params, param)
+	}
+	result.Parameters = append(params, op.Parameters...)
+	var extra []errorRef
+	if _, ok := op.Responses["400"]; !ok && (len(result.Parameters) > 0 || op.RequestBody != nil) {
+		extra = append(extra, badRequest)
+	}
+	if op.RequestBody != nil && op.RequestBody.Content["application/json"].Schema != nil {
+		extra = append(extra, payloadTooLarge)
+	}
+	if len(extra) > 0 {
+		result.Responses = map[string]*Response{}
+		for _, ref := range extra {
+			result.Responses[ref.status] = &Response{Ref: "#/components/responses/" + ref.name}
+		}
+		for status, response := range op.Responses {
+			result.Responses[status] = response
+		}
+	}
+	return &result
+}
+
+// Operations devolve as chaves ("MÉTODO /caminho") da tabela de operações
+func Operations() []string {
+	keys := make([]string, 0, len(operations))
+	for key := range operations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Spec é o documento OpenAPI de um roteador, montado na primeira vez em que
+// é usado, quando todas as rotas já foram registradas
+type Spec struct {
+	routes chi.Routes
+	once   sync.Once
+	doc    *Document
+	body   []byte
+}
+
+func NewSpec(routes chi.Routes) *Spec {
+	return &Spec{routes: routes}
+}
+
+func (s *Spec) Document() *Document {
+	s.once.Do(func() {
+		s.doc, _ = Generate(s.routes)
+		s.body, _ = json.MarshalIndent(s.doc, "", "  ")
+	})
+	return s.doc
+}
+
+// Operation devolve a operação do método no padrão de rota do chi, ou nil
+// quando a rota não está documentada
+func (s *Spec) Operation(method, pattern string) *Operation {
+	key := RouteKey(method, pattern)
+	path := key[len(method)+1:]
+	return s.Document().Paths[path][strings.ToLower(method)]
+}
+
+// ServeHTTP serve o documento em JSON
+func (s *Spec) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.Document()
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(s.body)
+}
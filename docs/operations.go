@@ -0,0 +1,571 @@
+package docs
+
+import (
+	"projeto_livros/internal/domain/models"
+	"strconv"
+)
+
+// errorRef aponta uma resposta de erro compartilhada em components.responses
+type errorRef struct {
+	status string
+	name   string
+}
+
+var (
+	badRequest         = errorRef{"400", "BadRequest"}
+	forbidden          = errorRef{"403", "Forbidden"}
+	notFound           = errorRef{"404", "NotFound"}
+	conflict           = errorRef{"409", "Conflict"}
+	payloadTooLarge    = errorRef{"413", "PayloadTooLarge"}
+	internalError      = errorRef{"500", "InternalError"}
+	badGateway         = errorRef{"502", "BadGateway"}
+	serviceUnavailable = errorRef{"503", "ServiceUnavailable"}
+)
+
+func jsonContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: schema}}
+}
+
+func problemContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{problemMediaType: {Schema: schema}}
+}
+
+func errorResponse(description string) *Response {
+	return &Response{Description: description, Content: problemContent(ref("Problem"))}
+}
+
+var errorResponses = map[string]*Response{
+	"BadRequest":         errorResponse("Requisição inválida"),
+	"Forbidden":          errorResponse("Operação permitida apenas a administradores"),
+	"NotFound":           errorResponse("Recurso não encontrado"),
+	"Conflict":           errorResponse("Conflito com o estado atual do recurso"),
+	"PayloadTooLarge":    errorResponse("Corpo JSON acima do limite da API"),
+	"InternalError":      errorResponse("Erro interno"),
+	"BadGateway":         errorResponse("Falha no serviço externo"),
+	"ServiceUnavailable": errorResponse("Serviço não configurado"),
+}
+
+// status é uma resposta de sucesso ou um erro específico da rota
+type status struct {
+	code     string
+	response *Response
+}
+
+func ok(code, description string, schema *Schema) status {
+	response := &Response{Description: description}
+	if schema != nil {
+		response.Content = jsonContent(schema)
+	}
+	return status{code, response}
+}
+
+func withContent(code, description string, content map[string]MediaType) status {
+	return status{code, &Response{Description: description, Content: content}}
+}
+
+// problemStatus é um erro da rota com membros de extensão próprios
+func problemStatus(code, description string, schema *Schema) status {
+	return withContent(code, description, problemContent(schema))
+}
+
+// responses junta as respostas de sucesso (e erros com corpo próprio) às
+// respostas de erro compartilhadas
+func responses(statuses []status, errors ...errorRef) map[string]*Response {
+	result := map[string]*Response{}
+	for _, s := range statuses {
+		result[s.code] = s.response
+	}
+	for _, e := range errors {
+		result[e.status] = &Response{Ref: "#/components/responses/" + e.name}
+	}
+	return result
+}
+
+func bodies(s ...status) []status {
+	return s
+}
+
+func query(name string, schema *Schema) Parameter {
+	return Parameter{Name: name, In: "query", Description: schema.Description, Schema: schema}
+}
+
+func jsonBody(schema *Schema, description string) *RequestBody {
+	return &RequestBody{Description: description, Required: true, Content: jsonContent(schema)}
+}
+
+func paging(perPage int) []Parameter {
+	return []Parameter{
+		query("page", minimum(integer("Página, a partir de 1"), 1)),
+		query("per_page", minimum(integer("Itens por página (padrão "+strconv.Itoa(perPage)+")"), 1)),
+	}
+}
+
+func boolean(description string) *Schema {
+	return &Schema{Type: "string", Enum: []string{"true", "false"}, Description: description}
+}
+
+var hardParam = query("hard", boolean("true apaga definitivamente (apenas administradores)"))
+
+var exportFilters = []Parameter{
+	query("genre_id", str("Só livros do gênero")),
+	query("author", str("Trecho do nome de um autor")),
+	query("q", str("Trecho do nome do livro")),
+	query("min_quantity", integer("Estoque mínimo")),
+	query("max_quantity", integer("Estoque máximo")),
+}
+
+// pathParams descreve os parâmetros de caminho pelo nome usado nas rotas
+var pathParams = map[string]Parameter{
+	"id":         {Description: "Identificador do recurso (KSUID)", Schema: &Schema{Type: "string"}},
+	"isbn":       {Description: "ISBN-10 ou ISBN-13, com ou sem hífens", Schema: &Schema{Type: "string"}},
+	"rev":        {Description: "Número da revisão, a partir de 1", Schema: minimum(&Schema{Type: "integer"}, 1)},
+	"deliveryID": {Description: "Identificador da entrega", Schema: &Schema{Type: "string"}},
+	"type":       {Description: "Último segmento do URI de tipo, como book-not-found", Schema: &Schema{Type: "string"}},
+}
+
+var tags = []Tag{
+	{Name: "books", Description: "Livros, estoque, histórico, importação e exportação"},
+	{Name: "authors", Description: "Autores"},
+	{Name: "genres", Description: "Gêneros e a hierarquia"},
+	{Name: "trash", Description: "Lixeira de livros e gêneros"},
+	{Name: "metadata", Description: "Metadados bibliográficos por ISBN"},
+	{Name: "alerts", Description: "Alertas de estoque baixo"},
+	{Name: "suppliers", Description: "Fornecedores"},
+	{Name: "purchase-orders", Description: "Pedidos de compra e recebimento"},
+	{Name: "stats", Description: "Painel do catálogo"},
+	{Name: "admin", Description: "Auditoria"},
+	{Name: "events", Description: "Feed de alterações"},
+	{Name: "graphql", Description: "Endpoint GraphQL"},
+	{Name: "webhooks", Description: "Assinaturas de webhook e entregas"},
+	{Name: "system", Description: "Saúde e documentação"},
+}
+
+var (
+	books          = []string{"books"}
+	authors        = []string{"authors"}
+	genres         = []string{"genres"}
+	alerts         = []string{"alerts"}
+	suppliers      = []string{"suppliers"}
+	purchaseOrders = []string{"purchase-orders"}
+	webhooks       = []string{"webhooks"}
+	system         = []string{"system"}
+)
+
+// operations documenta cada rota do roteador, pela chave de RouteKey. Uma
+// rota nova sem entrada aqui faz o teste do roteador falhar.
+var operations = map[string]*Operation{
+	// Sistema
+	"GET /health": {
+		Tags: system, OperationID: "health", Summary: "Verifica se o servidor está no ar",
+		Responses: responses(bodies(withContent("200", "OK", map[string]MediaType{"text/plain": {Schema: &Schema{Type: "string"}}}))),
+	},
+	"GET /api/openapi.json": {
+		Tags: system, OperationID: "getOpenAPI", Summary: "Este documento OpenAPI",
+		Responses: responses(bodies(ok("200", "Documento OpenAPI 3", &Schema{Type: "object"}))),
+	},
+	"GET /api/docs": {
+		Tags: system, OperationID: "getDocs", Summary: "Documentação navegável (Swagger UI)",
+		Responses: responses(bodies(withContent("200", "Página HTML", map[string]MediaType{"text/html": {Schema: &Schema{Type: "string"}}}))),
+	},
+	"GET /api/docs/{file}": {
+		Tags: system, OperationID: "getDocsAsset", Summary: "Arquivo da documentação navegável (CSS e JavaScript do Swagger UI)",
+		Responses: responses(bodies(withContent("200", "Arquivo", map[string]MediaType{
+			"text/css":               {Schema: &Schema{Type: "string"}},
+			"text/javascript":        {Schema: &Schema{Type: "string"}},
+			"text/plain":             {Schema: &Schema{Type: "string"}},
+			"application/javascript": {Schema: &Schema{Type: "string"}},
+		})), notFound),
+	},
+	"GET /api/problems": {
+		Tags: system, OperationID: "listProblemTypes", Summary: "Catálogo de códigos de erro",
+		Responses: responses(bodies(ok("200", "Tipos de problema, ordenados pelo código", arrayOf(ref("ProblemType"))))),
+	},
+	"GET /api/problems/{type}": {
+		Tags: system, OperationID: "getProblemType", Summary: "Descreve um tipo de problema",
+		Responses: responses(bodies(ok("200", "Tipo de problema", ref("ProblemType"))), notFound),
+	},
+
+	// Livros
+	"GET /update-quantity": {
+		Tags: books, OperationID: "updateQuantityDirect", Summary: "Atualiza o estoque pelos parâmetros da URL",
+		Description: "Rota legada; prefira POST /api/books/update-quantity.",
+		Parameters: []Parameter{
+			{Name: "id", In: "query", Required: true, Description: "ID do livro", Schema: &Schema{Type: "string"}},
+			{Name: "quantity", In: "query", Required: true, Description: "Novo estoque", Schema: minimum(&Schema{Type: "integer"}, 0)},
+		},
+		Responses: responses(bodies(ok("200", "Estoque atualizado", ref("DirectQuantityUpdateResult"))), badRequest, notFound, internalError),
+	},
+	"GET /api/books": {
+		Tags: books, OperationID: "listBooks", Summary: "Lista os livros",
+		Parameters: append(paging(20),
+			query("sort_field", enum("Campo de ordenação (padrão name)", "name", "quantity")),
+			query("sort_direction", enum("Direção da ordenação (padrão asc)", "asc", "desc"))),
+		Responses: responses(bodies(ok("200", "Página de livros", ref("BookPage"))), internalError),
+	},
+	"POST /api/books": {
+		Tags: books, OperationID: "createBook", Summary: "Cadastra um livro",
+		Parameters:  []Parameter{query("enrich", boolean("true completa os campos vazios com os metadados do ISBN"))},
+		RequestBody: jsonBody(ref("BookInput"), "Livro a cadastrar"),
+		Responses: responses(bodies(
+			ok("201", "Livro criado", ref("Book")),
+			problemStatus("409", "Já existe um livro com o ISBN", ref("ISBNConflictProblem")),
+		), badRequest, internalError),
+	},
+	"POST /api/books/batch": {
+		Tags: books, OperationID: "createBooks", Summary: "Cadastra vários livros",
+		Description: "Um livro fora do esquema ou um ISBN já cadastrado ou repetido recusa o lote inteiro. Livros com gêneros inexistentes são ignorados.",
+		Parameters:  []Parameter{query("enrich", boolean("true completa os campos vazios com os metadados do ISBN"))},
+		RequestBody: jsonBody(arrayOf(ref("BookInput")), "Livros a cadastrar"),
+		Responses: responses(bodies(
+			ok("201", "Livros criados", ref("BatchResult")),
+			problemStatus("409", "ISBNs já cadastrados ou repetidos", ref("BatchConflictProblem")),
+		), badRequest, internalError),
+	},
+	"GET /api/books/export": {
+		Tags: books, OperationID: "exportBooks", Summary: "Exporta o catálogo",
+		Parameters: append([]Parameter{query("format", enum("Formato (padrão csv)", "csv", "jsonl", "xlsx"))}, exportFilters...),
+		Responses: responses(bodies(withContent("200", "Arquivo com os livros", map[string]MediaType{
+			"text/csv":             {Schema: &Schema{Type: "string"}},
+			"application/x-ndjson": {Schema: &Schema{Type: "string"}},
+			"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet": {Schema: &Schema{Type: "string", Format: "binary"}},
+		})), badRequest, internalError),
+	},
+	"GET /api/books/export/marc": {
+		Tags: books, OperationID: "exportMARC", Summary: "Exporta o catálogo em MARC21 ou MARCXML",
+		Parameters: append([]Parameter{query("format", enum("Formato (padrão marc)", "marc", "marcxml"))}, exportFilters...),
+		Responses: responses(bodies(withContent("200", "Registros MARC", map[string]MediaType{
+			"application/marc":        {Schema: &Schema{Type: "string", Format: "binary"}},
+			"application/marcxml+xml": {Schema: &Schema{Type: "string"}},
+		})), badRequest, internalError),
+	},
+	"POST /api/books/import/marc": {
+		Tags: books, OperationID: "importMARC", Summary: "Importa registros MARC21 ou MARCXML",
+		Description: "Cria um livro por registro. Sem format, o formato vem do Content-Type.",
+		Parameters: []Parameter{
+			query("format", enum("Formato dos registros", "marc", "iso2709", "mrc", "marcxml", "xml")),
+			query("quantity", minimum(integer("Estoque de cada livro importado (padrão 1)"), 1)),
+		},
+		RequestBody: &RequestBody{Required: true, Content: map[string]MediaType{
+			"application/marc":        {Schema: &Schema{Type: "string", Format: "binary"}},
+			"application/marcxml+xml": {Schema: &Schema{Type: "string"}},
+		}},
+		Responses: responses(bodies(
+			ok("201", "Livros importados e erros por registro", ref("MARCImportResult")),
+			problemStatus("422", "Nenhum registro importado", ref("MARCImportProblem")),
+		), badRequest, internalError),
+	},
+	"GET /api/books/isbn/{isbn}": {
+		Tags: books, OperationID: "getBookByISBN", Summary: "Busca um livro pelo ISBN",
+		Responses: responses(bodies(ok("200", "Livro", ref("Book"))), badRequest, notFound, internalError),
+	},
+	"GET /api/books/{id}": {
+		Tags: books, OperationID: "getBook", Summary: "Busca um livro pelo ID",
+		Responses: responses(bodies(ok("200", "Livro", ref("Book"))), notFound, internalError),
+	},
+	"PUT /api/books/{id}": {
+		Tags: books, OperationID: "updateBook", Summary: "Atualiza um livro",
+		RequestBody: jsonBody(ref("BookUpdate"), "Novos dados do livro"),
+		Responses: responses(bodies(
+			ok("200", "Livro atualizado; um corpo só com id e quantity recebe o resultado da atualização do estoque",
+				&Schema{AnyOf: []*Schema{ref("Book"), ref("QuantityUpdateResult")}}),
+			problemStatus("409", "Outro livro já tem o ISBN", ref("ISBNConflictProblem")),
+		), badRequest, notFound, internalError),
+	},
+	"DELETE /api/books/{id}": {
+		Tags: books, OperationID: "deleteBook", Summary: "Move o livro para a lixeira",
+		Parameters: []Parameter{hardParam},
+		Responses:  responses(bodies(ok("204", "Livro removido", nil)), badRequest, forbidden, notFound, internalError),
+	},
+	"POST /api/books/{id}/restore": {
+		Tags: books, OperationID: "restoreBook", Summary: "Restaura um livro da lixeira",
+		Responses: responses(bodies(ok("200", "Livro restaurado", ref("Book"))), notFound, conflict, internalError),
+	},
+	"GET /api/books/{id}/history": {
+		Tags: books, OperationID: "getBookHistory", Summary: "Revisões do livro, da mais recente para a mais antiga",
+		Parameters: paging(20),
+		Responses:  responses(bodies(ok("200", "Página de revisões", ref("RevisionPage"))), notFound, internalError),
+	},
+	"GET /api/books/{id}/history/diff": {
+		Tags: books, OperationID: "diffBookRevisions", Summary: "Diferenças entre duas revisões",
+		Parameters: []Parameter{
+			{Name: "from", In: "query", Required: true, Description: "Revisão de origem", Schema: minimum(&Schema{Type: "integer"}, 1)},
+			{Name: "to", In: "query", Required: true, Description: "Revisão de destino", Schema: minimum(&Schema{Type: "integer"}, 1)},
+		},
+		Responses: responses(bodies(ok("200", "Campos alterados", ref("RevisionDiff"))), badRequest, notFound, internalError),
+	},
+	"GET /api/books/{id}/history/{rev}": {
+		Tags: books, OperationID: "getBookRevision", Summary: "Estado do livro em uma revisão",
+		Responses: responses(bodies(ok("200", "Revisão", ref("BookRevision"))), badRequest, notFound, internalError),
+	},
+	"POST /api/books/{id}/revert/{rev}": {
+		Tags: books, OperationID: "revertBook", Summary: "Volta o livro ao estado de uma revisão",
+		Description: "Registra uma nova revisão com a ação revert; um livro na lixeira é restaurado.",
+		Responses:   responses(bodies(ok("200", "Nova revisão", ref("BookRevision"))), badRequest, notFound, conflict, internalError),
+	},
+	"POST /api/books/update-quantity": {
+		Tags: books, OperationID: "updateBookQuantity", Summary: "Atualiza o estoque de um livro",
+		RequestBody: jsonBody(ref("QuantityUpdateRequest"), "Livro e novo estoque"),
+		Responses:   responses(bodies(ok("200", "Estoque atualizado", ref("QuantityUpdateResult"))), badRequest, notFound, internalError),
+	},
+
+	// Autores
+	"GET /api/authors": {
+		Tags: authors, OperationID: "listAuthors", Summary: "Lista os autores",
+		Parameters: append([]Parameter{query("q", str("Trecho do nome"))}, paging(20)...),
+		Responses:  responses(bodies(ok("200", "Página de autores", ref("AuthorPage"))), internalError),
+	},
+	"POST /api/authors": {
+		Tags: authors, OperationID: "createAuthor", Summary: "Cadastra um autor",
+		RequestBody: jsonBody(ref("AuthorInput"), "Autor"),
+		Responses:   responses(bodies(ok("201", "Autor criado", ref("Author"))), badRequest, conflict, internalError),
+	},
+	"GET /api/authors/{id}": {
+		Tags: authors, OperationID: "getAuthor", Summary: "Busca um autor",
+		Responses: responses(bodies(ok("200", "Autor", ref("Author"))), notFound, internalError),
+	},
+	"PUT /api/authors/{id}": {
+		Tags: authors, OperationID: "updateAuthor", Summary: "Renomeia um autor",
+		Description: "O campo author dos livros do autor é atualizado junto.",
+		RequestBody: jsonBody(ref("AuthorInput"), "Novo nome"),
+		Responses:   responses(bodies(ok("200", "Autor atualizado", ref("Author"))), badRequest, notFound, conflict, internalError),
+	},
+	"DELETE /api/authors/{id}": {
+		Tags: authors, OperationID: "deleteAuthor", Summary: "Remove um autor sem livros",
+		Responses: responses(bodies(ok("200", "Autor removido", ref("Message"))), notFound, conflict, internalError),
+	},
+	"GET /api/authors/{id}/books": {
+		Tags: authors, OperationID: "getAuthorBooks", Summary: "Livros do autor, com o papel em cada um",
+		Responses: responses(bodies(ok("200", "Autor e livros", ref("AuthorBooks"))), notFound, internalError),
+	},
+
+	// Gêneros
+	"GET /api/genres": {
+		Tags: genres, OperationID: "listGenres", Summary: "Lista os gêneros",
+		Description: "Com genre_id, devolve o gênero com os livros dele (GenreWithBooks).",
+		Parameters:  []Parameter{query("genre_id", str("Gênero a detalhar"))},
+		Responses: responses(bodies(ok("200", "Gêneros, ou o gênero com os livros quando genre_id é informado",
+			&Schema{AnyOf: []*Schema{arrayOf(ref("Genre")), ref("GenreWithBooks")}})), notFound, internalError),
+	},
+	"POST /api/genres": {
+		Tags: genres, OperationID: "createGenre", Summary: "Cadastra um gênero",
+		RequestBody: jsonBody(ref("GenreInput"), "Gênero"),
+		Responses:   responses(bodies(ok("201", "Gênero criado", ref("Genre"))), badRequest, notFound, conflict, internalError),
+	},
+	"GET /api/genres/tree": {
+		Tags: genres, OperationID: "getGenreTree", Summary: "Gêneros em árvore a partir das raízes",
+		Responses: responses(bodies(ok("200", "Árvore de gêneros", arrayOf(ref("GenreNode")))), internalError),
+	},
+	"GET /api/genres/{id}/books": {
+		Tags: genres, OperationID: "getGenreBooks", Summary: "Livros de um gênero",
+		Parameters: []Parameter{query("include_descendants", boolean("true inclui os livros dos subgêneros"))},
+		Responses:  responses(bodies(ok("200", "Livros", arrayOf(ref("Book")))), badRequest, internalError),
+	},
+	"PUT /api/genres/{id}": {
+		Tags: genres, OperationID: "replaceGenre", Summary: "Substitui os dados de um gênero",
+		RequestBody: jsonBody(ref("GenreInput"), "Gênero"),
+		Responses:   responses(bodies(ok("200", "Gênero atualizado", ref("Genre"))), badRequest, notFound, conflict, internalError),
+	},
+	"PATCH /api/genres/{id}": {
+		Tags: genres, OperationID: "patchGenre", Summary: "Atualiza campos de um gênero",
+		RequestBody: jsonBody(ref("GenrePatch"), "Campos alterados"),
+		Responses:   responses(bodies(ok("200", "Gênero atualizado", ref("Genre"))), badRequest, notFound, conflict, internalError),
+	},
+	"DELETE /api/genres/{id}": {
+		Tags: genres, OperationID: "deleteGenre", Summary: "Move o gênero para a lixeira",
+		Description: "Com livros associados, a remoção é recusada, a menos que reassign_to indique o gênero que os receberá.",
+		Parameters:  []Parameter{query("reassign_to", str("Gênero que recebe os livros")), hardParam},
+		Responses: responses(bodies(
+			ok("200", "Gênero removido", ref("GenreDeleteResult")),
+			problemStatus("409", "O gênero ainda tem livros", ref("GenreInUseProblem")),
+		), badRequest, forbidden, notFound, internalError),
+	},
+	"POST /api/genres/{id}/restore": {
+		Tags: genres, OperationID: "restoreGenre", Summary: "Restaura um gênero da lixeira",
+		Responses: responses(bodies(ok("200", "Gênero restaurado", ref("Genre"))), notFound, internalError),
+	},
+	"POST /api/genres/{id}/merge": {
+		Tags: genres, OperationID: "mergeGenre", Summary: "Mescla o gênero em outro",
+		RequestBody: jsonBody(ref("MergeGenreRequest"), "Gênero de destino"),
+		Responses:   responses(bodies(ok("200", "Gêneros mesclados", ref("GenreMergeResult"))), badRequest, notFound, internalError),
+	},
+
+	// Lixeira e metadados
+	"GET /api/trash": {
+		Tags: []string{"trash"}, OperationID: "listTrash", Summary: "Livros e gêneros na lixeira",
+		Parameters: append([]Parameter{query("type", enum("Tipo do item", models.TrashTypeBook, models.TrashTypeGenre))}, paging(20)...),
+		Responses:  responses(bodies(ok("200", "Página da lixeira", ref("TrashPage"))), badRequest, internalError),
+	},
+	"GET /api/metadata/isbn/{isbn}": {
+		Tags: []string{"metadata"}, OperationID: "getMetadataByISBN", Summary: "Metadados bibliográficos por ISBN",
+		Responses: responses(bodies(ok("200", "Metadados", ref("Metadata"))), badRequest, notFound, internalError, serviceUnavailable),
+	},
+
+	// Alertas
+	"GET /api/alerts/low-stock": {
+		Tags: alerts, OperationID: "listLowStockAlerts", Summary: "Lista os alertas de estoque baixo",
+		Parameters: append([]Parameter{query("status", enum("Situação (padrão open)",
+			models.AlertStatusOpen, models.AlertStatusAcknowledged, models.AlertStatusResolved, models.AlertStatusAll))}, paging(20)...),
+		Responses: responses(bodies(ok("200", "Página de alertas", ref("AlertPage"))), badRequest, internalError),
+	},
+	"POST /api/alerts/low-stock/digest": {
+		Tags: alerts, OperationID: "sendLowStockDigest", Summary: "Envia o resumo de alertas imediatamente",
+		Responses: responses(bodies(ok("200", "Resumo enviado", ref("DigestResult"))), badGateway, serviceUnavailable),
+	},
+	"POST /api/alerts/low-stock/{id}/acknowledge": {
+		Tags: alerts, OperationID: "acknowledgeAlert", Summary: "Reconhece um alerta",
+		Responses: responses(bodies(ok("200", "Alerta reconhecido", ref("Message"))), notFound, internalError),
+	},
+
+	// Fornecedores
+	"GET /api/suppliers": {
+		Tags: suppliers, OperationID: "listSuppliers", Summary: "Lista os fornecedores",
+		Responses: responses(bodies(ok("200", "Fornecedores", arrayOf(ref("Supplier")))), internalError),
+	},
+	"POST /api/suppliers": {
+		Tags: suppliers, OperationID: "createSupplier", Summary: "Cadastra um fornecedor",
+		RequestBody: jsonBody(ref("SupplierInput"), "Fornecedor"),
+		Responses:   responses(bodies(ok("201", "Fornecedor criado", ref("Supplier"))), badRequest, conflict, internalError),
+	},
+	"GET /api/suppliers/{id}": {
+		Tags: suppliers, OperationID: "getSupplier", Summary: "Busca um fornecedor",
+		Responses: responses(bodies(ok("200", "Fornecedor", ref("Supplier"))), notFound, internalError),
+	},
+	"PUT /api/suppliers/{id}": {
+		Tags: suppliers, OperationID: "updateSupplier", Summary: "Altera um fornecedor",
+		RequestBody: jsonBody(ref("SupplierInput"), "Fornecedor"),
+		Responses:   responses(bodies(ok("200", "Fornecedor atualizado", ref("Supplier"))), badRequest, notFound, conflict, internalError),
+	},
+	"DELETE /api/suppliers/{id}": {
+		Tags: suppliers, OperationID: "deleteSupplier", Summary: "Remove um fornecedor sem pedidos",
+		Responses: responses(bodies(ok("200", "Fornecedor removido", ref("Message"))), notFound, conflict, internalError),
+	},
+
+	// Pedidos de compra
+	"GET /api/purchase-orders": {
+		Tags: purchaseOrders, OperationID: "listPurchaseOrders", Summary: "Lista os pedidos de compra",
+		Parameters: append([]Parameter{
+			query("status", enum("Situação", models.OrderStatusDraft, models.OrderStatusSent,
+				models.OrderStatusPartiallyReceived, models.OrderStatusReceived)),
+			query("supplier_id", str("Só pedidos do fornecedor")),
+		}, paging(20)...),
+		Responses: responses(bodies(ok("200", "Página de pedidos", ref("OrderPage"))), badRequest, internalError),
+	},
+	"POST /api/purchase-orders": {
+		Tags: purchaseOrders, OperationID: "createPurchaseOrder", Summary: "Cria um pedido em rascunho",
+		RequestBody: jsonBody(ref("PurchaseOrderInput"), "Pedido"),
+		Responses:   responses(bodies(ok("201", "Pedido criado", ref("PurchaseOrder"))), badRequest, internalError),
+	},
+	"GET /api/purchase-orders/{id}": {
+		Tags: purchaseOrders, OperationID: "getPurchaseOrder", Summary: "Pedido com linhas e recebimentos",
+		Responses: responses(bodies(ok("200", "Pedido", ref("PurchaseOrder"))), notFound, internalError),
+	},
+	"PUT /api/purchase-orders/{id}": {
+		Tags: purchaseOrders, OperationID: "updatePurchaseOrder", Summary: "Altera um pedido em rascunho",
+		RequestBody: jsonBody(ref("PurchaseOrderInput"), "Pedido"),
+		Responses:   responses(bodies(ok("200", "Pedido atualizado", ref("PurchaseOrder"))), badRequest, notFound, conflict, internalError),
+	},
+	"DELETE /api/purchase-orders/{id}": {
+		Tags: purchaseOrders, OperationID: "deletePurchaseOrder", Summary: "Remove um pedido em rascunho",
+		Responses: responses(bodies(ok("200", "Pedido removido", ref("Message"))), notFound, conflict, internalError),
+	},
+	"POST /api/purchase-orders/{id}/send": {
+		Tags: purchaseOrders, OperationID: "sendPurchaseOrder", Summary: "Marca o rascunho como enviado",
+		Responses: responses(bodies(ok("200", "Pedido enviado", ref("PurchaseOrder"))), badRequest, notFound, conflict, internalError),
+	},
+	"POST /api/purchase-orders/{id}/receive": {
+		Tags: purchaseOrders, OperationID: "receivePurchaseOrder", Summary: "Recebe itens e aumenta o estoque",
+		Description: "Sem linhas, todo o saldo pendente do pedido é recebido.",
+		RequestBody: &RequestBody{Content: jsonContent(ref("ReceivePurchaseOrderRequest"))},
+		Responses:   responses(bodies(ok("200", "Pedido com os recebimentos", ref("PurchaseOrder"))), badRequest, notFound, conflict, internalError),
+	},
+
+	// Painel, auditoria e feed
+	"GET /api/stats": {
+		Tags: []string{"stats"}, OperationID: "getStats", Summary: "Totais do catálogo para o painel",
+		Parameters: []Parameter{query("refresh", boolean("true ignora o cache"))},
+		Responses:  responses(bodies(ok("200", "Totais", ref("CatalogStats"))), internalError),
+	},
+	"GET /api/admin/audit": {
+		Tags: []string{"admin"}, OperationID: "getAuditLog", Summary: "Log de auditoria (apenas administradores)",
+		Parameters: append([]Parameter{
+			query("actor", str("Usuário")),
+			query("entity", str("Tipo da entidade, como book ou genre")),
+			query("entity_id", str("")),
+			query("method", str("Método HTTP")),
+			query("outcome", enum("", models.AuditOutcomeSuccess, models.AuditOutcomeFailure)),
+			query("request_id", str("")),
+			query("from", &Schema{Type: "string", Format: "date-time", Description: "Início (RFC 3339)"}),
+			query("to", &Schema{Type: "string", Format: "date-time", Description: "Fim (RFC 3339)"}),
+		}, paging(50)...),
+		Responses: responses(bodies(ok("200", "Página do log", ref("AuditPage"))), badRequest, forbidden, internalError),
+	},
+	"GET /api/events/stream": {
+		Tags: []string{"events"}, OperationID: "streamEvents", Summary: "Feed de alterações (Server-Sent Events)",
+		Description: "Cada evento SSE traz um Event em JSON. Um evento reset indica que o histórico não cobre o Last-Event-ID.",
+		Parameters: []Parameter{
+			query("topics", str("Separados por vírgula: books, genres, stock, book:<id>, genre:<id> e type:<tipo>")),
+			query("last_event_id", str("Alternativa ao cabeçalho Last-Event-ID")),
+			{Name: "Last-Event-ID", In: "header", Description: "Último evento recebido, para a retomada", Schema: &Schema{Type: "string"}},
+		},
+		Responses: responses(bodies(withContent("200", "Fluxo de eventos", map[string]MediaType{"text/event-stream": {Schema: &Schema{Type: "string"}}})), badRequest),
+	},
+
+	// GraphQL
+	"GET /graphql": {
+		Tags: []string{"graphql"}, OperationID: "graphqlQuery", Summary: "Consulta GraphQL pela URL",
+		Parameters: []Parameter{
+			{Name: "query", In: "query", Required: true, Schema: &Schema{Type: "string"}},
+			query("variables", str("Variáveis em JSON")),
+			query("operationName", str("")),
+		},
+		Responses: responses(bodies(ok("200", "Resposta GraphQL", ref("GraphQLResponse")))),
+	},
+	"POST /graphql": {
+		Tags: []string{"graphql"}, OperationID: "graphqlExecute", Summary: "Consultas e mutações GraphQL",
+		RequestBody: jsonBody(ref("GraphQLRequest"), "Operação GraphQL"),
+		Responses:   responses(bodies(ok("200", "Resposta GraphQL", ref("GraphQLResponse"))), badRequest),
+	},
+
+	// Webhooks
+	"GET /api/webhooks": {
+		Tags: webhooks, OperationID: "listWebhooks", Summary: "Lista as assinaturas (apenas administradores)",
+		Parameters: paging(20),
+		Responses:  responses(bodies(ok("200", "Página de assinaturas", ref("WebhookPage"))), forbidden, internalError),
+	},
+	"POST /api/webhooks": {
+		Tags: webhooks, OperationID: "createWebhook", Summary: "Cria uma assinatura",
+		RequestBody: jsonBody(ref("WebhookRequest"), "Assinatura"),
+		Responses:   responses(bodies(ok("201", "Assinatura criada, com o segredo", ref("WebhookSubscription"))), badRequest, forbidden, internalError),
+	},
+	"GET /api/webhooks/{id}": {
+		Tags: webhooks, OperationID: "getWebhook", Summary: "Busca uma assinatura",
+		Responses: responses(bodies(ok("200", "Assinatura", ref("WebhookSubscription"))), forbidden, notFound, internalError),
+	},
+	"PUT /api/webhooks/{id}": {
+		Tags: webhooks, OperationID: "updateWebhook", Summary: "Altera uma assinatura",
+		RequestBody: jsonBody(ref("WebhookRequest"), "Assinatura"),
+		Responses:   responses(bodies(ok("200", "Assinatura atualizada", ref("WebhookSubscription"))), badRequest, forbidden, notFound, internalError),
+	},
+	"DELETE /api/webhooks/{id}": {
+		Tags: webhooks, OperationID: "deleteWebhook", Summary: "Remove a assinatura e as entregas",
+		Responses: responses(bodies(ok("204", "Assinatura removida", nil)), forbidden, notFound, internalError),
+	},
+	"POST /api/webhooks/{id}/ping": {
+		Tags: webhooks, OperationID: "pingWebhook", Summary: "Agenda uma entrega de teste",
+		Responses: responses(bodies(ok("202", "Entrega agendada", ref("WebhookDelivery"))), forbidden, notFound, internalError),
+	},
+	"GET /api/webhooks/{id}/deliveries": {
+		Tags: webhooks, OperationID: "listWebhookDeliveries", Summary: "Registro de entregas da assinatura",
+		Parameters: append([]Parameter{query("status", enum("Situação da entrega",
+			models.WebhookDeliveryPending, models.WebhookDeliverySucceeded, models.WebhookDeliveryFailed))}, paging(20)...),
+		Responses: responses(bodies(ok("200", "Página de entregas", ref("DeliveryPage"))), badRequest, forbidden, notFound, internalError),
+	},
+	"GET /api/webhooks/{id}/deliveries/{deliveryID}": {
+		Tags: webhooks, OperationID: "getWebhookDelivery", Summary: "Entrega com as tentativas",
+		Responses: responses(bodies(ok("200", "Entrega", ref("WebhookDelivery"))), forbidden, notFound, internalError),
+	},
+	"POST /api/webhooks/{id}/deliveries/{deliveryID}/redeliver": {
+		Tags: webhooks, OperationID: "redeliverWebhook", Summary: "Reenvia uma entrega",
+		Responses: responses(bodies(ok("202", "Nova tentativa agendada", ref("WebhookDelivery"))), forbidden, notFound, internalError),
+	},
+}
@@ -0,0 +1,433 @@
+package docs
+
+import (
+	"encoding/json"
+	"projeto_livros/internal/domain/models"
+	"projeto_livros/internal/metadata"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// outputModels são os tipos enviados nas respostas. Os schemas são gerados
+// pelos campos e tags json, então acompanham as mudanças dos modelos.
+var outputModels = []interface{}{
+	models.Book{}, models.BookAuthor{}, models.BookGenre{},
+	models.Genre{}, models.GenreNode{}, models.GenreWithBooks{},
+	models.Author{}, models.AuthorBook{},
+	models.BookRevision{}, models.FieldChange{},
+	models.LowStockAlert{}, models.TrashItem{},
+	models.Supplier{}, models.PurchaseOrder{}, models.PurchaseOrderLine{}, models.StockReceipt{},
+	models.CatalogStats{}, models.CatalogTotals{}, models.GenreStats{},
+	models.AuditEntry{},
+	models.WebhookSubscription{}, models.WebhookDelivery{}, models.WebhookAttempt{},
+	metadata.Metadata{},
+}
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	rawType  = reflect.TypeOf(json.RawMessage{})
+)
+
+// modelSchema gera o schema de uma struct como o encoding/json a serializa:
+// campos sem omitempty são obrigatórios, ponteiros sem omitempty e slices
+// podem vir null e structs anônimas embutidas têm os campos promovidos
+func modelSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	addFields(schema, t)
+	return schema
+}
+
+func addFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			addFields(schema, field.Type)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		omitempty := strings.Contains(opts, "omitempty")
+		prop := typeSchema(field.Type)
+		if (field.Type.Kind() == reflect.Ptr || field.Type.Kind() == reflect.Slice) && !omitempty && field.Type != rawType {
+			prop = nullable(prop)
+		}
+		schema.Properties[name] = prop
+		if !omitempty {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+// typeSchema gera o schema de um tipo; structs nomeadas viram referências
+func typeSchema(t reflect.Type) *Schema {
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == rawType:
+		return &Schema{Description: "JSON livre"}
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return typeSchema(t.Elem())
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int32:
+		return &Schema{Type: "integer"}
+	case reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice:
+		return &Schema{Type: "array", Items: typeSchema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: typeSchema(t.Elem())}
+	case reflect.Struct:
+		return ref(t.Name())
+	}
+	return &Schema{} // interface{}: qualquer valor
+}
+
+// nullable marca o schema como anulável. O OpenAPI 3.0 ignora os campos ao
+// lado de $ref, então as referências vão dentro de anyOf.
+func nullable(s *Schema) *Schema {
+	if s.Ref != "" {
+		return &Schema{AnyOf: []*Schema{s}, Nullable: true}
+	}
+	s.Nullable = true
+	return s
+}
+
+// componentSchemas reúne os schemas dos modelos, das entradas e dos erros
+func componentSchemas() map[string]*Schema {
+	schemas := map[string]*Schema{}
+	for _, model := range outputModels {
+		t := reflect.TypeOf(model)
+		schema := modelSchema(t)
+		schema.Description = modelDescriptions[t.Name()]
+		schemas[t.Name()] = schema
+	}
+	for name, schema := range inputSchemas() {
+		schemas[name] = schema
+	}
+	for name, schema := range responseSchemas() {
+		schemas[name] = schema
+	}
+	return schemas
+}
+
+var modelDescriptions = map[string]string{
+	"Book":                "Livro do catálogo. author e genre_id são derivados de authors e genres.",
+	"BookAuthor":          "Autor do livro com o papel (author, translator ou illustrator)",
+	"BookGenre":           "Gênero do livro; na entrada, basta o id",
+	"Genre":               "Gênero; parent_id é null nos gêneros raiz",
+	"GenreNode":           "Gênero na árvore, com os subgêneros",
+	"GenreWithBooks":      "Gênero com os livros (id, nome e quantidade)",
+	"Author":              "Autor com o total de livros",
+	"AuthorBook":          "Livro de um autor, com o papel dele no livro",
+	"BookRevision":        "Estado completo do livro depois de uma alteração",
+	"FieldChange":         "Diferença de um campo entre duas revisões",
+	"LowStockAlert":       "Alerta de estoque abaixo do mínimo",
+	"TrashItem":           "Livro ou gênero na lixeira",
+	"Supplier":            "Fornecedor",
+	"PurchaseOrder":       "Pedido de compra com as linhas e os recebimentos",
+	"PurchaseOrderLine":   "Linha de um pedido de compra",
+	"StockReceipt":        "Recebimento de uma linha do pedido",
+	"CatalogStats":        "Totais do catálogo para o painel",
+	"CatalogTotals":       "Contagens gerais do catálogo",
+	"GenreStats":          "Títulos e exemplares de um gênero",
+	"AuditEntry":          "Entrada do log de auditoria encadeado por hash",
+	"WebhookSubscription": "Assinatura de webhook; o segredo só vem na criação",
+	"WebhookDelivery":     "Entrega de um evento a uma assinatura",
+	"WebhookAttempt":      "Tentativa de entrega de um webhook",
+	"Metadata":            "Metadados bibliográficos encontrados pelo ISBN",
+}
+
+func ref(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+func arrayOf(items *Schema) *Schema {
+	return &Schema{Type: "array", Items: items}
+}
+
+func str(description string) *Schema {
+	return &Schema{Type: "string", Description: description}
+}
+
+func integer(description string) *Schema {
+	return &Schema{Type: "integer", Description: description}
+}
+
+func enum(description string, values ...string) *Schema {
+	return &Schema{Type: "string", Description: description, Enum: values}
+}
+
+func object(required []string, properties map[string]*Schema) *Schema {
+	return &Schema{Type: "object", Required: required, Properties: properties}
+}
+
+func minimum(s *Schema, min float64) *Schema {
+	s.Minimum = &min
+	return s
+}
+
+func maximum(s *Schema, max float64) *Schema {
+	s.Maximum = &max
+	return s
+}
+
+func maxLength(s *Schema, max int) *Schema {
+	s.MaxLength = &max
+	return s
+}
+
+// page descreve as respostas paginadas: data mais page, per_page, o total
+// (com nome próprio em cada rota) e total_pages
+func page(items *Schema, totalField string) *Schema {
+	return object([]string{"data", "page", "per_page", totalField, "total_pages"}, map[string]*Schema{
+		"data":        nullable(arrayOf(items)),
+		"page":        integer("Página atual, a partir de 1"),
+		"per_page":    integer("Itens por página"),
+		totalField:    integer("Total de itens em todas as páginas"),
+		"total_pages": integer("Total de páginas"),
+	})
+}
+
+func fieldError() *Schema {
+	return object([]string{"field", "location", "message"}, map[string]*Schema{
+		"field":    str("Caminho do campo, como authors[0].role; vazio para o corpo inteiro"),
+		"location": enum("", "path", "query", "body", "response"),
+		"message":  str(""),
+	})
+}
+
+// problemSchema é o corpo problem+json, com os membros de extensão extra
+func problemSchema(extra map[string]*Schema, required ...string) *Schema {
+	s := object(append([]string{"type", "title", "status", "code"}, required...), map[string]*Schema{
+		"type":     str("URI de tipo, como /api/problems/book-not-found"),
+		"title":    str("Resumo do tipo de problema"),
+		"status":   integer("Status HTTP"),
+		"detail":   str("Explicação desta ocorrência"),
+		"instance": str("Caminho da requisição"),
+		"code":     str("Código do catálogo de erros, como BOOK_NOT_FOUND"),
+		"errors":   arrayOf(ref("FieldError")),
+	})
+	for name, schema := range extra {
+		s.Properties[name] = schema
+	}
+	return s
+}
+
+func message() *Schema {
+	return object([]string{"message"}, map[string]*Schema{"message": str("")})
+}
+
+// inputSchemas são os corpos aceitos pelas rotas de escrita. O middleware de
+// validação aplica estas regras antes dos handlers; as verificações dos
+// handlers e do CatalogService seguem os mesmos limites.
+func inputSchemas() map[string]*Schema {
+	bookGenre := object([]string{"id"}, map[string]*Schema{"id": str("ID do gênero")})
+	bookAuthor := object(nil, map[string]*Schema{
+		"id":   str("ID de um autor existente"),
+		"name": maxLength(str("Nome do autor; criado se não existir"), 255),
+		"role": enum("Papel no livro (padrão author)", models.AuthorRoles...),
+	})
+	bookAuthor.AnyOf = []*Schema{{Required: []string{"id"}}, {Required: []string{"name"}}}
+	// Um livro novo precisa ter estoque; depois, o estoque pode chegar a zero
+	book := func(minQuantity float64) *Schema {
+		return object([]string{"quantity"}, map[string]*Schema{
+			"id":               str("Ignorado na criação"),
+			"name":             maxLength(str("Título do livro"), 255),
+			"title":            maxLength(str("Sinônimo de name, aceito por compatibilidade"), 255),
+			"author":           maxLength(str("Nomes dos autores separados por vírgula; use authors para informar papéis"), 255),
+			"quantity":         minimum(integer("Exemplares em estoque"), minQuantity),
+			"genre_id":         nullable(str("Gênero principal; use genres para vários")),
+			"min_quantity":     nullable(minimum(integer("Estoque mínimo para o alerta de reposição"), 0)),
+			"isbn":             str("ISBN-10 ou ISBN-13, com ou sem hífens"),
+			"isbn_10":          str(""),
+			"isbn_13":          str(""),
+			"publisher":        maxLength(str(""), 255),
+			"publication_year": nullable(maximum(minimum(integer(""), 1), 9999)),
+			"edition":          maxLength(str(""), 100),
+			"subjects":         nullable(arrayOf(str(""))),
+			"authors":          nullable(arrayOf(bookAuthor)),
+			"genres":           nullable(arrayOf(bookGenre)),
+		})
+	}
+	bookInput := book(1)
+	bookInput.Description = "Livro a cadastrar; informe name ou title"
+	bookInput.AnyOf = []*Schema{{Required: []string{"name"}}, {Required: []string{"title"}}}
+	bookUpdate := book(0)
+	bookUpdate.Description = "Dados completos do livro. Um corpo só com id e quantity altera apenas o estoque."
+
+	genreInput := object([]string{"name"}, map[string]*Schema{
+		"name":        maxLength(str("Nome do gênero"), 100),
+		"description": str(""),
+		"parent_id":   nullable(str("Gênero pai; null para gênero raiz")),
+	})
+	genrePatch := object(nil, map[string]*Schema{
+		"name":        maxLength(str(""), 100),
+		"description": str(""),
+		"parent_id":   nullable(str("Novo gênero pai; null ou vazio torna o gênero raiz")),
+	})
+	genrePatch.Description = "No PATCH, os campos omitidos mantêm o valor atual"
+
+	orderLine := object([]string{"book_id", "quantity"}, map[string]*Schema{
+		"book_id":   str(""),
+		"quantity":  minimum(integer(""), 1),
+		"unit_cost": minimum(&Schema{Type: "number"}, 0),
+	})
+
+	return map[string]*Schema{
+		"BookInput":  bookInput,
+		"BookUpdate": bookUpdate,
+		"QuantityUpdateRequest": object([]string{"id", "quantity"}, map[string]*Schema{
+			"id":       str("ID do livro"),
+			"quantity": minimum(integer("Novo estoque"), 0),
+		}),
+		"GenreInput":        genreInput,
+		"GenrePatch":        genrePatch,
+		"MergeGenreRequest": object([]string{"target_id"}, map[string]*Schema{"target_id": str("Gênero que recebe os livros")}),
+		"AuthorInput": object([]string{"name"}, map[string]*Schema{
+			"name": maxLength(str("Nome do autor"), 255),
+		}),
+		"SupplierInput": object([]string{"name"}, map[string]*Schema{
+			"name":  str(""),
+			"email": str(""),
+			"phone": str(""),
+			"notes": str(""),
+		}),
+		"PurchaseOrderInput": object([]string{"supplier_id"}, map[string]*Schema{
+			"supplier_id": str(""),
+			"notes":       str(""),
+			"lines":       arrayOf(orderLine),
+		}),
+		"ReceivePurchaseOrderRequest": object(nil, map[string]*Schema{
+			"lines": arrayOf(object([]string{"line_id", "quantity"}, map[string]*Schema{
+				"line_id":  str(""),
+				"quantity": minimum(integer(""), 1),
+			})),
+		}),
+		"WebhookRequest": object([]string{"url"}, map[string]*Schema{
+			"url":         &Schema{Type: "string", Format: "uri", Description: "URL http ou https"},
+			"description": str(""),
+			"events":      arrayOf(enum("", models.EventTypes...)),
+			"active":      &Schema{Type: "boolean", Description: "Padrão true; true reativa e zera as falhas"},
+			"secret":      str("Vazio gera (na criação) ou mantém (na atualização) o segredo"),
+		}),
+	}
+}
+
+// responseSchemas são as respostas montadas nos próprios handlers e os erros
+func responseSchemas() map[string]*Schema {
+	return map[string]*Schema{
+		"BookPage":     page(ref("Book"), "total_books"),
+		"RevisionPage": page(ref("BookRevision"), "total_revisions"),
+		"AuthorPage":   page(ref("Author"), "total_authors"),
+		"AlertPage":    page(ref("LowStockAlert"), "total_alerts"),
+		"OrderPage":    page(ref("PurchaseOrder"), "total_orders"),
+		"TrashPage":    page(ref("TrashItem"), "total_items"),
+		"AuditPage":    page(ref("AuditEntry"), "total_items"),
+		"WebhookPage":  page(ref("WebhookSubscription"), "total_items"),
+		"DeliveryPage": page(ref("WebhookDelivery"), "total_items"),
+		"BatchResult": object([]string{"message", "books"}, map[string]*Schema{
+			"message": str(""),
+			"books":   arrayOf(ref("Book")),
+		}),
+		"MARCImportResult": object([]string{"message", "books", "errors"}, map[string]*Schema{
+			"message": str(""),
+			"books":   arrayOf(ref("Book")),
+			"errors":  arrayOf(ref("MARCRecordError")),
+		}),
+		"MARCRecordError": object([]string{"record", "error"}, map[string]*Schema{
+			"record":      integer("Posição do registro no arquivo, a partir de 1"),
+			"error":       str(""),
+			"existing_id": str("Livro já cadastrado com o mesmo ISBN"),
+		}),
+		"QuantityUpdateResult": object([]string{"id", "requested_quantity", "final_quantity", "success"}, map[string]*Schema{
+			"id":                 str(""),
+			"requested_quantity": integer(""),
+			"final_quantity":     integer(""),
+			"original_quantity":  integer("Ausente no PUT /api/books/{id}"),
+			"success":            &Schema{Type: "boolean"},
+			"message":            str(""),
+		}),
+		"DirectQuantityUpdateResult": object([]string{"id", "quantity", "requested_quantity", "original_quantity", "success", "message"}, map[string]*Schema{
+			"id":                 str(""),
+			"quantity":           integer(""),
+			"requested_quantity": integer(""),
+			"original_quantity":  integer(""),
+			"success":            &Schema{Type: "boolean"},
+			"message":            str(""),
+		}),
+		"RevisionDiff": object([]string{"book_id", "from", "to", "changes"}, map[string]*Schema{
+			"book_id": str(""),
+			"from":    integer(""),
+			"to":      integer(""),
+			"changes": nullable(arrayOf(ref("FieldChange"))),
+		}),
+		"AuthorBooks": object([]string{"author", "books"}, map[string]*Schema{
+			"author": ref("Author"),
+			"books":  arrayOf(ref("AuthorBook")),
+		}),
+		"GenreDeleteResult": object([]string{"message", "moved_books"}, map[string]*Schema{
+			"message":     str(""),
+			"moved_books": integer("Livros transferidos para reassign_to"),
+		}),
+		"GenreMergeResult": object([]string{"message", "genre", "moved_books"}, map[string]*Schema{
+			"message":     str(""),
+			"genre":       ref("Genre"),
+			"moved_books": integer(""),
+		}),
+		"DigestResult": object([]string{"message", "open_alerts"}, map[string]*Schema{
+			"message":     str(""),
+			"open_alerts": integer(""),
+		}),
+		"Message": message(),
+		"GraphQLRequest": object([]string{"query"}, map[string]*Schema{
+			"query":         str(""),
+			"variables":     &Schema{Type: "object", Nullable: true},
+			"operationName": str(""),
+		}),
+		"GraphQLResponse": object(nil, map[string]*Schema{
+			"data":   &Schema{Type: "object", Nullable: true},
+			"errors": arrayOf(object([]string{"message"}, map[string]*Schema{"message": str("")})),
+		}),
+
+		// Modelo de erro (application/problem+json, RFC 7807)
+		"Problem":    problemSchema(nil),
+		"FieldError": fieldError(),
+		"ISBNConflictProblem": problemSchema(map[string]*Schema{
+			"existing_id": str("Livro já cadastrado com o mesmo ISBN"),
+		}, "existing_id"),
+		"BatchConflictProblem": problemSchema(map[string]*Schema{
+			"duplicates": arrayOf(object([]string{"index", "isbn"}, map[string]*Schema{
+				"index":       integer("Posição do livro no lote"),
+				"isbn":        str("ISBN-13 normalizado"),
+				"existing_id": str("Vazio quando o ISBN se repete dentro do lote"),
+			})),
+		}, "duplicates"),
+		"MARCImportProblem": problemSchema(map[string]*Schema{
+			"records": arrayOf(ref("MARCRecordError")),
+		}, "records"),
+		"GenreInUseProblem": problemSchema(map[string]*Schema{
+			"total_books": integer("Livros ainda associados ao gênero"),
+		}, "total_books"),
+		"ProblemType": object([]string{"type", "code", "status", "title", "message"}, map[string]*Schema{
+			"type":    str("URI de tipo"),
+			"code":    str("Código do catálogo, como BOOK_NOT_FOUND"),
+			"status":  integer("Status HTTP"),
+			"title":   str("Resumo do problema"),
+			"message": str("Modelo do detail; %s e %d recebem os valores da ocorrência"),
+		}),
+	}
+}
@@ -0,0 +1,88 @@
+// Visualização simples da especificação OpenAPI, usada quando o
+// swagger-ui-dist não foi embutido: lista as operações por tag, com os
+// parâmetros, o corpo e as respostas de cada uma.
+function renderSpec(url, root) {
+  function el(tag, className, text) {
+    var node = document.createElement(tag);
+    if (className) node.className = className;
+    if (text !== undefined) node.textContent = text;
+    return node;
+  }
+
+  function schemaName(schema) {
+    if (!schema) return "";
+    if (schema.$ref) return schema.$ref.split("/").pop();
+    if (schema.type === "array") return schemaName(schema.items) + "[]";
+    return schema.type || "object";
+  }
+
+  function contentSchemas(content) {
+    return Object.keys(content || {}).map(function (type) {
+      return type + ": " + schemaName(content[type].schema);
+    }).join(", ");
+  }
+
+  function operation(method, path, op) {
+    var details = el("details", "op op-" + method);
+    var summary = el("summary");
+    summary.appendChild(el("span", "method", method.toUpperCase()));
+    summary.appendChild(el("code", "path", path));
+    summary.appendChild(el("span", "summary", op.summary || ""));
+    details.appendChild(summary);
+    if (op.description) details.appendChild(el("p", "", op.description));
+
+    var params = op.parameters || [];
+    if (params.length > 0) {
+      details.appendChild(el("h4", "", "Parâmetros"));
+      var list = el("ul");
+      params.forEach(function (p) {
+        var text = p.name + " (" + p.in + (p.required ? ", obrigatório" : "") + "): " + schemaName(p.schema);
+        if (p.description) text += " - " + p.description;
+        list.appendChild(el("li", "", text));
+      });
+      details.appendChild(list);
+    }
+    if (op.requestBody) {
+      details.appendChild(el("h4", "", "Corpo"));
+      details.appendChild(el("p", "", contentSchemas(op.requestBody.content)));
+    }
+    details.appendChild(el("h4", "", "Respostas"));
+    var responses = el("ul");
+    Object.keys(op.responses || {}).sort().forEach(function (status) {
+      var response = op.responses[status];
+      var text = status + " " + (response.$ref ? response.$ref.split("/").pop() : response.description || "");
+      var schemas = contentSchemas(response.content);
+      responses.appendChild(el("li", "", schemas ? text + " (" + schemas + ")" : text));
+    });
+    details.appendChild(responses);
+    return details;
+  }
+
+  var css = el("link");
+  css.rel = "stylesheet";
+  css.href = "/api/docs/viewer.css";
+  document.head.appendChild(css);
+
+  fetch(url).then(function (resp) { return resp.json(); }).then(function (doc) {
+    root.appendChild(el("h1", "", doc.info.title + " " + doc.info.version));
+    if (doc.info.description) root.appendChild(el("p", "", doc.info.description));
+    var link = el("a", "", "Especificação em JSON");
+    link.href = url;
+    root.appendChild(link);
+
+    var byTag = {};
+    Object.keys(doc.paths).sort().forEach(function (path) {
+      Object.keys(doc.paths[path]).forEach(function (method) {
+        var op = doc.paths[path][method];
+        var tag = (op.tags && op.tags[0]) || "outros";
+        (byTag[tag] = byTag[tag] || []).push(operation(method, path, op));
+      });
+    });
+    Object.keys(byTag).sort().forEach(function (tag) {
+      root.appendChild(el("h2", "", tag));
+      byTag[tag].forEach(function (node) { root.appendChild(node); });
+    });
+  }).catch(function (err) {
+    root.appendChild(el("p", "", "Erro ao carregar " + url + ": " + err));
+  });
+}
@@ -1,12 +1,13 @@
 package docs
 
 // Swagger documentation for the Book API
-// This file contains Swagger annotations that describe the API endpoints
+// O documento OpenAPI 3 completo é gerado a partir do roteador (openapi.go)
+// e servido em /api/openapi.json, com o Swagger UI embutido em /api/docs.
 
 // @title Book Management API
 // @version 1.0
 // @description API for managing books
-// @host localhost:3000
+// @host localhost:3001
 // @BasePath /
 
 // @tag.name books
@@ -0,0 +1,46 @@
+package docs
+
+import (
+	"embed"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"projeto_livros/internal/delivery/problem"
+	apperrors "projeto_livros/internal/domain/errors"
+
+	"github.com/go-chi/chi/v5"
+)
+
+//go:generate go run fetch_swagger_ui.go
+
+// swaggerUI guarda a página de /api/docs e os arquivos do swagger-ui-dist
+// baixados por go generate, para que a documentação funcione sem internet.
+// Sem o swagger-ui-dist, a página usa a visualização simples de viewer.js.
+//
+//go:embed swagger-ui
+var swaggerUI embed.FS
+
+// UIHandler serve a página do Swagger UI, que carrega /api/openapi.json
+func UIHandler(w http.ResponseWriter, r *http.Request) {
+	serveUIFile(w, r, "index.html")
+}
+
+// UIAssetHandler serve os arquivos da página (GET /api/docs/{file})
+func UIAssetHandler(w http.ResponseWriter, r *http.Request) {
+	serveUIFile(w, r, chi.URLParam(r, "file"))
+}
+
+func serveUIFile(w http.ResponseWriter, r *http.Request, name string) {
+	data, err := fs.ReadFile(swaggerUI, path.Join("swagger-ui", path.Clean("/"+name)))
+	if err != nil {
+		problem.Send(w, r, apperrors.CodeNotFound)
+		return
+	}
+	contentType := mime.TypeByExtension(path.Ext(name))
+	if contentType == "" {
+		contentType = "text/plain; charset=utf-8"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Write(data)
+}
@@ -0,0 +1,218 @@
+package docs
+
+import (
+	"encoding/json"
+	"fmt"
+	apperrors "projeto_livros/internal/domain/errors"
+	"projeto_livros/internal/i18n"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// FieldError é uma violação da especificação em um campo da requisição ou
+// da resposta; é o mesmo tipo do errors[] das respostas problem+json
+type FieldError = apperrors.FieldError
+
+// Resolve segue a referência $ref de um schema até os componentes
+func (d *Document) Resolve(s *Schema) *Schema {
+	for s != nil && s.Ref != "" {
+		s = d.Components.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+	}
+	return s
+}
+
+// Validate confere value com o schema e devolve todas as violações. value é
+// o JSON decodificado com UseNumber; field é o caminho até ele.
+func (d *Document) Validate(s *Schema, value interface{}, field, location string) []FieldError {
+	v := validation{doc: d, location: location}
+	v.check(s, value, field)
+	return v.errors
+}
+
+type validation struct {
+	doc      *Document
+	location string
+	errors   []FieldError
+}
+
+// fail registra a violação com a mensagem key do catálogo de i18n
+func (v *validation) fail(field, key string, args ...interface{}) {
+	v.errors = append(v.errors, apperrors.NewFieldError(field, v.location, key, args...))
+}
+
+func (v *validation) check(s *Schema, value interface{}, field string) {
+	s = v.doc.Resolve(s)
+	if s == nil {
+		return
+	}
+	if value == nil {
+		if !s.Nullable && (s.Type != "" || len(s.AnyOf) > 0) {
+			v.fail(field, "validation.not_null")
+		}
+		return
+	}
+	// Um objeto que falha nas alternativas ainda tem os campos conferidos,
+	// para que todas as violações sejam informadas
+	if len(s.AnyOf) > 0 && !v.checkAnyOf(s, value, field) && s.Type == "" {
+		return
+	}
+
+	switch s.Type {
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			v.fail(field, "validation.type."+s.Type)
+			return
+		}
+		v.checkString(s, text, field)
+	case "integer", "number":
+		number, ok := value.(json.Number)
+		if !ok {
+			v.fail(field, "validation.type."+s.Type)
+			return
+		}
+		n, err := number.Float64()
+		if _, intErr := number.Int64(); err != nil || (s.Type == "integer" && intErr != nil) {
+			v.fail(field, "validation.type."+s.Type)
+			return
+		}
+		if s.Minimum != nil && n < *s.Minimum {
+			v.fail(field, "validation.minimum", formatNumber(*s.Minimum))
+		}
+		if s.Maximum != nil && n > *s.Maximum {
+			v.fail(field, "validation.maximum", formatNumber(*s.Maximum))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			v.fail(field, "validation.type."+s.Type)
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			v.fail(field, "validation.type."+s.Type)
+			return
+		}
+		if s.MinItems != nil && len(items) < *s.MinItems {
+			v.fail(field, "validation.min_items", *s.MinItems)
+		}
+		for i, item := range items {
+			v.check(s.Items, item, fmt.Sprintf("%s[%d]", field, i))
+		}
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			v.fail(field, "validation.type."+s.Type)
+			return
+		}
+		v.checkObject(s, object, field)
+	case "":
+		// Sem tipo: as alternativas de anyOf que só exigem campos
+		if object, ok := value.(map[string]interface{}); ok {
+			v.checkObject(s, object, field)
+		}
+	}
+}
+
+// checkAnyOf aceita o valor se ele satisfizer uma das alternativas. As
+// alternativas que só exigem campos (name ou title) geram uma mensagem única.
+func (v *validation) checkAnyOf(s *Schema, value interface{}, field string) bool {
+	var firstErrors []FieldError
+	var alternatives []string
+	for _, alternative := range s.AnyOf {
+		errors := v.doc.Validate(alternative, value, field, v.location)
+		if len(errors) == 0 {
+			return true
+		}
+		if firstErrors == nil {
+			firstErrors = errors
+		}
+		if alternative.Ref == "" && alternative.Type == "" && len(alternative.Required) == 1 {
+			alternatives = append(alternatives, alternative.Required[0])
+		}
+	}
+	if len(alternatives) == len(s.AnyOf) {
+		v.fail(field, "validation.any_of", i18n.Either(alternatives))
+		return false
+	}
+	v.errors = append(v.errors, firstErrors...)
+	return false
+}
+
+func (v *validation) checkString(s *Schema, text, field string) {
+	if len(s.Enum) > 0 {
+		valid := false
+		for _, allowed := range s.Enum {
+			valid = valid || text == allowed
+		}
+		if !valid {
+			v.fail(field, "validation.enum", strings.Join(s.Enum, ", "))
+			return
+		}
+	}
+	length := utf8.RuneCountInString(text)
+	if s.MinLength != nil && length < *s.MinLength {
+		v.fail(field, "validation.min_length", *s.MinLength)
+	}
+	if s.MaxLength != nil && length > *s.MaxLength {
+		v.fail(field, "validation.max_length", *s.MaxLength)
+	}
+	if s.Pattern != "" {
+		if re, err := regexp.Compile(s.Pattern); err == nil && !re.MatchString(text) {
+			v.fail(field, "validation.pattern")
+		}
+	}
+	if s.Format == "date-time" {
+		if _, err := time.Parse(time.RFC3339, text); err != nil {
+			v.fail(field, "validation.date_time")
+		}
+	}
+}
+
+func (v *validation) checkObject(s *Schema, object map[string]interface{}, field string) {
+	for _, name := range s.Required {
+		if _, ok := object[name]; !ok {
+			v.fail(joinField(field, name), "validation.required")
+		}
+	}
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if prop, ok := s.Properties[name]; ok {
+			v.check(prop, object[name], joinField(field, name))
+		} else if s.AdditionalProperties != nil {
+			v.check(s.AdditionalProperties, object[name], joinField(field, name))
+		}
+	}
+}
+
+func joinField(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// ParseParameter converte o texto de um parâmetro de caminho ou de consulta
+// para o tipo do schema, para que Validate confira as regras dele
+func (d *Document) ParseParameter(s *Schema, text string) interface{} {
+	switch d.Resolve(s).Type {
+	case "integer", "number":
+		return json.Number(text)
+	case "boolean":
+		if b, err := strconv.ParseBool(text); err == nil {
+			return b
+		}
+	}
+	return text
+}
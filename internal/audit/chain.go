@@ -0,0 +1,172 @@
+// Package audit calcula e verifica a cadeia de hashes do log de auditoria.
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"projeto_livros/internal/domain/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GenesisHash é o PrevHash da primeira entrada do log
+const GenesisHash = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// hashPayload fixa a ordem e o formato dos campos que entram no hash
+type hashPayload struct {
+	Seq        int64           `json:"seq"`
+	CreatedAt  string          `json:"created_at"`
+	Actor      string          `json:"actor"`
+	Method     string          `json:"method"`
+	Route      string          `json:"route"`
+	Path       string          `json:"path"`
+	Entity     string          `json:"entity"`
+	EntityID   string          `json:"entity_id"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	ClientIP   string          `json:"client_ip"`
+	RequestID  string          `json:"request_id"`
+	StatusCode int             `json:"status_code"`
+	Outcome    string          `json:"outcome"`
+	PrevHash   string          `json:"prev_hash"`
+}
+
+// Normalize ajusta CreatedAt à precisão do banco (microssegundos, UTC), para
+// que o hash calculado antes da gravação seja o mesmo da entrada lida depois
+func Normalize(entry *models.AuditEntry) {
+	entry.CreatedAt = entry.CreatedAt.UTC().Truncate(time.Microsecond)
+}
+
+// Hash calcula o SHA-256 da entrada, que inclui o hash da entrada anterior
+func Hash(entry *models.AuditEntry) (string, error) {
+	payload, err := json.Marshal(hashPayload{
+		Seq:        entry.Seq,
+		CreatedAt:  entry.CreatedAt.UTC().Format(time.RFC3339Nano),
+		Actor:      entry.Actor,
+		Method:     entry.Method,
+		Route:      entry.Route,
+		Path:       entry.Path,
+		Entity:     entry.Entity,
+		EntityID:   entry.EntityID,
+		Before:     entry.Before,
+		After:      entry.After,
+		ClientIP:   entry.ClientIP,
+		RequestID:  entry.RequestID,
+		StatusCode: entry.StatusCode,
+		Outcome:    entry.Outcome,
+		PrevHash:   entry.PrevHash,
+	})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Chain encadeia a entrada à anterior (seq e hash da última entrada
+// gravada; 0 e "" quando o log está vazio) e calcula o seu hash
+func Chain(entry *models.AuditEntry, lastSeq int64, lastHash string) error {
+	Normalize(entry)
+	entry.Seq = lastSeq + 1
+	entry.PrevHash = lastHash
+	if lastSeq == 0 {
+		entry.PrevHash = GenesisHash
+	}
+	hash, err := Hash(entry)
+	if err != nil {
+		return err
+	}
+	entry.Hash = hash
+	return nil
+}
+
+// Problem é uma inconsistência encontrada na verificação
+type Problem struct {
+	Seq     int64
+	Message string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("seq %d: %s", p.Seq, p.Message)
+}
+
+// Checkpoint identifica uma entrada do log pelo seq e pelo hash. Guardado fora
+// do banco, permite detectar a remoção de entradas do fim do log, que a cadeia
+// sozinha não acusa.
+type Checkpoint struct {
+	Seq  int64
+	Hash string
+}
+
+// ParseCheckpoint lê um ponto de controle no formato seq:hash
+func ParseCheckpoint(s string) (Checkpoint, error) {
+	seqText, hash, ok := strings.Cut(strings.TrimSpace(s), ":")
+	seq, err := strconv.ParseInt(seqText, 10, 64)
+	if !ok || err != nil || seq <= 0 || len(hash) != len(GenesisHash) {
+		return Checkpoint{}, fmt.Errorf("ponto de controle inválido %q: use seq:hash", s)
+	}
+	return Checkpoint{Seq: seq, Hash: hash}, nil
+}
+
+func (c Checkpoint) String() string {
+	return fmt.Sprintf("%d:%s", c.Seq, c.Hash)
+}
+
+// Verifier confere as entradas do log, que devem ser adicionadas em ordem de
+// seq. Detecta lacunas na numeração, entradas alteradas (hash diferente do
+// recalculado) e quebras na cadeia (PrevHash diferente do hash anterior).
+// Com Expect, Finish acusa também a falta do ponto de controle esperado.
+type Verifier struct {
+	Problems []Problem
+	Count    int64
+	LastSeq  int64
+	LastHash string
+	Expect   *Checkpoint
+
+	expectFound bool
+}
+
+// Add verifica a próxima entrada do log
+func (v *Verifier) Add(entry *models.AuditEntry) {
+	expectedPrev := v.LastHash
+	if v.Count == 0 {
+		expectedPrev = GenesisHash
+	}
+	if entry.Seq != v.LastSeq+1 {
+		v.problem(entry.Seq, fmt.Sprintf("lacuna na numeração: esperava seq %d", v.LastSeq+1))
+	}
+	if entry.PrevHash != expectedPrev {
+		v.problem(entry.Seq, "prev_hash não corresponde ao hash da entrada anterior")
+	}
+	if hash, err := Hash(entry); err != nil {
+		v.problem(entry.Seq, "erro ao calcular hash: "+err.Error())
+	} else if hash != entry.Hash {
+		v.problem(entry.Seq, "hash não confere: a entrada foi alterada")
+	}
+	if v.Expect != nil && entry.Seq == v.Expect.Seq && entry.Hash == v.Expect.Hash {
+		v.expectFound = true
+	}
+	v.Count++
+	v.LastSeq = entry.Seq
+	v.LastHash = entry.Hash
+}
+
+// Finish encerra a verificação depois da última entrada: o ponto de controle
+// esperado precisa ter aparecido, senão o log foi truncado ou reescrito
+func (v *Verifier) Finish() {
+	if v.Expect != nil && !v.expectFound {
+		v.problem(v.Expect.Seq, "ponto de controle ausente ou com hash diferente: entradas removidas do fim do log?")
+	}
+}
+
+// Head devolve o ponto de controle da última entrada verificada
+func (v *Verifier) Head() Checkpoint {
+	return Checkpoint{Seq: v.LastSeq, Hash: v.LastHash}
+}
+
+func (v *Verifier) problem(seq int64, message string) {
+	v.Problems = append(v.Problems, Problem{Seq: seq, Message: message})
+}
@@ -0,0 +1,126 @@
+package audit
+
+import (
+	"encoding/json"
+	"projeto_livros/internal/domain/models"
+	"strings"
+	"testing"
+	"time"
+)
+
+func buildChain(t *testing.T, n int) []*models.AuditEntry {
+	t.Helper()
+	var entries []*models.AuditEntry
+	var lastSeq int64
+	var lastHash string
+	for i := 0; i < n; i++ {
+		entry := &models.AuditEntry{
+			CreatedAt:  time.Date(2024, 5, 1, 10, i, 0, 123456789, time.UTC),
+			Actor:      "user-1",
+			Method:     "PUT",
+			Route:      "/api/books/{id}",
+			Path:       "/api/books/abc",
+			Entity:     "books",
+			EntityID:   "abc",
+			Before:     json.RawMessage(`{"quantity":1}`),
+			After:      json.RawMessage(`{"quantity":2}`),
+			ClientIP:   "127.0.0.1",
+			StatusCode: 200,
+			Outcome:    models.AuditOutcomeSuccess,
+		}
+		if err := Chain(entry, lastSeq, lastHash); err != nil {
+			t.Fatalf("Erro ao encadear: %v", err)
+		}
+		lastSeq, lastHash = entry.Seq, entry.Hash
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func verify(entries []*models.AuditEntry) *Verifier {
+	v := &Verifier{}
+	for _, e := range entries {
+		v.Add(e)
+	}
+	return v
+}
+
+func TestChainVerifies(t *testing.T) {
+	entries := buildChain(t, 3)
+	if entries[0].PrevHash != GenesisHash || entries[1].PrevHash != entries[0].Hash {
+		t.Fatalf("cadeia mal formada: %+v", entries)
+	}
+	if entries[0].CreatedAt.Nanosecond() != 123456000 {
+		t.Errorf("created_at não foi truncado para microssegundos: %v", entries[0].CreatedAt)
+	}
+	v := verify(entries)
+	if len(v.Problems) != 0 {
+		t.Fatalf("problemas inesperados: %v", v.Problems)
+	}
+	if v.Count != 3 || v.LastSeq != 3 || v.LastHash != entries[2].Hash {
+		t.Errorf("verificador = %+v", v)
+	}
+}
+
+func TestVerifierDetectsTampering(t *testing.T) {
+	entries := buildChain(t, 3)
+	entries[1].After = json.RawMessage(`{"quantity":200}`)
+
+	v := verify(entries)
+	if len(v.Problems) != 1 || v.Problems[0].Seq != 2 || !strings.Contains(v.Problems[0].Message, "alterada") {
+		t.Fatalf("problemas = %v", v.Problems)
+	}
+}
+
+func TestVerifierDetectsGap(t *testing.T) {
+	entries := buildChain(t, 3)
+	v := verify([]*models.AuditEntry{entries[0], entries[2]})
+
+	if len(v.Problems) != 2 {
+		t.Fatalf("problemas = %v", v.Problems)
+	}
+	if !strings.Contains(v.Problems[0].Message, "lacuna") || !strings.Contains(v.Problems[1].Message, "prev_hash") {
+		t.Errorf("problemas = %v", v.Problems)
+	}
+}
+
+func TestVerifierDetectsRehashedEntry(t *testing.T) {
+	// Reescrever uma entrada e recalcular o seu hash quebra o elo com a seguinte
+	entries := buildChain(t, 3)
+	entries[1].Actor = "outro"
+	entries[1].Hash, _ = Hash(entries[1])
+
+	v := verify(entries)
+	if len(v.Problems) != 1 || v.Problems[0].Seq != 3 {
+		t.Fatalf("problemas = %v", v.Problems)
+	}
+}
+
+func TestVerifierDetectsTruncatedTail(t *testing.T) {
+	entries := buildChain(t, 3)
+	head, err := ParseCheckpoint(verify(entries).Head().String())
+	if err != nil {
+		t.Fatalf("erro ao ler o ponto de controle: %v", err)
+	}
+
+	v := &Verifier{Expect: &head}
+	for _, e := range entries {
+		v.Add(e)
+	}
+	v.Finish()
+	if len(v.Problems) != 0 {
+		t.Fatalf("problemas inesperados: %v", v.Problems)
+	}
+
+	// Sem as duas últimas entradas, a cadeia restante continua íntegra
+	v = &Verifier{Expect: &head}
+	v.Add(entries[0])
+	v.Finish()
+	if len(v.Problems) != 1 || v.Problems[0].Seq != 3 {
+		t.Fatalf("problemas = %v", v.Problems)
+	}
+
+	if _, err := ParseCheckpoint("3"); err == nil {
+		t.Error("esperava erro para ponto de controle sem hash")
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -14,6 +17,59 @@ type Config struct {
 	DBUser     string
 	DBPassword string
 	DBName     string
+
+	// Caminho do dump local (Open Library ou CSV) usado para preencher metadados por ISBN
+	MetadataDumpPath string
+
+	// Tempo durante o qual /api/stats reaproveita o último resultado calculado
+	StatsCacheTTL time.Duration
+	// Quantidade máxima (inclusive) para um livro sem min_quantity ser considerado com estoque baixo
+	LowStockThreshold int
+
+	// Resumo diário de alertas de estoque baixo
+	AlertNotifier   string // log, webhook ou smtp
+	AlertDigestTime string // Horário do envio, HH:MM
+	AlertWebhookURL string
+	SMTPAddr        string
+	SMTPUser        string
+	SMTPPassword    string
+	SMTPFrom        string
+	AlertEmailTo    []string
+
+	// Lixeira: tempo até um item removido ser apagado definitivamente e
+	// intervalo entre as execuções da limpeza
+	TrashRetention     time.Duration
+	TrashPurgeInterval time.Duration
+
+	// Entrega dos eventos de domínio gravados no outbox
+	EventsSink         string // stdout, file, webhook ou nats
+	EventsFilePath     string
+	EventsWebhookURL   string
+	EventsNATSURL      string
+	EventsNATSSubject  string
+	EventsPollInterval time.Duration
+	EventsRetention    time.Duration // Tempo que os eventos entregues ficam no outbox
+
+	// Feed de alterações via Server-Sent Events
+	StreamReplaySize int           // Eventos mantidos para retomar pelo Last-Event-ID
+	StreamHeartbeat  time.Duration // Intervalo dos comentários que mantêm a conexão
+
+	// Endereço do servidor gRPC (LibraryService), separado do HTTP
+	GRPCAddr string
+
+	// Limites das consultas GraphQL
+	GraphQLMaxDepth      int // Níveis de campos aninhados
+	GraphQLMaxComplexity int // Custo estimado, contando os itens das listas
+
+	// Webhooks de saída
+	WebhookPollInterval time.Duration
+	WebhookMaxAttempts  int // Tentativas de cada entrega antes de desistir
+	WebhookDisableAfter int // Falhas consecutivas que desativam a assinatura
+
+	// Confere também as respostas com a especificação OpenAPI (para testes)
+	ValidateResponses bool
+	// Tamanho máximo, em bytes, dos corpos JSON conferidos com a especificação
+	MaxBodyBytes int64
 }
 
 func LoadConfig() (*Config, error) {
@@ -26,6 +82,88 @@ func LoadConfig() (*Config, error) {
 		DBUser:     getEnv("DB_USER", "postgres"),
 		DBPassword: getEnv("DB_PASSWORD", "postgres"),
 		DBName:     getEnv("DB_NAME", "livros"),
+
+		MetadataDumpPath: getEnv("METADATA_DUMP_PATH", ""),
+
+		AlertNotifier:   getEnv("ALERT_NOTIFIER", "log"),
+		AlertDigestTime: getEnv("ALERT_DIGEST_TIME", "08:00"),
+		AlertWebhookURL: getEnv("ALERT_WEBHOOK_URL", ""),
+		SMTPAddr:        getEnv("SMTP_ADDR", ""),
+		SMTPUser:        getEnv("SMTP_USER", ""),
+		SMTPPassword:    getEnv("SMTP_PASSWORD", ""),
+		SMTPFrom:        getEnv("SMTP_FROM", ""),
+
+		EventsSink:        getEnv("EVENTS_SINK", "stdout"),
+		EventsFilePath:    getEnv("EVENTS_FILE", ""),
+		EventsWebhookURL:  getEnv("EVENTS_WEBHOOK_URL", ""),
+		EventsNATSURL:     getEnv("EVENTS_NATS_URL", ""),
+		EventsNATSSubject: getEnv("EVENTS_NATS_SUBJECT", "livros.events"),
+
+		GRPCAddr: getEnv("GRPC_ADDR", ":9090"),
+	}
+	for _, to := range strings.Split(getEnv("ALERT_EMAIL_TO", ""), ",") {
+		if to = strings.TrimSpace(to); to != "" {
+			config.AlertEmailTo = append(config.AlertEmailTo, to)
+		}
+	}
+
+	var err error
+	if config.StatsCacheTTL, err = time.ParseDuration(getEnv("STATS_CACHE_TTL", "1m")); err != nil {
+		return nil, fmt.Errorf("STATS_CACHE_TTL inválido: %w", err)
+	}
+	if config.LowStockThreshold, err = strconv.Atoi(getEnv("LOW_STOCK_THRESHOLD", "2")); err != nil {
+		return nil, fmt.Errorf("LOW_STOCK_THRESHOLD inválido: %w", err)
+	}
+	if config.TrashRetention, err = time.ParseDuration(getEnv("TRASH_RETENTION", "720h")); err != nil {
+		return nil, fmt.Errorf("TRASH_RETENTION inválido: %w", err)
+	}
+	if config.TrashPurgeInterval, err = time.ParseDuration(getEnv("TRASH_PURGE_INTERVAL", "1h")); err != nil {
+		return nil, fmt.Errorf("TRASH_PURGE_INTERVAL inválido: %w", err)
+	}
+	if config.EventsPollInterval, err = time.ParseDuration(getEnv("EVENTS_POLL_INTERVAL", "1s")); err != nil {
+		return nil, fmt.Errorf("EVENTS_POLL_INTERVAL inválido: %w", err)
+	} else if config.EventsPollInterval <= 0 {
+		// O intervalo alimenta time.NewTicker, que entra em pânico com valores <= 0
+		return nil, fmt.Errorf("EVENTS_POLL_INTERVAL deve ser positivo: %s", config.EventsPollInterval)
+	}
+	if config.EventsRetention, err = time.ParseDuration(getEnv("EVENTS_RETENTION", "168h")); err != nil {
+		return nil, fmt.Errorf("EVENTS_RETENTION inválido: %w", err)
+	}
+	if config.StreamReplaySize, err = strconv.Atoi(getEnv("STREAM_REPLAY_SIZE", "1000")); err != nil {
+		return nil, fmt.Errorf("STREAM_REPLAY_SIZE inválido: %w", err)
+	} else if config.StreamReplaySize <= 0 {
+		// Sem histórico, o Broker descartaria cada evento e o Last-Event-ID não retomaria nada
+		return nil, fmt.Errorf("STREAM_REPLAY_SIZE deve ser positivo: %d", config.StreamReplaySize)
+	}
+	if config.StreamHeartbeat, err = time.ParseDuration(getEnv("STREAM_HEARTBEAT", "15s")); err != nil {
+		return nil, fmt.Errorf("STREAM_HEARTBEAT inválido: %w", err)
+	} else if config.StreamHeartbeat <= 0 {
+		// O intervalo alimenta time.NewTicker, que entra em pânico com valores <= 0
+		return nil, fmt.Errorf("STREAM_HEARTBEAT deve ser positivo: %s", config.StreamHeartbeat)
+	}
+	if config.GraphQLMaxDepth, err = strconv.Atoi(getEnv("GRAPHQL_MAX_DEPTH", "10")); err != nil {
+		return nil, fmt.Errorf("GRAPHQL_MAX_DEPTH inválido: %w", err)
+	}
+	if config.GraphQLMaxComplexity, err = strconv.Atoi(getEnv("GRAPHQL_MAX_COMPLEXITY", "5000")); err != nil {
+		return nil, fmt.Errorf("GRAPHQL_MAX_COMPLEXITY inválido: %w", err)
+	}
+	if config.WebhookPollInterval, err = time.ParseDuration(getEnv("WEBHOOK_POLL_INTERVAL", "2s")); err != nil {
+		return nil, fmt.Errorf("WEBHOOK_POLL_INTERVAL inválido: %w", err)
+	} else if config.WebhookPollInterval <= 0 {
+		// O intervalo alimenta time.NewTicker, que entra em pânico com valores <= 0
+		return nil, fmt.Errorf("WEBHOOK_POLL_INTERVAL deve ser positivo: %s", config.WebhookPollInterval)
+	}
+	if config.WebhookMaxAttempts, err = strconv.Atoi(getEnv("WEBHOOK_MAX_ATTEMPTS", "8")); err != nil {
+		return nil, fmt.Errorf("WEBHOOK_MAX_ATTEMPTS inválido: %w", err)
+	}
+	if config.WebhookDisableAfter, err = strconv.Atoi(getEnv("WEBHOOK_DISABLE_AFTER", "20")); err != nil {
+		return nil, fmt.Errorf("WEBHOOK_DISABLE_AFTER inválido: %w", err)
+	}
+	if config.ValidateResponses, err = strconv.ParseBool(getEnv("VALIDATE_RESPONSES", "false")); err != nil {
+		return nil, fmt.Errorf("VALIDATE_RESPONSES inválido: %w", err)
+	}
+	if config.MaxBodyBytes, err = strconv.ParseInt(getEnv("MAX_BODY_BYTES", "10485760"), 10, 64); err != nil || config.MaxBodyBytes <= 0 {
+		return nil, fmt.Errorf("MAX_BODY_BYTES inválido: %q", getEnv("MAX_BODY_BYTES", ""))
 	}
 	return config, nil
 }
@@ -0,0 +1,130 @@
+package graphql
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	services "projeto_livros/internal/usecase"
+
+	gql "github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// Handler atende o endpoint /graphql. Cada requisição ganha seus próprios
+// loaders, então o cache dos lotes não vaza entre usuários.
+type Handler struct {
+	schema  gql.Schema
+	catalog *services.CatalogService
+	limits  Limits
+}
+
+func NewHandler(db *sql.DB, limits Limits) (*Handler, error) {
+	catalog := services.NewCatalogService(db)
+	schema, err := NewSchema(catalog)
+	if err != nil {
+		return nil, err
+	}
+	return &Handler{schema: schema, catalog: catalog, limits: limits}, nil
+}
+
+// Request é o corpo de uma requisição GraphQL
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// ServeHTTP aceita POST com o corpo em JSON e GET com query, operationName e
+// variables na URL. Pelo GET só são aceitas consultas, nunca mutações.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req Request
+	switch r.Method {
+	case http.MethodGet:
+		query := r.URL.Query()
+		req.Query = query.Get("query")
+		req.OperationName = query.Get("operationName")
+		if variables := query.Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				sendErrors(w, http.StatusBadRequest, gqlerrors.NewFormattedError("variables inválido: deve ser um objeto JSON"))
+				return
+			}
+		}
+	case http.MethodPost:
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			sendErrors(w, http.StatusBadRequest, gqlerrors.NewFormattedError("Corpo da requisição inválido"))
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		sendErrors(w, http.StatusMethodNotAllowed, gqlerrors.NewFormattedError("Use GET ou POST"))
+		return
+	}
+	if req.Query == "" {
+		sendErrors(w, http.StatusBadRequest, gqlerrors.NewFormattedError("O campo query é obrigatório"))
+		return
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"})})
+	if err != nil {
+		sendErrors(w, http.StatusBadRequest, gqlerrors.FormatError(err))
+		return
+	}
+	if validation := gql.ValidateDocument(&h.schema, doc, nil); !validation.IsValid {
+		sendErrors(w, http.StatusBadRequest, validation.Errors...)
+		return
+	}
+	if r.Method == http.MethodGet && isMutation(doc, req.OperationName) {
+		w.Header().Set("Allow", "POST")
+		sendErrors(w, http.StatusMethodNotAllowed, gqlerrors.NewFormattedError("Mutações devem ser enviadas por POST"))
+		return
+	}
+	if _, _, err := h.limits.Check(doc, req.OperationName, req.Variables); err != nil {
+		var limitErr *LimitError
+		if errors.As(err, &limitErr) {
+			formatted := gqlerrors.NewFormattedError(limitErr.Message)
+			formatted.Extensions = map[string]interface{}{"code": limitErr.Code}
+			sendErrors(w, http.StatusBadRequest, formatted)
+			return
+		}
+	}
+
+	result := gql.Execute(gql.ExecuteParams{
+		Schema:        h.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       withLoaders(r.Context(), newLoaders(h.catalog)),
+	})
+	sendJSON(w, http.StatusOK, result)
+}
+
+// isMutation informa se a operação escolhida é uma mutação
+func isMutation(doc *ast.Document, operationName string) bool {
+	for _, def := range doc.Definitions {
+		operation, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if operationName == "" || (operation.Name != nil && operation.Name.Value == operationName) {
+			return operation.Operation == ast.OperationTypeMutation
+		}
+	}
+	return false
+}
+
+func sendErrors(w http.ResponseWriter, status int, errs ...gqlerrors.FormattedError) {
+	sendJSON(w, status, &gql.Result{Errors: errs})
+}
+
+func sendJSON(w http.ResponseWriter, status int, result *gql.Result) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.Printf("Erro ao escrever a resposta GraphQL: %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package graphql
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+type response struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+func newTestHandler(t *testing.T, limits Limits) (*Handler, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Erro ao criar mock do banco de dados: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	handler, err := NewHandler(db, limits)
+	if err != nil {
+		t.Fatalf("Erro ao montar o schema: %v", err)
+	}
+	return handler, mock
+}
+
+func post(t *testing.T, handler *Handler, query string) (*httptest.ResponseRecorder, response) {
+	body, _ := json.Marshal(Request{Query: query})
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	var resp response
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("resposta não é um JSON válido: %v\n%s", err, rr.Body.String())
+	}
+	return rr, resp
+}
+
+func TestBooksQueryBatchesGenres(t *testing.T) {
+	handler, mock := newTestHandler(t, Limits{MaxDepth: 10, MaxComplexity: 5000})
+	mock.ExpectQuery("SELECT COUNT").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectQuery("SELECT (.+) FROM livros l WHERE l.deleted_at IS NULL ORDER BY l.name ASC").
+		WithArgs(2, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "quantity", "genre_id", "author",
+			"isbn", "publisher", "publication_year", "edition", "subjects", "min_quantity"}).
+			AddRow("1", "Livro 1", 1, "g1", "", "", "", nil, "", "{}", nil).
+			AddRow("2", "Livro 2", 2, nil, "", "", "", nil, "", "{}", nil))
+	// Uma única consulta para os gêneros de todos os livros da página
+	mock.ExpectQuery("FROM book_genres bg (.+) WHERE bg.book_id = ANY").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "parent_id", "book_id"}).
+			AddRow("g1", "Ficção", "", nil, "1"))
+
+	rr, resp := post(t, handler, `{ books(first: 2) { totalCount hasNextPage nodes { id name genres { id name } } } }`)
+	if rr.Code != http.StatusOK || len(resp.Errors) > 0 {
+		t.Fatalf("esperava 200 sem erros, obteve %d: %s", rr.Code, rr.Body.String())
+	}
+	books := resp.Data["books"].(map[string]interface{})
+	if books["totalCount"] != float64(3) || books["hasNextPage"] != true {
+		t.Errorf("paginação incorreta: %v", books)
+	}
+	nodes := books["nodes"].([]interface{})
+	if len(nodes) != 2 {
+		t.Fatalf("esperava 2 livros, obteve %d", len(nodes))
+	}
+	first := nodes[0].(map[string]interface{})["genres"].([]interface{})
+	second := nodes[1].(map[string]interface{})["genres"].([]interface{})
+	if len(first) != 1 || first[0].(map[string]interface{})["name"] != "Ficção" || len(second) != 0 {
+		t.Errorf("gêneros incorretos: %v e %v", first, second)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas não atendidas: %s", err)
+	}
+}
+
+func TestQueryRejectedByLimits(t *testing.T) {
+	handler, mock := newTestHandler(t, Limits{MaxDepth: 3})
+	rr, resp := post(t, handler, `{ genre(id: "1") { parent { parent { id } } } }`)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("esperava 400, obteve %d", rr.Code)
+	}
+	if len(resp.Errors) != 1 || resp.Errors[0].Extensions["code"] != "QUERY_TOO_DEEP" {
+		t.Errorf("esperava o erro QUERY_TOO_DEEP, obteve %s", rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("a consulta não deveria chegar ao banco: %s", err)
+	}
+}
+
+func TestMutationOverGetNotAllowed(t *testing.T) {
+	handler, _ := newTestHandler(t, Limits{})
+	query := url.Values{"query": {`mutation { deleteBook(id: "1") }`}}
+	req := httptest.NewRequest(http.MethodGet, "/graphql?"+query.Encode(), nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Errorf("esperava 405, obteve %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestHardDeleteRequiresAdmin(t *testing.T) {
+	handler, mock := newTestHandler(t, Limits{})
+	rr, resp := post(t, handler, `mutation { deleteBook(id: "1", hard: true) }`)
+	if rr.Code != http.StatusOK {
+		t.Errorf("erros de execução vêm com 200, obteve %d", rr.Code)
+	}
+	if len(resp.Errors) != 1 || resp.Errors[0].Extensions["code"] != "FORBIDDEN" {
+		t.Errorf("esperava o erro FORBIDDEN, obteve %s", rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("a remoção não deveria chegar ao banco: %s", err)
+	}
+}
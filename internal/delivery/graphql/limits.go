@@ -0,0 +1,134 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// Limits restringe o custo das consultas aceitas. A profundidade conta os
+// níveis de campos aninhados; a complexidade soma 1 por campo, multiplicando
+// o custo dos campos internos de uma lista pelo número de itens pedidos
+// (argumento first ou o tamanho padrão da página). Os campos de introspecção
+// (__schema, __type, __typename) não contam.
+type Limits struct {
+	MaxDepth      int
+	MaxComplexity int
+}
+
+// LimitError indica que a consulta ultrapassou um dos limites
+type LimitError struct {
+	Code    string // QUERY_TOO_DEEP ou QUERY_TOO_COMPLEX
+	Message string
+}
+
+func (e *LimitError) Error() string {
+	return e.Message
+}
+
+// paginatedFields são os campos de lista cujo tamanho vem do argumento first
+var paginatedFields = map[string]bool{"books": true}
+
+// analysis percorre a operação escolhida, resolvendo os fragmentos e as
+// variáveis usadas como first
+type analysis struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+	visiting  map[string]bool
+}
+
+// Check calcula a profundidade e a complexidade da operação operationName (ou
+// da única operação do documento) e devolve *LimitError se algum limite for
+// ultrapassado. Limites zerados não são verificados.
+func (l Limits) Check(doc *ast.Document, operationName string, variables map[string]interface{}) (depth, complexity int, err error) {
+	a := &analysis{fragments: map[string]*ast.FragmentDefinition{}, variables: variables, visiting: map[string]bool{}}
+	var operation *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		switch def := def.(type) {
+		case *ast.FragmentDefinition:
+			a.fragments[def.Name.Value] = def
+		case *ast.OperationDefinition:
+			if operationName == "" || (def.Name != nil && def.Name.Value == operationName) {
+				operation = def
+			}
+		}
+	}
+	if operation == nil {
+		return 0, 0, nil
+	}
+	depth, complexity = a.selectionSet(operation.SelectionSet)
+	if l.MaxDepth > 0 && depth > l.MaxDepth {
+		return depth, complexity, &LimitError{Code: "QUERY_TOO_DEEP",
+			Message: fmt.Sprintf("A consulta tem profundidade %d; o máximo é %d", depth, l.MaxDepth)}
+	}
+	if l.MaxComplexity > 0 && complexity > l.MaxComplexity {
+		return depth, complexity, &LimitError{Code: "QUERY_TOO_COMPLEX",
+			Message: fmt.Sprintf("A consulta tem complexidade %d; o máximo é %d", complexity, l.MaxComplexity)}
+	}
+	return depth, complexity, nil
+}
+
+// selectionSet devolve a profundidade e o custo de um conjunto de seleções
+func (a *analysis) selectionSet(set *ast.SelectionSet) (depth, cost int) {
+	if set == nil {
+		return 0, 0
+	}
+	for _, selection := range set.Selections {
+		var d, c int
+		switch s := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(s.Name.Value, "__") {
+				continue
+			}
+			childDepth, childCost := a.selectionSet(s.SelectionSet)
+			d, c = childDepth+1, 1+a.multiplier(s)*childCost
+		case *ast.InlineFragment:
+			d, c = a.selectionSet(s.SelectionSet)
+		case *ast.FragmentSpread:
+			fragment := a.fragments[s.Name.Value]
+			if fragment == nil || a.visiting[s.Name.Value] {
+				continue
+			}
+			a.visiting[s.Name.Value] = true
+			d, c = a.selectionSet(fragment.SelectionSet)
+			delete(a.visiting, s.Name.Value)
+		}
+		if d > depth {
+			depth = d
+		}
+		cost += c
+	}
+	return depth, cost
+}
+
+// multiplier é o número de itens que o campo pode devolver
+func (a *analysis) multiplier(field *ast.Field) int {
+	for _, arg := range field.Arguments {
+		if arg.Name.Value != "first" {
+			continue
+		}
+		switch value := arg.Value.(type) {
+		case *ast.IntValue:
+			if n, err := strconv.Atoi(value.Value); err == nil && n > 0 {
+				return n
+			}
+		case *ast.Variable:
+			switch n := a.variables[value.Name.Value].(type) {
+			case float64:
+				if n > 0 {
+					return int(n)
+				}
+			case int:
+				if n > 0 {
+					return n
+				}
+			}
+		}
+	}
+	if paginatedFields[field.Name.Value] {
+		return defaultPageSize
+	}
+	return 1
+}
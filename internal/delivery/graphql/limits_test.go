@@ -0,0 +1,90 @@
+package graphql
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+func TestLimitsCheck(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		variables     map[string]interface{}
+		depth         int
+		complexity    int
+		wantCode      string
+		maxDepth      int
+		maxComplexity int
+	}{
+		{
+			name:       "campos simples",
+			query:      `{ book(id: "1") { id name } }`,
+			depth:      2,
+			complexity: 3,
+		},
+		{
+			name:       "lista usa o tamanho padrão da página",
+			query:      `{ books { nodes { id } } }`,
+			depth:      3,
+			complexity: 1 + defaultPageSize*2,
+		},
+		{
+			name:       "first multiplica os campos internos",
+			query:      `{ books(first: 5) { totalCount nodes { id genres { name } } } }`,
+			depth:      4,
+			complexity: 1 + 5*(1+1+1+2),
+		},
+		{
+			name:       "first em variável",
+			query:      `query($n: Int) { books(first: $n) { nodes { id } } }`,
+			variables:  map[string]interface{}{"n": float64(3)},
+			depth:      3,
+			complexity: 1 + 3*2,
+		},
+		{
+			name:       "fragmentos e introspecção",
+			query:      `{ __typename genre(id: "1") { ...campos } } fragment campos on Genre { id children { id } }`,
+			depth:      3,
+			complexity: 1 + 1 + 2,
+		},
+		{
+			name:       "profundidade acima do limite",
+			query:      `{ genre(id: "1") { parent { parent { parent { id } } } } }`,
+			depth:      5,
+			complexity: 5,
+			maxDepth:   4,
+			wantCode:   "QUERY_TOO_DEEP",
+		},
+		{
+			name:          "complexidade acima do limite",
+			query:         `{ books(first: 100) { nodes { genre { books(first: 100) { nodes { id } } } } } }`,
+			depth:         6,
+			complexity:    1 + 100*(1+1*(1+1+100*2)),
+			maxComplexity: 5000,
+			wantCode:      "QUERY_TOO_COMPLEX",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := parser.Parse(parser.ParseParams{Source: tt.query})
+			if err != nil {
+				t.Fatalf("consulta inválida: %v", err)
+			}
+			limits := Limits{MaxDepth: tt.maxDepth, MaxComplexity: tt.maxComplexity}
+			depth, complexity, err := limits.Check(doc, "", tt.variables)
+			if depth != tt.depth || complexity != tt.complexity {
+				t.Errorf("obteve profundidade %d e complexidade %d, esperava %d e %d",
+					depth, complexity, tt.depth, tt.complexity)
+			}
+			var limitErr *LimitError
+			switch {
+			case tt.wantCode == "" && err != nil:
+				t.Errorf("erro inesperado: %v", err)
+			case tt.wantCode != "" && (!errors.As(err, &limitErr) || limitErr.Code != tt.wantCode):
+				t.Errorf("esperava %s, obteve %v", tt.wantCode, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package graphql
+
+import (
+	"context"
+	"projeto_livros/internal/domain/models"
+	services "projeto_livros/internal/usecase"
+	"sync"
+)
+
+// loader agrupa as chaves pedidas pelos resolvers e as busca em uma única
+// chamada na primeira vez que um dos resultados é lido. O executor resolve os
+// thunks nível a nível, então os campos irmãos (por exemplo, os gêneros de
+// cada livro de uma lista) são todos registrados antes da primeira leitura e
+// viram uma só consulta. Os resultados ficam em cache até o fim da requisição.
+type loader[K comparable, V any] struct {
+	mu      sync.Mutex
+	fetch   func(keys []K) (map[K]V, error)
+	pending []K
+	queued  map[K]bool
+	results map[K]V
+	errs    map[K]error
+	batches int // Chamadas a fetch, usado nos testes
+}
+
+func newLoader[K comparable, V any](fetch func(keys []K) (map[K]V, error)) *loader[K, V] {
+	return &loader[K, V]{fetch: fetch, queued: map[K]bool{}, results: map[K]V{}, errs: map[K]error{}}
+}
+
+// Load registra a chave no próximo lote e devolve o thunk que lê o resultado.
+// Chaves sem resultado devolvem o valor zero de V.
+func (l *loader[K, V]) Load(key K) func() (interface{}, error) {
+	l.mu.Lock()
+	_, done := l.results[key]
+	if !done && l.errs[key] == nil && !l.queued[key] {
+		l.queued[key] = true
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+	return func() (interface{}, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if l.queued[key] {
+			l.dispatch()
+		}
+		if err := l.errs[key]; err != nil {
+			return nil, err
+		}
+		return l.results[key], nil
+	}
+}
+
+// dispatch busca todas as chaves pendentes; deve ser chamado com mu travado
+func (l *loader[K, V]) dispatch() {
+	keys := l.pending
+	l.pending = nil
+	l.batches++
+	results, err := l.fetch(keys)
+	for _, key := range keys {
+		delete(l.queued, key)
+		if err != nil {
+			l.errs[key] = err
+		} else {
+			l.results[key] = results[key]
+		}
+	}
+}
+
+// loaders são os loaders de uma requisição. Os livros de cada gênero têm um
+// loader por limite pedido, já que o limite faz parte da consulta.
+type loaders struct {
+	catalog  *services.CatalogService
+	authors  *loader[string, []models.BookAuthor]
+	genres   *loader[string, []models.Genre] // Gêneros de cada livro
+	genre    *loader[string, *models.Genre]  // Gênero pelo id
+	children *loader[string, []models.Genre]
+	counts   *loader[string, int]
+
+	mu    sync.Mutex
+	books map[int]*loader[string, []models.Book]
+}
+
+func newLoaders(catalog *services.CatalogService) *loaders {
+	return &loaders{
+		catalog: catalog,
+		authors: newLoader(catalog.AuthorsByBooks),
+		genres:  newLoader(catalog.GenresByBooks),
+		genre: newLoader(func(ids []string) (map[string]*models.Genre, error) {
+			genres, err := catalog.GenresByIDs(ids)
+			if err != nil {
+				return nil, err
+			}
+			result := make(map[string]*models.Genre, len(genres))
+			for id, genre := range genres {
+				genre := genre
+				result[id] = &genre
+			}
+			return result, nil
+		}),
+		children: newLoader(catalog.GenreChildren),
+		counts:   newLoader(catalog.BookCountsByGenres),
+		books:    map[int]*loader[string, []models.Book]{},
+	}
+}
+
+// booksByGenre devolve o loader dos livros de gênero com até limit livros cada
+func (l *loaders) booksByGenre(limit int) *loader[string, []models.Book] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.books[limit] == nil {
+		l.books[limit] = newLoader(func(ids []string) (map[string][]models.Book, error) {
+			return l.catalog.BooksByGenres(ids, limit)
+		})
+	}
+	return l.books[limit]
+}
+
+type contextKey string
+
+const loadersKey contextKey = "graphqlLoaders"
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey).(*loaders)
+}
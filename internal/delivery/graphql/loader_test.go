@@ -0,0 +1,67 @@
+package graphql
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestLoaderBatchesPendingKeys(t *testing.T) {
+	var calls [][]string
+	l := newLoader(func(keys []string) (map[string]int, error) {
+		calls = append(calls, append([]string(nil), keys...))
+		result := map[string]int{}
+		for _, key := range keys {
+			if key != "desconhecido" {
+				result[key] = len(key)
+			}
+		}
+		return result, nil
+	})
+
+	thunks := []func() (interface{}, error){l.Load("a"), l.Load("bb"), l.Load("a"), l.Load("desconhecido")}
+	var got []interface{}
+	for _, thunk := range thunks {
+		value, err := thunk()
+		if err != nil {
+			t.Fatalf("erro inesperado: %v", err)
+		}
+		got = append(got, value)
+	}
+	if want := []interface{}{1, 2, 1, 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("resultados: obteve %v, esperava %v", got, want)
+	}
+	if l.batches != 1 || len(calls) != 1 {
+		t.Fatalf("esperava um único lote, obteve %d: %v", l.batches, calls)
+	}
+	sort.Strings(calls[0])
+	if want := []string{"a", "bb", "desconhecido"}; !reflect.DeepEqual(calls[0], want) {
+		t.Errorf("chaves do lote: obteve %v, esperava %v", calls[0], want)
+	}
+
+	// Chaves já carregadas vêm do cache; as novas formam outro lote
+	if value, _ := l.Load("bb")(); value != 2 {
+		t.Errorf("valor em cache: obteve %v", value)
+	}
+	if value, _ := l.Load("ccc")(); value != 3 || l.batches != 2 {
+		t.Errorf("esperava o segundo lote com ccc, obteve %v em %d lotes", value, l.batches)
+	}
+}
+
+func TestLoaderPropagatesFetchError(t *testing.T) {
+	fail := errors.New("falha no banco")
+	l := newLoader(func(keys []string) (map[string]int, error) {
+		return nil, fail
+	})
+	first, second := l.Load("a"), l.Load("b")
+	if _, err := first(); err != fail {
+		t.Errorf("esperava o erro do lote, obteve %v", err)
+	}
+	if _, err := second(); err != fail {
+		t.Errorf("esperava o erro do lote para todas as chaves, obteve %v", err)
+	}
+	if l.batches != 1 {
+		t.Errorf("esperava um único lote, obteve %d", l.batches)
+	}
+}
@@ -0,0 +1,264 @@
+package graphql
+
+import (
+	"projeto_livros/internal/delivery/middleware"
+	apperrors "projeto_livros/internal/domain/errors"
+	"projeto_livros/internal/domain/models"
+	services "projeto_livros/internal/usecase"
+
+	gql "github.com/graphql-go/graphql"
+)
+
+// bookInputFields são os campos de CreateBookInput e UpdateBookInput; na
+// criação name e quantity são obrigatórios
+func bookInputFields(authorInput *gql.InputObject, create bool) gql.InputObjectConfigFieldMap {
+	required := func(t gql.Input) gql.Input {
+		if create {
+			return gql.NewNonNull(t)
+		}
+		return t
+	}
+	return gql.InputObjectConfigFieldMap{
+		"name":            &gql.InputObjectFieldConfig{Type: required(gql.String)},
+		"quantity":        &gql.InputObjectFieldConfig{Type: required(gql.Int)},
+		"minQuantity":     &gql.InputObjectFieldConfig{Type: gql.Int},
+		"isbn":            &gql.InputObjectFieldConfig{Type: gql.String, Description: "ISBN-10 ou ISBN-13, com ou sem hífens"},
+		"publisher":       &gql.InputObjectFieldConfig{Type: gql.String},
+		"publicationYear": &gql.InputObjectFieldConfig{Type: gql.Int},
+		"edition":         &gql.InputObjectFieldConfig{Type: gql.String},
+		"subjects":        &gql.InputObjectFieldConfig{Type: gql.NewList(gql.NewNonNull(gql.String))},
+		"authors": &gql.InputObjectFieldConfig{
+			Type:        gql.NewList(gql.NewNonNull(authorInput)),
+			Description: "Substitui os autores do livro",
+		},
+		"genreIds": &gql.InputObjectFieldConfig{
+			Type:        gql.NewList(gql.NewNonNull(gql.ID)),
+			Description: "Substitui os gêneros do livro; o primeiro é o principal",
+		},
+	}
+}
+
+// applyBookInput copia para o livro os campos presentes em input. Campos
+// ausentes mantêm o valor atual; authors e genreIds só substituem os vínculos
+// quando presentes.
+func applyBookInput(book *models.Book, input map[string]interface{}) services.BookRelations {
+	var rel services.BookRelations
+	optionalInt := func(value interface{}) *int {
+		if n, ok := value.(int); ok {
+			return &n
+		}
+		return nil
+	}
+	for key, value := range input {
+		switch key {
+		case "name":
+			book.Name, _ = value.(string)
+		case "quantity":
+			book.Quantity, _ = value.(int)
+		case "minQuantity":
+			book.MinQuantity = optionalInt(value)
+		case "isbn":
+			book.ISBN, _ = value.(string)
+			book.ISBN10, book.ISBN13 = "", ""
+		case "publisher":
+			book.Publisher, _ = value.(string)
+		case "publicationYear":
+			book.PublicationYear = optionalInt(value)
+		case "edition":
+			book.Edition, _ = value.(string)
+		case "subjects":
+			book.Subjects = []string{}
+			for _, subject := range listValue(value) {
+				book.Subjects = append(book.Subjects, subject.(string))
+			}
+		case "authors":
+			rel.Authors = []models.BookAuthor{}
+			for _, item := range listValue(value) {
+				author, _ := item.(map[string]interface{})
+				id, _ := author["id"].(string)
+				name, _ := author["name"].(string)
+				role, _ := author["role"].(string)
+				rel.Authors = append(rel.Authors, models.BookAuthor{ID: id, Name: name, Role: role})
+			}
+		case "genreIds":
+			rel.GenreIDs = []string{}
+			for _, id := range listValue(value) {
+				rel.GenreIDs = append(rel.GenreIDs, id.(string))
+			}
+		}
+	}
+	return rel
+}
+
+func listValue(value interface{}) []interface{} {
+	list, _ := value.([]interface{})
+	return list
+}
+
+// requireAdminForHard recusa a remoção definitiva para quem não é administrador
+func requireAdminForHard(p gql.ResolveParams) (bool, error) {
+	hard, _ := p.Args["hard"].(bool)
+	if hard && !middleware.IsAdmin(p.Context) {
+		return false, resolverError{apperrors.NewForbiddenError("Apenas administradores podem apagar definitivamente")}
+	}
+	return hard, nil
+}
+
+func newMutationType(catalog *services.CatalogService, bookType, genreType *gql.Object) *gql.Object {
+	authorInput := gql.NewInputObject(gql.InputObjectConfig{
+		Name: "BookAuthorInput",
+		Fields: gql.InputObjectConfigFieldMap{
+			"id":   &gql.InputObjectFieldConfig{Type: gql.ID, Description: "Autor já cadastrado"},
+			"name": &gql.InputObjectFieldConfig{Type: gql.String, Description: "Nome do autor, criado se ainda não existir"},
+			"role": &gql.InputObjectFieldConfig{Type: gql.String, Description: "author (padrão), translator ou illustrator"},
+		},
+	})
+	createBookInput := gql.NewInputObject(gql.InputObjectConfig{Name: "CreateBookInput", Fields: bookInputFields(authorInput, true)})
+	updateBookInput := gql.NewInputObject(gql.InputObjectConfig{Name: "UpdateBookInput", Fields: bookInputFields(authorInput, false)})
+	genreInputFields := func(create bool) gql.InputObjectConfigFieldMap {
+		name := gql.Input(gql.String)
+		if create {
+			name = gql.NewNonNull(gql.String)
+		}
+		return gql.InputObjectConfigFieldMap{
+			"name":        &gql.InputObjectFieldConfig{Type: name},
+			"description": &gql.InputObjectFieldConfig{Type: gql.String},
+			"parentId":    &gql.InputObjectFieldConfig{Type: gql.ID, Description: "Gênero pai; null torna o gênero raiz"},
+		}
+	}
+	createGenreInput := gql.NewInputObject(gql.InputObjectConfig{Name: "CreateGenreInput", Fields: genreInputFields(true)})
+	updateGenreInput := gql.NewInputObject(gql.InputObjectConfig{Name: "UpdateGenreInput", Fields: genreInputFields(false)})
+	deleteGenreResult := gql.NewObject(gql.ObjectConfig{
+		Name: "DeleteGenreResult",
+		Fields: gql.Fields{
+			"movedBooks": &gql.Field{Type: gql.NewNonNull(gql.Int), Description: "Livros transferidos para reassignTo"},
+		},
+	})
+	applyGenreInput := func(genre *models.Genre, input map[string]interface{}) {
+		for key, value := range input {
+			switch key {
+			case "name":
+				genre.Name, _ = value.(string)
+			case "description":
+				genre.Description, _ = value.(string)
+			case "parentId":
+				genre.ParentID = nil
+				if id, ok := value.(string); ok {
+					genre.ParentID = &id
+				}
+			}
+		}
+	}
+
+	return gql.NewObject(gql.ObjectConfig{
+		Name: "Mutation",
+		Fields: gql.Fields{
+			"createBook": &gql.Field{
+				Type: gql.NewNonNull(bookType),
+				Args: gql.FieldConfigArgument{"input": &gql.ArgumentConfig{Type: gql.NewNonNull(createBookInput)}},
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					var book models.Book
+					rel := applyBookInput(&book, p.Args["input"].(map[string]interface{}))
+					book.Authors = rel.Authors
+					for _, id := range rel.GenreIDs {
+						book.Genres = append(book.Genres, models.BookGenre{ID: id})
+					}
+					if err := catalog.CreateBook(&book, actor(p)); err != nil {
+						return nil, resolveError(err)
+					}
+					return book, nil
+				},
+			},
+			"updateBook": &gql.Field{
+				Type:        gql.NewNonNull(bookType),
+				Description: "Altera apenas os campos informados",
+				Args: gql.FieldConfigArgument{
+					"id":    &gql.ArgumentConfig{Type: gql.NewNonNull(gql.ID)},
+					"input": &gql.ArgumentConfig{Type: gql.NewNonNull(updateBookInput)},
+				},
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					book, err := catalog.GetBook(p.Args["id"].(string))
+					if err != nil {
+						return nil, resolveError(err)
+					}
+					rel := applyBookInput(book, p.Args["input"].(map[string]interface{}))
+					if err := catalog.UpdateBook(book, rel, actor(p)); err != nil {
+						return nil, resolveError(err)
+					}
+					return *book, nil
+				},
+			},
+			"deleteBook": &gql.Field{
+				Type:        gql.NewNonNull(gql.Boolean),
+				Description: "Move o livro para a lixeira; hard (só administradores) apaga definitivamente",
+				Args: gql.FieldConfigArgument{
+					"id":   &gql.ArgumentConfig{Type: gql.NewNonNull(gql.ID)},
+					"hard": &gql.ArgumentConfig{Type: gql.Boolean, DefaultValue: false},
+				},
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					hard, err := requireAdminForHard(p)
+					if err != nil {
+						return nil, err
+					}
+					if err := catalog.DeleteBook(p.Args["id"].(string), hard, actor(p)); err != nil {
+						return nil, resolveError(err)
+					}
+					return true, nil
+				},
+			},
+			"createGenre": &gql.Field{
+				Type: gql.NewNonNull(genreType),
+				Args: gql.FieldConfigArgument{"input": &gql.ArgumentConfig{Type: gql.NewNonNull(createGenreInput)}},
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					var genre models.Genre
+					applyGenreInput(&genre, p.Args["input"].(map[string]interface{}))
+					if err := catalog.CreateGenre(&genre, actor(p)); err != nil {
+						return nil, resolveError(err)
+					}
+					return genre, nil
+				},
+			},
+			"updateGenre": &gql.Field{
+				Type:        gql.NewNonNull(genreType),
+				Description: "Altera apenas os campos informados",
+				Args: gql.FieldConfigArgument{
+					"id":    &gql.ArgumentConfig{Type: gql.NewNonNull(gql.ID)},
+					"input": &gql.ArgumentConfig{Type: gql.NewNonNull(updateGenreInput)},
+				},
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					genre, err := catalog.GetGenre(p.Args["id"].(string))
+					if err != nil {
+						return nil, resolveError(err)
+					}
+					applyGenreInput(genre, p.Args["input"].(map[string]interface{}))
+					if err := catalog.UpdateGenre(genre, actor(p)); err != nil {
+						return nil, resolveError(err)
+					}
+					return *genre, nil
+				},
+			},
+			"deleteGenre": &gql.Field{
+				Type: gql.NewNonNull(deleteGenreResult),
+				Description: "Move o gênero para a lixeira. Gêneros com livros exigem reassignTo, que recebe " +
+					"os livros; hard (só administradores) apaga definitivamente",
+				Args: gql.FieldConfigArgument{
+					"id":         &gql.ArgumentConfig{Type: gql.NewNonNull(gql.ID)},
+					"reassignTo": &gql.ArgumentConfig{Type: gql.ID},
+					"hard":       &gql.ArgumentConfig{Type: gql.Boolean, DefaultValue: false},
+				},
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					hard, err := requireAdminForHard(p)
+					if err != nil {
+						return nil, err
+					}
+					reassignTo, _ := p.Args["reassignTo"].(string)
+					moved, err := catalog.DeleteGenre(p.Args["id"].(string), reassignTo, hard, actor(p))
+					if err != nil {
+						return nil, resolveError(err)
+					}
+					return map[string]interface{}{"movedBooks": moved}, nil
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,381 @@
+package graphql
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"projeto_livros/internal/delivery/middleware"
+	apperrors "projeto_livros/internal/domain/errors"
+	"projeto_livros/internal/domain/models"
+	services "projeto_livros/internal/usecase"
+
+	gql "github.com/graphql-go/graphql"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// resolverError leva o código do erro de domínio para extensions.code
+type resolverError struct {
+	apperrors.APIError
+}
+
+func (e resolverError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.Code}
+}
+
+// resolveError prepara um erro para a resposta: erros de domínio seguem com a
+// mensagem e o código; os demais (do banco) são registrados no log e
+// substituídos por uma mensagem genérica
+func resolveError(err error) error {
+	var apiErr apperrors.APIError
+	if errors.As(err, &apiErr) {
+		return resolverError{apiErr}
+	}
+	var inUse *services.GenreInUseError
+	if errors.As(err, &inUse) {
+		return resolverError{apperrors.APIError{Status: http.StatusConflict, Code: apperrors.CodeGenreInUse,
+			Message: fmt.Sprintf("O gênero possui %d livros. Informe reassignTo para transferi-los antes de remover", inUse.Books)}}
+	}
+	log.Printf("Erro ao resolver campo GraphQL: %v", err)
+	return errors.New("Erro interno do servidor")
+}
+
+// deferred adapta o thunk de um loader, convertendo o erro com resolveError
+func deferred(thunk func() (interface{}, error)) func() (interface{}, error) {
+	return func() (interface{}, error) {
+		value, err := thunk()
+		if err != nil {
+			return nil, resolveError(err)
+		}
+		return value, nil
+	}
+}
+
+// pageArgs lê first e offset, limitando first a maxPageSize
+func pageArgs(args map[string]interface{}) (first, offset int, err error) {
+	first, _ = args["first"].(int)
+	offset, _ = args["offset"].(int)
+	if first < 1 || first > maxPageSize {
+		return 0, 0, resolverError{apperrors.NewBadRequestError(fmt.Sprintf("first deve estar entre 1 e %d", maxPageSize))}
+	}
+	if offset < 0 {
+		return 0, 0, resolverError{apperrors.NewBadRequestError("offset não pode ser negativo")}
+	}
+	return first, offset, nil
+}
+
+// bookPage é uma página da busca de livros
+type bookPage struct {
+	Nodes      []models.Book
+	TotalCount int
+	First      int
+	Offset     int
+}
+
+func bookField(get func(book models.Book) interface{}) gql.FieldResolveFn {
+	return func(p gql.ResolveParams) (interface{}, error) {
+		return get(p.Source.(models.Book)), nil
+	}
+}
+
+func genreField(get func(genre models.Genre) interface{}) gql.FieldResolveFn {
+	return func(p gql.ResolveParams) (interface{}, error) {
+		return get(p.Source.(models.Genre)), nil
+	}
+}
+
+func authorField(get func(author models.BookAuthor) interface{}) gql.FieldResolveFn {
+	return func(p gql.ResolveParams) (interface{}, error) {
+		return get(p.Source.(models.BookAuthor)), nil
+	}
+}
+
+// loadGenre busca um gênero pelo id com o loader; ids nil ou vazios viram null
+func loadGenre(p gql.ResolveParams, id *string) (interface{}, error) {
+	if id == nil || *id == "" {
+		return nil, nil
+	}
+	thunk := loadersFrom(p.Context).genre.Load(*id)
+	return deferred(func() (interface{}, error) {
+		value, err := thunk()
+		if err != nil || value.(*models.Genre) == nil {
+			return nil, err
+		}
+		return *value.(*models.Genre), nil
+	}), nil
+}
+
+func optionalString(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+func optionalInt(value *int) interface{} {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
+
+func nonNil(value []string) []string {
+	if value == nil {
+		return []string{}
+	}
+	return value
+}
+
+// NewSchema monta o schema GraphQL do catálogo sobre o serviço compartilhado
+func NewSchema(catalog *services.CatalogService) (gql.Schema, error) {
+	bookAuthorType := gql.NewObject(gql.ObjectConfig{
+		Name:        "BookAuthor",
+		Description: "Autor de um livro, com o papel no livro (author, translator ou illustrator)",
+		Fields: gql.Fields{
+			"id":   &gql.Field{Type: gql.NewNonNull(gql.ID), Resolve: authorField(func(a models.BookAuthor) interface{} { return a.ID })},
+			"name": &gql.Field{Type: gql.NewNonNull(gql.String), Resolve: authorField(func(a models.BookAuthor) interface{} { return a.Name })},
+			"role": &gql.Field{Type: gql.NewNonNull(gql.String), Resolve: authorField(func(a models.BookAuthor) interface{} { return a.Role })},
+		},
+	})
+
+	// Os campos de Genre são criados sob demanda porque se referem ao próprio
+	// tipo e a Book, criado depois
+	var bookType, genreType *gql.Object
+	genreType = gql.NewObject(gql.ObjectConfig{
+		Name: "Genre",
+		Fields: gql.FieldsThunk(func() gql.Fields {
+			return gql.Fields{
+				"id":          &gql.Field{Type: gql.NewNonNull(gql.ID), Resolve: genreField(func(g models.Genre) interface{} { return g.ID })},
+				"name":        &gql.Field{Type: gql.NewNonNull(gql.String), Resolve: genreField(func(g models.Genre) interface{} { return g.Name })},
+				"description": &gql.Field{Type: gql.NewNonNull(gql.String), Resolve: genreField(func(g models.Genre) interface{} { return g.Description })},
+				"parent": &gql.Field{
+					Type:        genreType,
+					Description: "Gênero pai; null para gêneros raiz",
+					Resolve: func(p gql.ResolveParams) (interface{}, error) {
+						return loadGenre(p, p.Source.(models.Genre).ParentID)
+					},
+				},
+				"children": &gql.Field{
+					Type:        gql.NewNonNull(gql.NewList(gql.NewNonNull(genreType))),
+					Description: "Subgêneros diretos, pelo nome",
+					Resolve: func(p gql.ResolveParams) (interface{}, error) {
+						return deferred(loadersFrom(p.Context).children.Load(p.Source.(models.Genre).ID)), nil
+					},
+				},
+				"bookCount": &gql.Field{
+					Type: gql.NewNonNull(gql.Int),
+					Resolve: func(p gql.ResolveParams) (interface{}, error) {
+						return deferred(loadersFrom(p.Context).counts.Load(p.Source.(models.Genre).ID)), nil
+					},
+				},
+				"books": &gql.Field{
+					Type:        gql.NewNonNull(gql.NewList(gql.NewNonNull(bookType))),
+					Description: "Livros do gênero, pelo nome",
+					Args: gql.FieldConfigArgument{
+						"first": &gql.ArgumentConfig{Type: gql.Int, DefaultValue: defaultPageSize},
+					},
+					Resolve: func(p gql.ResolveParams) (interface{}, error) {
+						first, _, err := pageArgs(p.Args)
+						if err != nil {
+							return nil, err
+						}
+						return deferred(loadersFrom(p.Context).booksByGenre(first).Load(p.Source.(models.Genre).ID)), nil
+					},
+				},
+			}
+		}),
+	})
+
+	bookType = gql.NewObject(gql.ObjectConfig{
+		Name: "Book",
+		Fields: gql.Fields{
+			"id":              &gql.Field{Type: gql.NewNonNull(gql.ID), Resolve: bookField(func(b models.Book) interface{} { return b.ID })},
+			"name":            &gql.Field{Type: gql.NewNonNull(gql.String), Resolve: bookField(func(b models.Book) interface{} { return b.Name })},
+			"title":           &gql.Field{Type: gql.NewNonNull(gql.String), Resolve: bookField(func(b models.Book) interface{} { return b.Name })},
+			"author":          &gql.Field{Type: gql.NewNonNull(gql.String), Description: "Nomes dos autores (papel author)", Resolve: bookField(func(b models.Book) interface{} { return b.Author })},
+			"quantity":        &gql.Field{Type: gql.NewNonNull(gql.Int), Resolve: bookField(func(b models.Book) interface{} { return b.Quantity })},
+			"minQuantity":     &gql.Field{Type: gql.Int, Resolve: bookField(func(b models.Book) interface{} { return optionalInt(b.MinQuantity) })},
+			"isbn":            &gql.Field{Type: gql.String, Resolve: bookField(func(b models.Book) interface{} { return optionalString(b.ISBN) })},
+			"isbn10":          &gql.Field{Type: gql.String, Resolve: bookField(func(b models.Book) interface{} { return optionalString(b.ISBN10) })},
+			"isbn13":          &gql.Field{Type: gql.String, Resolve: bookField(func(b models.Book) interface{} { return optionalString(b.ISBN13) })},
+			"publisher":       &gql.Field{Type: gql.String, Resolve: bookField(func(b models.Book) interface{} { return optionalString(b.Publisher) })},
+			"publicationYear": &gql.Field{Type: gql.Int, Resolve: bookField(func(b models.Book) interface{} { return optionalInt(b.PublicationYear) })},
+			"edition":         &gql.Field{Type: gql.String, Resolve: bookField(func(b models.Book) interface{} { return optionalString(b.Edition) })},
+			"subjects":        &gql.Field{Type: gql.NewNonNull(gql.NewList(gql.NewNonNull(gql.String))), Resolve: bookField(func(b models.Book) interface{} { return nonNil(b.Subjects) })},
+			"authors": &gql.Field{
+				Type: gql.NewNonNull(gql.NewList(gql.NewNonNull(bookAuthorType))),
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					return deferred(loadersFrom(p.Context).authors.Load(p.Source.(models.Book).ID)), nil
+				},
+			},
+			"genres": &gql.Field{
+				Type:        gql.NewNonNull(gql.NewList(gql.NewNonNull(genreType))),
+				Description: "Gêneros do livro; o primeiro é o principal",
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					return deferred(loadersFrom(p.Context).genres.Load(p.Source.(models.Book).ID)), nil
+				},
+			},
+			"genre": &gql.Field{
+				Type:        genreType,
+				Description: "Gênero principal",
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					return loadGenre(p, p.Source.(models.Book).GenreID)
+				},
+			},
+		},
+	})
+
+	bookPageType := gql.NewObject(gql.ObjectConfig{
+		Name: "BookPage",
+		Fields: gql.Fields{
+			"nodes": &gql.Field{
+				Type:    gql.NewNonNull(gql.NewList(gql.NewNonNull(bookType))),
+				Resolve: func(p gql.ResolveParams) (interface{}, error) { return p.Source.(bookPage).Nodes, nil },
+			},
+			"totalCount": &gql.Field{
+				Type:    gql.NewNonNull(gql.Int),
+				Resolve: func(p gql.ResolveParams) (interface{}, error) { return p.Source.(bookPage).TotalCount, nil },
+			},
+			"hasNextPage": &gql.Field{
+				Type: gql.NewNonNull(gql.Boolean),
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					page := p.Source.(bookPage)
+					return page.Offset+len(page.Nodes) < page.TotalCount, nil
+				},
+			},
+		},
+	})
+
+	bookFilterType := gql.NewInputObject(gql.InputObjectConfig{
+		Name: "BookFilter",
+		Fields: gql.InputObjectConfigFieldMap{
+			"search":             &gql.InputObjectFieldConfig{Type: gql.String, Description: "Trecho do nome, do autor ou do ISBN"},
+			"genreId":            &gql.InputObjectFieldConfig{Type: gql.ID},
+			"includeDescendants": &gql.InputObjectFieldConfig{Type: gql.Boolean, Description: "Com genreId, inclui os subgêneros"},
+			"authorId":           &gql.InputObjectFieldConfig{Type: gql.ID},
+			"minQuantity":        &gql.InputObjectFieldConfig{Type: gql.Int},
+			"maxQuantity":        &gql.InputObjectFieldConfig{Type: gql.Int},
+			"lowStock":           &gql.InputObjectFieldConfig{Type: gql.Boolean, Description: "Só livros abaixo do estoque mínimo"},
+		},
+	})
+	bookSortFieldType := gql.NewEnum(gql.EnumConfig{
+		Name: "BookSortField",
+		Values: gql.EnumValueConfigMap{
+			"NAME":             &gql.EnumValueConfig{Value: "name"},
+			"QUANTITY":         &gql.EnumValueConfig{Value: "quantity"},
+			"PUBLICATION_YEAR": &gql.EnumValueConfig{Value: "publication_year"},
+		},
+	})
+	sortDirectionType := gql.NewEnum(gql.EnumConfig{
+		Name: "SortDirection",
+		Values: gql.EnumValueConfigMap{
+			"ASC":  &gql.EnumValueConfig{Value: "asc"},
+			"DESC": &gql.EnumValueConfig{Value: "desc"},
+		},
+	})
+
+	query := gql.NewObject(gql.ObjectConfig{
+		Name: "Query",
+		Fields: gql.Fields{
+			"book": &gql.Field{
+				Type: bookType,
+				Args: gql.FieldConfigArgument{"id": &gql.ArgumentConfig{Type: gql.NewNonNull(gql.ID)}},
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					book, err := catalog.GetBook(p.Args["id"].(string))
+					var apiErr apperrors.APIError
+					if errors.As(err, &apiErr) && apiErr.Status == http.StatusNotFound {
+						return nil, nil
+					} else if err != nil {
+						return nil, resolveError(err)
+					}
+					return *book, nil
+				},
+			},
+			"books": &gql.Field{
+				Type: gql.NewNonNull(bookPageType),
+				Args: gql.FieldConfigArgument{
+					"filter":        &gql.ArgumentConfig{Type: bookFilterType},
+					"sortBy":        &gql.ArgumentConfig{Type: bookSortFieldType, DefaultValue: "name"},
+					"sortDirection": &gql.ArgumentConfig{Type: sortDirectionType, DefaultValue: "asc"},
+					"first":         &gql.ArgumentConfig{Type: gql.Int, DefaultValue: defaultPageSize},
+					"offset":        &gql.ArgumentConfig{Type: gql.Int, DefaultValue: 0},
+				},
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					first, offset, err := pageArgs(p.Args)
+					if err != nil {
+						return nil, err
+					}
+					filter := bookFilterFromArgs(p.Args)
+					books, total, err := catalog.ListBooks(filter, first, offset)
+					if err != nil {
+						return nil, resolveError(err)
+					}
+					return bookPage{Nodes: books, TotalCount: total, First: first, Offset: offset}, nil
+				},
+			},
+			"genre": &gql.Field{
+				Type: genreType,
+				Args: gql.FieldConfigArgument{"id": &gql.ArgumentConfig{Type: gql.NewNonNull(gql.ID)}},
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					id := p.Args["id"].(string)
+					return loadGenre(p, &id)
+				},
+			},
+			"genres": &gql.Field{
+				Type:        gql.NewNonNull(gql.NewList(gql.NewNonNull(genreType))),
+				Description: "Gêneros pelo nome; com rootsOnly, só os que não têm pai",
+				Args: gql.FieldConfigArgument{
+					"rootsOnly": &gql.ArgumentConfig{Type: gql.Boolean, DefaultValue: false},
+				},
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					genres, err := catalog.ListGenres()
+					if err != nil {
+						return nil, resolveError(err)
+					}
+					if rootsOnly, _ := p.Args["rootsOnly"].(bool); rootsOnly {
+						roots := []models.Genre{}
+						for _, g := range genres {
+							if g.ParentID == nil {
+								roots = append(roots, g)
+							}
+						}
+						genres = roots
+					}
+					return genres, nil
+				},
+			},
+		},
+	})
+
+	mutation := newMutationType(catalog, bookType, genreType)
+	return gql.NewSchema(gql.SchemaConfig{Query: query, Mutation: mutation})
+}
+
+// bookFilterFromArgs monta o filtro da busca de livros a partir dos argumentos
+func bookFilterFromArgs(args map[string]interface{}) models.BookFilter {
+	filter := models.BookFilter{}
+	filter.SortField, _ = args["sortBy"].(string)
+	filter.SortDesc = args["sortDirection"] == "desc"
+	input, _ := args["filter"].(map[string]interface{})
+	filter.Search, _ = input["search"].(string)
+	filter.GenreID, _ = input["genreId"].(string)
+	filter.IncludeDescendants, _ = input["includeDescendants"].(bool)
+	filter.AuthorID, _ = input["authorId"].(string)
+	filter.LowStock, _ = input["lowStock"].(bool)
+	if n, ok := input["minQuantity"].(int); ok {
+		filter.MinQuantity = &n
+	}
+	if n, ok := input["maxQuantity"].(int); ok {
+		filter.MaxQuantity = &n
+	}
+	return filter
+}
+
+// actor é o usuário autenticado que executa a mutação
+func actor(p gql.ResolveParams) string {
+	return middleware.GetUserID(p.Context)
+}
@@ -0,0 +1,55 @@
+package grpc
+
+import (
+	"context"
+	"projeto_livros/internal/delivery/middleware"
+	"strings"
+
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// authenticate faz para o gRPC o que middleware.OptionalAuth faz para o HTTP:
+// sem o metadado authorization a chamada segue anônima; com ele, o token
+// ("Bearer <jwt>") precisa ser válido e identifica o usuário e o papel.
+func authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 || values[0] == "" {
+		return ctx, nil
+	}
+	token := strings.TrimPrefix(values[0], "Bearer ")
+	claims, err := middleware.ParseToken(token)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return middleware.WithClaims(ctx, claims), nil
+}
+
+func unaryAuth(ctx context.Context, req interface{}, info *grpclib.UnaryServerInfo, handler grpclib.UnaryHandler) (interface{}, error) {
+	ctx, err := authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// authStream troca o contexto do stream pelo contexto autenticado
+type authStream struct {
+	grpclib.ServerStream
+	ctx context.Context
+}
+
+func (s *authStream) Context() context.Context {
+	return s.ctx
+}
+
+func streamAuth(srv interface{}, stream grpclib.ServerStream, info *grpclib.StreamServerInfo, handler grpclib.StreamHandler) error {
+	ctx, err := authenticate(stream.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authStream{ServerStream: stream, ctx: ctx})
+}
@@ -0,0 +1,127 @@
+package grpc
+
+import (
+	"projeto_livros/internal/domain/models"
+	services "projeto_livros/internal/usecase"
+	libraryv1 "projeto_livros/pkg/pb/library/v1"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func bookToProto(book *models.Book) *libraryv1.Book {
+	pb := &libraryv1.Book{
+		Id:              book.ID,
+		Name:            book.Name,
+		Author:          book.Author,
+		Quantity:        int32(book.Quantity),
+		MinQuantity:     optionalInt32(book.MinQuantity),
+		Isbn:            book.ISBN,
+		Isbn_10:         book.ISBN10,
+		Isbn_13:         book.ISBN13,
+		Publisher:       book.Publisher,
+		PublicationYear: optionalInt32(book.PublicationYear),
+		Edition:         book.Edition,
+		Subjects:        book.Subjects,
+	}
+	for _, author := range book.Authors {
+		pb.Authors = append(pb.Authors, &libraryv1.BookAuthor{Id: author.ID, Name: author.Name, Role: author.Role})
+	}
+	for _, genre := range book.Genres {
+		pb.GenreIds = append(pb.GenreIds, genre.ID)
+	}
+	if len(pb.GenreIds) == 0 && book.GenreID != nil {
+		pb.GenreIds = []string{*book.GenreID}
+	}
+	return pb
+}
+
+// setBookField copia o campo path de pb para o livro. Devolve false para
+// campos desconhecidos ou que não podem ser alterados.
+func setBookField(book *models.Book, rel *services.BookRelations, pb *libraryv1.Book, path string) bool {
+	switch path {
+	case "name":
+		book.Name = pb.GetName()
+	case "quantity":
+		book.Quantity = int(pb.GetQuantity())
+	case "min_quantity":
+		book.MinQuantity = optionalInt(pb.MinQuantity)
+	case "isbn":
+		book.ISBN, book.ISBN10, book.ISBN13 = pb.GetIsbn(), "", ""
+	case "publisher":
+		book.Publisher = pb.GetPublisher()
+	case "publication_year":
+		book.PublicationYear = optionalInt(pb.PublicationYear)
+	case "edition":
+		book.Edition = pb.GetEdition()
+	case "subjects":
+		book.Subjects = append([]string{}, pb.GetSubjects()...)
+	case "authors":
+		rel.Authors = []models.BookAuthor{}
+		for _, author := range pb.GetAuthors() {
+			rel.Authors = append(rel.Authors, models.BookAuthor{ID: author.GetId(), Name: author.GetName(), Role: author.GetRole()})
+		}
+	case "genre_ids":
+		rel.GenreIDs = append([]string{}, pb.GetGenreIds()...)
+	default:
+		return false
+	}
+	return true
+}
+
+// bookFields são os campos alterados por UpdateBook sem update_mask
+var bookFields = []string{"name", "quantity", "min_quantity", "isbn", "publisher", "publication_year",
+	"edition", "subjects", "authors", "genre_ids"}
+
+func genreToProto(genre *models.Genre) *libraryv1.Genre {
+	return &libraryv1.Genre{Id: genre.ID, Name: genre.Name, Description: genre.Description, ParentId: genre.ParentID}
+}
+
+// setGenreField copia o campo path de pb para o gênero
+func setGenreField(genre *models.Genre, pb *libraryv1.Genre, path string) bool {
+	switch path {
+	case "name":
+		genre.Name = pb.GetName()
+	case "description":
+		genre.Description = pb.GetDescription()
+	case "parent_id":
+		genre.ParentID = nil
+		if pb.ParentId != nil && *pb.ParentId != "" {
+			parentID := *pb.ParentId
+			genre.ParentID = &parentID
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// genreFields são os campos alterados por UpdateGenre sem update_mask
+var genreFields = []string{"name", "description", "parent_id"}
+
+func eventToProto(event models.Event) *libraryv1.ChangeEvent {
+	return &libraryv1.ChangeEvent{
+		Id:            event.ID,
+		Type:          event.Type,
+		AggregateType: event.AggregateType,
+		AggregateId:   event.AggregateID,
+		Actor:         event.Actor,
+		Payload:       event.Payload,
+		OccurredAt:    timestamppb.New(event.OccurredAt),
+	}
+}
+
+func optionalInt32(value *int) *int32 {
+	if value == nil {
+		return nil
+	}
+	n := int32(*value)
+	return &n
+}
+
+func optionalInt(value *int32) *int {
+	if value == nil {
+		return nil
+	}
+	n := int(*value)
+	return &n
+}
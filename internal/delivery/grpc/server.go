@@ -0,0 +1,337 @@
+package grpc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"projeto_livros/internal/delivery/middleware"
+	apperrors "projeto_livros/internal/domain/errors"
+	"projeto_livros/internal/domain/models"
+	"projeto_livros/internal/events"
+	services "projeto_livros/internal/usecase"
+	libraryv1 "projeto_livros/pkg/pb/library/v1"
+	"strconv"
+	"strings"
+
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// Server implementa o LibraryService sobre o mesmo CatalogService das APIs
+// REST e GraphQL, e WatchChanges sobre o broker do feed SSE
+type Server struct {
+	libraryv1.UnimplementedLibraryServiceServer
+	catalog *services.CatalogService
+	broker  *events.Broker
+}
+
+// NewServer monta o servidor gRPC com o LibraryService, a autenticação por
+// token e a reflexão (para grpcurl e afins)
+func NewServer(db *sql.DB, broker *events.Broker) *grpclib.Server {
+	server := grpclib.NewServer(
+		grpclib.ChainUnaryInterceptor(unaryAuth),
+		grpclib.ChainStreamInterceptor(streamAuth),
+	)
+	libraryv1.RegisterLibraryServiceServer(server, &Server{catalog: services.NewCatalogService(db), broker: broker})
+	reflection.Register(server)
+	return server
+}
+
+// statusError converte os erros do CatalogService em status gRPC. Erros que
+// não são de domínio vêm do banco: são registrados no log e devolvidos como
+// Internal com uma mensagem genérica.
+func statusError(err error) error {
+	var apiErr apperrors.APIError
+	if errors.As(err, &apiErr) {
+		code := codes.Internal
+		switch apiErr.Status {
+		case http.StatusBadRequest:
+			code = codes.InvalidArgument
+		case http.StatusNotFound:
+			code = codes.NotFound
+		case http.StatusConflict:
+			// Conflitos de unicidade viram AlreadyExists; os de estado
+			// (gênero em uso, livro com pedidos) viram FailedPrecondition
+			code = codes.FailedPrecondition
+			if apiErr.Code == apperrors.CodeConflict || strings.HasSuffix(apiErr.Code, "_CONFLICT") {
+				code = codes.AlreadyExists
+			}
+		case http.StatusForbidden:
+			code = codes.PermissionDenied
+		case http.StatusUnauthorized:
+			code = codes.Unauthenticated
+		case http.StatusServiceUnavailable:
+			code = codes.Unavailable
+		}
+		return status.Error(code, apiErr.Message)
+	}
+	var inUse *services.GenreInUseError
+	if errors.As(err, &inUse) {
+		return status.Errorf(codes.FailedPrecondition,
+			"O gênero possui %d livros. Informe reassign_to para transferi-los antes de remover", inUse.Books)
+	}
+	log.Printf("Erro na chamada gRPC: %v", err)
+	return status.Error(codes.Internal, "Erro interno do servidor")
+}
+
+func actor(ctx context.Context) string {
+	return middleware.GetUserID(ctx)
+}
+
+// requireAdminForHard recusa a remoção definitiva para quem não é administrador
+func requireAdminForHard(ctx context.Context, hard bool) error {
+	if hard && !middleware.IsAdmin(ctx) {
+		return status.Error(codes.PermissionDenied, "Apenas administradores podem apagar definitivamente")
+	}
+	return nil
+}
+
+// updatePaths devolve os campos de update_mask ou, sem máscara, todos os editáveis
+func updatePaths(paths, all []string) []string {
+	if len(paths) == 0 {
+		return all
+	}
+	return paths
+}
+
+func (s *Server) GetBook(ctx context.Context, req *libraryv1.GetBookRequest) (*libraryv1.Book, error) {
+	book, err := s.catalog.GetBook(req.GetId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return bookToProto(book), nil
+}
+
+var bookSortFields = map[libraryv1.BookSortField]string{
+	libraryv1.BookSortField_BOOK_SORT_FIELD_UNSPECIFIED:      "name",
+	libraryv1.BookSortField_BOOK_SORT_FIELD_NAME:             "name",
+	libraryv1.BookSortField_BOOK_SORT_FIELD_QUANTITY:         "quantity",
+	libraryv1.BookSortField_BOOK_SORT_FIELD_PUBLICATION_YEAR: "publication_year",
+}
+
+// ListBooks busca uma página de livros, com os autores e gêneros de todos os
+// livros da página carregados em duas consultas
+func (s *Server) ListBooks(ctx context.Context, req *libraryv1.ListBooksRequest) (*libraryv1.ListBooksResponse, error) {
+	pageSize := int(req.GetPageSize())
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+	if pageSize < 0 || pageSize > maxPageSize || req.GetOffset() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "page_size deve estar entre 1 e %d e offset não pode ser negativo", maxPageSize)
+	}
+	sortField, ok := bookSortFields[req.GetSortBy()]
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "sort_by inválido")
+	}
+	filter := models.BookFilter{
+		Search:             req.GetSearch(),
+		GenreID:            req.GetGenreId(),
+		IncludeDescendants: req.GetIncludeDescendants(),
+		AuthorID:           req.GetAuthorId(),
+		MinQuantity:        optionalInt(req.MinQuantity),
+		MaxQuantity:        optionalInt(req.MaxQuantity),
+		LowStock:           req.GetLowStock(),
+		SortField:          sortField,
+		SortDesc:           req.GetSortDesc(),
+	}
+	books, total, err := s.catalog.ListBooks(filter, pageSize, int(req.GetOffset()))
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	ids := make([]string, len(books))
+	for i, book := range books {
+		ids[i] = book.ID
+	}
+	authors, err := s.catalog.AuthorsByBooks(ids)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	genres, err := s.catalog.GenresByBooks(ids)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	resp := &libraryv1.ListBooksResponse{TotalCount: int32(total)}
+	for _, book := range books {
+		book.Authors = authors[book.ID]
+		book.Genres = nil
+		for _, genre := range genres[book.ID] {
+			book.Genres = append(book.Genres, models.BookGenre{ID: genre.ID, Name: genre.Name})
+		}
+		resp.Books = append(resp.Books, bookToProto(&book))
+	}
+	return resp, nil
+}
+
+func (s *Server) CreateBook(ctx context.Context, req *libraryv1.CreateBookRequest) (*libraryv1.Book, error) {
+	var book models.Book
+	var rel services.BookRelations
+	for _, path := range bookFields {
+		setBookField(&book, &rel, req.GetBook(), path)
+	}
+	book.Authors = rel.Authors
+	for _, id := range rel.GenreIDs {
+		book.Genres = append(book.Genres, models.BookGenre{ID: id})
+	}
+	if err := s.catalog.CreateBook(&book, actor(ctx)); err != nil {
+		return nil, statusError(err)
+	}
+	return bookToProto(&book), nil
+}
+
+func (s *Server) UpdateBook(ctx context.Context, req *libraryv1.UpdateBookRequest) (*libraryv1.Book, error) {
+	book, err := s.catalog.GetBook(req.GetId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	var rel services.BookRelations
+	for _, path := range updatePaths(req.GetUpdateMask().GetPaths(), bookFields) {
+		if !setBookField(book, &rel, req.GetBook(), path) {
+			return nil, status.Errorf(codes.InvalidArgument, "Campo desconhecido em update_mask: %s", path)
+		}
+	}
+	if err := s.catalog.UpdateBook(book, rel, actor(ctx)); err != nil {
+		return nil, statusError(err)
+	}
+	return bookToProto(book), nil
+}
+
+func (s *Server) DeleteBook(ctx context.Context, req *libraryv1.DeleteBookRequest) (*emptypb.Empty, error) {
+	if err := requireAdminForHard(ctx, req.GetHard()); err != nil {
+		return nil, err
+	}
+	if err := s.catalog.DeleteBook(req.GetId(), req.GetHard(), actor(ctx)); err != nil {
+		return nil, statusError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) AdjustStock(ctx context.Context, req *libraryv1.AdjustStockRequest) (*libraryv1.AdjustStockResponse, error) {
+	previous, quantity, err := s.catalog.AdjustStock(req.GetBookId(), int(req.GetDelta()), actor(ctx))
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &libraryv1.AdjustStockResponse{
+		BookId:           req.GetBookId(),
+		PreviousQuantity: int32(previous),
+		Quantity:         int32(quantity),
+	}, nil
+}
+
+func (s *Server) ListGenres(ctx context.Context, req *libraryv1.ListGenresRequest) (*libraryv1.ListGenresResponse, error) {
+	genres, err := s.catalog.ListGenres()
+	if err != nil {
+		return nil, statusError(err)
+	}
+	resp := &libraryv1.ListGenresResponse{}
+	for i := range genres {
+		if req.GetRootsOnly() && genres[i].ParentID != nil {
+			continue
+		}
+		resp.Genres = append(resp.Genres, genreToProto(&genres[i]))
+	}
+	return resp, nil
+}
+
+func (s *Server) GetGenre(ctx context.Context, req *libraryv1.GetGenreRequest) (*libraryv1.Genre, error) {
+	genre, err := s.catalog.GetGenre(req.GetId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return genreToProto(genre), nil
+}
+
+func (s *Server) CreateGenre(ctx context.Context, req *libraryv1.CreateGenreRequest) (*libraryv1.Genre, error) {
+	var genre models.Genre
+	for _, path := range genreFields {
+		setGenreField(&genre, req.GetGenre(), path)
+	}
+	if err := s.catalog.CreateGenre(&genre, actor(ctx)); err != nil {
+		return nil, statusError(err)
+	}
+	return genreToProto(&genre), nil
+}
+
+func (s *Server) UpdateGenre(ctx context.Context, req *libraryv1.UpdateGenreRequest) (*libraryv1.Genre, error) {
+	genre, err := s.catalog.GetGenre(req.GetId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	for _, path := range updatePaths(req.GetUpdateMask().GetPaths(), genreFields) {
+		if !setGenreField(genre, req.GetGenre(), path) {
+			return nil, status.Errorf(codes.InvalidArgument, "Campo desconhecido em update_mask: %s", path)
+		}
+	}
+	if err := s.catalog.UpdateGenre(genre, actor(ctx)); err != nil {
+		return nil, statusError(err)
+	}
+	return genreToProto(genre), nil
+}
+
+func (s *Server) DeleteGenre(ctx context.Context, req *libraryv1.DeleteGenreRequest) (*libraryv1.DeleteGenreResponse, error) {
+	if err := requireAdminForHard(ctx, req.GetHard()); err != nil {
+		return nil, err
+	}
+	moved, err := s.catalog.DeleteGenre(req.GetId(), req.GetReassignTo(), req.GetHard(), actor(ctx))
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &libraryv1.DeleteGenreResponse{MovedBooks: moved}, nil
+}
+
+// WatchChanges envia os eventos do outbox que atendem aos tópicos, como o
+// feed SSE: primeiro o reset (se não foi possível retomar) e os eventos
+// posteriores a last_event_id, depois os novos eventos. Um cliente que não
+// acompanha o ritmo recebe Unavailable e deve retomar pelo último id recebido.
+func (s *Server) WatchChanges(req *libraryv1.WatchChangesRequest, stream libraryv1.LibraryService_WatchChangesServer) error {
+	filter := events.Filter{Topics: req.GetTopics()}
+	for _, topic := range filter.Topics {
+		if !events.ValidTopic(topic) {
+			return status.Errorf(codes.InvalidArgument,
+				"Tópico inválido: %s. Use books, genres, stock, book:<id>, genre:<id> ou type:<tipo>", topic)
+		}
+	}
+	lastEventID := ""
+	if req.GetLastEventId() > 0 {
+		lastEventID = strconv.FormatInt(req.GetLastEventId(), 10)
+	}
+
+	sub, replay, resumed := s.broker.Subscribe(filter, lastEventID)
+	defer sub.Close()
+	if !resumed {
+		if err := stream.Send(&libraryv1.WatchChangesResponse{Kind: &libraryv1.WatchChangesResponse_Reset_{Reset_: true}}); err != nil {
+			return err
+		}
+	}
+	send := func(event models.Event) error {
+		return stream.Send(&libraryv1.WatchChangesResponse{Kind: &libraryv1.WatchChangesResponse_Event{Event: eventToProto(event)}})
+	}
+	for _, event := range replay {
+		if err := send(event); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event, ok := <-sub.Events():
+			if !ok {
+				return status.Error(codes.Unavailable, "Assinatura encerrada por atraso; retome com last_event_id")
+			}
+			if err := send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
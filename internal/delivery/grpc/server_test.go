@@ -0,0 +1,190 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"projeto_livros/internal/delivery/middleware"
+	"projeto_livros/internal/domain/models"
+	"projeto_livros/internal/events"
+	libraryv1 "projeto_livros/pkg/pb/library/v1"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newTestClient sobe o servidor em memória e devolve a conexão do cliente
+func newTestClient(t *testing.T, broker *events.Broker) (*grpclib.ClientConn, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Erro ao criar mock do banco de dados: %v", err)
+	}
+	listener := bufconn.Listen(1 << 20)
+	server := NewServer(db, broker)
+	go server.Serve(listener)
+	conn, err := grpclib.NewClient("passthrough:///bufnet",
+		grpclib.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpclib.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Erro ao conectar: %v", err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		server.Stop()
+		db.Close()
+	})
+	return conn, mock
+}
+
+func withToken(t *testing.T, role string) context.Context {
+	token, err := middleware.GenerateTokenWithRole("usuario-1", role)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func TestGetBookNotFound(t *testing.T) {
+	conn, mock := newTestClient(t, events.NewBroker(10))
+	mock.ExpectQuery("SELECT (.+) FROM livros l WHERE l.id = \\$1").WithArgs("nao-existe").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := libraryv1.NewLibraryServiceClient(conn).GetBook(context.Background(), &libraryv1.GetBookRequest{Id: "nao-existe"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("esperava NotFound, obteve %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas não atendidas: %s", err)
+	}
+}
+
+func TestAuthInterceptor(t *testing.T) {
+	conn, mock := newTestClient(t, events.NewBroker(10))
+	client := libraryv1.NewLibraryServiceClient(conn)
+
+	invalid := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer token-invalido")
+	if _, err := client.DeleteBook(invalid, &libraryv1.DeleteBookRequest{Id: "1"}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("token inválido: esperava Unauthenticated, obteve %v", err)
+	}
+	if _, err := client.DeleteBook(context.Background(), &libraryv1.DeleteBookRequest{Id: "1", Hard: true}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("anônimo com hard: esperava PermissionDenied, obteve %v", err)
+	}
+	if _, err := client.DeleteBook(withToken(t, ""), &libraryv1.DeleteBookRequest{Id: "1", Hard: true}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("usuário comum com hard: esperava PermissionDenied, obteve %v", err)
+	}
+
+	// O administrador passa pela verificação e chega ao banco
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM livros l WHERE l.id = \\$1 FOR UPDATE").WithArgs("1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+	if _, err := client.DeleteBook(withToken(t, middleware.RoleAdmin), &libraryv1.DeleteBookRequest{Id: "1", Hard: true}); status.Code(err) != codes.NotFound {
+		t.Errorf("administrador: esperava NotFound, obteve %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas não atendidas: %s", err)
+	}
+}
+
+func TestAdjustStockUnderflow(t *testing.T) {
+	conn, mock := newTestClient(t, events.NewBroker(10))
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT quantity FROM livros WHERE id = \\$1 AND deleted_at IS NULL FOR UPDATE").WithArgs("1").
+		WillReturnRows(sqlmock.NewRows([]string{"quantity"}).AddRow(2))
+	mock.ExpectRollback()
+
+	_, err := libraryv1.NewLibraryServiceClient(conn).AdjustStock(context.Background(),
+		&libraryv1.AdjustStockRequest{BookId: "1", Delta: -3})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("esperava InvalidArgument, obteve %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas não atendidas: %s", err)
+	}
+}
+
+func TestWatchChangesReplayAndLive(t *testing.T) {
+	broker := events.NewBroker(10)
+	stock, _ := json.Marshal(models.StockChangedPayload{BookID: "b1", From: 1, To: 3, Delta: 2})
+	broker.Publish(models.Event{ID: 1, Type: models.EventBookCreated, AggregateType: models.AggregateBook, AggregateID: "b1"})
+	broker.Publish(models.Event{ID: 2, Type: models.EventStockChanged, AggregateType: models.AggregateBook, AggregateID: "b1", Payload: stock})
+	broker.Publish(models.Event{ID: 3, Type: models.EventGenreCreated, AggregateType: models.AggregateGenre, AggregateID: "g1"})
+	conn, _ := newTestClient(t, broker)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := libraryv1.NewLibraryServiceClient(conn).WatchChanges(ctx,
+		&libraryv1.WatchChangesRequest{Topics: []string{"books"}, LastEventId: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.GetEvent().GetId() != 2 || string(first.GetEvent().GetPayload()) != string(stock) {
+		t.Errorf("esperava o evento 2 do histórico, obteve %v", first)
+	}
+
+	// O assinante já está registrado quando o histórico chega ao cliente
+	broker.Publish(models.Event{ID: 4, Type: models.EventGenreUpdated, AggregateType: models.AggregateGenre, AggregateID: "g1"})
+	broker.Publish(models.Event{ID: 5, Type: models.EventBookUpdated, AggregateType: models.AggregateBook, AggregateID: "b1"})
+	live, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if live.GetEvent().GetId() != 5 {
+		t.Errorf("esperava o evento 5 (o 4 não é de livro), obteve %v", live)
+	}
+
+	// Um id fora do histórico começa com reset
+	reset, err := libraryv1.NewLibraryServiceClient(conn).WatchChanges(ctx, &libraryv1.WatchChangesRequest{LastEventId: 99})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg, err := reset.Recv(); err != nil || !msg.GetReset_() {
+		t.Errorf("esperava reset, obteve %v (%v)", msg, err)
+	}
+
+	invalid, err := libraryv1.NewLibraryServiceClient(conn).WatchChanges(ctx, &libraryv1.WatchChangesRequest{Topics: []string{"autores"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := invalid.Recv(); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("tópico inválido: esperava InvalidArgument, obteve %v", err)
+	}
+}
+
+func TestReflectionListsLibraryService(t *testing.T) {
+	conn, _ := newTestClient(t, events.NewBroker(10))
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.Send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, service := range resp.GetListServicesResponse().GetService() {
+		if service.GetName() == "library.v1.LibraryService" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("library.v1.LibraryService não aparece na reflexão: %v", resp)
+	}
+}
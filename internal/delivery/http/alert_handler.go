@@ -0,0 +1,111 @@
+package http
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"projeto_livros/internal/delivery/middleware"
+	apperrors "projeto_livros/internal/domain/errors"
+	"projeto_livros/internal/domain/models"
+	"projeto_livros/internal/notify"
+	repositories "projeto_livros/internal/repository"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type AlertHandler struct {
+	alerts repositories.AlertRepository
+	digest *notify.DigestJob
+}
+
+// NewAlertHandler cria o handler de alertas. digest pode ser nil quando o
+// resumo diário não estiver configurado; nesse caso o envio manual responde 503.
+func NewAlertHandler(db *sql.DB, digest *notify.DigestJob) *AlertHandler {
+	return &AlertHandler{alerts: repositories.NewPostgresAlertRepository(db), digest: digest}
+}
+
+// GetLowStockAlerts lista os alertas de estoque baixo. status pode ser open
+// (padrão), acknowledged, resolved ou all.
+func (h *AlertHandler) GetLowStockAlerts(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	status := r.URL.Query().Get("status")
+	if status == "" {
+		status = models.AlertStatusOpen
+	}
+	switch status {
+	case models.AlertStatusOpen, models.AlertStatusAcknowledged, models.AlertStatusResolved, models.AlertStatusAll:
+	default:
+		sendError(w, r, apperrors.CodeInvalidParameter, "status", "open, acknowledged, resolved, all")
+		return
+	}
+
+	page := 1
+	perPage := 20
+	if p, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && p > 0 {
+		page = p
+	}
+	if pp, err := strconv.Atoi(r.URL.Query().Get("per_page")); err == nil && pp > 0 {
+		perPage = pp
+	}
+
+	alerts, total, err := h.alerts.FindLowStock(status, perPage, (page-1)*perPage)
+	if err != nil {
+		log.Printf("Erro ao buscar alertas de estoque baixo: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data":         alerts,
+		"page":         page,
+		"per_page":     perPage,
+		"total_alerts": total,
+		"total_pages":  (total + perPage - 1) / perPage,
+	})
+}
+
+// AcknowledgeAlert marca um alerta como reconhecido pelo usuário autenticado
+func (h *AlertHandler) AcknowledgeAlert(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id := chi.URLParam(r, "id")
+	rowsAffected, err := h.alerts.Acknowledge(id, middleware.GetUserID(r.Context()))
+	if err != nil {
+		log.Printf("Erro ao reconhecer alerta %s: %v", id, err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+	if rowsAffected == 0 {
+		sendError(w, r, apperrors.CodeAlertNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Alerta reconhecido com sucesso"})
+}
+
+// SendDigest envia o resumo dos alertas em aberto imediatamente, sem esperar o horário diário
+func (h *AlertHandler) SendDigest(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if h.digest == nil {
+		sendError(w, r, apperrors.CodeDigestNotConfigured)
+		return
+	}
+	total, err := h.digest.Send(r.Context())
+	if err != nil {
+		log.Printf("Erro ao enviar resumo de estoque baixo: %v", err)
+		sendError(w, r, apperrors.CodeDigestFailed)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":     "Resumo enviado",
+		"open_alerts": total,
+	})
+}
@@ -0,0 +1,115 @@
+package http
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"projeto_livros/internal/delivery/middleware"
+	apperrors "projeto_livros/internal/domain/errors"
+	"projeto_livros/internal/domain/models"
+	repositories "projeto_livros/internal/repository"
+	"strconv"
+	"time"
+)
+
+type AuditHandler struct {
+	audit repositories.AuditRepository
+}
+
+func NewAuditHandler(db *sql.DB) *AuditHandler {
+	return &AuditHandler{audit: repositories.NewPostgresAuditRepository(db)}
+}
+
+// AuditLoaders devolve, para cada entidade da API, a função que lê o seu
+// estado atual, usada pelo middleware de auditoria para o antes e o depois
+func AuditLoaders(db *sql.DB) map[string]middleware.AuditLoader {
+	return map[string]middleware.AuditLoader{
+		"books":           auditLoader(repositories.NewPostgresBookRepository(db).FindByID),
+		"genres":          auditLoader(repositories.NewPostgresGenreRepository(db).FindByID),
+		"authors":         auditLoader(repositories.NewPostgresAuthorRepository(db).FindByID),
+		"suppliers":       auditLoader(repositories.NewPostgresSupplierRepository(db).FindByID),
+		"purchase-orders": auditLoader(repositories.NewPostgresPurchaseOrderRepository(db).FindByID),
+		// FindByID não lê o segredo, que assim não vai para o log
+		"webhooks": auditLoader(repositories.NewPostgresWebhookRepository(db).FindByID),
+	}
+}
+
+// auditLoader adapta um FindByID, tratando registro inexistente como estado vazio
+func auditLoader[T any](find func(id string) (*T, error)) middleware.AuditLoader {
+	return func(id string) (interface{}, error) {
+		found, err := find(id)
+		if err == sql.ErrNoRows {
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+		return found, nil
+	}
+}
+
+// GetAuditLog consulta o log de auditoria, das entradas mais recentes para as
+// mais antigas. Apenas administradores. Filtros: actor, entity, entity_id,
+// method, outcome, request_id e o intervalo from/to (RFC 3339).
+func (h *AuditHandler) GetAuditLog(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if !middleware.IsAdmin(r.Context()) {
+		sendError(w, r, apperrors.CodeAdminRequired)
+		return
+	}
+
+	q := r.URL.Query()
+	filter := models.AuditFilter{
+		Actor:     q.Get("actor"),
+		Entity:    q.Get("entity"),
+		EntityID:  q.Get("entity_id"),
+		Method:    q.Get("method"),
+		Outcome:   q.Get("outcome"),
+		RequestID: q.Get("request_id"),
+	}
+	if filter.Outcome != "" && filter.Outcome != models.AuditOutcomeSuccess && filter.Outcome != models.AuditOutcomeFailure {
+		sendError(w, r, apperrors.CodeInvalidParameter, "outcome", "success, failure")
+		return
+	}
+	for _, bound := range []struct {
+		param string
+		dest  **time.Time
+	}{{"from", &filter.From}, {"to", &filter.To}} {
+		value := q.Get(bound.param)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			sendError(w, r, apperrors.CodeInvalidDate, bound.param)
+			return
+		}
+		*bound.dest = &t
+	}
+
+	page := 1
+	perPage := 50
+	if p, err := strconv.Atoi(q.Get("page")); err == nil && p > 0 {
+		page = p
+	}
+	if pp, err := strconv.Atoi(q.Get("per_page")); err == nil && pp > 0 {
+		perPage = pp
+	}
+
+	entries, total, err := h.audit.FindAll(filter, perPage, (page-1)*perPage)
+	if err != nil {
+		log.Printf("Erro ao consultar log de auditoria: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data":        entries,
+		"page":        page,
+		"per_page":    perPage,
+		"total_items": total,
+		"total_pages": (total + perPage - 1) / perPage,
+	})
+}
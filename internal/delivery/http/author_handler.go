@@ -0,0 +1,200 @@
+package http
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	apperrors "projeto_livros/internal/domain/errors"
+	"projeto_livros/internal/domain/models"
+	"projeto_livros/internal/domain/validators"
+	repositories "projeto_livros/internal/repository"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type AuthorHandler struct {
+	db      *sql.DB
+	authors repositories.AuthorRepository
+}
+
+func NewAuthorHandler(db *sql.DB) *AuthorHandler {
+	return &AuthorHandler{db: db, authors: repositories.NewPostgresAuthorRepository(db)}
+}
+
+// decodeAuthor lê e valida o corpo de criação/atualização de autor
+func decodeAuthor(w http.ResponseWriter, r *http.Request) (*models.Author, bool) {
+	var author models.Author
+	if err := json.NewDecoder(r.Body).Decode(&author); err != nil {
+		log.Printf("Erro ao decodificar JSON: %v", err)
+		sendError(w, r, apperrors.CodeInvalidBody)
+		return nil, false
+	}
+	if author.Name, _ = validators.NormalizeAuthorName(author.Name); author.Name == "" {
+		sendError(w, r, apperrors.CodeAuthorNameRequired)
+		return nil, false
+	}
+	return &author, true
+}
+
+// GetAllAuthors lista os autores, com busca por nome (q) e paginação
+func (h *AuthorHandler) GetAllAuthors(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	page := 1
+	perPage := 20
+	if p, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && p > 0 {
+		page = p
+	}
+	if pp, err := strconv.Atoi(r.URL.Query().Get("per_page")); err == nil && pp > 0 {
+		perPage = pp
+	}
+
+	authors, total, err := h.authors.FindAll(r.URL.Query().Get("q"), perPage, (page-1)*perPage)
+	if err != nil {
+		log.Printf("Erro ao buscar autores: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"data":          authors,
+		"page":          page,
+		"per_page":      perPage,
+		"total_authors": total,
+		"total_pages":   (total + perPage - 1) / perPage,
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *AuthorHandler) GetAuthor(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	author, err := h.authors.FindByID(chi.URLParam(r, "id"))
+	if err == sql.ErrNoRows {
+		sendError(w, r, apperrors.CodeAuthorNotFound)
+		return
+	} else if err != nil {
+		log.Printf("Erro ao buscar autor: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(author)
+}
+
+func (h *AuthorHandler) CreateAuthor(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	author, ok := decodeAuthor(w, r)
+	if !ok {
+		return
+	}
+	if err := h.authors.Create(author); err != nil {
+		if repositories.IsUniqueViolation(err) {
+			sendError(w, r, apperrors.CodeAuthorNameConflict)
+			return
+		}
+		log.Printf("Erro ao criar autor: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(author)
+}
+
+// UpdateAuthor renomeia o autor; o campo author dos seus livros é atualizado junto
+func (h *AuthorHandler) UpdateAuthor(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	author, ok := decodeAuthor(w, r)
+	if !ok {
+		return
+	}
+	author.ID = chi.URLParam(r, "id")
+
+	var rowsAffected int64
+	err := repositories.RunInTx(h.db, func(tx *sql.Tx) error {
+		var err error
+		rowsAffected, err = h.authors.WithTx(tx).Update(author)
+		return err
+	})
+	if err != nil {
+		if repositories.IsUniqueViolation(err) {
+			sendError(w, r, apperrors.CodeAuthorNameConflict)
+			return
+		}
+		log.Printf("Erro ao atualizar autor: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+	if rowsAffected == 0 {
+		sendError(w, r, apperrors.CodeAuthorNotFound)
+		return
+	}
+
+	updated, err := h.authors.FindByID(author.ID)
+	if err != nil {
+		log.Printf("Erro ao buscar autor atualizado: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(updated)
+}
+
+// DeleteAuthor remove um autor que não esteja vinculado a nenhum livro
+func (h *AuthorHandler) DeleteAuthor(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	rowsAffected, err := h.authors.Delete(chi.URLParam(r, "id"))
+	if err != nil {
+		// O autor ainda está em book_authors
+		if repositories.IsForeignKeyViolation(err) {
+			sendError(w, r, apperrors.CodeAuthorInUse)
+			return
+		}
+		log.Printf("Erro ao remover autor: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+	if rowsAffected == 0 {
+		sendError(w, r, apperrors.CodeAuthorNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Autor removido com sucesso"})
+}
+
+// GetAuthorBooks lista os livros do autor com o papel dele em cada um
+func (h *AuthorHandler) GetAuthorBooks(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	author, err := h.authors.FindByID(chi.URLParam(r, "id"))
+	if err == sql.ErrNoRows {
+		sendError(w, r, apperrors.CodeAuthorNotFound)
+		return
+	} else if err != nil {
+		log.Printf("Erro ao buscar autor: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+
+	books, err := h.authors.FindBooks(author.ID)
+	if err != nil {
+		log.Printf("Erro ao buscar livros do autor: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"author": author,
+		"books":  books,
+	})
+}
@@ -2,46 +2,107 @@ package http
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"projeto_livros/internal/delivery/middleware"
+	"projeto_livros/internal/delivery/problem"
+	apperrors "projeto_livros/internal/domain/errors"
 	"projeto_livros/internal/domain/models"
+	"projeto_livros/internal/domain/validators"
+	"projeto_livros/internal/metadata"
+	repositories "projeto_livros/internal/repository"
+	services "projeto_livros/internal/usecase"
 	"strconv"
 	"strings"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/segmentio/ksuid"
 )
 
 type BookHandler struct {
-	db *sql.DB
+	db        *sql.DB
+	books     repositories.BookRepository
+	authors   repositories.AuthorRepository
+	genres    repositories.GenreRepository
+	revisions repositories.RevisionRepository
+	catalog   *services.CatalogService
+	metadata  metadata.Provider
 }
 
 type IDRequest struct {
 	ID string `json:"id"`
 }
 
-type ErrorResponse struct {
-	Error string `json:"error"`
-	Code  int    `json:"code"`
+// sendError envia o problema (application/problem+json) de um código do
+// catálogo de erros; args preenchem a mensagem de detalhe
+func sendError(w http.ResponseWriter, r *http.Request, code string, args ...interface{}) {
+	problem.Send(w, r, code, args...)
 }
 
-// Função auxiliar para enviar respostas de erro em formato JSON
-func sendErrorResponse(w http.ResponseWriter, message string, statusCode int) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	err := ErrorResponse{
-		Error: message,
-		Code:  statusCode,
+// sendServiceError envia um erro devolvido pelo CatalogService ou pelos
+// validadores: erros do catálogo vão com o próprio código; os demais são
+// registrados no log com message e enviados como INTERNAL_ERROR
+func sendServiceError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	problem.SendError(w, r, err, message)
+}
+
+// sendISBNConflict envia o 409 de ISBN duplicado, com o id do livro que já
+// usa o ISBN quando ele é conhecido
+func sendISBNConflict(w http.ResponseWriter, r *http.Request, existingID string) {
+	p := problem.New(r, apperrors.CodeISBNConflict)
+	if existingID != "" {
+		p.With("existing_id", existingID)
+	}
+	problem.Write(w, p)
+}
+
+// sendBookError envia um erro da gravação de livros no CatalogService, como
+// sendServiceError; o 409 de ISBN duplicado leva o id do livro que já usa o
+// ISBN
+func sendBookError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	var conflict *services.ISBNConflictError
+	if errors.As(err, &conflict) {
+		sendISBNConflict(w, r, conflict.ExistingID)
+		return
 	}
-	json.NewEncoder(w).Encode(err)
+	sendServiceError(w, r, err, message)
 }
 
 func NewBookHandler(db *sql.DB) *BookHandler {
-	return &BookHandler{db: db}
+	return &BookHandler{
+		db:        db,
+		books:     repositories.NewPostgresBookRepository(db),
+		authors:   repositories.NewPostgresAuthorRepository(db),
+		genres:    repositories.NewPostgresGenreRepository(db),
+		revisions: repositories.NewPostgresRevisionRepository(db),
+		catalog:   services.NewCatalogService(db),
+	}
+}
+
+// SetMetadataProvider habilita a opção enrich=true na criação de livros
+func (h *BookHandler) SetMetadataProvider(provider metadata.Provider) {
+	h.metadata = provider
+}
+
+// enrichBook completa o livro com os metadados do seu ISBN. Falhas na consulta
+// não impedem a criação: o livro segue apenas com os dados informados.
+func (h *BookHandler) enrichBook(ctx context.Context, book *models.Book) {
+	if h.metadata == nil || book.ISBN == "" {
+		return
+	}
+	meta, err := h.metadata.LookupISBN(ctx, book.ISBN)
+	if err != nil {
+		if err != metadata.ErrNotFound {
+			log.Printf("Erro ao consultar metadados do ISBN %s: %v", book.ISBN, err)
+		}
+		return
+	}
+	metadata.Enrich(book, meta)
 }
 
 func (h *BookHandler) CreateBook(w http.ResponseWriter, r *http.Request) {
@@ -49,53 +110,27 @@ func (h *BookHandler) CreateBook(w http.ResponseWriter, r *http.Request) {
 	var book models.Book
 	if err := json.NewDecoder(r.Body).Decode(&book); err != nil {
 		log.Printf("Erro ao decodificar JSON: %v", err)
-		sendErrorResponse(w, "Erro ao ler dados do livro", http.StatusBadRequest)
+		sendError(w, r, apperrors.CodeInvalidBody)
 		return
 	}
 
-	// Usar Title como fallback para Name se necessário
-	if book.Name == "" && book.Title != "" {
-		book.Name = book.Title
-	}
-
-	if book.Name == "" || book.Quantity <= 0 {
-		sendErrorResponse(w, "Nome vazio ou quantidade inválida. A quantidade deve ser maior que zero.", http.StatusBadRequest)
-		return
-	}
-	if book.GenreID != nil {
-		var count int
-		err := h.db.QueryRow("SELECT COUNT(*) FROM genres WHERE id = $1", book.GenreID).Scan(&count)
-		if err != nil || count == 0 {
-			sendErrorResponse(w, "Gênero não encontrado", http.StatusBadRequest)
+	// Com enrich=true, os campos vazios são preenchidos pelos metadados do ISBN
+	// antes da validação, para que o título possa vir do provedor
+	if r.URL.Query().Get("enrich") == "true" {
+		if err := validators.NormalizeBookISBN(&book); err != nil {
+			sendServiceError(w, r, err, "Erro ao validar ISBN")
 			return
 		}
+		h.enrichBook(r.Context(), &book)
 	}
 
-	book.ID = ksuid.New().String()
-
 	log.Printf("Tentando criar livro: %s, Autor: %s", book.Name, book.Author)
 
-	// Logging detalhado para debug
-	log.Printf("DEBUG - Criando livro - Título: %s", book.Name)
-	// Adicionar no handler CreateBook
-	log.Printf("DEBUG - Quantidade recebida: %v (tipo: %T)", book.Quantity, book.Quantity)
-	log.Printf("DEBUG - Quantidade recebida para criação: %d (tipo: %T)", book.Quantity, book.Quantity)
-
-	// Incluir a coluna author na consulta SQL
-	query := `INSERT INTO livros (id, name, quantity, genre_id, author) VALUES ($1, $2, $3, $4, $5) RETURNING id`
-
-	log.Printf("Executando consulta SQL: %s", query)
-
-	var returnedID string
-	if err := h.db.QueryRow(query, book.ID, book.Name, book.Quantity, book.GenreID, book.Author).Scan(&returnedID); err != nil {
-		log.Printf("Erro ao inserir livro: %v", err)
-		sendErrorResponse(w, "Erro ao criar livro", http.StatusInternalServerError)
+	if err := h.catalog.CreateBook(&book, middleware.GetUserID(r.Context())); err != nil {
+		sendBookError(w, r, err, "Erro ao inserir livro")
 		return
 	}
 
-	// Não precisamos mais definir book.Title = book.Name aqui
-	// pois o frontend espera o campo Title
-
 	log.Printf("Livro criado com sucesso: %s, ID: %s, Autor: %s", book.Name, book.ID, book.Author)
 
 	w.WriteHeader(http.StatusCreated)
@@ -151,10 +186,11 @@ func (h *BookHandler) GetAllBooks(w http.ResponseWriter, r *http.Request) {
 
 	// IMPORTANTE: Buscar livros SEM MODIFICAR os valores originais
 	query := fmt.Sprintf(`
-		SELECT id, name, quantity, genre_id, author 
-		FROM livros 
-		ORDER BY %s 
-		LIMIT $1 OFFSET $2`, orderClause)
+		SELECT %s
+		FROM livros l
+		WHERE l.deleted_at IS NULL
+		ORDER BY %s
+		LIMIT $1 OFFSET $2`, repositories.BookColumns, orderClause)
 
 	log.Printf("DEBUG - LISTAGEM: Executando query SQL: %s", query)
 
@@ -162,7 +198,7 @@ func (h *BookHandler) GetAllBooks(w http.ResponseWriter, r *http.Request) {
 	rows, err := h.db.Query(query, perPage, offset)
 	if err != nil {
 		log.Printf("Erro ao buscar livros: %v", err)
-		sendErrorResponse(w, "Erro ao buscar livros", http.StatusInternalServerError)
+		sendError(w, r, apperrors.CodeInternalError)
 		return
 	}
 	defer rows.Close()
@@ -170,45 +206,33 @@ func (h *BookHandler) GetAllBooks(w http.ResponseWriter, r *http.Request) {
 	// Processar os resultados
 	var books []models.Book
 	for rows.Next() {
-		var book models.Book
-		var authorNull sql.NullString
-
 		// Log para cada linha lida do banco
-		if err := rows.Scan(&book.ID, &book.Name, &book.Quantity, &book.GenreID, &authorNull); err != nil {
+		book, err := repositories.ScanBook(rows)
+		if err != nil {
 			log.Printf("Erro ao ler dados do livro: %v", err)
-			sendErrorResponse(w, "Erro ao ler dados dos livros", http.StatusInternalServerError)
+			sendError(w, r, apperrors.CodeInternalError)
 			return
 		}
 
-		// Converter author de NullString para string
-		if authorNull.Valid {
-			book.Author = authorNull.String
-		} else {
-			book.Author = ""
-		}
-
-		// Definir Title igual a Name para compatibilidade com o frontend
-		book.Title = book.Name
-
 		// Log detalhado para cada livro encontrado na busca
 		log.Printf("DEBUG - LISTAGEM: Livro encontrado - ID: %s, Nome: %s, Quantidade (direto do banco): %d",
 			book.ID, book.Name, book.Quantity)
 
-		books = append(books, book)
+		books = append(books, *book)
 	}
 
 	// Verificar erros após a iteração
 	if err := rows.Err(); err != nil {
 		log.Printf("Erro ao iterar sobre os resultados: %v", err)
-		sendErrorResponse(w, "Erro ao processar dados dos livros", http.StatusInternalServerError)
+		sendError(w, r, apperrors.CodeInternalError)
 		return
 	}
 
 	// Contar o total de livros para paginação
 	var totalBooks int
-	if err := h.db.QueryRow("SELECT COUNT(*) FROM livros").Scan(&totalBooks); err != nil {
+	if err := h.db.QueryRow("SELECT COUNT(*) FROM livros WHERE deleted_at IS NULL").Scan(&totalBooks); err != nil {
 		log.Printf("Erro ao contar livros: %v", err)
-		sendErrorResponse(w, "Erro ao contar livros", http.StatusInternalServerError)
+		sendError(w, r, apperrors.CodeInternalError)
 		return
 	}
 
@@ -253,7 +277,7 @@ func (h *BookHandler) GetBook(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if id == "" {
-		sendErrorResponse(w, "ID do livro não fornecido", http.StatusBadRequest)
+		sendError(w, r, apperrors.CodeBookIDRequired)
 		return
 	}
 
@@ -263,33 +287,26 @@ func (h *BookHandler) GetBook(w http.ResponseWriter, r *http.Request) {
 
 	log.Printf("Buscando livro com ID (limpo): %s", id)
 
-	// Incluir a coluna author na consulta SQL
-	query := "SELECT id, name, quantity, genre_id, author FROM livros WHERE id = $1"
-
-	log.Printf("Executando consulta SQL: %s", query)
-
-	var book models.Book
-	var authorNull sql.NullString
-	err := h.db.QueryRow(query, id).Scan(&book.ID, &book.Name, &book.Quantity, &book.GenreID, &authorNull)
-
+	book, err := h.books.FindByID(id)
 	if err == sql.ErrNoRows {
-		sendErrorResponse(w, "Livro não encontrado", http.StatusNotFound)
+		sendError(w, r, apperrors.CodeBookNotFound)
 		return
 	} else if err != nil {
 		log.Printf("Erro ao buscar livro: %v", err)
-		sendErrorResponse(w, "Erro ao buscar livro", http.StatusInternalServerError)
+		sendError(w, r, apperrors.CodeInternalError)
 		return
 	}
 
-	// Converter author de NullString para string
-	if authorNull.Valid {
-		book.Author = authorNull.String
-	} else {
-		book.Author = ""
+	if book.Authors, err = h.authors.FindBookAuthors(book.ID); err != nil {
+		log.Printf("Erro ao buscar autores do livro: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+	if book.Genres, err = h.genres.FindBookGenres(book.ID); err != nil {
+		log.Printf("Erro ao buscar gêneros do livro: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
 	}
-
-	// Garantir que title seja igual a name para compatibilidade
-	book.Title = book.Name
 
 	log.Printf("Livro encontrado com sucesso: %s", book.Name)
 
@@ -297,6 +314,30 @@ func (h *BookHandler) GetBook(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(book)
 }
 
+// GetBookByISBN busca um livro pelo ISBN, aceitando ISBN-10 ou ISBN-13 com ou sem hífens
+func (h *BookHandler) GetBookByISBN(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	isbn, err := validators.NormalizeISBN(chi.URLParam(r, "isbn"))
+	if err != nil {
+		sendServiceError(w, r, err, "Erro ao validar ISBN")
+		return
+	}
+
+	book, err := h.books.FindByISBN(isbn)
+	if err == sql.ErrNoRows {
+		sendError(w, r, apperrors.CodeBookNotFound)
+		return
+	} else if err != nil {
+		log.Printf("Erro ao buscar livro por ISBN: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(book)
+}
+
 func (h *BookHandler) DeleteBook(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
@@ -313,29 +354,67 @@ func (h *BookHandler) DeleteBook(w http.ResponseWriter, r *http.Request) {
 		var req IDRequest
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			log.Printf("Erro ao decodificar JSON: %v", err)
-			sendErrorResponse(w, "Erro ao ler dados", http.StatusBadRequest)
+			sendError(w, r, apperrors.CodeInvalidBody)
 			return
 		}
 		id = req.ID
 	}
 
 	if id == "" {
-		sendErrorResponse(w, "ID não fornecido", http.StatusBadRequest)
+		sendError(w, r, apperrors.CodeBookIDRequired)
 		return
 	}
 
-	result, err := h.db.Exec("DELETE FROM livros WHERE id = $1", id)
+	// Por padrão o livro vai para a lixeira; hard=true (apenas administradores)
+	// apaga definitivamente, inclusive livros que já estão na lixeira
+	hard, ok := hardDeleteRequested(w, r)
+	if !ok {
+		return
+	}
+	if err := h.catalog.DeleteBook(id, hard, middleware.GetUserID(r.Context())); err != nil {
+		sendServiceError(w, r, err, "Erro ao deletar livro")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RestoreBook tira um livro da lixeira
+func (h *BookHandler) RestoreBook(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	id := chi.URLParam(r, "id")
+
+	var rowsAffected int64
+	err := repositories.RunInTx(h.db, func(tx *sql.Tx) error {
+		var err error
+		if rowsAffected, err = h.books.WithTx(tx).Restore(id); err != nil || rowsAffected == 0 {
+			return err
+		}
+		_, err = h.revisions.WithTx(tx).Record(id, models.RevisionRestore, middleware.GetUserID(r.Context()), nil)
+		return err
+	})
 	if err != nil {
-		log.Printf("Erro ao deletar livro: %v", err)
-		sendErrorResponse(w, "Erro ao deletar livro", http.StatusInternalServerError)
+		if repositories.IsUniqueViolation(err) {
+			sendError(w, r, apperrors.CodeISBNConflict)
+			return
+		}
+		log.Printf("Erro ao restaurar livro: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
 		return
 	}
-	rowsAffected, _ := result.RowsAffected()
 	if rowsAffected == 0 {
-		sendErrorResponse(w, "Livro não encontrado", http.StatusNotFound)
+		sendError(w, r, apperrors.CodeBookNotInTrash)
 		return
 	}
-	w.WriteHeader(http.StatusNoContent)
+
+	book, err := h.books.FindByID(id)
+	if err != nil {
+		log.Printf("Erro ao buscar livro restaurado: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+	log.Printf("Livro restaurado da lixeira: %s", id)
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(book)
 }
 
 // UpdateBook atualiza um livro existente
@@ -346,7 +425,7 @@ func (h *BookHandler) UpdateBook(w http.ResponseWriter, r *http.Request) {
 	bodyBytes, err := io.ReadAll(r.Body)
 	if err != nil {
 		log.Printf("Erro ao ler corpo da requisição: %v", err)
-		sendErrorResponse(w, "Erro ao ler dados", http.StatusBadRequest)
+		sendError(w, r, apperrors.CodeInvalidBody)
 		return
 	}
 	r.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
@@ -364,7 +443,7 @@ func (h *BookHandler) UpdateBook(w http.ResponseWriter, r *http.Request) {
 		var requestData map[string]interface{}
 		if err := json.Unmarshal(bodyBytes, &requestData); err != nil {
 			log.Printf("Erro ao decodificar JSON: %v", err)
-			sendErrorResponse(w, "Erro ao ler dados", http.StatusBadRequest)
+			sendError(w, r, apperrors.CodeInvalidBody)
 			return
 		}
 
@@ -372,7 +451,7 @@ func (h *BookHandler) UpdateBook(w http.ResponseWriter, r *http.Request) {
 		if id, ok := requestData["id"].(string); ok {
 			bookID = id
 		} else {
-			sendErrorResponse(w, "ID do livro não fornecido", http.StatusBadRequest)
+			sendError(w, r, apperrors.CodeBookIDRequired)
 			return
 		}
 	}
@@ -381,7 +460,7 @@ func (h *BookHandler) UpdateBook(w http.ResponseWriter, r *http.Request) {
 	var requestData map[string]interface{}
 	if err := json.Unmarshal(bodyBytes, &requestData); err != nil {
 		log.Printf("Erro ao decodificar JSON para map: %v", err)
-		sendErrorResponse(w, "Erro ao ler dados", http.StatusBadRequest)
+		sendError(w, r, apperrors.CodeInvalidBody)
 		return
 	}
 
@@ -399,7 +478,7 @@ func (h *BookHandler) UpdateBook(w http.ResponseWriter, r *http.Request) {
 		quantityValue, ok := requestData["quantity"].(float64)
 		if !ok {
 			log.Printf("DEBUG - Quantidade inválida no payload: %v (tipo: %T)", requestData["quantity"], requestData["quantity"])
-			sendErrorResponse(w, "Formato de quantidade inválido", http.StatusBadRequest)
+			sendError(w, r, apperrors.CodeQuantityInvalid)
 			return
 		}
 
@@ -411,89 +490,14 @@ func (h *BookHandler) UpdateBook(w http.ResponseWriter, r *http.Request) {
 
 		log.Printf("DEBUG - Atualizando apenas quantidade - ID: %s, Nova quantidade: %d", bookID, newQuantity)
 
-		// Verificar se o livro existe
-		var exists bool
-		if err := h.db.QueryRow("SELECT EXISTS(SELECT 1 FROM livros WHERE id = $1)", bookID).Scan(&exists); err != nil {
-			log.Printf("Erro ao verificar existência do livro: %v", err)
-			sendErrorResponse(w, "Erro ao verificar existência do livro", http.StatusInternalServerError)
-			return
-		}
-
-		if !exists {
-			sendErrorResponse(w, "Livro não encontrado", http.StatusNotFound)
-			return
-		}
-
-		// Obter quantidade atual para debug
-		var currentQuantity int
-		if err := h.db.QueryRow("SELECT quantity FROM livros WHERE id = $1", bookID).Scan(&currentQuantity); err == nil {
-			log.Printf("DEBUG - Quantidade atual no banco antes de atualizar: %d, Nova quantidade: %d", currentQuantity, newQuantity)
-		}
-
-		// Validar nova quantidade
-		if newQuantity <= 0 {
-			log.Printf("DEBUG - QUANTIDADE: Rejeitada por ser <= 0: %d", newQuantity)
-			sendErrorResponse(w, "A quantidade deve ser maior que zero", http.StatusBadRequest)
-			return
-		}
-
-		// Executar atualização direta da quantidade - IMPORTANTE: sem nenhuma manipulação
-		query := "UPDATE livros SET quantity = $1 WHERE id = $2"
-
-		log.Printf("DEBUG - Executando query direta para quantidade: %s - Params: [%d, %s]", query, newQuantity, bookID)
-
-		// IMPORTANTE: Verificar tipo da coluna quantity no banco
-		var columnType string
-		err = h.db.QueryRow("SELECT data_type FROM information_schema.columns WHERE table_name = 'livros' AND column_name = 'quantity'").Scan(&columnType)
-		if err == nil {
-			log.Printf("DEBUG - QUANTIDADE: Tipo da coluna no banco: %s", columnType)
-		} else {
-			log.Printf("DEBUG - QUANTIDADE: Erro ao verificar tipo da coluna: %v", err)
-		}
-
-		result, err := h.db.Exec(query, newQuantity, bookID)
+		// A escrita, a revisão e os eventos de estoque vão na mesma transação
+		previous, err := h.catalog.SetStock(bookID, newQuantity, middleware.GetUserID(r.Context()))
 		if err != nil {
-			log.Printf("Erro ao atualizar quantidade: %v", err)
-			sendErrorResponse(w, "Erro ao atualizar quantidade", http.StatusInternalServerError)
-			return
-		}
-
-		rowsAffected, _ := result.RowsAffected()
-		if rowsAffected == 0 {
-			sendErrorResponse(w, "Nenhum livro foi atualizado", http.StatusNotFound)
+			sendServiceError(w, r, err, "Erro ao atualizar quantidade")
 			return
 		}
-
-		// Verificar quantidade após atualização
-		var finalQuantity int
-		if err := h.db.QueryRow("SELECT quantity FROM livros WHERE id = $1", bookID).Scan(&finalQuantity); err != nil {
-			log.Printf("Erro ao verificar quantidade final: %v", err)
-		} else {
-			log.Printf("DEBUG - QUANTIDADE: Quantidade após atualização diretamente do banco: %d", finalQuantity)
-			log.Printf("DEBUG - ANÁLISE DETALHADA: Quantidade solicitada: %d, Quantidade final no banco: %d, Razão: %.4f",
-				newQuantity, finalQuantity, float64(finalQuantity)/float64(newQuantity))
-
-			// Verificar se a quantidade no banco é diferente da solicitada
-			if finalQuantity != newQuantity {
-				log.Printf("DEBUG - ALERTA: Quantidade no banco (%d) diferente da solicitada (%d)!", finalQuantity, newQuantity)
-
-				// Tentar novamente com uma abordagem diferente, usando prepared statement
-				stmt, err := h.db.Prepare("UPDATE livros SET quantity = $1 WHERE id = $2")
-				if err != nil {
-					log.Printf("DEBUG - Erro ao preparar statement: %v", err)
-				} else {
-					defer stmt.Close()
-					_, err = stmt.Exec(newQuantity, bookID)
-					if err != nil {
-						log.Printf("DEBUG - Erro na segunda tentativa com prepared statement: %v", err)
-					} else {
-						// Verificar se a segunda tentativa funcionou
-						h.db.QueryRow("SELECT quantity FROM livros WHERE id = $1", bookID).Scan(&finalQuantity)
-						log.Printf("DEBUG - QUANTIDADE: Após segunda tentativa: %d", finalQuantity)
-					}
-				}
-			}
-		}
+		log.Printf("Quantidade do livro %s atualizada de %d para %d", bookID, previous, newQuantity)
+		finalQuantity := newQuantity
 
 		// Retornar resposta com informações da atualização
 		response := struct {
@@ -536,75 +540,93 @@ func (h *BookHandler) UpdateBook(w http.ResponseWriter, r *http.Request) {
 	var book models.Book
 	if err := json.NewDecoder(bytes.NewBuffer(bodyBytes)).Decode(&book); err != nil {
 		log.Printf("Erro ao decodificar JSON para struct Book: %v", err)
-		sendErrorResponse(w, "Erro ao ler dados", http.StatusBadRequest)
+		sendError(w, r, apperrors.CodeInvalidBody)
 		return
 	}
 
 	// Verificar se o ID foi fornecido
 	if book.ID == "" {
-		sendErrorResponse(w, "ID do livro não fornecido", http.StatusBadRequest)
+		sendError(w, r, apperrors.CodeBookIDRequired)
 		return
 	}
 
 	// Verificar se o livro existe
-	var exists bool
-	if err := h.db.QueryRow("SELECT EXISTS(SELECT 1 FROM livros WHERE id = $1)", book.ID).Scan(&exists); err != nil {
-		log.Printf("Erro ao verificar existência do livro: %v", err)
-		sendErrorResponse(w, "Erro ao verificar existência do livro", http.StatusInternalServerError)
+	existing, err := h.catalog.GetBook(book.ID)
+	if err != nil {
+		sendServiceError(w, r, err, "Erro ao verificar existência do livro")
 		return
 	}
 
-	if !exists {
-		sendErrorResponse(w, "Livro não encontrado", http.StatusNotFound)
+	// Campos ausentes no payload mantêm os valores atuais do livro, para que
+	// clientes que não conhecem os campos bibliográficos não os apaguem
+	book = *existing
+	if err := json.Unmarshal(bodyBytes, &book); err != nil {
+		log.Printf("Erro ao decodificar JSON para struct Book: %v", err)
+		sendError(w, r, apperrors.CodeInvalidBody)
 		return
 	}
+	if _, hasName := requestData["name"]; !hasName && book.Title != existing.Title {
+		book.Name = book.Title
+	}
 
-	// Verificar se o gênero existe, se fornecido
-	if book.GenreID != nil {
-		var genreExists bool
-		if err := h.db.QueryRow("SELECT EXISTS(SELECT 1 FROM genres WHERE id = $1)", *book.GenreID).Scan(&genreExists); err != nil {
-			log.Printf("Erro ao verificar existência do gênero: %v", err)
-			sendErrorResponse(w, "Erro ao verificar existência do gênero", http.StatusInternalServerError)
+	// Os autores só são substituídos quando "authors" ou "author" vier no payload
+	var rel services.BookRelations
+	_, authorsSent := requestData["authors"]
+	_, authorSent := requestData["author"]
+	if authorsSent {
+		rel.Authors = book.Authors
+	} else if authorSent {
+		book.Authors = nil
+		if rel.Authors, err = services.BookAuthorsInput(&book); err != nil {
+			sendServiceError(w, r, err, "Erro ao validar autores")
 			return
 		}
+	}
+	if (authorsSent || authorSent) && rel.Authors == nil {
+		rel.Authors = []models.BookAuthor{}
+	}
 
-		if !genreExists {
-			sendErrorResponse(w, "Gênero não encontrado", http.StatusBadRequest)
-			return
+	// Se o payload trouxer algum campo de ISBN, só os enviados valem; os
+	// demais voltam a ser derivados do novo valor
+	isbnFields := map[string]*string{"isbn": &book.ISBN, "isbn_10": &book.ISBN10, "isbn_13": &book.ISBN13}
+	isbnSent := false
+	for key := range isbnFields {
+		if _, ok := requestData[key]; ok {
+			isbnSent = true
 		}
 	}
-
-	log.Printf("Atualizando livro: %s, ID: %s, Quantidade: %d", book.Name, book.ID, book.Quantity)
-
-	// Usar apenas o campo Name para atualização
-	query := `UPDATE livros SET name = $1, quantity = $2, genre_id = $3, author = $4 WHERE id = $5`
-
-	// Adicionar log para debug
-	log.Printf("DEBUG - Query de atualização completa: %s - Params: [%s, %d, %v, %s, %s]",
-		query, book.Name, book.Quantity, book.GenreID, book.Author, book.ID)
-
-	result, err := h.db.Exec(query, book.Name, book.Quantity, book.GenreID, book.Author, book.ID)
-	if err != nil {
-		log.Printf("Erro ao atualizar livro: %v", err)
-		sendErrorResponse(w, "Erro ao atualizar livro", http.StatusInternalServerError)
-		return
+	if isbnSent {
+		for key, field := range isbnFields {
+			if _, ok := requestData[key]; !ok {
+				*field = ""
+			}
+		}
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		log.Printf("Erro ao obter linhas afetadas: %v", err)
-		sendErrorResponse(w, "Erro ao atualizar livro", http.StatusInternalServerError)
-		return
+	// Os gêneros só são substituídos quando "genres" ou "genre_id" vier no
+	// payload. Só com genre_id (clientes antigos), ele passa a ser o gênero
+	// principal e os demais gêneros do livro são mantidos; null remove todos.
+	_, genresSent := requestData["genres"]
+	genreIDValue, genreIDSent := requestData["genre_id"]
+	if genresSent || genreIDSent {
+		switch {
+		case !genreIDSent:
+			book.GenreID = nil
+		case !genresSent && genreIDValue == nil:
+			book.Genres = nil
+		}
+		if rel.GenreIDs = services.BookGenresInput(&book); rel.GenreIDs == nil {
+			rel.GenreIDs = []string{}
+		}
 	}
 
-	if rowsAffected == 0 {
-		sendErrorResponse(w, "Nenhum livro foi atualizado", http.StatusNotFound)
+	log.Printf("Atualizando livro: %s, ID: %s, Quantidade: %d", book.Name, book.ID, book.Quantity)
+
+	if err := h.catalog.UpdateBook(&book, rel, middleware.GetUserID(r.Context())); err != nil {
+		sendBookError(w, r, err, "Erro ao atualizar livro")
 		return
 	}
 
-	// Definir Title igual a Name para compatibilidade com o frontend
-	book.Title = book.Name
-
 	log.Printf("Livro atualizado com sucesso: %s", book.Name)
 
 	w.WriteHeader(http.StatusOK)
@@ -616,53 +638,34 @@ func (h *BookHandler) CreateAllBooks(w http.ResponseWriter, r *http.Request) {
 	var books []models.Book
 	if err := json.NewDecoder(r.Body).Decode(&books); err != nil {
 		log.Printf("Erro ao decodificar JSON: %v", err)
-		sendErrorResponse(w, "Erro ao ler dados", http.StatusBadRequest)
+		sendError(w, r, apperrors.CodeInvalidBody)
 		return
 	}
 
-	createdBooks := []models.Book{}
-	for _, book := range books {
-		if book.Name == "" && book.Title != "" {
-			book.Name = book.Title
-		}
-
-		if book.Name == "" || book.Quantity <= 0 {
-			continue
-		}
-
-		if book.GenreID != nil {
-			var count int
-			err := h.db.QueryRow("SELECT COUNT(*) FROM genres WHERE id = $1", book.GenreID).Scan(&count)
-			if err != nil || count == 0 {
-				continue
+	// Com enrich=true, os metadados completam cada livro de ISBN válido; os
+	// ISBNs inválidos são apontados pela validação do lote
+	if r.URL.Query().Get("enrich") == "true" {
+		for i := range books {
+			if validators.NormalizeBookISBN(&books[i]) == nil {
+				h.enrichBook(r.Context(), &books[i])
 			}
 		}
+	}
 
-		newID := ksuid.New().String()
-
-		log.Printf("Tentando criar livro em lote: %s, Autor: %s", book.Name, book.Author)
-
-		// Preparar o valor do autor (que pode ser NULL)
-		var authorValue interface{}
-		if book.Author == "" {
-			authorValue = nil
-		} else {
-			authorValue = book.Author
-		}
-
-		// Incluir a coluna author na consulta SQL
-		query := `INSERT INTO livros (id, name, quantity, genre_id, author) VALUES ($1, $2, $3, $4, $5) RETURNING id`
-
-		var returnedID string
-		if err := h.db.QueryRow(query, newID, book.Name, book.Quantity, book.GenreID, authorValue).Scan(&returnedID); err != nil {
-			log.Printf("Erro ao inserir livro: %v", err)
-			continue
+	// Se algum livro for inválido ou algum ISBN já existir (no banco ou
+	// repetido no próprio lote), nada é criado
+	if err := h.catalog.CreateBooks(books, middleware.GetUserID(r.Context())); err != nil {
+		var conflict *services.BatchISBNConflictError
+		if errors.As(err, &conflict) {
+			problem.Write(w, problem.New(r, apperrors.CodeBatchISBNConflict).With("duplicates", conflict.Duplicates))
+			return
 		}
-
-		book.ID = newID
-		// Garantir que title seja igual a name para compatibilidade
-		book.Title = book.Name
-		createdBooks = append(createdBooks, book)
+		sendBookError(w, r, err, "Erro ao inserir lote de livros")
+		return
+	}
+	createdBooks := books
+	if createdBooks == nil {
+		createdBooks = []models.Book{}
 	}
 
 	response := map[string]interface{}{
@@ -685,7 +688,7 @@ func (h *BookHandler) UpdateBookQuantity(w http.ResponseWriter, r *http.Request)
 	// Verificar método HTTP
 	if r.Method != "POST" {
 		log.Printf("ROTA ESPECIAL - Método incorreto: %s (esperado POST)", r.Method)
-		sendErrorResponse(w, fmt.Sprintf("Método %s não permitido para esta rota", r.Method), http.StatusMethodNotAllowed)
+		sendError(w, r, apperrors.CodeMethodNotAllowed, r.Method)
 		return
 	}
 
@@ -693,7 +696,7 @@ func (h *BookHandler) UpdateBookQuantity(w http.ResponseWriter, r *http.Request)
 	bodyBytes, err := io.ReadAll(r.Body)
 	if err != nil {
 		log.Printf("ROTA ESPECIAL - Erro ao ler corpo: %v", err)
-		sendErrorResponse(w, "Erro ao ler dados da requisição", http.StatusBadRequest)
+		sendError(w, r, apperrors.CodeInvalidBody)
 		return
 	}
 	r.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
@@ -710,83 +713,33 @@ func (h *BookHandler) UpdateBookQuantity(w http.ResponseWriter, r *http.Request)
 	var update quantityUpdate
 	if err := json.Unmarshal(bodyBytes, &update); err != nil {
 		log.Printf("ROTA ESPECIAL - Erro ao decodificar: %v - Payload: %s", err, string(bodyBytes))
-		sendErrorResponse(w, "Formato inválido. Esperado: {\"id\":\"...\", \"quantity\":N}", http.StatusBadRequest)
+		sendError(w, r, apperrors.CodeInvalidBody)
 		return
 	}
 
 	// Validar dados recebidos
 	if update.ID == "" {
 		log.Printf("ROTA ESPECIAL - ID não fornecido")
-		sendErrorResponse(w, "ID do livro não fornecido", http.StatusBadRequest)
+		sendError(w, r, apperrors.CodeBookIDRequired)
 		return
 	}
 
-	if update.Quantity <= 0 {
+	if update.Quantity < 0 {
 		log.Printf("ROTA ESPECIAL - Quantidade inválida: %d", update.Quantity)
-		sendErrorResponse(w, "A quantidade deve ser maior que zero", http.StatusBadRequest)
+		sendError(w, r, apperrors.CodeQuantityNegative)
 		return
 	}
 
 	log.Printf("ROTA ESPECIAL - Processando: ID=%s, Quantidade=%d", update.ID, update.Quantity)
 
-	// Verificar se o livro existe
-	var exists bool
-	if err := h.db.QueryRow("SELECT EXISTS(SELECT 1 FROM livros WHERE id = $1)", update.ID).Scan(&exists); err != nil {
-		log.Printf("ROTA ESPECIAL - Erro ao verificar existência: %v", err)
-		sendErrorResponse(w, "Erro ao verificar existência do livro", http.StatusInternalServerError)
-		return
-	}
-
-	if !exists {
-		log.Printf("ROTA ESPECIAL - Livro não encontrado: %s", update.ID)
-		sendErrorResponse(w, "Livro não encontrado", http.StatusNotFound)
-		return
-	}
-
-	// Verificar quantidade atual no banco
-	var currentQuantity int
-	if err := h.db.QueryRow("SELECT quantity FROM livros WHERE id = $1", update.ID).Scan(&currentQuantity); err != nil {
-		log.Printf("ROTA ESPECIAL - Erro ao obter quantidade atual: %v", err)
-	} else {
-		log.Printf("ROTA ESPECIAL - Quantidade atual no banco: %d, Nova quantidade solicitada: %d",
-			currentQuantity, update.Quantity)
-	}
-
-	// Usar uma query SQL direta que APENAS atualiza a quantidade
-	query := "UPDATE livros SET quantity = $1 WHERE id = $2"
-
-	log.Printf("ROTA ESPECIAL - Executando query: %s com [%d, %s]", query, update.Quantity, update.ID)
-
-	// Executar a query
-	result, err := h.db.Exec(query, update.Quantity, update.ID)
+	// A escrita, a revisão e os eventos de estoque vão na mesma transação
+	currentQuantity, err := h.catalog.SetStock(update.ID, update.Quantity, middleware.GetUserID(r.Context()))
 	if err != nil {
-		log.Printf("ROTA ESPECIAL - Erro na atualização: %v", err)
-		sendErrorResponse(w, "Erro ao atualizar quantidade", http.StatusInternalServerError)
+		sendServiceError(w, r, err, "ROTA ESPECIAL - Erro na atualização")
 		return
 	}
-
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
-		log.Printf("ROTA ESPECIAL - Nenhuma linha afetada")
-		sendErrorResponse(w, "Nenhum livro foi atualizado", http.StatusNotFound)
-		return
-	}
-
-	log.Printf("ROTA ESPECIAL - Atualização concluída, %d linhas afetadas", rowsAffected)
-
-	// Verificar a quantidade após a atualização
-	var finalQuantity int
-	if err := h.db.QueryRow("SELECT quantity FROM livros WHERE id = $1", update.ID).Scan(&finalQuantity); err != nil {
-		log.Printf("ROTA ESPECIAL - Erro ao verificar quantidade final: %v", err)
-	} else {
-		log.Printf("ROTA ESPECIAL - Quantidade APÓS atualização: %d", finalQuantity)
-
-		// Verificar se a quantidade foi atualizada corretamente
-		if finalQuantity != update.Quantity {
-			log.Printf("ROTA ESPECIAL - ATENÇÃO: Valor no banco (%d) diferente do solicitado (%d)",
-				finalQuantity, update.Quantity)
-		}
-	}
+	log.Printf("ROTA ESPECIAL - Quantidade atualizada de %d para %d", currentQuantity, update.Quantity)
+	finalQuantity := update.Quantity
 
 	// Retornar resposta
 	response := struct {
@@ -830,86 +783,30 @@ func (h *BookHandler) UpdateQuantityDirect(w http.ResponseWriter, r *http.Reques
 	// Validar ID
 	if bookID == "" {
 		log.Printf("MÉTODO DIRETO - ID não fornecido")
-		sendErrorResponse(w, "ID do livro não fornecido", http.StatusBadRequest)
+		sendError(w, r, apperrors.CodeBookIDRequired)
 		return
 	}
 
 	// Validar e converter quantidade
 	quantity, err := strconv.Atoi(quantityStr)
-	if err != nil || quantity <= 0 {
+	if err != nil || quantity < 0 {
 		log.Printf("MÉTODO DIRETO - Quantidade inválida: %s", quantityStr)
-		sendErrorResponse(w, "Quantidade inválida. Deve ser um número maior que zero.", http.StatusBadRequest)
-		return
-	}
-
-	// Verificar se o livro existe
-	var exists bool
-	if err := h.db.QueryRow("SELECT EXISTS(SELECT 1 FROM livros WHERE id = $1)", bookID).Scan(&exists); err != nil {
-		log.Printf("MÉTODO DIRETO - Erro ao verificar livro: %v", err)
-		sendErrorResponse(w, "Erro ao verificar existência do livro", http.StatusInternalServerError)
-		return
-	}
-
-	if !exists {
-		log.Printf("MÉTODO DIRETO - Livro não encontrado: %s", bookID)
-		sendErrorResponse(w, "Livro não encontrado", http.StatusNotFound)
-		return
-	}
-
-	// Obter quantidade atual para comparação
-	var currentQuantity int
-	if err := h.db.QueryRow("SELECT quantity FROM livros WHERE id = $1", bookID).Scan(&currentQuantity); err == nil {
-		log.Printf("MÉTODO DIRETO - Quantidade atual: %d, Nova quantidade: %d", currentQuantity, quantity)
-	}
-
-	// MODIFICAÇÃO IMPORTANTE: Usar um comando SQL preparado para garantir que o valor seja exatamente como fornecido
-	stmt, err := h.db.Prepare("UPDATE livros SET quantity = $1 WHERE id = $2")
-	if err != nil {
-		log.Printf("MÉTODO DIRETO - Erro ao preparar statement: %v", err)
-		sendErrorResponse(w, "Erro interno ao preparar atualização", http.StatusInternalServerError)
+		if err != nil {
+			sendError(w, r, apperrors.CodeQuantityInvalid)
+		} else {
+			sendError(w, r, apperrors.CodeQuantityNegative)
+		}
 		return
 	}
-	defer stmt.Close()
 
-	// Executar a atualização com o valor exato recebido
-	log.Printf("MÉTODO DIRETO - Executando statement com exatamente: [%d, %s]", quantity, bookID)
-	result, err := stmt.Exec(quantity, bookID)
+	// A escrita, a revisão e os eventos de estoque vão na mesma transação
+	currentQuantity, err := h.catalog.SetStock(bookID, quantity, middleware.GetUserID(r.Context()))
 	if err != nil {
-		log.Printf("MÉTODO DIRETO - Erro na atualização: %v", err)
-		sendErrorResponse(w, "Erro ao atualizar quantidade", http.StatusInternalServerError)
-		return
-	}
-
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
-		log.Printf("MÉTODO DIRETO - Nenhuma linha afetada")
-		sendErrorResponse(w, "Nenhum livro foi atualizado", http.StatusNotFound)
+		sendServiceError(w, r, err, "MÉTODO DIRETO - Erro na atualização")
 		return
 	}
-
-	// Verificar quantidade final após atualização
-	var finalQuantity int
-	if err := h.db.QueryRow("SELECT quantity FROM livros WHERE id = $1", bookID).Scan(&finalQuantity); err != nil {
-		log.Printf("MÉTODO DIRETO - Erro ao verificar quantidade final: %v", err)
-	} else {
-		log.Printf("MÉTODO DIRETO - Quantidade final: %d", finalQuantity)
-
-		// Verificar se houve discrepância
-		if finalQuantity != quantity {
-			log.Printf("MÉTODO DIRETO - ALERTA! Valor no banco (%d) diferente do solicitado (%d). Tentando correção...",
-				finalQuantity, quantity)
-
-			// Tentar uma última vez com consulta direta
-			_, directErr := h.db.Exec("UPDATE livros SET quantity = $1 WHERE id = $2", quantity, bookID)
-			if directErr == nil {
-				// Verificar novamente
-				var checkQuantity int
-				h.db.QueryRow("SELECT quantity FROM livros WHERE id = $1", bookID).Scan(&checkQuantity)
-				log.Printf("MÉTODO DIRETO - Após correção final: %d", checkQuantity)
-				finalQuantity = checkQuantity
-			}
-		}
-	}
+	log.Printf("MÉTODO DIRETO - Quantidade atualizada de %d para %d", currentQuantity, quantity)
+	finalQuantity := quantity
 
 	// Retornar resposta de sucesso com dados atualizados
 	response := struct {
@@ -1,9 +1,14 @@
 package http
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"projeto_livros/internal/delivery/middleware"
+	"projeto_livros/internal/domain/models"
+	"strings"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -15,10 +20,12 @@ func TestGetAllBooks(t *testing.T) {
 		t.Fatalf("Erro ao criar mock do banco de dados: %v", err)
 	}
 	defer db.Close()
-	rows := sqlmock.NewRows([]string{"id", "title", "author", "genre_id"}).
-		AddRow(1, "Livro 1", "Autor 1", 1).
-		AddRow(2, "Livro 2", "Autor 2", 2)
+	rows := sqlmock.NewRows([]string{"id", "name", "quantity", "genre_id", "author",
+		"isbn", "publisher", "publication_year", "edition", "subjects", "min_quantity"}).
+		AddRow("1", "Livro 1", 1, "1", "Autor 1", "", "", nil, "", "{}", nil).
+		AddRow("2", "Livro 2", 2, "2", "Autor 2", "", "", nil, "", "{}", nil)
 	mock.ExpectQuery("SELECT (.*)").WillReturnRows(rows)
+	mock.ExpectQuery("SELECT COUNT").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
 	bookHandler := NewBookHandler(db)
 	req, err := http.NewRequest("GET", "/books/get-all", nil)
 	if err != nil {
@@ -45,3 +52,340 @@ func TestGetAllBooks(t *testing.T) {
 		t.Errorf("Expectativas não atendidas: %s", err)
 	}
 }
+
+func TestCreateBookDuplicateISBN(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Erro ao criar mock do banco de dados: %v", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "name", "quantity", "genre_id", "author",
+		"isbn", "publisher", "publication_year", "edition", "subjects", "min_quantity"}).
+		AddRow("existente", "Dom Casmurro", 1, nil, "Machado de Assis", "9788535910667", "", nil, "", "{}", nil)
+	mock.ExpectQuery("SELECT (.+) WHERE l.isbn = \\$1").WithArgs("9788535910667").WillReturnRows(rows)
+
+	body := strings.NewReader(`{"name":"Dom Casmurro","quantity":2,"isbn":"85-359-1066-2"}`)
+	req := httptest.NewRequest("POST", "/api/books", body)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(NewBookHandler(db).CreateBook).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("handler retornou código de status errado: obteve %v, esperava %v", rr.Code, http.StatusConflict)
+	}
+	if contentType := rr.Header().Get("Content-Type"); contentType != "application/problem+json" {
+		t.Errorf("Content-Type = %q, esperava application/problem+json", contentType)
+	}
+	var response struct {
+		Type       string `json:"type"`
+		Status     int    `json:"status"`
+		Code       string `json:"code"`
+		Instance   string `json:"instance"`
+		ExistingID string `json:"existing_id"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("resposta não é um JSON válido: %v", err)
+	}
+	if response.Code != "ISBN_CONFLICT" || response.Type != "/api/problems/isbn-conflict" ||
+		response.Status != http.StatusConflict || response.Instance != "/api/books" {
+		t.Errorf("problema incorreto: %s", rr.Body.String())
+	}
+	if response.ExistingID != "existente" {
+		t.Errorf("existing_id incorreto: %q", response.ExistingID)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas não atendidas: %s", err)
+	}
+}
+
+func TestCreateAllBooksReportsAllViolations(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Erro ao criar mock do banco de dados: %v", err)
+	}
+	defer db.Close()
+
+	body := strings.NewReader(`[{"name":"Dom Casmurro","quantity":1},{"name":"  ","quantity":0},{"name":"Iracema","quantity":1,"edition":"` + strings.Repeat("x", 101) + `"}]`)
+	req := httptest.NewRequest("POST", "/api/books/batch", body)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(NewBookHandler(db).CreateAllBooks).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("handler retornou código de status errado: obteve %v, esperava %v", rr.Code, http.StatusBadRequest)
+	}
+	var response struct {
+		Code   string `json:"code"`
+		Errors []struct {
+			Field string `json:"field"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("resposta não é um JSON válido: %v", err)
+	}
+	var fields []string
+	for _, e := range response.Errors {
+		fields = append(fields, e.Field)
+	}
+	if response.Code != "VALIDATION_FAILED" || strings.Join(fields, ",") != "[1].name,[1].quantity,[2].edition" {
+		t.Errorf("problema incorreto: %s", rr.Body.String())
+	}
+	// Nenhum livro do lote é gravado
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas não atendidas: %s", err)
+	}
+}
+
+func TestCreateAllBooksReportsReferenceViolations(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Erro ao criar mock do banco de dados: %v", err)
+	}
+	defer db.Close()
+
+	genreID := strings.Repeat("f", 27)
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM genres").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	body := strings.NewReader(`[{"name":"Dom Casmurro","quantity":1,"isbn":"123"},` +
+		`{"name":"Iracema","quantity":1,"genre_id":"` + genreID + `"},` +
+		`{"name":"O Guarani","quantity":1,"authors":[{"role":"autor"}]}]`)
+	req := httptest.NewRequest("POST", "/api/books/batch", body)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(NewBookHandler(db).CreateAllBooks).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("handler retornou código de status errado: obteve %v, esperava %v", rr.Code, http.StatusBadRequest)
+	}
+	var response struct {
+		Code   string `json:"code"`
+		Errors []struct {
+			Field   string `json:"field"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("resposta não é um JSON válido: %v", err)
+	}
+	var fields []string
+	for _, e := range response.Errors {
+		fields = append(fields, e.Field)
+		if e.Message == "" {
+			t.Errorf("violação sem mensagem: %s", e.Field)
+		}
+	}
+	if response.Code != "VALIDATION_FAILED" || strings.Join(fields, ",") != "[0].isbn,[1].genres,[2].authors" {
+		t.Errorf("problema incorreto: %s", rr.Body.String())
+	}
+	// Nenhum livro do lote é gravado
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas não atendidas: %s", err)
+	}
+}
+
+func TestCreateAllBooksFailsWholeBatchOnSaveError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Erro ao criar mock do banco de dados: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO livros").WillReturnError(sql.ErrConnDone)
+	mock.ExpectRollback()
+
+	body := strings.NewReader(`[{"name":"Dom Casmurro","quantity":1},{"name":"Iracema","quantity":1}]`)
+	req := httptest.NewRequest("POST", "/api/books/batch", body)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(NewBookHandler(db).CreateAllBooks).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Fatalf("handler retornou código de status errado: obteve %v, esperava %v", rr.Code, http.StatusInternalServerError)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas não atendidas: %s", err)
+	}
+}
+
+func TestCreateBookGenreLookupFailure(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Erro ao criar mock do banco de dados: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM genres").WillReturnError(sql.ErrConnDone)
+
+	body := strings.NewReader(`{"name":"Dom Casmurro","quantity":1,"genre_id":"` + strings.Repeat("f", 27) + `"}`)
+	req := httptest.NewRequest("POST", "/api/books", body)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(NewBookHandler(db).CreateBook).ServeHTTP(rr, req)
+
+	// Uma falha do banco não é um gênero inexistente
+	if rr.Code != http.StatusInternalServerError || !strings.Contains(rr.Body.String(), "INTERNAL_ERROR") {
+		t.Fatalf("esperava 500 INTERNAL_ERROR, obteve %d: %s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas não atendidas: %s", err)
+	}
+}
+
+func TestDeleteBookMovesToTrash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Erro ao criar mock do banco de dados: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE livros SET deleted_at = CURRENT_TIMESTAMP").WithArgs("b1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectRevision(mock, "b1", models.RevisionDelete)
+	mock.ExpectCommit()
+
+	req := withURLParam(httptest.NewRequest(http.MethodDelete, "/api/books/b1", nil), "id", "b1")
+	rr := httptest.NewRecorder()
+	NewBookHandler(db).DeleteBook(rr, req)
+
+	if rr.Code != http.StatusNoContent {
+		t.Errorf("status = %d, esperava %d", rr.Code, http.StatusNoContent)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas não atendidas: %s", err)
+	}
+}
+
+func TestDeleteBookHardRequiresAdmin(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Erro ao criar mock do banco de dados: %v", err)
+	}
+	defer db.Close()
+	handler := NewBookHandler(db)
+
+	// Sem papel de administrador nada é apagado
+	req := withURLParam(httptest.NewRequest(http.MethodDelete, "/api/books/b1?hard=true", nil), "id", "b1")
+	rr := httptest.NewRecorder()
+	handler.DeleteBook(rr, req)
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("status = %d, esperava %d", rr.Code, http.StatusForbidden)
+	}
+
+	// A revisão com o estado final é gravada antes da remoção definitiva
+	mock.ExpectBegin()
+	expectRevision(mock, "b1", models.RevisionDelete)
+	mock.ExpectExec("DELETE FROM livros WHERE id = \\$1").WithArgs("b1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	req = withURLParam(httptest.NewRequest(http.MethodDelete, "/api/books/b1?hard=true", nil), "id", "b1")
+	req = req.WithContext(context.WithValue(req.Context(), middleware.RoleKey, middleware.RoleAdmin))
+	rr = httptest.NewRecorder()
+	handler.DeleteBook(rr, req)
+	if rr.Code != http.StatusNoContent {
+		t.Errorf("status = %d, esperava %d", rr.Code, http.StatusNoContent)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas não atendidas: %s", err)
+	}
+}
+
+func TestRestoreBook(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Erro ao criar mock do banco de dados: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE livros SET deleted_at = NULL").WithArgs("b1").WillReturnResult(sqlmock.NewResult(0, 1))
+	expectRevision(mock, "b1", models.RevisionRestore)
+	mock.ExpectCommit()
+	mock.ExpectQuery("SELECT (.+) WHERE l.id = \\$1 AND l.deleted_at IS NULL").WithArgs("b1").WillReturnRows(
+		sqlmock.NewRows(bookColumnNames).
+			AddRow("b1", "Dom Casmurro", 3, nil, "Machado de Assis", "", "", nil, "", "{}", nil))
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE livros SET deleted_at = NULL").WithArgs("b2").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	handler := NewBookHandler(db)
+	rr := httptest.NewRecorder()
+	handler.RestoreBook(rr, withURLParam(httptest.NewRequest(http.MethodPost, "/api/books/b1/restore", nil), "id", "b1"))
+	if rr.Code != http.StatusOK {
+		t.Errorf("status = %d, esperava %d", rr.Code, http.StatusOK)
+	}
+
+	// Um livro fora da lixeira (ou inexistente) não é restaurado
+	rr = httptest.NewRecorder()
+	handler.RestoreBook(rr, withURLParam(httptest.NewRequest(http.MethodPost, "/api/books/b2/restore", nil), "id", "b2"))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("status = %d, esperava %d", rr.Code, http.StatusNotFound)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas não atendidas: %s", err)
+	}
+}
+
+func TestUpdateQuantityDirectRecordsStockChange(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Erro ao criar mock do banco de dados: %v", err)
+	}
+	defer db.Close()
+
+	// Escrita, revisão e eventos na mesma transação; o livro ainda não tem
+	// revisão, e o estoque lido antes da escrita gera o StockChanged
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT quantity FROM livros WHERE id = \\$1 AND deleted_at IS NULL FOR UPDATE").WithArgs("b1").
+		WillReturnRows(sqlmock.NewRows([]string{"quantity"}).AddRow(5))
+	mock.ExpectExec("UPDATE livros SET quantity = \\$1 WHERE id = \\$2").WithArgs(3, "b1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectRevision(mock, "b1", models.RevisionUpdate)
+	expectStockChanged(mock, "b1")
+	mock.ExpectCommit()
+
+	req := httptest.NewRequest("GET", "/update-quantity?id=b1&quantity=3", nil)
+	rr := httptest.NewRecorder()
+	NewBookHandler(db).UpdateQuantityDirect(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, esperava %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	var response struct {
+		Quantity    int `json:"quantity"`
+		OriginalQty int `json:"original_quantity"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("resposta não é um JSON válido: %v", err)
+	}
+	if response.Quantity != 3 || response.OriginalQty != 5 {
+		t.Errorf("resposta incorreta: %s", rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas não atendidas: %s", err)
+	}
+}
+
+func TestUpdateQuantityDirectRollsBackWithoutRevision(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Erro ao criar mock do banco de dados: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT quantity FROM livros WHERE id = \\$1 AND deleted_at IS NULL FOR UPDATE").WithArgs("b1").
+		WillReturnRows(sqlmock.NewRows([]string{"quantity"}).AddRow(5))
+	mock.ExpectExec("UPDATE livros SET quantity = \\$1 WHERE id = \\$2").WithArgs(3, "b1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT (.+) FROM livros l WHERE l.id = \\$1 FOR UPDATE").WithArgs("b1").
+		WillReturnError(sql.ErrConnDone)
+	mock.ExpectRollback()
+
+	req := httptest.NewRequest("GET", "/update-quantity?id=b1&quantity=3", nil)
+	rr := httptest.NewRecorder()
+	NewBookHandler(db).UpdateQuantityDirect(rr, req)
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, esperava %d", rr.Code, http.StatusInternalServerError)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas não atendidas: %s", err)
+	}
+}
@@ -0,0 +1,186 @@
+package http
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"projeto_livros/internal/delivery/middleware"
+	apperrors "projeto_livros/internal/domain/errors"
+	"projeto_livros/internal/domain/models"
+	repositories "projeto_livros/internal/repository"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// GetBookHistory lista as revisões do livro, da mais recente para a mais
+// antiga. O histórico continua disponível depois da remoção do livro.
+func (h *BookHandler) GetBookHistory(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	id := chi.URLParam(r, "id")
+
+	page := 1
+	perPage := 20
+	if p, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && p > 0 {
+		page = p
+	}
+	if pp, err := strconv.Atoi(r.URL.Query().Get("per_page")); err == nil && pp > 0 {
+		perPage = pp
+	}
+
+	revisions, total, err := h.revisions.FindByBook(id, perPage, (page-1)*perPage)
+	if err != nil {
+		log.Printf("Erro ao buscar histórico do livro: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+	if total == 0 {
+		sendError(w, r, apperrors.CodeBookHistoryEmpty)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data":            revisions,
+		"page":            page,
+		"per_page":        perPage,
+		"total_revisions": total,
+		"total_pages":     (total + perPage - 1) / perPage,
+	})
+}
+
+// findRevision busca a revisão rev do livro e envia a resposta de erro quando
+// ela não existe ou rev é inválido
+func (h *BookHandler) findRevision(w http.ResponseWriter, r *http.Request, bookID, rev string) (*models.BookRevision, bool) {
+	n, err := strconv.Atoi(rev)
+	if err != nil || n <= 0 {
+		sendError(w, r, apperrors.CodeRevisionInvalid, rev)
+		return nil, false
+	}
+	revision, err := h.revisions.FindByRev(bookID, n)
+	if err == sql.ErrNoRows {
+		sendError(w, r, apperrors.CodeRevisionNotFound, n)
+		return nil, false
+	} else if err != nil {
+		log.Printf("Erro ao buscar revisão %s do livro %s: %v", rev, bookID, err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return nil, false
+	}
+	return revision, true
+}
+
+// GetBookRevision devolve uma revisão do livro com o estado completo
+func (h *BookHandler) GetBookRevision(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	revision, ok := h.findRevision(w, r, chi.URLParam(r, "id"), chi.URLParam(r, "rev"))
+	if !ok {
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(revision)
+}
+
+// GetBookRevisionDiff compara duas revisões do livro (from e to) campo a campo
+func (h *BookHandler) GetBookRevisionDiff(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	id := chi.URLParam(r, "id")
+
+	q := r.URL.Query()
+	if q.Get("from") == "" || q.Get("to") == "" {
+		sendError(w, r, apperrors.CodeRevisionRange)
+		return
+	}
+	from, ok := h.findRevision(w, r, id, q.Get("from"))
+	if !ok {
+		return
+	}
+	to, ok := h.findRevision(w, r, id, q.Get("to"))
+	if !ok {
+		return
+	}
+
+	changes, err := repositories.DiffBooks(&from.Snapshot, &to.Snapshot)
+	if err != nil {
+		log.Printf("Erro ao comparar revisões: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"book_id": id,
+		"from":    from.Rev,
+		"to":      to.Rev,
+		"changes": changes,
+	})
+}
+
+// RevertBook devolve o livro ao estado da revisão {rev}, inclusive autores e
+// gêneros, e registra uma nova revisão. Um livro na lixeira é restaurado.
+func (h *BookHandler) RevertBook(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	id := chi.URLParam(r, "id")
+
+	target, ok := h.findRevision(w, r, id, chi.URLParam(r, "rev"))
+	if !ok {
+		return
+	}
+	if target.Action == models.RevisionDelete {
+		sendError(w, r, apperrors.CodeRevertToDelete)
+		return
+	}
+
+	book := target.Snapshot
+	book.ID = id
+	genreIDs := make([]string, len(book.Genres))
+	for i, g := range book.Genres {
+		genreIDs[i] = g.ID
+	}
+	// Os autores são resolvidos pelo nome: um autor removido depois da revisão é recriado
+	authors := make([]models.BookAuthor, len(book.Authors))
+	for i, a := range book.Authors {
+		authors[i] = models.BookAuthor{Name: a.Name, Role: a.Role}
+	}
+
+	var revision *models.BookRevision
+	err := repositories.RunInTx(h.db, func(tx *sql.Tx) error {
+		books := h.books.WithTx(tx)
+		if _, err := books.Restore(id); err != nil {
+			return err
+		}
+		affected, err := books.Update(&book)
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return sql.ErrNoRows
+		}
+		if _, err := h.genres.WithTx(tx).SetBookGenres(id, genreIDs); err != nil {
+			return err
+		}
+		if _, _, err := h.authors.WithTx(tx).SetBookAuthors(id, authors); err != nil {
+			return err
+		}
+		revision, err = h.revisions.WithTx(tx).Record(id, models.RevisionRevert, middleware.GetUserID(r.Context()), &target.Rev)
+		return err
+	})
+	switch {
+	case err == sql.ErrNoRows:
+		sendError(w, r, apperrors.CodeBookNotFound)
+		return
+	case err == repositories.ErrGenreNotFound:
+		sendError(w, r, apperrors.CodeRevisionGenreMissing)
+		return
+	case repositories.IsUniqueViolation(err):
+		sendError(w, r, apperrors.CodeISBNConflict)
+		return
+	case err != nil:
+		log.Printf("Erro ao reverter livro %s para a revisão %d: %v", id, target.Rev, err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+
+	log.Printf("Livro %s revertido para a revisão %d", id, target.Rev)
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(revision)
+}
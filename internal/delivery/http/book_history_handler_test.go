@@ -0,0 +1,114 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"projeto_livros/internal/domain/models"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+var bookColumnNames = []string{"id", "name", "quantity", "genre_id", "author",
+	"isbn", "publisher", "publication_year", "edition", "subjects", "min_quantity"}
+
+var revisionColumnNames = []string{"id", "book_id", "rev", "action", "actor", "changed_fields",
+	"reverted_from", "snapshot", "created_at"}
+
+// expectRevision registra as consultas feitas ao gravar uma revisão de um
+// livro sem revisões anteriores
+func expectRevision(mock sqlmock.Sqlmock, bookID, action string) {
+	mock.ExpectQuery("SELECT (.+) FROM livros l WHERE l.id = \\$1 FOR UPDATE").WithArgs(bookID).WillReturnRows(
+		sqlmock.NewRows(bookColumnNames).AddRow(bookID, "Dom Casmurro", 3, nil, "Machado de Assis", "", "", nil, "", "{}", nil))
+	mock.ExpectQuery("FROM book_authors ba").WithArgs(bookID).WillReturnRows(
+		sqlmock.NewRows([]string{"id", "name", "role"}).AddRow("a1", "Machado de Assis", "author"))
+	mock.ExpectQuery("FROM book_genres bg").WithArgs(bookID).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+	mock.ExpectQuery("SELECT rev, snapshot FROM book_revisions").WithArgs(bookID).
+		WillReturnRows(sqlmock.NewRows([]string{"rev", "snapshot"}))
+	mock.ExpectQuery("INSERT INTO book_revisions").
+		WithArgs(sqlmock.AnyArg(), bookID, 1, action, "", sqlmock.AnyArg(), nil, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(time.Now()))
+	mock.ExpectExec("INSERT INTO outbox_events").
+		WithArgs(bookEvents[action], models.AggregateBook, bookID, "", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+// expectStockChanged espera o StockChanged gravado no outbox logo após a
+// revisão de expectRevision
+func expectStockChanged(mock sqlmock.Sqlmock, bookID string) {
+	mock.ExpectExec("INSERT INTO outbox_events").
+		WithArgs(models.EventStockChanged, models.AggregateBook, bookID, "", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+}
+
+// bookEvents é o evento publicado para cada ação do histórico
+var bookEvents = map[string]string{
+	models.RevisionCreate:  models.EventBookCreated,
+	models.RevisionUpdate:  models.EventBookUpdated,
+	models.RevisionRevert:  models.EventBookUpdated,
+	models.RevisionDelete:  models.EventBookDeleted,
+	models.RevisionRestore: models.EventBookRestored,
+}
+
+func TestGetBookRevisionDiff(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Erro ao criar mock do banco de dados: %v", err)
+	}
+	defer db.Close()
+	now := time.Now()
+
+	mock.ExpectQuery("FROM book_revisions WHERE book_id = \\$1 AND rev = \\$2").WithArgs("b1", 1).WillReturnRows(
+		sqlmock.NewRows(revisionColumnNames).AddRow("r1", "b1", 1, "create", "u1", "{name,quantity}", nil,
+			[]byte(`{"id":"b1","name":"Dom Casmurro","quantity":3,"author":"Machado"}`), now))
+	mock.ExpectQuery("FROM book_revisions WHERE book_id = \\$1 AND rev = \\$2").WithArgs("b1", 2).WillReturnRows(
+		sqlmock.NewRows(revisionColumnNames).AddRow("r2", "b1", 2, "update", "u2", "{quantity,publisher}", nil,
+			[]byte(`{"id":"b1","name":"Dom Casmurro","quantity":5,"author":"Machado","publisher":"Garnier"}`), now))
+
+	req := withURLParam(httptest.NewRequest(http.MethodGet, "/api/books/b1/history/diff?from=1&to=2", nil), "id", "b1")
+	rr := httptest.NewRecorder()
+	NewBookHandler(db).GetBookRevisionDiff(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, esperava %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	var response struct {
+		Changes []models.FieldChange `json:"changes"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatalf("Erro ao decodificar resposta: %v", err)
+	}
+	if len(response.Changes) != 2 || response.Changes[0].Field != "publisher" || response.Changes[1].Field != "quantity" {
+		t.Fatalf("diferenças inesperadas: %+v", response.Changes)
+	}
+	if response.Changes[1].From != float64(3) || response.Changes[1].To != float64(5) {
+		t.Errorf("quantidade: %+v", response.Changes[1])
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas não atendidas: %s", err)
+	}
+}
+
+func TestRevertBookRejectsDeleteRevision(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Erro ao criar mock do banco de dados: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("FROM book_revisions WHERE book_id = \\$1 AND rev = \\$2").WithArgs("b1", 3).WillReturnRows(
+		sqlmock.NewRows(revisionColumnNames).AddRow("r3", "b1", 3, "delete", "", "{}", nil, []byte(`{"id":"b1"}`), time.Now()))
+
+	req := withURLParams(httptest.NewRequest(http.MethodPost, "/api/books/b1/revert/3", nil), "id", "b1", "rev", "3")
+	rr := httptest.NewRecorder()
+	NewBookHandler(db).RevertBook(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, esperava %d", rr.Code, http.StatusBadRequest)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas não atendidas: %s", err)
+	}
+}
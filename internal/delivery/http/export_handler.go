@@ -0,0 +1,325 @@
+package http
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	apperrors "projeto_livros/internal/domain/errors"
+	"projeto_livros/internal/domain/models"
+	repositories "projeto_livros/internal/repository"
+	"projeto_livros/pkg/xlsx"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Quantidade de linhas lidas do cursor do banco a cada FETCH
+const exportFetchSize = 500
+
+// exportColumns define a ordem fixa das colunas em todos os formatos de exportação
+var exportColumns = []string{
+	"id", "name", "author", "quantity", "genre_id", "genre_names",
+	"isbn", "publisher", "publication_year", "edition", "subjects",
+}
+
+// exportRecord é uma linha da exportação do catálogo. A ordem dos campos
+// segue exportColumns, o que também mantém a ordem das chaves no JSON Lines.
+type exportRecord struct {
+	ID              string   `json:"id"`
+	Name            string   `json:"name"`
+	Author          string   `json:"author"`
+	Quantity        int      `json:"quantity"`
+	GenreID         string   `json:"genre_id"`
+	GenreNames      []string `json:"genre_names"`
+	ISBN            string   `json:"isbn"`
+	Publisher       string   `json:"publisher"`
+	PublicationYear *int     `json:"publication_year"`
+	Edition         string   `json:"edition"`
+	Subjects        []string `json:"subjects"`
+}
+
+// newExportRecord monta a linha do livro; genreNames são os nomes de todos os
+// gêneros do livro, na ordem de book_genres (o principal primeiro)
+func newExportRecord(book *models.Book, genreNames []string) exportRecord {
+	record := exportRecord{
+		ID:              book.ID,
+		Name:            book.Name,
+		Author:          book.Author,
+		Quantity:        book.Quantity,
+		GenreNames:      genreNames,
+		ISBN:            book.ISBN,
+		Publisher:       book.Publisher,
+		PublicationYear: book.PublicationYear,
+		Edition:         book.Edition,
+		Subjects:        book.Subjects,
+	}
+	if book.GenreID != nil {
+		record.GenreID = *book.GenreID
+	}
+	return record
+}
+
+// values devolve os campos na ordem de exportColumns, para os formatos tabulares.
+// Ano ausente vira nil (célula vazia) e os gêneros e assuntos são unidos por "; ".
+func (e exportRecord) values() []interface{} {
+	var year interface{}
+	if e.PublicationYear != nil {
+		year = *e.PublicationYear
+	}
+	return []interface{}{
+		e.ID, e.Name, e.Author, e.Quantity, e.GenreID, strings.Join(e.GenreNames, "; "),
+		e.ISBN, e.Publisher, year, e.Edition, strings.Join(e.Subjects, "; "),
+	}
+}
+
+// exportWriter abstrai os formatos suportados pela exportação
+type exportWriter interface {
+	WriteRecord(record exportRecord) error
+	Flush() error
+	Close() error
+}
+
+type csvExportWriter struct {
+	w *csv.Writer
+}
+
+func newCSVExportWriter(out io.Writer) (*csvExportWriter, error) {
+	w := csv.NewWriter(out)
+	if err := w.Write(exportColumns); err != nil {
+		return nil, err
+	}
+	return &csvExportWriter{w: w}, nil
+}
+
+func (c *csvExportWriter) WriteRecord(record exportRecord) error {
+	values := record.values()
+	line := make([]string, len(values))
+	for i, v := range values {
+		if v != nil {
+			line[i] = fmt.Sprint(v)
+		}
+	}
+	return c.w.Write(line)
+}
+
+func (c *csvExportWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvExportWriter) Close() error {
+	return c.Flush()
+}
+
+type jsonlExportWriter struct {
+	enc *json.Encoder
+}
+
+func (j *jsonlExportWriter) WriteRecord(record exportRecord) error {
+	// Encode já termina cada objeto com uma quebra de linha
+	return j.enc.Encode(record)
+}
+
+func (j *jsonlExportWriter) Flush() error { return nil }
+func (j *jsonlExportWriter) Close() error { return nil }
+
+type xlsxExportWriter struct {
+	w *xlsx.StreamWriter
+}
+
+func newXLSXExportWriter(out io.Writer) (*xlsxExportWriter, error) {
+	w, err := xlsx.NewStreamWriter(out, "Livros")
+	if err != nil {
+		return nil, err
+	}
+	header := make([]interface{}, len(exportColumns))
+	for i, column := range exportColumns {
+		header[i] = column
+	}
+	if err := w.WriteRow(header); err != nil {
+		return nil, err
+	}
+	return &xlsxExportWriter{w: w}, nil
+}
+
+func (x *xlsxExportWriter) WriteRecord(record exportRecord) error {
+	return x.w.WriteRow(record.values())
+}
+
+func (x *xlsxExportWriter) Flush() error { return x.w.Flush() }
+func (x *xlsxExportWriter) Close() error { return x.w.Close() }
+
+// exportFormats associa cada formato ao tipo de conteúdo e à extensão do arquivo
+var exportFormats = map[string]struct {
+	contentType string
+	extension   string
+}{
+	"csv":   {"text/csv; charset=utf-8", "csv"},
+	"jsonl": {"application/x-ndjson", "jsonl"},
+	"xlsx":  {"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", "xlsx"},
+}
+
+func newExportWriter(format string, out io.Writer) (exportWriter, error) {
+	switch format {
+	case "csv":
+		return newCSVExportWriter(out)
+	case "jsonl":
+		return &jsonlExportWriter{enc: json.NewEncoder(out)}, nil
+	case "xlsx":
+		return newXLSXExportWriter(out)
+	}
+	return nil, fmt.Errorf("formato de exportação desconhecido: %s", format)
+}
+
+// buildExportFilter monta a cláusula WHERE da exportação a partir dos filtros opcionais
+func buildExportFilter(r *http.Request) (string, []interface{}, error) {
+	q := r.URL.Query()
+	// Livros na lixeira nunca são exportados
+	conditions := []string{"l.deleted_at IS NULL"}
+	var args []interface{}
+	add := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if genreID := q.Get("genre_id"); genreID != "" {
+		// Qualquer um dos gêneros do livro, não apenas o principal
+		add("EXISTS (SELECT 1 FROM book_genres bg WHERE bg.book_id = l.id AND bg.genre_id = $%d)", genreID)
+	}
+	if author := strings.TrimSpace(q.Get("author")); author != "" {
+		add("l.author ILIKE $%d", "%"+author+"%")
+	}
+	if name := strings.TrimSpace(q.Get("q")); name != "" {
+		add("l.name ILIKE $%d", "%"+name+"%")
+	}
+	for _, param := range []struct {
+		name     string
+		operator string
+	}{{"min_quantity", ">="}, {"max_quantity", "<="}} {
+		value := q.Get(param.name)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return "", nil, apperrors.New(apperrors.CodeInvalidInteger, param.name)
+		}
+		add("l.quantity "+param.operator+" $%d", n)
+	}
+
+	return "WHERE " + strings.Join(conditions, " AND "), args, nil
+}
+
+// ExportBooks exporta o catálogo completo em CSV, JSON Lines ou XLSX.
+// Os livros são lidos de um cursor do banco em blocos e escritos diretamente
+// na resposta, sem carregar o catálogo inteiro em memória.
+func (h *BookHandler) ExportBooks(w http.ResponseWriter, r *http.Request) {
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if format == "" {
+		format = "csv"
+	}
+	spec, ok := exportFormats[format]
+	if !ok {
+		sendError(w, r, apperrors.CodeInvalidParameter, "format", "csv, jsonl, xlsx")
+		return
+	}
+
+	where, args, err := buildExportFilter(r)
+	if err != nil {
+		sendServiceError(w, r, err, "Erro ao ler filtros")
+		return
+	}
+
+	ctx := r.Context()
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Erro ao iniciar transação de exportação: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+	// A exportação só lê dados; o rollback apenas libera o cursor
+	defer tx.Rollback()
+
+	// Todos os gêneros do livro, na ordem de book_genres
+	query := fmt.Sprintf(`
+		DECLARE export_cursor NO SCROLL CURSOR FOR
+		SELECT %s, ARRAY(
+			SELECT g.name FROM book_genres bg JOIN genres g ON g.id = bg.genre_id
+			WHERE bg.book_id = l.id ORDER BY bg.position
+		)
+		FROM livros l
+		%s
+		ORDER BY l.name, l.id`, repositories.BookColumns, where)
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		log.Printf("Erro ao abrir cursor de exportação: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+
+	filename := fmt.Sprintf("livros_%s.%s", time.Now().Format("20060102_150405"), spec.extension)
+	w.Header().Set("Content-Type", spec.contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	w.WriteHeader(http.StatusOK)
+
+	writer, err := newExportWriter(format, w)
+	if err != nil {
+		log.Printf("Erro ao iniciar exportação: %v", err)
+		return
+	}
+	flusher, _ := w.(http.Flusher)
+
+	// A partir daqui o cabeçalho já foi enviado; erros só podem ser registrados
+	total := 0
+	fetch := fmt.Sprintf("FETCH %d FROM export_cursor", exportFetchSize)
+	for {
+		rows, err := tx.QueryContext(ctx, fetch)
+		if err != nil {
+			log.Printf("Erro ao ler cursor de exportação: %v", err)
+			return
+		}
+		fetched := 0
+		for rows.Next() {
+			var genreNames []string
+			book, err := repositories.ScanBook(rows, pq.Array(&genreNames))
+			if err != nil {
+				rows.Close()
+				log.Printf("Erro ao ler livro para exportação: %v", err)
+				return
+			}
+			if err := writer.WriteRecord(newExportRecord(book, genreNames)); err != nil {
+				rows.Close()
+				log.Printf("Erro ao escrever exportação: %v", err)
+				return
+			}
+			fetched++
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			log.Printf("Erro ao iterar cursor de exportação: %v", err)
+			return
+		}
+		total += fetched
+		if fetched < exportFetchSize {
+			break
+		}
+		if err := writer.Flush(); err != nil {
+			log.Printf("Erro ao enviar exportação: %v", err)
+			return
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		log.Printf("Erro ao finalizar exportação: %v", err)
+		return
+	}
+	log.Printf("Exportação concluída: %d livros no formato %s", total, format)
+}
@@ -0,0 +1,109 @@
+package http
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestExportBooksCSV(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Erro ao criar mock do banco de dados: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DECLARE export_cursor").WithArgs("g1").WillReturnResult(sqlmock.NewResult(0, 0))
+	rows := sqlmock.NewRows([]string{"id", "name", "quantity", "genre_id", "author", "isbn",
+		"publisher", "publication_year", "edition", "subjects", "min_quantity", "genre_names"}).
+		AddRow("b1", "Dom Casmurro", 3, "g1", "Machado de Assis", "9788535910667",
+			"Companhia das Letras", 1899, "", "{Romance brasileiro}", 2, `{Romance,"Ficção brasileira"}`).
+		AddRow("b2", "Iracema", 1, "g1", "", "", "", nil, "", "{}", nil, "{Romance}")
+	mock.ExpectQuery("FETCH (.+) FROM export_cursor").WillReturnRows(rows)
+	mock.ExpectRollback()
+
+	bookHandler := NewBookHandler(db)
+	req := httptest.NewRequest("GET", "/api/books/export?format=csv&genre_id=g1", nil)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(bookHandler.ExportBooks).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("handler retornou código de status errado: obteve %v, esperava %v", rr.Code, http.StatusOK)
+	}
+	if cd := rr.Header().Get("Content-Disposition"); !strings.Contains(cd, ".csv") {
+		t.Errorf("Content-Disposition sem nome de arquivo csv: %q", cd)
+	}
+	records, err := csv.NewReader(rr.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("resposta não é um CSV válido: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("esperava cabeçalho e 2 linhas, obteve %d linhas", len(records))
+	}
+	if strings.Join(records[0], ",") != strings.Join(exportColumns, ",") {
+		t.Errorf("cabeçalho incorreto: %v", records[0])
+	}
+	if records[1][1] != "Dom Casmurro" || records[1][5] != "Romance; Ficção brasileira" || records[1][8] != "1899" {
+		t.Errorf("linha incorreta: %v", records[1])
+	}
+	if records[2][8] != "" {
+		t.Errorf("ano ausente deveria gerar célula vazia, obteve %q", records[2][8])
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas não atendidas: %s", err)
+	}
+}
+
+func TestExportBooksJSONLListsAllGenres(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Erro ao criar mock do banco de dados: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DECLARE export_cursor (.+) book_genres").WillReturnResult(sqlmock.NewResult(0, 0))
+	rows := sqlmock.NewRows([]string{"id", "name", "quantity", "genre_id", "author", "isbn",
+		"publisher", "publication_year", "edition", "subjects", "min_quantity", "genre_names"}).
+		AddRow("b1", "Dom Casmurro", 3, "g1", "", "", "", nil, "", "{}", nil, `{Romance,"Ficção brasileira"}`)
+	mock.ExpectQuery("FETCH (.+) FROM export_cursor").WillReturnRows(rows)
+	mock.ExpectRollback()
+
+	req := httptest.NewRequest("GET", "/api/books/export?format=jsonl", nil)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(NewBookHandler(db).ExportBooks).ServeHTTP(rr, req)
+
+	var record struct {
+		GenreNames []string `json:"genre_names"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &record); err != nil {
+		t.Fatalf("linha JSON inválida: %v (%s)", err, rr.Body.String())
+	}
+	if strings.Join(record.GenreNames, "|") != "Romance|Ficção brasileira" {
+		t.Errorf("genre_names = %q", record.GenreNames)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas não atendidas: %s", err)
+	}
+}
+
+func TestExportBooksInvalidFormat(t *testing.T) {
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Erro ao criar mock do banco de dados: %v", err)
+	}
+	defer db.Close()
+
+	req := httptest.NewRequest("GET", "/api/books/export?format=pdf", nil)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(NewBookHandler(db).ExportBooks).ServeHTTP(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("esperava %v para formato inválido, obteve %v", http.StatusBadRequest, rr.Code)
+	}
+}
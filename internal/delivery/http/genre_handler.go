@@ -3,19 +3,41 @@ package http
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
+	"projeto_livros/internal/delivery/middleware"
+	"projeto_livros/internal/delivery/problem"
+	apperrors "projeto_livros/internal/domain/errors"
 	"projeto_livros/internal/domain/models"
+	repositories "projeto_livros/internal/repository"
+	services "projeto_livros/internal/usecase"
 
-	"github.com/segmentio/ksuid"
+	"github.com/go-chi/chi/v5"
 )
 
 type GenreHandler struct {
-	db *sql.DB
+	db        *sql.DB
+	genres    repositories.GenreRepository
+	outbox    repositories.OutboxRepository
+	revisions repositories.RevisionRepository
+	catalog   *services.CatalogService
 }
 
 func NewGenreHandler(db *sql.DB) *GenreHandler {
-	return &GenreHandler{db: db}
+	return &GenreHandler{
+		db:        db,
+		genres:    repositories.NewPostgresGenreRepository(db),
+		outbox:    repositories.NewPostgresOutboxRepository(db),
+		revisions: repositories.NewPostgresRevisionRepository(db),
+		catalog:   services.NewCatalogService(db),
+	}
+}
+
+// addGenreEvent grava no outbox, na transação tx, um evento do gênero
+func (h *GenreHandler) addGenreEvent(tx *sql.Tx, r *http.Request, eventType string, payload models.GenreEventPayload) error {
+	return h.outbox.WithTx(tx).Add(eventType, models.AggregateGenre, payload.Genre.ID,
+		middleware.GetUserID(r.Context()), payload)
 }
 func (h *GenreHandler) GetAllGenres(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
@@ -28,25 +50,26 @@ func (h *GenreHandler) GetAllGenres(w http.ResponseWriter, r *http.Request) {
 		err := h.db.QueryRow(`
 			SELECT name, description 
 			FROM genres 
-			WHERE id = $1`, genreID).Scan(&genreName, &genreDescription)
+			WHERE id = $1 AND deleted_at IS NULL`, genreID).Scan(&genreName, &genreDescription)
 		if err == sql.ErrNoRows {
-			http.Error(w, "Gênero não encontrado", http.StatusNotFound)
+			sendError(w, r, apperrors.CodeGenreNotFound)
 			return
 		} else if err != nil {
 			log.Printf("Erro ao buscar gênero: %v", err)
-			http.Error(w, "Erro ao buscar gênero", http.StatusInternalServerError)
+			sendError(w, r, apperrors.CodeInternalError)
 			return
 		}
 
 		query := `
 			SELECT l.id, l.name, l.quantity
 			FROM livros l
-			WHERE l.genre_id = $1
+			JOIN book_genres bg ON bg.book_id = l.id
+			WHERE bg.genre_id = $1 AND l.deleted_at IS NULL
 			ORDER BY l.name`
 		rows, err := h.db.Query(query, genreID)
 		if err != nil {
 			log.Printf("Erro ao buscar livros: %v", err)
-			http.Error(w, "Erro ao buscar livros", http.StatusInternalServerError)
+			sendError(w, r, apperrors.CodeInternalError)
 			return
 		}
 		defer rows.Close()
@@ -71,27 +94,12 @@ func (h *GenreHandler) GetAllGenres(w http.ResponseWriter, r *http.Request) {
 		json.NewEncoder(w).Encode(genreWithBooks)
 	} else {
 		// Return all genres when no specific genre_id is provided
-		query := `
-			SELECT id, name, description 
-			FROM genres 
-			ORDER BY name`
-		rows, err := h.db.Query(query)
+		genres, err := h.genres.FindAll()
 		if err != nil {
 			log.Printf("Erro ao buscar todos os gêneros: %v", err)
-			http.Error(w, "Erro ao buscar gêneros", http.StatusInternalServerError)
+			sendError(w, r, apperrors.CodeInternalError)
 			return
 		}
-		defer rows.Close()
-
-		var genres []models.Genre
-		for rows.Next() {
-			var genre models.Genre
-			if err := rows.Scan(&genre.ID, &genre.Name, &genre.Description); err != nil {
-				log.Printf("Erro ao ler gênero: %v", err)
-				continue
-			}
-			genres = append(genres, genre)
-		}
 
 		json.NewEncoder(w).Encode(genres)
 	}
@@ -100,59 +108,287 @@ func (h *GenreHandler) CreateGenre(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	var genre models.Genre
 	if err := json.NewDecoder(r.Body).Decode(&genre); err != nil {
-		http.Error(w, "Erro ao ler dados", http.StatusBadRequest)
+		sendError(w, r, apperrors.CodeInvalidBody)
 		return
 	}
-	genre.ID = ksuid.New().String()
-	_, err := h.db.Exec("INSERT INTO genres (id, name, description) VALUES ($1, $2, $3)",
-		genre.ID, genre.Name, genre.Description)
-	if err != nil {
-		log.Printf("Erro ao criar gênero: %v", err)
-		http.Error(w, "Erro ao criar gênero", http.StatusInternalServerError)
+	if err := h.catalog.CreateGenre(&genre, middleware.GetUserID(r.Context())); err != nil {
+		sendServiceError(w, r, err, "Erro ao criar gênero")
 		return
 	}
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(genre)
 }
+
+// GetGenreTree devolve os gêneros organizados em árvore a partir das raízes
+func (h *GenreHandler) GetGenreTree(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	genres, err := h.genres.FindAll()
+	if err != nil {
+		log.Printf("Erro ao buscar gêneros: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+	json.NewEncoder(w).Encode(buildGenreTree(genres))
+}
+
+// buildGenreTree monta a árvore de gêneros. Gêneros cujo pai não está na
+// lista são tratados como raízes.
+func buildGenreTree(genres []models.Genre) []models.GenreNode {
+	children := map[string][]models.Genre{}
+	known := map[string]bool{}
+	for _, g := range genres {
+		known[g.ID] = true
+	}
+	var roots []models.Genre
+	for _, g := range genres {
+		if g.ParentID != nil && known[*g.ParentID] {
+			children[*g.ParentID] = append(children[*g.ParentID], g)
+		} else {
+			roots = append(roots, g)
+		}
+	}
+	var build func(level []models.Genre) []models.GenreNode
+	build = func(level []models.Genre) []models.GenreNode {
+		nodes := []models.GenreNode{}
+		for _, g := range level {
+			nodes = append(nodes, models.GenreNode{Genre: g, Children: build(children[g.ID])})
+		}
+		return nodes
+	}
+	return build(roots)
+}
+
+// GetBooksByGenre lista os livros de um gênero. Com include_descendants=true,
+// inclui também os livros dos subgêneros (ex.: Ficção inclui Cyberpunk).
 func (h *GenreHandler) GetBooksByGenre(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	genreID := r.URL.Query().Get("genre_id")
+	genreID := chi.URLParam(r, "id")
 	if genreID == "" {
-		http.Error(w, "ID do gênero é obrigatório", http.StatusBadRequest)
+		// Compatibilidade com o parâmetro de consulta usado anteriormente
+		genreID = r.URL.Query().Get("genre_id")
+	}
+	if genreID == "" {
+		sendError(w, r, apperrors.CodeGenreIDRequired)
 		return
 	}
+	genreFilter := "$1"
+	if r.URL.Query().Get("include_descendants") == "true" {
+		genreFilter = repositories.GenreSubtreeQuery
+	}
 	query := `
-		SELECT l.id, l.name, l.quantity, l.genre_id, l.author 
-		FROM livros l 
-		WHERE l.genre_id = $1`
+		SELECT ` + repositories.BookColumns + `
+		FROM livros l
+		WHERE l.deleted_at IS NULL AND EXISTS (
+			SELECT 1 FROM book_genres bg
+			WHERE bg.book_id = l.id AND bg.genre_id IN (` + genreFilter + `)
+		)
+		ORDER BY l.name`
 	rows, err := h.db.Query(query, genreID)
 	if err != nil {
 		log.Printf("Erro ao buscar livros por gênero: %v", err)
-		http.Error(w, "Erro ao buscar livros", http.StatusInternalServerError)
+		sendError(w, r, apperrors.CodeInternalError)
 		return
 	}
 	defer rows.Close()
-	var books []models.Book
+	books := []models.Book{}
 	for rows.Next() {
-		var book models.Book
-		var authorNull sql.NullString
-
-		if err := rows.Scan(&book.ID, &book.Name, &book.Quantity, &book.GenreID, &authorNull); err != nil {
+		book, err := repositories.ScanBook(rows)
+		if err != nil {
 			log.Printf("Erro ao ler livro: %v", err)
 			continue
 		}
+		books = append(books, *book)
+	}
+	json.NewEncoder(w).Encode(books)
+}
 
-		// Converter author de NullString para string
-		if authorNull.Valid {
-			book.Author = authorNull.String
-		} else {
-			book.Author = ""
+// UpdateGenre atualiza um gênero. Com PUT o corpo substitui o gênero inteiro;
+// com PATCH apenas os campos enviados são alterados.
+func (h *GenreHandler) UpdateGenre(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	id := chi.URLParam(r, "id")
+
+	existing, err := h.genres.FindByID(id)
+	if err == sql.ErrNoRows {
+		sendError(w, r, apperrors.CodeGenreNotFound)
+		return
+	} else if err != nil {
+		log.Printf("Erro ao buscar gênero: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+
+	genre := models.Genre{}
+	if r.Method == http.MethodPatch {
+		genre = *existing
+	}
+	if err := json.NewDecoder(r.Body).Decode(&genre); err != nil {
+		sendError(w, r, apperrors.CodeInvalidBody)
+		return
+	}
+	genre.ID = id
+	if err := h.catalog.UpdateGenre(&genre, middleware.GetUserID(r.Context())); err != nil {
+		sendServiceError(w, r, err, "Erro ao atualizar gênero")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(genre)
+}
+
+// DeleteGenre remove um gênero. Se houver livros com o gênero, a remoção é
+// recusada, a menos que reassign_to indique o gênero que os receberá. Os
+// subgêneros passam a ser filhos do pai do gênero removido.
+func (h *GenreHandler) DeleteGenre(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	id := chi.URLParam(r, "id")
+	reassignTo := r.URL.Query().Get("reassign_to")
+	hard, ok := hardDeleteRequested(w, r)
+	if !ok {
+		return
+	}
+
+	moved, err := h.catalog.DeleteGenre(id, reassignTo, hard, middleware.GetUserID(r.Context()))
+	var inUse *services.GenreInUseError
+	if errors.As(err, &inUse) {
+		problem.Write(w, problem.New(r, apperrors.CodeGenreInUse, inUse.Books).With("total_books", inUse.Books))
+		return
+	} else if err != nil {
+		sendServiceError(w, r, err, "Erro ao remover gênero")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":     "Gênero removido com sucesso",
+		"moved_books": moved,
+	})
+}
+
+// RestoreGenre tira um gênero da lixeira. Os livros e subgêneros transferidos
+// na remoção continuam onde estão.
+func (h *GenreHandler) RestoreGenre(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	id := chi.URLParam(r, "id")
+
+	var genre *models.Genre
+	err := repositories.RunInTx(h.db, func(tx *sql.Tx) error {
+		genres := h.genres.WithTx(tx)
+		rowsAffected, err := genres.Restore(id)
+		if err != nil {
+			return err
 		}
+		if rowsAffected == 0 {
+			return sql.ErrNoRows
+		}
+		if genre, err = genres.FindByID(id); err != nil {
+			return err
+		}
+		return h.addGenreEvent(tx, r, models.EventGenreRestored, models.GenreEventPayload{Genre: *genre})
+	})
+	if err == sql.ErrNoRows {
+		sendError(w, r, apperrors.CodeGenreNotInTrash)
+		return
+	} else if err != nil {
+		log.Printf("Erro ao restaurar gênero: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(genre)
+}
 
-		// Garantir que title seja igual a name para compatibilidade
-		book.Title = book.Name
+// MergeGenreRequest indica o gênero que recebe os livros na mesclagem
+type MergeGenreRequest struct {
+	TargetID string `json:"target_id"`
+}
+
+// MergeGenre mescla o gênero {id} em target_id: os livros e subgêneros passam
+// para o destino e o gênero de origem é removido, tudo em uma transação.
+func (h *GenreHandler) MergeGenre(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	sourceID := chi.URLParam(r, "id")
 
-		books = append(books, book)
+	var req MergeGenreRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.TargetID == "" {
+		sendError(w, r, apperrors.CodeMergeTargetRequired)
+		return
 	}
-	json.NewEncoder(w).Encode(books)
+	if req.TargetID == sourceID {
+		sendError(w, r, apperrors.CodeMergeIntoSelf)
+		return
+	}
+
+	var source *models.Genre
+	for _, id := range []string{sourceID, req.TargetID} {
+		genre, err := h.genres.FindByID(id)
+		if err == sql.ErrNoRows {
+			sendError(w, r, apperrors.CodeGenreNotFound)
+			return
+		} else if err != nil {
+			log.Printf("Erro ao buscar gênero: %v", err)
+			sendError(w, r, apperrors.CodeInternalError)
+			return
+		}
+		if id == sourceID {
+			source = genre
+		}
+	}
+	// Mover os subgêneros da origem para um de seus descendentes criaria um ciclo
+	descendant, err := h.genres.IsDescendant(req.TargetID, sourceID)
+	if err != nil {
+		log.Printf("Erro ao verificar hierarquia de gêneros: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+	if descendant {
+		sendError(w, r, apperrors.CodeMergeIntoDescendant)
+		return
+	}
+
+	var moved int64
+	err = repositories.RunInTx(h.db, func(tx *sql.Tx) error {
+		genres := h.genres.WithTx(tx)
+		bookIDs, err := genres.MoveBooks(sourceID, req.TargetID)
+		if err != nil {
+			return err
+		}
+		moved = int64(len(bookIDs))
+		// Cada livro movido ganha uma revisão, com o BookUpdated no outbox
+		revisions := h.revisions.WithTx(tx)
+		for _, id := range bookIDs {
+			if _, err := revisions.Record(id, models.RevisionUpdate, middleware.GetUserID(r.Context()), nil); err != nil {
+				return err
+			}
+		}
+		if err := genres.MoveChildren(sourceID, &req.TargetID); err != nil {
+			return err
+		}
+		// A origem fica vazia após a mesclagem e não vai para a lixeira
+		if _, err = genres.HardDelete(sourceID); err != nil {
+			return err
+		}
+		return h.addGenreEvent(tx, r, models.EventGenreMerged,
+			models.GenreEventPayload{Genre: *source, MergedInto: req.TargetID, MovedBooks: moved})
+	})
+	if err != nil {
+		log.Printf("Erro ao mesclar gêneros %s -> %s: %v", sourceID, req.TargetID, err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+
+	target, err := h.genres.FindByID(req.TargetID)
+	if err != nil {
+		log.Printf("Erro ao buscar gênero de destino: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+	log.Printf("Gênero %s mesclado em %s: %d livros movidos", sourceID, req.TargetID, moved)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":     "Gêneros mesclados com sucesso",
+		"genre":       target,
+		"moved_books": moved,
+	})
 }
@@ -0,0 +1,178 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"projeto_livros/internal/domain/models"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-chi/chi/v5"
+	"github.com/lib/pq"
+)
+
+// withURLParam devolve a requisição com o parâmetro de rota do chi preenchido
+func withURLParam(req *http.Request, key, value string) *http.Request {
+	return withURLParams(req, key, value)
+}
+
+// withURLParams adiciona vários parâmetros de rota, informados em pares chave, valor
+func withURLParams(req *http.Request, pairs ...string) *http.Request {
+	routeCtx := chi.NewRouteContext()
+	for i := 0; i+1 < len(pairs); i += 2 {
+		routeCtx.URLParams.Add(pairs[i], pairs[i+1])
+	}
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx))
+}
+
+var genreColumns = []string{"id", "name", "description", "parent_id"}
+
+func TestGetBooksByGenreIncludeDescendants(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Erro ao criar mock do banco de dados: %v", err)
+	}
+	defer db.Close()
+	rows := sqlmock.NewRows([]string{"id", "name", "quantity", "genre_id", "author",
+		"isbn", "publisher", "publication_year", "edition", "subjects", "min_quantity"}).
+		AddRow("1", "Neuromancer", 3, "cyberpunk", "William Gibson", "", "", nil, "", "{}", nil)
+	mock.ExpectQuery("WITH RECURSIVE subtree").WithArgs("ficcao").WillReturnRows(rows)
+
+	req := withURLParam(httptest.NewRequest(http.MethodGet, "/api/genres/ficcao/books?include_descendants=true", nil),
+		"id", "ficcao")
+	rr := httptest.NewRecorder()
+	NewGenreHandler(db).GetBooksByGenre(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, esperava %d", rr.Code, http.StatusOK)
+	}
+	var books []models.Book
+	if err := json.NewDecoder(rr.Body).Decode(&books); err != nil {
+		t.Fatalf("Erro ao decodificar resposta: %v", err)
+	}
+	if len(books) != 1 || books[0].Name != "Neuromancer" {
+		t.Errorf("livros inesperados: %+v", books)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas não atendidas: %s", err)
+	}
+}
+
+func TestBuildGenreTree(t *testing.T) {
+	ficcao, sciFi := "ficcao", "scifi"
+	tree := buildGenreTree([]models.Genre{
+		{ID: "cyberpunk", Name: "Cyberpunk", ParentID: &sciFi},
+		{ID: "ficcao", Name: "Ficção"},
+		{ID: "scifi", Name: "Ficção Científica", ParentID: &ficcao},
+		{ID: "poesia", Name: "Poesia"},
+	})
+
+	if len(tree) != 2 || tree[0].ID != "ficcao" || tree[1].ID != "poesia" {
+		t.Fatalf("raízes incorretas: %+v", tree)
+	}
+	if len(tree[0].Children) != 1 || len(tree[0].Children[0].Children) != 1 ||
+		tree[0].Children[0].Children[0].ID != "cyberpunk" {
+		t.Errorf("hierarquia incorreta: %+v", tree[0])
+	}
+}
+
+func TestCreateGenreDuplicateName(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Erro ao criar mock do banco de dados: %v", err)
+	}
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO genres").WillReturnError(&pq.Error{Code: "23505"})
+	mock.ExpectRollback()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/genres", strings.NewReader(`{"name": "Romance"}`))
+	rr := httptest.NewRecorder()
+	NewGenreHandler(db).CreateGenre(rr, req)
+
+	if rr.Code != http.StatusConflict {
+		t.Errorf("status = %d, esperava %d", rr.Code, http.StatusConflict)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas não atendidas: %s", err)
+	}
+}
+
+func TestDeleteGenreInUse(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Erro ao criar mock do banco de dados: %v", err)
+	}
+	defer db.Close()
+	mock.ExpectQuery("SELECT (.+) FROM genres WHERE id").WithArgs("g1").
+		WillReturnRows(sqlmock.NewRows(genreColumns).AddRow("g1", "Romance", "", nil))
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT COUNT").WithArgs("g1").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectRollback()
+
+	req := withURLParam(httptest.NewRequest(http.MethodDelete, "/api/genres/g1", nil), "id", "g1")
+	rr := httptest.NewRecorder()
+	NewGenreHandler(db).DeleteGenre(rr, req)
+
+	if rr.Code != http.StatusConflict {
+		t.Errorf("status = %d, esperava %d", rr.Code, http.StatusConflict)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas não atendidas: %s", err)
+	}
+}
+
+func TestMergeGenre(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Erro ao criar mock do banco de dados: %v", err)
+	}
+	defer db.Close()
+	mock.ExpectQuery("SELECT (.+) FROM genres WHERE id").WithArgs("g1").
+		WillReturnRows(sqlmock.NewRows(genreColumns).AddRow("g1", "Sci-Fi", "", nil))
+	mock.ExpectQuery("SELECT (.+) FROM genres WHERE id").WithArgs("g2").
+		WillReturnRows(sqlmock.NewRows(genreColumns).AddRow("g2", "Ficção Científica", "", nil))
+	mock.ExpectQuery("WITH RECURSIVE subtree").WithArgs("g1", "g2").
+		WillReturnRows(sqlmock.NewRows([]string{"found"}).AddRow(false))
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT l.id FROM livros l").WithArgs("g1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("b1").AddRow("b2"))
+	mock.ExpectExec("DELETE FROM book_genres").WithArgs("g1", "g2").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE book_genres SET genre_id").WithArgs("g1", "g2").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE livros l SET genre_id").WithArgs("g1", "g2").WillReturnResult(sqlmock.NewResult(0, 2))
+	// Os livros movidos ganham revisão e BookUpdated na mesma transação
+	expectRevision(mock, "b1", models.RevisionUpdate)
+	expectRevision(mock, "b2", models.RevisionUpdate)
+	mock.ExpectExec("UPDATE genres SET parent_id").WithArgs("g1", "g2").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM genres").WithArgs("g1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO outbox_events").
+		WithArgs(models.EventGenreMerged, models.AggregateGenre, "g1", "", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery("SELECT (.+) FROM genres WHERE id").WithArgs("g2").
+		WillReturnRows(sqlmock.NewRows(genreColumns).AddRow("g2", "Ficção Científica", "", nil))
+
+	req := withURLParam(httptest.NewRequest(http.MethodPost, "/api/genres/g1/merge",
+		strings.NewReader(`{"target_id": "g2"}`)), "id", "g1")
+	rr := httptest.NewRecorder()
+	NewGenreHandler(db).MergeGenre(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, esperava %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	var response struct {
+		MovedBooks int `json:"moved_books"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatalf("Erro ao decodificar resposta: %v", err)
+	}
+	if response.MovedBooks != 2 {
+		t.Errorf("moved_books = %d, esperava 2", response.MovedBooks)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas não atendidas: %s", err)
+	}
+}
@@ -0,0 +1,445 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"projeto_livros/internal/delivery/middleware"
+	"projeto_livros/internal/delivery/problem"
+	apperrors "projeto_livros/internal/domain/errors"
+	"projeto_livros/internal/domain/models"
+	"projeto_livros/internal/domain/validators"
+	repositories "projeto_livros/internal/repository"
+	services "projeto_livros/internal/usecase"
+	"projeto_livros/pkg/marc"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/segmentio/ksuid"
+)
+
+// Mapeamento entre models.Book e MARC21:
+//
+//	001      id
+//	020 $a   isbn
+//	100 $a   primeiro autor (papel author)
+//	700 $a   demais autores, com o papel em $4 (aut, trl, ill) ou $e
+//	245 $a   name (com $b, subtítulo, quando presente)
+//	250 $a   edition
+//	264 $b   publisher (260 $b em registros antigos)
+//	264 $c   publication_year (260 $c em registros antigos)
+//	650 $a   genres (assuntos que correspondem a gêneros cadastrados) e subjects (demais)
+
+var yearPattern = regexp.MustCompile(`\d{4}`)
+
+// marcRecordReader é satisfeito por marc.Reader e marc.XMLReader
+type marcRecordReader interface {
+	Read() (*marc.Record, error)
+}
+
+// marcRelators associa os códigos de relator MARC ($4) aos papéis de autor
+var marcRelators = map[string]string{
+	"aut": models.RoleAuthor,
+	"trl": models.RoleTranslator,
+	"ill": models.RoleIllustrator,
+}
+
+// marcRelatorTerms associa os termos de relator ($e) aos papéis de autor
+var marcRelatorTerms = map[string]string{
+	"autor":       models.RoleAuthor,
+	"author":      models.RoleAuthor,
+	"tradutor":    models.RoleTranslator,
+	"tradução":    models.RoleTranslator,
+	"translator":  models.RoleTranslator,
+	"ilustrador":  models.RoleIllustrator,
+	"ilustração":  models.RoleIllustrator,
+	"illustrator": models.RoleIllustrator,
+}
+
+// marcRelatorCode devolve o código $4 de um papel de autor
+func marcRelatorCode(role string) string {
+	for code, r := range marcRelators {
+		if r == role {
+			return code
+		}
+	}
+	return "aut"
+}
+
+// marcAuthorRole lê o papel de um campo 100/700 pelo $4 ou, na falta dele, pelo $e
+func marcAuthorRole(field marc.Field) string {
+	if role, ok := marcRelators[strings.ToLower(trimISBD(field.Subfield("4")))]; ok {
+		return role
+	}
+	if role, ok := marcRelatorTerms[strings.ToLower(trimISBD(field.Subfield("e")))]; ok {
+		return role
+	}
+	return models.RoleAuthor
+}
+
+// trimISBD remove a pontuação final usada na catalogação (ISBD), como em
+// "Dom Casmurro /" ou "Assis, Machado de,".
+func trimISBD(s string) string {
+	s = strings.TrimSpace(s)
+	for len(s) > 0 && strings.ContainsAny(s[len(s)-1:], "/:;,.=") {
+		s = strings.TrimSpace(s[:len(s)-1])
+	}
+	return s
+}
+
+// bookFromMARC converte um registro MARC em livro. genres associa o nome do
+// gênero em minúsculas ao seu id.
+func bookFromMARC(rec *marc.Record, genres map[string]string) models.Book {
+	var book models.Book
+
+	book.Name = trimISBD(rec.SubfieldValue("245", "a"))
+	if subtitle := trimISBD(rec.SubfieldValue("245", "b")); subtitle != "" {
+		book.Name += ": " + subtitle
+	}
+	book.Title = book.Name
+	for _, tag := range []string{"100", "700"} {
+		for _, field := range rec.DataFields(tag) {
+			if name := trimISBD(field.Subfield("a")); name != "" {
+				book.Authors = append(book.Authors, models.BookAuthor{Name: name, Role: marcAuthorRole(field)})
+			}
+		}
+	}
+	book.Author = trimISBD(rec.SubfieldValue("100", "a"))
+
+	// O 020 $a pode trazer qualificadores, como "9788535910667 (broch.)"
+	if isbn := strings.Fields(rec.SubfieldValue("020", "a")); len(isbn) > 0 {
+		book.ISBN = isbn[0]
+	}
+	book.Edition = trimISBD(rec.SubfieldValue("250", "a"))
+
+	for _, tag := range []string{"264", "260"} {
+		if book.Publisher == "" {
+			book.Publisher = trimISBD(rec.SubfieldValue(tag, "b"))
+		}
+		if book.PublicationYear == nil {
+			if y := yearPattern.FindString(rec.SubfieldValue(tag, "c")); y != "" {
+				year, _ := strconv.Atoi(y)
+				book.PublicationYear = &year
+			}
+		}
+	}
+	// Posições 07-10 do 008 guardam o ano de publicação
+	if f008 := rec.ControlField("008"); book.PublicationYear == nil && len(f008) >= 11 {
+		if year, err := strconv.Atoi(f008[7:11]); err == nil {
+			book.PublicationYear = &year
+		}
+	}
+
+	for _, field := range rec.DataFields("650") {
+		subject := trimISBD(field.Subfield("a"))
+		if subject == "" {
+			continue
+		}
+		// Os assuntos que viram gêneros não são repetidos em subjects, já que
+		// a exportação os devolve como os primeiros 650
+		if id, ok := genres[strings.ToLower(subject)]; ok {
+			book.Genres = append(book.Genres, models.BookGenre{ID: id, Name: subject})
+			continue
+		}
+		book.Subjects = append(book.Subjects, subject)
+	}
+	if len(book.Genres) > 0 {
+		genreID := book.Genres[0].ID
+		book.GenreID = &genreID
+	}
+	return book
+}
+
+// bookToMARC converte um livro em registro MARC. Os gêneros vêm nos primeiros 650.
+func bookToMARC(book *models.Book) *marc.Record {
+	rec := marc.NewRecord()
+	rec.AddControlField("001", book.ID)
+	rec.AddControlField("005", time.Now().UTC().Format("20060102150405.0"))
+
+	// 008: data de entrada, tipo de data "s" e ano de publicação
+	year := "    "
+	if book.PublicationYear != nil {
+		year = fmt.Sprintf("%04d", *book.PublicationYear)
+	}
+	f008 := time.Now().Format("060102") + "s" + year + strings.Repeat(" ", 29)
+	rec.AddControlField("008", f008)
+
+	if book.ISBN != "" {
+		rec.AddDataField("020", "", "", marc.Subfield{Code: "a", Value: book.ISBN})
+	}
+	// O primeiro autor vai no 100 e os demais participantes em 700
+	authors := book.Authors
+	if len(authors) == 0 && book.Author != "" {
+		authors = []models.BookAuthor{{Name: book.Author, Role: models.RoleAuthor}}
+	}
+	titleInd1 := "0"
+	for i, author := range authors {
+		tag := "700"
+		if i == 0 && author.Role == models.RoleAuthor {
+			tag = "100"
+			titleInd1 = "1"
+		}
+		rec.AddDataField(tag, "1", "",
+			marc.Subfield{Code: "a", Value: author.Name},
+			marc.Subfield{Code: "4", Value: marcRelatorCode(author.Role)})
+	}
+	rec.AddDataField("245", titleInd1, "0", marc.Subfield{Code: "a", Value: book.Name})
+	if book.Edition != "" {
+		rec.AddDataField("250", "", "", marc.Subfield{Code: "a", Value: book.Edition})
+	}
+	if book.Publisher != "" || book.PublicationYear != nil {
+		var subfields []marc.Subfield
+		if book.Publisher != "" {
+			subfields = append(subfields, marc.Subfield{Code: "b", Value: book.Publisher})
+		}
+		if book.PublicationYear != nil {
+			subfields = append(subfields, marc.Subfield{Code: "c", Value: strconv.Itoa(*book.PublicationYear)})
+		}
+		rec.AddDataField("264", "", "1", subfields...)
+	}
+
+	var subjects []string
+	for _, genre := range book.Genres {
+		subjects = append(subjects, genre.Name)
+	}
+	subjects = append(subjects, book.Subjects...)
+	seen := map[string]bool{}
+	for _, subject := range subjects {
+		key := strings.ToLower(subject)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		rec.AddDataField("650", "", "4", marc.Subfield{Code: "a", Value: subject})
+	}
+	return rec
+}
+
+// isMARCXMLRequest decide o formato da importação pelo parâmetro format ou pelo Content-Type
+func isMARCXMLRequest(r *http.Request) bool {
+	switch strings.ToLower(r.URL.Query().Get("format")) {
+	case "marcxml", "xml":
+		return true
+	case "marc", "iso2709", "mrc":
+		return false
+	}
+	return strings.Contains(r.Header.Get("Content-Type"), "xml")
+}
+
+type marcImportError struct {
+	Record     int    `json:"record"`
+	Error      string `json:"error"`
+	ExistingID string `json:"existing_id,omitempty"`
+}
+
+// ImportMARC importa registros MARC21 (ISO 2709) ou MARCXML e cria um livro por registro.
+// Como MARC não descreve estoque, a quantidade vem do parâmetro quantity (padrão 1).
+// Se nenhum livro for criado, responde 422 com os erros de cada registro.
+func (h *BookHandler) ImportMARC(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	defer r.Body.Close()
+
+	quantity := 1
+	if q := r.URL.Query().Get("quantity"); q != "" {
+		n, err := strconv.Atoi(q)
+		if err != nil || n <= 0 {
+			sendError(w, r, apperrors.CodeQuantityNotPositive)
+			return
+		}
+		quantity = n
+	}
+
+	genres := map[string]string{}
+	rows, err := h.db.Query("SELECT id, name FROM genres WHERE deleted_at IS NULL")
+	if err != nil {
+		log.Printf("Erro ao buscar gêneros para importação MARC: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+	for rows.Next() {
+		var id, name string
+		if err := rows.Scan(&id, &name); err == nil {
+			genres[strings.ToLower(name)] = id
+		}
+	}
+	rows.Close()
+
+	var reader marcRecordReader
+	if isMARCXMLRequest(r) {
+		reader = marc.NewXMLReader(r.Body)
+	} else {
+		reader = marc.NewReader(r.Body)
+	}
+
+	created := []models.Book{}
+	importErrors := []marcImportError{}
+	for index := 1; ; index++ {
+		rec, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// Um registro malformado compromete a leitura dos seguintes
+			importErrors = append(importErrors, marcImportError{Record: index, Error: err.Error()})
+			break
+		}
+
+		book := bookFromMARC(rec, genres)
+		if book.Name == "" {
+			importErrors = append(importErrors, marcImportError{Record: index, Error: "registro sem título (245 $a)"})
+			continue
+		}
+		book.Quantity = quantity
+		if fields := validators.NewBookSchema.Check(&book); len(fields) > 0 {
+			importErrors = append(importErrors, marcImportError{Record: index, Error: violationsText(fields)})
+			continue
+		}
+		if err := validators.NormalizeBookISBN(&book); err != nil {
+			importErrors = append(importErrors, marcImportError{Record: index, Error: err.Error()})
+			continue
+		}
+		existingID, err := h.catalog.FindBookByISBN(book.ISBN, "")
+		if err != nil {
+			log.Printf("Erro ao verificar ISBN duplicado: %v", err)
+			importErrors = append(importErrors, marcImportError{Record: index, Error: "erro ao verificar ISBN"})
+			continue
+		}
+		if existingID != "" {
+			importErrors = append(importErrors, marcImportError{
+				Record:     index,
+				Error:      "já existe um livro com este ISBN",
+				ExistingID: existingID,
+			})
+			continue
+		}
+		book.ID = ksuid.New().String()
+		authors, err := services.BookAuthorsInput(&book)
+		if err != nil {
+			importErrors = append(importErrors, marcImportError{Record: index, Error: err.Error()})
+			continue
+		}
+		rel := services.BookRelations{Authors: authors, GenreIDs: services.BookGenresInput(&book)}
+		if _, err := h.catalog.SaveBook(&book, true, rel, middleware.GetUserID(r.Context())); err != nil {
+			log.Printf("Erro ao inserir livro importado de MARC: %v", err)
+			importErrors = append(importErrors, marcImportError{Record: index, Error: "erro ao gravar livro"})
+			continue
+		}
+		created = append(created, book)
+	}
+
+	log.Printf("Importação MARC concluída: %d livros criados, %d erros", len(created), len(importErrors))
+	if len(created) == 0 {
+		problem.Write(w, problem.New(r, apperrors.CodeMARCImportFailed).With("records", importErrors))
+		return
+	}
+
+	response := map[string]interface{}{
+		"message": fmt.Sprintf("%d livros importados com sucesso", len(created)),
+		"books":   created,
+		"errors":  importErrors,
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// ExportMARC exporta o catálogo como MARC21 binário (padrão) ou MARCXML,
+// aceitando os mesmos filtros da exportação tabular.
+func (h *BookHandler) ExportMARC(w http.ResponseWriter, r *http.Request) {
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if format == "" {
+		format = "marc"
+	}
+	if format != "marc" && format != "marcxml" {
+		sendError(w, r, apperrors.CodeInvalidParameter, "format", "marc, marcxml")
+		return
+	}
+
+	where, args, err := buildExportFilter(r)
+	if err != nil {
+		sendServiceError(w, r, err, "Erro ao ler filtros")
+		return
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s,
+			ARRAY(SELECT g.name FROM book_genres bg
+				JOIN genres g ON g.id = bg.genre_id
+				WHERE bg.book_id = l.id ORDER BY bg.position),
+			ARRAY(SELECT ba.role || ':' || a.name FROM book_authors ba
+				JOIN authors a ON a.id = ba.author_id
+				WHERE ba.book_id = l.id ORDER BY ba.position)
+		FROM livros l
+		%s
+		ORDER BY l.name, l.id`, repositories.BookColumns, where)
+	rows, err := h.db.QueryContext(r.Context(), query, args...)
+	if err != nil {
+		log.Printf("Erro ao buscar livros para exportação MARC: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+	defer rows.Close()
+
+	timestamp := time.Now().Format("20060102_150405")
+	var write func(*marc.Record) error
+	var finish func() error
+	if format == "marcxml" {
+		w.Header().Set("Content-Type", "application/marcxml+xml; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="livros_%s.xml"`, timestamp))
+		xw := marc.NewXMLWriter(w)
+		write, finish = xw.Write, xw.Close
+	} else {
+		w.Header().Set("Content-Type", "application/marc")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="livros_%s.mrc"`, timestamp))
+		mw := marc.NewWriter(w)
+		write, finish = mw.Write, func() error { return nil }
+	}
+	w.WriteHeader(http.StatusOK)
+
+	total := 0
+	for rows.Next() {
+		var genres, authors pq.StringArray
+		book, err := repositories.ScanBook(rows, &genres, &authors)
+		if err != nil {
+			log.Printf("Erro ao ler livro para exportação MARC: %v", err)
+			return
+		}
+		for _, name := range genres {
+			book.Genres = append(book.Genres, models.BookGenre{Name: name})
+		}
+		// Cada autor vem como "papel:nome"
+		for _, entry := range authors {
+			if role, name, ok := strings.Cut(entry, ":"); ok {
+				book.Authors = append(book.Authors, models.BookAuthor{Name: name, Role: role})
+			}
+		}
+		if err := write(bookToMARC(book)); err != nil {
+			log.Printf("Erro ao escrever registro MARC do livro %s: %v", book.ID, err)
+			return
+		}
+		total++
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Erro ao iterar livros para exportação MARC: %v", err)
+		return
+	}
+	if err := finish(); err != nil {
+		log.Printf("Erro ao finalizar exportação MARC: %v", err)
+		return
+	}
+	log.Printf("Exportação MARC concluída: %d registros no formato %s", total, format)
+}
+
+// violationsText resume as violações de campo de um registro em uma linha,
+// como "name: deve ter no máximo 255 caracteres"
+func violationsText(fields []apperrors.FieldError) string {
+	parts := make([]string, len(fields))
+	for i, field := range fields {
+		parts[i] = field.Field + ": " + field.Message
+	}
+	return strings.Join(parts, "; ")
+}
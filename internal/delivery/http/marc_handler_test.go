@@ -0,0 +1,138 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"projeto_livros/internal/domain/models"
+	"projeto_livros/pkg/marc"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestMARCBookMappingRoundTrip(t *testing.T) {
+	year := 1899
+	genreID := "g1"
+	book := &models.Book{
+		ID:              "b1",
+		Name:            "Dom Casmurro",
+		Author:          "Machado de Assis",
+		GenreID:         &genreID,
+		ISBN:            "9788535910667",
+		Publisher:       "Garnier",
+		PublicationYear: &year,
+		Edition:         "1. ed.",
+		Subjects:        []string{"Ciúme"},
+		Genres:          []models.BookGenre{{ID: "g1", Name: "Romance"}, {ID: "g2", Name: "Drama"}},
+	}
+
+	rec := bookToMARC(book)
+	got := bookFromMARC(rec, map[string]string{"romance": "g1", "drama": "g2"})
+
+	if got.Name != book.Name || got.Author != book.Author || got.ISBN != book.ISBN {
+		t.Errorf("campos principais não preservados: %+v", got)
+	}
+	if got.Publisher != book.Publisher || got.PublicationYear == nil || *got.PublicationYear != year {
+		t.Errorf("editora/ano não preservados: %+v", got)
+	}
+	if got.GenreID == nil || *got.GenreID != "g1" {
+		t.Errorf("gênero não foi associado pelo 650: %+v", got.GenreID)
+	}
+	if len(got.Genres) != 2 || got.Genres[1].ID != "g2" {
+		t.Errorf("gêneros incorretos: %+v", got.Genres)
+	}
+	if len(got.Subjects) != 1 || got.Subjects[0] != "Ciúme" {
+		t.Errorf("assuntos incorretos: %v", got.Subjects)
+	}
+}
+
+func TestMARCAuthorRoles(t *testing.T) {
+	book := &models.Book{
+		ID:   "b1",
+		Name: "Dom Quixote",
+		Authors: []models.BookAuthor{
+			{Name: "Miguel de Cervantes", Role: models.RoleAuthor},
+			{Name: "Ernani Ssó", Role: models.RoleTranslator},
+			{Name: "Gustave Doré", Role: models.RoleIllustrator},
+		},
+	}
+
+	rec := bookToMARC(book)
+	if len(rec.DataFields("100")) != 1 || len(rec.DataFields("700")) != 2 {
+		t.Fatalf("esperava um 100 e dois 700, obteve %d e %d",
+			len(rec.DataFields("100")), len(rec.DataFields("700")))
+	}
+
+	got := bookFromMARC(rec, nil)
+	if len(got.Authors) != 3 {
+		t.Fatalf("autores não preservados: %+v", got.Authors)
+	}
+	for i, want := range book.Authors {
+		if got.Authors[i] != want {
+			t.Errorf("autor %d = %+v, esperava %+v", i, got.Authors[i], want)
+		}
+	}
+	if got.Author != "Miguel de Cervantes" {
+		t.Errorf("author = %q", got.Author)
+	}
+}
+
+func TestTrimISBD(t *testing.T) {
+	cases := map[string]string{
+		"Dom Casmurro /":      "Dom Casmurro",
+		"Assis, Machado de,":  "Assis, Machado de",
+		"Rio de Janeiro :":    "Rio de Janeiro",
+		"Sem pontuação":       "Sem pontuação",
+		"  Companhia das L. ": "Companhia das L",
+	}
+	for in, want := range cases {
+		if got := trimISBD(in); got != want {
+			t.Errorf("trimISBD(%q) = %q, esperava %q", in, got, want)
+		}
+	}
+}
+
+func TestImportMARCWithoutBooksIsUnprocessable(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Erro ao criar mock do banco de dados: %v", err)
+	}
+	defer db.Close()
+	mock.ExpectQuery("SELECT id, name FROM genres").WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+
+	// Dois registros sem título (245 $a): nenhum livro pode ser criado
+	var body bytes.Buffer
+	w := marc.NewWriter(&body)
+	for _, id := range []string{"1", "2"} {
+		rec := marc.NewRecord()
+		rec.AddControlField("001", id)
+		if err := w.Write(rec); err != nil {
+			t.Fatalf("erro ao escrever registro: %v", err)
+		}
+	}
+	req := httptest.NewRequest(http.MethodPost, "/api/books/import/marc", &body)
+	req.Header.Set("Content-Type", "application/marc")
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(NewBookHandler(db).ImportMARC).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, esperava %d: %s", rr.Code, http.StatusUnprocessableEntity, rr.Body.String())
+	}
+	var response struct {
+		Code    string `json:"code"`
+		Records []struct {
+			Record int `json:"record"`
+		} `json:"records"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("resposta não é um JSON válido: %v", err)
+	}
+	if response.Code != "MARC_IMPORT_FAILED" || len(response.Records) != 2 || response.Records[1].Record != 2 {
+		t.Errorf("problema incorreto: %s", rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas não atendidas: %s", err)
+	}
+}
@@ -0,0 +1,50 @@
+package http
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	apperrors "projeto_livros/internal/domain/errors"
+	"projeto_livros/internal/domain/validators"
+	"projeto_livros/internal/metadata"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type MetadataHandler struct {
+	provider metadata.Provider
+}
+
+// NewMetadataHandler cria o handler de metadados. provider pode ser nil quando
+// nenhum dump estiver configurado; nesse caso as consultas respondem 503.
+func NewMetadataHandler(provider metadata.Provider) *MetadataHandler {
+	return &MetadataHandler{provider: provider}
+}
+
+// GetByISBN devolve os metadados bibliográficos conhecidos para um ISBN
+func (h *MetadataHandler) GetByISBN(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	isbn, err := validators.NormalizeISBN(chi.URLParam(r, "isbn"))
+	if err != nil {
+		sendServiceError(w, r, err, "Erro ao validar ISBN")
+		return
+	}
+	if h.provider == nil {
+		sendError(w, r, apperrors.CodeMetadataNotConfigured)
+		return
+	}
+
+	meta, err := h.provider.LookupISBN(r.Context(), isbn)
+	if err == metadata.ErrNotFound {
+		sendError(w, r, apperrors.CodeMetadataNotFound)
+		return
+	} else if err != nil {
+		log.Printf("Erro ao consultar metadados do ISBN %s: %v", isbn, err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(meta)
+}
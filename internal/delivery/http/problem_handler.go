@@ -0,0 +1,50 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"projeto_livros/internal/delivery/problem"
+	apperrors "projeto_livros/internal/domain/errors"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ProblemType descreve um código do catálogo de erros no endereço do próprio
+// URI de tipo
+type ProblemType struct {
+	Type string `json:"type"`
+	apperrors.Definition
+}
+
+func problemType(d apperrors.Definition) ProblemType {
+	return ProblemType{Type: problem.TypeURI(d.Code), Definition: d}
+}
+
+// GetProblemTypes lista o catálogo de erros, ordenado pelo código, no idioma
+// do Accept-Language
+func GetProblemTypes(w http.ResponseWriter, r *http.Request) {
+	lang := problem.Language(r)
+	definitions := apperrors.Definitions(lang)
+	types := make([]ProblemType, 0, len(definitions))
+	for _, d := range definitions {
+		types = append(types, problemType(d))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Language", lang)
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(types)
+}
+
+// GetProblemType descreve o tipo de problema de /api/problems/{type}
+func GetProblemType(w http.ResponseWriter, r *http.Request) {
+	lang := problem.Language(r)
+	d, ok := apperrors.LookupIn(lang, problem.CodeFromType(chi.URLParam(r, "type")))
+	if !ok {
+		sendError(w, r, apperrors.CodeNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Language", lang)
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(problemType(d))
+}
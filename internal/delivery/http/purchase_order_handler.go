@@ -0,0 +1,324 @@
+package http
+
+import (
+	"database/sql"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"projeto_livros/internal/delivery/middleware"
+	apperrors "projeto_livros/internal/domain/errors"
+	"projeto_livros/internal/domain/models"
+	repositories "projeto_livros/internal/repository"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type PurchaseOrderHandler struct {
+	db        *sql.DB
+	orders    repositories.PurchaseOrderRepository
+	revisions repositories.RevisionRepository
+}
+
+func NewPurchaseOrderHandler(db *sql.DB) *PurchaseOrderHandler {
+	return &PurchaseOrderHandler{
+		db:        db,
+		orders:    repositories.NewPostgresPurchaseOrderRepository(db),
+		revisions: repositories.NewPostgresRevisionRepository(db),
+	}
+}
+
+// validateOrder confere fornecedor e linhas de um pedido e devolve o erro
+// para o cliente, ou nil se o pedido for válido
+func validateOrder(order *models.PurchaseOrder) error {
+	if order.SupplierID == "" {
+		return apperrors.New(apperrors.CodeOrderSupplierRequired)
+	}
+	seen := map[string]bool{}
+	for _, line := range order.Lines {
+		if line.BookID == "" {
+			return apperrors.New(apperrors.CodeOrderLineBookRequired)
+		}
+		if line.Quantity <= 0 {
+			return apperrors.New(apperrors.CodeOrderLineQuantityInvalid)
+		}
+		if line.UnitCost < 0 {
+			return apperrors.New(apperrors.CodeOrderLineCostNegative)
+		}
+		if seen[line.BookID] {
+			return apperrors.New(apperrors.CodeOrderLineDuplicateBook, line.BookID)
+		}
+		seen[line.BookID] = true
+	}
+	return nil
+}
+
+// sendOrderError traduz os erros de gravação de pedidos em respostas HTTP.
+// Dentro das transações, a situação do pedido que não permite a operação
+// vem como um erro do catálogo (ORDER_NOT_DRAFT...).
+func sendOrderError(w http.ResponseWriter, r *http.Request, err error, action string) {
+	switch {
+	case err == sql.ErrNoRows:
+		sendError(w, r, apperrors.CodeOrderNotFound)
+	case err == repositories.ErrOrderLineNotFound:
+		sendError(w, r, apperrors.CodeOrderLineNotFound)
+	case err == repositories.ErrReceiveExceedsOrdered:
+		sendError(w, r, apperrors.CodeReceiptExceedsOrdered)
+	case repositories.IsForeignKeyViolation(err):
+		sendError(w, r, apperrors.CodeOrderReferenceNotFound)
+	default:
+		sendServiceError(w, r, err, "Erro ao "+action+" pedido de compra")
+	}
+}
+
+// writeOrder responde com o pedido completo, como gravado no banco
+func (h *PurchaseOrderHandler) writeOrder(w http.ResponseWriter, r *http.Request, id string, status int) {
+	order, err := h.orders.FindByID(id)
+	if err != nil {
+		sendOrderError(w, r, err, "buscar")
+		return
+	}
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(order)
+}
+
+// GetPurchaseOrders lista os pedidos, com filtros opcionais status e supplier_id
+func (h *PurchaseOrderHandler) GetPurchaseOrders(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", models.OrderStatusDraft, models.OrderStatusSent, models.OrderStatusPartiallyReceived, models.OrderStatusReceived:
+	default:
+		sendError(w, r, apperrors.CodeInvalidParameter, "status", "draft, sent, partially_received, received")
+		return
+	}
+	page := 1
+	perPage := 20
+	if p, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && p > 0 {
+		page = p
+	}
+	if pp, err := strconv.Atoi(r.URL.Query().Get("per_page")); err == nil && pp > 0 {
+		perPage = pp
+	}
+
+	orders, total, err := h.orders.FindAll(status, r.URL.Query().Get("supplier_id"), perPage, (page-1)*perPage)
+	if err != nil {
+		log.Printf("Erro ao buscar pedidos de compra: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data":         orders,
+		"page":         page,
+		"per_page":     perPage,
+		"total_orders": total,
+		"total_pages":  (total + perPage - 1) / perPage,
+	})
+}
+
+func (h *PurchaseOrderHandler) GetPurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	h.writeOrder(w, r, chi.URLParam(r, "id"), http.StatusOK)
+}
+
+// CreatePurchaseOrder cria um pedido em rascunho
+func (h *PurchaseOrderHandler) CreatePurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var order models.PurchaseOrder
+	if err := json.NewDecoder(r.Body).Decode(&order); err != nil {
+		sendError(w, r, apperrors.CodeInvalidBody)
+		return
+	}
+	if err := validateOrder(&order); err != nil {
+		sendServiceError(w, r, err, "Erro ao validar pedido")
+		return
+	}
+
+	err := repositories.RunInTx(h.db, func(tx *sql.Tx) error {
+		return h.orders.WithTx(tx).Create(&order)
+	})
+	if err != nil {
+		sendOrderError(w, r, err, "criar")
+		return
+	}
+	log.Printf("Pedido de compra criado: %s (%d linhas)", order.ID, len(order.Lines))
+	h.writeOrder(w, r, order.ID, http.StatusCreated)
+}
+
+// UpdatePurchaseOrder substitui fornecedor, observações e linhas de um pedido em rascunho
+func (h *PurchaseOrderHandler) UpdatePurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var order models.PurchaseOrder
+	if err := json.NewDecoder(r.Body).Decode(&order); err != nil {
+		sendError(w, r, apperrors.CodeInvalidBody)
+		return
+	}
+	order.ID = chi.URLParam(r, "id")
+	if err := validateOrder(&order); err != nil {
+		sendServiceError(w, r, err, "Erro ao validar pedido")
+		return
+	}
+
+	err := repositories.RunInTx(h.db, func(tx *sql.Tx) error {
+		orders := h.orders.WithTx(tx)
+		status, err := orders.LockStatus(order.ID)
+		if err != nil {
+			return err
+		}
+		if status != models.OrderStatusDraft {
+			return apperrors.New(apperrors.CodeOrderNotDraft)
+		}
+		return orders.Update(&order)
+	})
+	if err != nil {
+		sendOrderError(w, r, err, "atualizar")
+		return
+	}
+	h.writeOrder(w, r, order.ID, http.StatusOK)
+}
+
+// DeletePurchaseOrder remove um pedido em rascunho
+func (h *PurchaseOrderHandler) DeletePurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	id := chi.URLParam(r, "id")
+
+	err := repositories.RunInTx(h.db, func(tx *sql.Tx) error {
+		orders := h.orders.WithTx(tx)
+		status, err := orders.LockStatus(id)
+		if err != nil {
+			return err
+		}
+		if status != models.OrderStatusDraft {
+			return apperrors.New(apperrors.CodeOrderNotDraft)
+		}
+		_, err = orders.Delete(id)
+		return err
+	})
+	if err != nil {
+		sendOrderError(w, r, err, "remover")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Pedido removido com sucesso"})
+}
+
+// SendPurchaseOrder marca um pedido em rascunho como enviado ao fornecedor
+func (h *PurchaseOrderHandler) SendPurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	id := chi.URLParam(r, "id")
+
+	err := repositories.RunInTx(h.db, func(tx *sql.Tx) error {
+		orders := h.orders.WithTx(tx)
+		status, err := orders.LockStatus(id)
+		if err != nil {
+			return err
+		}
+		if status != models.OrderStatusDraft {
+			return apperrors.New(apperrors.CodeOrderNotDraft)
+		}
+		lines, err := orders.PendingLines(id)
+		if err != nil {
+			return err
+		}
+		if len(lines) == 0 {
+			return apperrors.New(apperrors.CodeOrderEmpty)
+		}
+		return orders.SetStatus(id, models.OrderStatusSent)
+	})
+	if err != nil {
+		sendOrderError(w, r, err, "enviar")
+		return
+	}
+	h.writeOrder(w, r, id, http.StatusOK)
+}
+
+// ReceivePurchaseOrderRequest lista as linhas recebidas. Sem linhas, todo o
+// saldo pendente do pedido é recebido.
+type ReceivePurchaseOrderRequest struct {
+	Lines []models.ReceiveLine `json:"lines"`
+}
+
+// ReceivePurchaseOrder registra o recebimento de itens de um pedido enviado.
+// Em uma única transação, soma as quantidades ao estoque dos livros, grava um
+// recebimento (stock_receipts) por linha e atualiza a situação do pedido.
+func (h *PurchaseOrderHandler) ReceivePurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	id := chi.URLParam(r, "id")
+
+	var req ReceivePurchaseOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		sendError(w, r, apperrors.CodeInvalidBody)
+		return
+	}
+	for _, line := range req.Lines {
+		if line.LineID == "" || line.Quantity <= 0 {
+			sendError(w, r, apperrors.CodeReceiptLineInvalid)
+			return
+		}
+	}
+	userID := middleware.GetUserID(r.Context())
+
+	var receipts []models.StockReceipt
+	var finalStatus string
+	err := repositories.RunInTx(h.db, func(tx *sql.Tx) error {
+		orders := h.orders.WithTx(tx)
+		status, err := orders.LockStatus(id)
+		if err != nil {
+			return err
+		}
+		if status != models.OrderStatusSent && status != models.OrderStatusPartiallyReceived {
+			return apperrors.New(apperrors.CodeOrderNotReceivable)
+		}
+
+		lines := req.Lines
+		if len(lines) == 0 {
+			pending, err := orders.PendingLines(id)
+			if err != nil {
+				return err
+			}
+			for _, p := range pending {
+				lines = append(lines, models.ReceiveLine{LineID: p.ID, Quantity: p.Quantity - p.ReceivedQuantity})
+			}
+		}
+		for _, line := range lines {
+			receipt, err := orders.ReceiveLine(id, line.LineID, line.Quantity, userID)
+			if err != nil {
+				return err
+			}
+			receipts = append(receipts, *receipt)
+		}
+		// O aumento de estoque entra no histórico de revisões de cada livro; o
+		// estoque anterior é o do primeiro recebimento do livro neste pedido
+		recorded := map[string]bool{}
+		for _, receipt := range receipts {
+			if recorded[receipt.BookID] {
+				continue
+			}
+			recorded[receipt.BookID] = true
+			if _, err := h.revisions.WithTx(tx).RecordStockChange(receipt.BookID, userID, receipt.QuantityBefore); err != nil {
+				return err
+			}
+		}
+
+		pending, err := orders.PendingLines(id)
+		if err != nil {
+			return err
+		}
+		finalStatus = models.OrderStatusPartiallyReceived
+		if len(pending) == 0 {
+			finalStatus = models.OrderStatusReceived
+		}
+		return orders.SetStatus(id, finalStatus)
+	})
+	if err != nil {
+		sendOrderError(w, r, err, "receber")
+		return
+	}
+	log.Printf("Pedido de compra %s recebido: %d linhas, situação %s", id, len(receipts), finalStatus)
+	h.writeOrder(w, r, id, http.StatusOK)
+}
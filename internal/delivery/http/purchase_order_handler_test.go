@@ -0,0 +1,101 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"projeto_livros/internal/domain/models"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+var orderLineColumns = []string{"id", "book_id", "name", "quantity", "unit_cost", "received_quantity"}
+
+func TestReceivePurchaseOrderAllPending(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Erro ao criar mock do banco de dados: %v", err)
+	}
+	defer db.Close()
+	now := time.Now()
+
+	// Sem corpo, todo o saldo pendente é recebido na mesma transação
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT status FROM purchase_orders WHERE id = \\$1 FOR UPDATE").WithArgs("po1").
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(models.OrderStatusPartiallyReceived))
+	mock.ExpectQuery("AND pl.received_quantity < pl.quantity").WithArgs("po1").
+		WillReturnRows(sqlmock.NewRows(orderLineColumns).AddRow("pl1", "b1", "Livro 1", 10, 12.5, 4))
+	mock.ExpectQuery("SELECT book_id, quantity - received_quantity FROM purchase_order_lines").
+		WithArgs("pl1", "po1").WillReturnRows(sqlmock.NewRows([]string{"book_id", "pending"}).AddRow("b1", 6))
+	mock.ExpectExec("UPDATE purchase_order_lines SET received_quantity").WithArgs(6, "pl1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("UPDATE livros SET quantity = quantity \\+ \\$1 WHERE id = \\$2 RETURNING").WithArgs(6, "b1").
+		WillReturnRows(sqlmock.NewRows([]string{"quantity_before"}).AddRow(0))
+	mock.ExpectQuery("INSERT INTO stock_receipts").
+		WithArgs(sqlmock.AnyArg(), "po1", "pl1", "b1", 6, "").
+		WillReturnRows(sqlmock.NewRows([]string{"received_at"}).AddRow(now))
+	// Sem revisão anterior, o estoque de antes do recebimento gera o StockChanged
+	expectRevision(mock, "b1", models.RevisionUpdate)
+	expectStockChanged(mock, "b1")
+	mock.ExpectQuery("AND pl.received_quantity < pl.quantity").WithArgs("po1").
+		WillReturnRows(sqlmock.NewRows(orderLineColumns))
+	mock.ExpectExec("UPDATE purchase_orders").WithArgs("po1", models.OrderStatusReceived).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	mock.ExpectQuery("FROM purchase_orders o").WithArgs("po1").WillReturnRows(
+		sqlmock.NewRows([]string{"id", "supplier_id", "name", "status", "notes", "total", "created_at", "sent_at", "received_at"}).
+			AddRow("po1", "s1", "Distribuidora", models.OrderStatusReceived, "", 125.0, now, now, now))
+	mock.ExpectQuery("FROM purchase_order_lines pl").WithArgs("po1").
+		WillReturnRows(sqlmock.NewRows(orderLineColumns).AddRow("pl1", "b1", "Livro 1", 10, 12.5, 10))
+	mock.ExpectQuery("FROM stock_receipts").WithArgs("po1").WillReturnRows(
+		sqlmock.NewRows([]string{"id", "order_id", "line_id", "book_id", "quantity", "received_at", "received_by"}).
+			AddRow("r1", "po1", "pl1", "b1", 4, now, "").
+			AddRow("r2", "po1", "pl1", "b1", 6, now, ""))
+
+	handler := NewPurchaseOrderHandler(db)
+	req := withURLParam(httptest.NewRequest(http.MethodPost, "/api/purchase-orders/po1/receive", nil), "id", "po1")
+	rr := httptest.NewRecorder()
+	handler.ReceivePurchaseOrder(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, esperava %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	var order models.PurchaseOrder
+	if err := json.NewDecoder(rr.Body).Decode(&order); err != nil {
+		t.Fatalf("Erro ao decodificar resposta: %v", err)
+	}
+	if order.Status != models.OrderStatusReceived || len(order.Receipts) != 2 {
+		t.Errorf("pedido inesperado: %+v", order)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas não atendidas: %s", err)
+	}
+}
+
+func TestReceivePurchaseOrderRejectsDraft(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Erro ao criar mock do banco de dados: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT status FROM purchase_orders").WithArgs("po1").
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(models.OrderStatusDraft))
+	mock.ExpectRollback()
+
+	handler := NewPurchaseOrderHandler(db)
+	req := withURLParam(httptest.NewRequest(http.MethodPost, "/api/purchase-orders/po1/receive", nil), "id", "po1")
+	rr := httptest.NewRecorder()
+	handler.ReceivePurchaseOrder(rr, req)
+
+	if rr.Code != http.StatusConflict {
+		t.Errorf("status = %d, esperava %d", rr.Code, http.StatusConflict)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas não atendidas: %s", err)
+	}
+}
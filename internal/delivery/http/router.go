@@ -0,0 +1,177 @@
+package http
+
+import (
+	"database/sql"
+	"net/http"
+	"projeto_livros/docs"
+	"projeto_livros/internal/config"
+	"projeto_livros/internal/delivery/graphql"
+	"projeto_livros/internal/delivery/middleware"
+	"projeto_livros/internal/delivery/problem"
+	apperrors "projeto_livros/internal/domain/errors"
+	"projeto_livros/internal/events"
+	"projeto_livros/internal/metadata"
+	"projeto_livros/internal/notify"
+	repositories "projeto_livros/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// RouterDeps são as dependências das rotas que vivem fora da requisição
+type RouterDeps struct {
+	Metadata  metadata.Provider // nil desabilita o enriquecimento por ISBN
+	DigestJob *notify.DigestJob // nil desabilita o envio imediato do resumo
+	Broker    *events.Broker    // Origem do feed SSE
+}
+
+// NewRouter monta o roteador da API: os middlewares, /health, as rotas /api e
+// /graphql. Os arquivos do frontend são registrados por quem chama.
+func NewRouter(db *sql.DB, cfg *config.Config, deps RouterDeps) (*chi.Mux, error) {
+	bookHandler := NewBookHandler(db)
+	bookHandler.SetMetadataProvider(deps.Metadata)
+	genreHandler := NewGenreHandler(db)
+	authorHandler := NewAuthorHandler(db)
+	metadataHandler := NewMetadataHandler(deps.Metadata)
+	statsHandler := NewStatsHandler(db, cfg.StatsCacheTTL, cfg.LowStockThreshold)
+	alertHandler := NewAlertHandler(db, deps.DigestJob)
+	supplierHandler := NewSupplierHandler(db)
+	purchaseOrderHandler := NewPurchaseOrderHandler(db)
+	trashHandler := NewTrashHandler(db, cfg.TrashRetention)
+	auditHandler := NewAuditHandler(db)
+	webhookHandler := NewWebhookHandler(db)
+	streamHandler := NewStreamHandler(deps.Broker, cfg.StreamHeartbeat)
+	graphqlHandler, err := graphql.NewHandler(db, graphql.Limits{MaxDepth: cfg.GraphQLMaxDepth, MaxComplexity: cfg.GraphQLMaxComplexity})
+	if err != nil {
+		return nil, err
+	}
+
+	r := chi.NewRouter()
+	r.Use(chimiddleware.RequestID)
+	r.Use(chimiddleware.Logger)
+	r.Use(middleware.Recoverer)
+	r.Use(middleware.CorsMiddleware)
+	// Temporarily comment out the auth middleware for testing
+	// r.Use(middleware.AuthMiddleware)
+	// Enquanto isso, tokens enviados ainda são validados e identificam o usuário e o papel
+	r.Use(middleware.OptionalAuth)
+	// Registra as requisições de escrita no log de auditoria encadeado
+	r.Use(middleware.Audit(r, repositories.NewPostgresAuditRepository(db), AuditLoaders(db)))
+	// Confere parâmetros e corpos com a especificação OpenAPI gerada das rotas
+	spec := docs.NewSpec(r)
+	r.Use(middleware.ValidateRequests(r, spec, cfg.ValidateResponses, cfg.MaxBodyBytes))
+
+	// Rotas e métodos desconhecidos também respondem em problem+json
+	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		problem.Send(w, r, apperrors.CodeNotFound)
+	})
+	r.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		problem.Send(w, r, apperrors.CodeMethodNotAllowed, r.Method)
+	})
+
+	// Health check endpoint
+	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("OK"))
+	})
+
+	// Endpoint direto para atualizar quantidade via query params
+	r.Get("/update-quantity", bookHandler.UpdateQuantityDirect)
+
+	// API routes using RESTful conventions
+	r.Route("/api/books", func(r chi.Router) {
+		r.Get("/", bookHandler.GetAllBooks)                          // Lista todos os livros
+		r.Post("/", bookHandler.CreateBook)                          // Cria um livro
+		r.Post("/batch", bookHandler.CreateAllBooks)                 // Cria vários livros
+		r.Get("/export", bookHandler.ExportBooks)                    // Exporta o catálogo (csv, jsonl, xlsx)
+		r.Get("/export/marc", bookHandler.ExportMARC)                // Exporta o catálogo em MARC21 ou MARCXML
+		r.Post("/import/marc", bookHandler.ImportMARC)               // Importa registros MARC21 ou MARCXML
+		r.Get("/isbn/{isbn}", bookHandler.GetBookByISBN)             // Busca um livro pelo ISBN
+		r.Get("/{id}", bookHandler.GetBook)                          // Busca um livro pelo ID
+		r.Put("/{id}", bookHandler.UpdateBook)                       // Atualiza um livro
+		r.Delete("/{id}", bookHandler.DeleteBook)                    // Move para a lixeira (hard=true apaga, só admin)
+		r.Post("/{id}/restore", bookHandler.RestoreBook)             // Restaura um livro da lixeira
+		r.Get("/{id}/history", bookHandler.GetBookHistory)           // Revisões do livro
+		r.Get("/{id}/history/diff", bookHandler.GetBookRevisionDiff) // Diferenças entre as revisões from e to
+		r.Get("/{id}/history/{rev}", bookHandler.GetBookRevision)    // Estado do livro em uma revisão
+		r.Post("/{id}/revert/{rev}", bookHandler.RevertBook)         // Volta o livro ao estado de uma revisão
+		r.Post("/update-quantity", bookHandler.UpdateBookQuantity)   // Endpoint para atualização de quantidade
+	})
+
+	r.Route("/api/authors", func(r chi.Router) {
+		r.Get("/", authorHandler.GetAllAuthors)            // Lista os autores (busca por q)
+		r.Post("/", authorHandler.CreateAuthor)            // Cria um autor
+		r.Get("/{id}", authorHandler.GetAuthor)            // Busca um autor pelo ID
+		r.Put("/{id}", authorHandler.UpdateAuthor)         // Renomeia um autor
+		r.Delete("/{id}", authorHandler.DeleteAuthor)      // Remove um autor sem livros
+		r.Get("/{id}/books", authorHandler.GetAuthorBooks) // Livros do autor, com o papel em cada um
+	})
+
+	r.Route("/api/genres", func(r chi.Router) {
+		r.Get("/", genreHandler.GetAllGenres)              // Lista todos os gêneros
+		r.Post("/", genreHandler.CreateGenre)              // Cria um gênero
+		r.Get("/tree", genreHandler.GetGenreTree)          // Gêneros em árvore (hierarquia)
+		r.Get("/{id}/books", genreHandler.GetBooksByGenre) // Livros de um gênero
+		r.Put("/{id}", genreHandler.UpdateGenre)           // Substitui os dados de um gênero
+		r.Patch("/{id}", genreHandler.UpdateGenre)         // Atualiza campos de um gênero
+		r.Delete("/{id}", genreHandler.DeleteGenre)        // Move para a lixeira (reassign_to move os livros)
+		r.Post("/{id}/restore", genreHandler.RestoreGenre) // Restaura um gênero da lixeira
+		r.Post("/{id}/merge", genreHandler.MergeGenre)     // Mescla o gênero em outro
+	})
+
+	r.Get("/api/trash", trashHandler.GetTrash) // Livros e gêneros na lixeira (type=book|genre)
+
+	r.Get("/api/metadata/isbn/{isbn}", metadataHandler.GetByISBN) // Metadados bibliográficos por ISBN
+	r.Route("/api/alerts/low-stock", func(r chi.Router) {
+		r.Get("/", alertHandler.GetLowStockAlerts)                 // Lista alertas (status=open|acknowledged|resolved|all)
+		r.Post("/digest", alertHandler.SendDigest)                 // Envia o resumo de alertas imediatamente
+		r.Post("/{id}/acknowledge", alertHandler.AcknowledgeAlert) // Reconhece um alerta
+	})
+
+	r.Route("/api/suppliers", func(r chi.Router) {
+		r.Get("/", supplierHandler.GetAllSuppliers)
+		r.Post("/", supplierHandler.CreateSupplier)
+		r.Get("/{id}", supplierHandler.GetSupplier)
+		r.Put("/{id}", supplierHandler.UpdateSupplier)
+		r.Delete("/{id}", supplierHandler.DeleteSupplier) // Apenas fornecedores sem pedidos
+	})
+
+	r.Route("/api/purchase-orders", func(r chi.Router) {
+		r.Get("/", purchaseOrderHandler.GetPurchaseOrders)                 // Lista pedidos (status, supplier_id)
+		r.Post("/", purchaseOrderHandler.CreatePurchaseOrder)              // Cria um pedido em rascunho
+		r.Get("/{id}", purchaseOrderHandler.GetPurchaseOrder)              // Pedido com linhas e recebimentos
+		r.Put("/{id}", purchaseOrderHandler.UpdatePurchaseOrder)           // Altera um rascunho
+		r.Delete("/{id}", purchaseOrderHandler.DeletePurchaseOrder)        // Remove um rascunho
+		r.Post("/{id}/send", purchaseOrderHandler.SendPurchaseOrder)       // Rascunho -> enviado
+		r.Post("/{id}/receive", purchaseOrderHandler.ReceivePurchaseOrder) // Recebe itens e aumenta o estoque
+	})
+
+	r.Get("/api/stats", statsHandler.GetStats) // Totais do catálogo para o painel
+
+	r.Get("/api/admin/audit", auditHandler.GetAuditLog) // Log de auditoria (só admin)
+
+	r.Get("/api/events/stream", streamHandler.StreamEvents) // Feed de alterações (SSE; topics e Last-Event-ID)
+
+	r.Get("/graphql", graphqlHandler.ServeHTTP)  // Consultas GraphQL (query na URL)
+	r.Post("/graphql", graphqlHandler.ServeHTTP) // Consultas e mutações GraphQL
+
+	r.Get("/api/openapi.json", spec.ServeHTTP)     // Especificação OpenAPI 3 de todas as rotas
+	r.Get("/api/docs", docs.UIHandler)             // Swagger UI com a especificação
+	r.Get("/api/docs/{file}", docs.UIAssetHandler) // Arquivos do Swagger UI, embutidos no binário
+
+	r.Get("/api/problems", GetProblemTypes)       // Catálogo de códigos de erro (problem+json)
+	r.Get("/api/problems/{type}", GetProblemType) // Um tipo de problema, pelo URI de tipo
+
+	r.Route("/api/webhooks", func(r chi.Router) {
+		r.Get("/", webhookHandler.GetWebhooks)                                             // Lista as assinaturas (só admin)
+		r.Post("/", webhookHandler.CreateWebhook)                                          // Cria uma assinatura; o segredo só vem nesta resposta
+		r.Get("/{id}", webhookHandler.GetWebhook)                                          // Busca uma assinatura
+		r.Put("/{id}", webhookHandler.UpdateWebhook)                                       // Altera; active=true reativa e zera as falhas
+		r.Delete("/{id}", webhookHandler.DeleteWebhook)                                    // Remove a assinatura e as entregas
+		r.Post("/{id}/ping", webhookHandler.PingWebhook)                                   // Agenda uma entrega de teste
+		r.Get("/{id}/deliveries", webhookHandler.GetWebhookDeliveries)                     // Registro de entregas (status)
+		r.Get("/{id}/deliveries/{deliveryID}", webhookHandler.GetWebhookDelivery)          // Entrega com as tentativas
+		r.Post("/{id}/deliveries/{deliveryID}/redeliver", webhookHandler.RedeliverWebhook) // Reenvia uma entrega
+	})
+
+	return r, nil
+}
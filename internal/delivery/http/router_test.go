@@ -0,0 +1,234 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"projeto_livros/docs"
+	"projeto_livros/internal/config"
+	apperrors "projeto_livros/internal/domain/errors"
+	"projeto_livros/internal/events"
+	"projeto_livros/internal/i18n"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-chi/chi/v5"
+)
+
+func newTestRouter(t *testing.T) *chi.Mux {
+	t.Helper()
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Erro ao criar mock do banco de dados: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	router, err := NewRouter(db, &config.Config{StreamHeartbeat: time.Second, GraphQLMaxDepth: 10, GraphQLMaxComplexity: 5000,
+		ValidateResponses: true},
+		RouterDeps{Broker: events.NewBroker(10)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return router
+}
+
+// Toda rota do roteador precisa de uma operação na especificação OpenAPI, e
+// toda operação documentada precisa existir no roteador
+func TestOpenAPICoversAllRoutes(t *testing.T) {
+	router := newTestRouter(t)
+	_, undocumented := docs.Generate(router)
+	for _, route := range undocumented {
+		t.Errorf("Rota sem entrada na especificação OpenAPI (docs/operations.go): %s", route)
+	}
+
+	routed := map[string]bool{}
+	chi.Walk(router, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		routed[docs.RouteKey(method, route)] = true
+		return nil
+	})
+	for _, key := range docs.Operations() {
+		if !routed[key] {
+			t.Errorf("Operação documentada sem rota correspondente: %s", key)
+		}
+	}
+}
+
+func TestOpenAPIDocumentServed(t *testing.T) {
+	router := newTestRouter(t)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Status esperado 200, obtido %d", rr.Code)
+	}
+
+	var doc struct {
+		OpenAPI    string                                `json:"openapi"`
+		Servers    []struct{ URL string }                `json:"servers"`
+		Paths      map[string]map[string]json.RawMessage `json:"paths"`
+		Components struct {
+			Schemas   map[string]json.RawMessage `json:"schemas"`
+			Responses map[string]json.RawMessage `json:"responses"`
+		} `json:"components"`
+	}
+	body := rr.Body.String()
+	if err := json.Unmarshal([]byte(body), &doc); err != nil {
+		t.Fatalf("Documento inválido: %v", err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") || len(doc.Servers) == 0 || doc.Servers[0].URL != "http://localhost:3001" {
+		t.Errorf("Cabeçalho do documento incorreto: %s %+v", doc.OpenAPI, doc.Servers)
+	}
+	if _, ok := doc.Paths["/api/books/{id}"]["put"]; !ok {
+		t.Error("Operação PUT /api/books/{id} ausente do documento")
+	}
+
+	// Todas as referências apontam para componentes existentes
+	for _, match := range regexp.MustCompile(`"#/components/(schemas|responses)/([^"]+)"`).FindAllStringSubmatch(body, -1) {
+		components := doc.Components.Schemas
+		if match[1] == "responses" {
+			components = doc.Components.Responses
+		}
+		if _, ok := components[match[2]]; !ok {
+			t.Errorf("Referência sem componente: %s", match[0])
+		}
+	}
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/docs", nil))
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), "/api/openapi.json") {
+		t.Errorf("Página do Swagger UI incorreta: %d", rr.Code)
+	}
+
+	// Os arquivos da página vêm do próprio binário, sem CDN
+	if strings.Contains(rr.Body.String(), "https://") {
+		t.Errorf("Página do Swagger UI carrega arquivos externos: %s", rr.Body.String())
+	}
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/docs/viewer.js", nil))
+	if rr.Code != http.StatusOK || !strings.HasPrefix(rr.Header().Get("Content-Type"), "text/javascript") {
+		t.Errorf("viewer.js: status %d, Content-Type %q", rr.Code, rr.Header().Get("Content-Type"))
+	}
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/docs/..%2Fopenapi.go", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("arquivo fora da pasta do Swagger UI: status %d", rr.Code)
+	}
+}
+
+func TestProblemCatalogServed(t *testing.T) {
+	router := newTestRouter(t)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/problems", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rr.Code, rr.Body.String())
+	}
+	var types []ProblemType
+	if err := json.Unmarshal(rr.Body.Bytes(), &types); err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, pt := range types {
+		if pt.Code == "GENRE_IN_USE" {
+			found = pt.Type == "/api/problems/genre-in-use" && pt.Status == http.StatusConflict
+		}
+	}
+	if !found {
+		t.Errorf("GENRE_IN_USE ausente ou incorreto no catálogo")
+	}
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/problems/stock-underflow", nil))
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), `"code":"STOCK_UNDERFLOW"`) {
+		t.Errorf("tipo STOCK_UNDERFLOW: %d %s", rr.Code, rr.Body.String())
+	}
+}
+
+// Rotas desconhecidas, métodos não aceitos e tipos fora do catálogo também
+// respondem em problem+json
+func TestUnknownRoutesAnswerWithProblems(t *testing.T) {
+	router := newTestRouter(t)
+	for _, tc := range []struct {
+		method, url string
+		status      int
+		code        string
+	}{
+		{http.MethodGet, "/api/nada", http.StatusNotFound, "NOT_FOUND"},
+		{http.MethodDelete, "/api/stats", http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED"},
+		{http.MethodGet, "/api/problems/nada", http.StatusNotFound, "NOT_FOUND"},
+	} {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(tc.method, tc.url, nil))
+		var body struct {
+			Status   int    `json:"status"`
+			Code     string `json:"code"`
+			Instance string `json:"instance"`
+		}
+		json.Unmarshal(rr.Body.Bytes(), &body)
+		if rr.Code != tc.status || rr.Header().Get("Content-Type") != "application/problem+json" ||
+			body.Code != tc.code || body.Status != tc.status || body.Instance != tc.url {
+			t.Errorf("%s %s: %d %s %s", tc.method, tc.url, rr.Code, rr.Header().Get("Content-Type"), rr.Body.String())
+		}
+	}
+}
+
+// Todo código do catálogo de erros tem título e mensagem nos dois idiomas
+func TestProblemCatalogTranslated(t *testing.T) {
+	for _, lang := range i18n.Languages() {
+		for _, d := range apperrors.Definitions(lang) {
+			if !i18n.Has(d.Code+".title") || !i18n.Has(d.Code+".detail") || d.Status < 400 {
+				t.Errorf("%s: código %s incompleto: %+v", lang, d.Code, d)
+			}
+		}
+	}
+}
+
+func TestProblemsFollowAcceptLanguage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Erro ao criar mock do banco de dados: %v", err)
+	}
+	defer db.Close()
+	router, err := NewRouter(db, &config.Config{StreamHeartbeat: time.Second, GraphQLMaxDepth: 10, GraphQLMaxComplexity: 5000,
+		ValidateResponses: true},
+		RouterDeps{Broker: events.NewBroker(10)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mock.MatchExpectationsInOrder(false)
+	for i := 0; i < 3; i++ {
+		mock.ExpectQuery("SELECT (.+) FROM livros l WHERE l.id = \\$1").WillReturnRows(sqlmock.NewRows(nil))
+	}
+
+	for _, tc := range []struct{ header, lang, title, detail string }{
+		{"", "pt-BR", "Livro não encontrado", "Livro não encontrado"},
+		{"en-US,en;q=0.9", "en", "Book not found", "Book not found"},
+		{"fr", "pt-BR", "Livro não encontrado", "Livro não encontrado"},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/api/books/nao-existe", nil)
+		req.Header.Set("Accept-Language", tc.header)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		var body struct {
+			Title  string `json:"title"`
+			Detail string `json:"detail"`
+			Code   string `json:"code"`
+		}
+		json.Unmarshal(rr.Body.Bytes(), &body)
+		if rr.Code != http.StatusNotFound || body.Code != "BOOK_NOT_FOUND" || body.Title != tc.title || body.Detail != tc.detail ||
+			rr.Header().Get("Content-Language") != tc.lang {
+			t.Errorf("Accept-Language %q: %d %s %s", tc.header, rr.Code, rr.Header().Get("Content-Language"), rr.Body.String())
+		}
+	}
+
+	// As violações de campo também são traduzidas
+	req := httptest.NewRequest(http.MethodPost, "/api/books", strings.NewReader(`{"name": "Dom Casmurro", "quantity": 0}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Language", "en")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusBadRequest || !strings.Contains(rr.Body.String(), `"message":"must be greater than or equal to 1"`) ||
+		!strings.Contains(rr.Body.String(), `"title":"Invalid data"`) {
+		t.Errorf("validação em inglês: %d %s", rr.Code, rr.Body.String())
+	}
+}
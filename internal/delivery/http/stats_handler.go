@@ -0,0 +1,63 @@
+package http
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	apperrors "projeto_livros/internal/domain/errors"
+	"projeto_livros/internal/domain/models"
+	repositories "projeto_livros/internal/repository"
+	"sync"
+	"time"
+)
+
+// statsListLimit é o tamanho máximo das listas de estoque baixo e de livros recentes
+const statsListLimit = 10
+
+type StatsHandler struct {
+	stats             repositories.StatsRepository
+	ttl               time.Duration
+	lowStockThreshold int
+
+	mu        sync.Mutex
+	cached    *models.CatalogStats
+	expiresAt time.Time
+}
+
+// NewStatsHandler cria o handler do painel. O resultado é reaproveitado por
+// ttl; com ttl zero as estatísticas são recalculadas a cada requisição.
+func NewStatsHandler(db *sql.DB, ttl time.Duration, lowStockThreshold int) *StatsHandler {
+	return &StatsHandler{
+		stats:             repositories.NewPostgresStatsRepository(db),
+		ttl:               ttl,
+		lowStockThreshold: lowStockThreshold,
+	}
+}
+
+// GetStats devolve os totais do catálogo para o painel. refresh=true ignora o cache.
+func (h *StatsHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	// O lock cobre o cálculo para que requisições simultâneas com o cache
+	// expirado não disparem várias vezes as mesmas consultas
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	cacheStatus := "HIT"
+	if h.cached == nil || time.Now().After(h.expiresAt) || r.URL.Query().Get("refresh") == "true" {
+		stats, err := h.stats.CatalogStats(h.lowStockThreshold, statsListLimit)
+		if err != nil {
+			log.Printf("Erro ao calcular estatísticas: %v", err)
+			sendError(w, r, apperrors.CodeInternalError)
+			return
+		}
+		h.cached = stats
+		h.expiresAt = time.Now().Add(h.ttl)
+		cacheStatus = "MISS"
+	}
+
+	w.Header().Set("X-Cache", cacheStatus)
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(h.cached)
+}
@@ -0,0 +1,59 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"projeto_livros/internal/domain/models"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestGetStatsCached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Erro ao criar mock do banco de dados: %v", err)
+	}
+	defer db.Close()
+	bookColumns := []string{"id", "name", "quantity", "genre_id", "author",
+		"isbn", "publisher", "publication_year", "edition", "subjects", "min_quantity"}
+
+	// As consultas só devem ser feitas uma vez: a segunda requisição usa o cache
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\)").WithArgs(2).WillReturnRows(
+		sqlmock.NewRows([]string{"titles", "copies", "genres", "no_genre", "no_author", "low_stock"}).
+			AddRow(3, 12, 2, 1, 0, 1))
+	mock.ExpectQuery("FROM genres g").WillReturnRows(
+		sqlmock.NewRows([]string{"id", "name", "parent_id", "titles", "copies"}).
+			AddRow("g1", "Romance", nil, 2, 10).
+			AddRow("g2", "Terror", nil, 0, 0))
+	mock.ExpectQuery("AND \\(l.quantity < l.min_quantity").WithArgs(2, statsListLimit).WillReturnRows(
+		sqlmock.NewRows(bookColumns).AddRow("1", "Livro 1", 1, nil, "", "", "", nil, "", "{}", nil))
+	mock.ExpectQuery("ORDER BY l.created_at DESC").WithArgs(statsListLimit).WillReturnRows(
+		sqlmock.NewRows(bookColumns).AddRow("3", "Livro 3", 5, "g1", "Autor", "", "", nil, "", "{}", nil))
+
+	handler := NewStatsHandler(db, time.Minute, 2)
+	for i, wantCache := range []string{"MISS", "HIT"} {
+		rr := httptest.NewRecorder()
+		handler.GetStats(rr, httptest.NewRequest(http.MethodGet, "/api/stats", nil))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("requisição %d: status = %d, esperava %d", i, rr.Code, http.StatusOK)
+		}
+		if got := rr.Header().Get("X-Cache"); got != wantCache {
+			t.Errorf("requisição %d: X-Cache = %q, esperava %q", i, got, wantCache)
+		}
+		var stats models.CatalogStats
+		if err := json.NewDecoder(rr.Body).Decode(&stats); err != nil {
+			t.Fatalf("Erro ao decodificar resposta: %v", err)
+		}
+		if stats.Totals.Titles != 3 || stats.Totals.Copies != 12 || len(stats.Genres) != 2 ||
+			len(stats.LowStock) != 1 || len(stats.RecentBooks) != 1 {
+			t.Errorf("requisição %d: estatísticas inesperadas: %+v", i, stats)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas não atendidas: %s", err)
+	}
+}
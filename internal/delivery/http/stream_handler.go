@@ -0,0 +1,98 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	apperrors "projeto_livros/internal/domain/errors"
+	"projeto_livros/internal/domain/models"
+	"projeto_livros/internal/events"
+	"time"
+)
+
+type StreamHandler struct {
+	broker    *events.Broker
+	heartbeat time.Duration
+}
+
+// NewStreamHandler cria o handler do feed de alterações. heartbeat é o
+// intervalo dos comentários que mantêm a conexão aberta em proxies.
+func NewStreamHandler(broker *events.Broker, heartbeat time.Duration) *StreamHandler {
+	return &StreamHandler{broker: broker, heartbeat: heartbeat}
+}
+
+// StreamEvents envia as alterações de livros, gêneros e estoque como
+// Server-Sent Events. O filtro topics aceita, separados por vírgula: books,
+// genres, stock, book:<id>, genre:<id> e type:<tipo>. Clientes reconectados
+// informam o último evento recebido no cabeçalho Last-Event-ID (ou no
+// parâmetro last_event_id) e recebem o que perderam; se ele já saiu do
+// histórico, recebem um evento reset e devem recarregar os dados.
+func (h *StreamHandler) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	filter := events.ParseFilter(r.URL.Query().Get("topics"))
+	for _, topic := range filter.Topics {
+		if !events.ValidTopic(topic) {
+			sendError(w, r, apperrors.CodeStreamTopicInvalid, topic)
+			return
+		}
+	}
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+
+	sub, replay, resumed := h.broker.Subscribe(filter, lastEventID)
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // Desliga o buffer do nginx
+	w.WriteHeader(http.StatusOK)
+	rc := http.NewResponseController(w)
+
+	fmt.Fprint(w, "retry: 3000\n\n")
+	if !resumed {
+		fmt.Fprint(w, "event: reset\ndata: {\"reason\":\"Last-Event-ID fora do histórico\"}\n\n")
+	}
+	for _, event := range replay {
+		if err := writeSSE(w, event); err != nil {
+			return
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-sub.Events():
+			if !ok {
+				// Cliente lento desconectado; ele volta pelo Last-Event-ID
+				return
+			}
+			if err := writeSSE(w, event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func writeSSE(w http.ResponseWriter, event models.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
@@ -0,0 +1,96 @@
+package http
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"projeto_livros/internal/domain/models"
+	"projeto_livros/internal/events"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readSSEMessage lê uma mensagem SSE (até a linha em branco)
+func readSSEMessage(t *testing.T, reader *bufio.Reader) []string {
+	t.Helper()
+	var lines []string
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Erro ao ler o stream: %v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return lines
+		}
+		lines = append(lines, line)
+	}
+}
+
+func TestStreamEventsReplaysAndStreams(t *testing.T) {
+	broker := events.NewBroker(10)
+	broker.Publish(models.Event{ID: 1, Type: models.EventBookCreated, AggregateType: models.AggregateBook, AggregateID: "b1"})
+	broker.Publish(models.Event{ID: 2, Type: models.EventBookUpdated, AggregateType: models.AggregateBook, AggregateID: "b2"})
+	broker.Publish(models.Event{ID: 3, Type: models.EventBookUpdated, AggregateType: models.AggregateBook, AggregateID: "b1"})
+
+	server := httptest.NewServer(http.HandlerFunc(NewStreamHandler(broker, 50*time.Millisecond).StreamEvents))
+	defer server.Close()
+
+	req, _ := http.NewRequest("GET", server.URL+"?topics=book:b1", nil)
+	req.Header.Set("Last-Event-ID", "1")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Erro na requisição: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+	reader := bufio.NewReader(resp.Body)
+
+	if msg := readSSEMessage(t, reader); len(msg) != 1 || msg[0] != "retry: 3000" {
+		t.Errorf("primeira mensagem = %v", msg)
+	}
+	// Só o evento 3 é do livro b1 e posterior ao 1
+	msg := readSSEMessage(t, reader)
+	if len(msg) != 3 || msg[0] != "id: 3" || msg[1] != "event: BookUpdated" || !strings.HasPrefix(msg[2], "data: {") {
+		t.Errorf("replay = %v", msg)
+	}
+
+	broker.Publish(models.Event{ID: 4, Type: models.EventBookDeleted, AggregateType: models.AggregateBook, AggregateID: "b2"})
+	broker.Publish(models.Event{ID: 5, Type: models.EventBookDeleted, AggregateType: models.AggregateBook, AggregateID: "b1"})
+	if msg := readSSEMessage(t, reader); len(msg) != 3 || msg[0] != "id: 5" || msg[1] != "event: BookDeleted" {
+		t.Errorf("evento ao vivo = %v", msg)
+	}
+	if msg := readSSEMessage(t, reader); len(msg) != 1 || msg[0] != ": heartbeat" {
+		t.Errorf("esperava heartbeat, recebeu %v", msg)
+	}
+}
+
+func TestStreamEventsSendsResetForUnknownLastEventID(t *testing.T) {
+	broker := events.NewBroker(10)
+	server := httptest.NewServer(http.HandlerFunc(NewStreamHandler(broker, time.Minute).StreamEvents))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "?last_event_id=42")
+	if err != nil {
+		t.Fatalf("Erro na requisição: %v", err)
+	}
+	defer resp.Body.Close()
+	reader := bufio.NewReader(resp.Body)
+	readSSEMessage(t, reader)
+	if msg := readSSEMessage(t, reader); len(msg) != 2 || msg[0] != "event: reset" {
+		t.Errorf("esperava reset, recebeu %v", msg)
+	}
+}
+
+func TestStreamEventsRejectsInvalidTopic(t *testing.T) {
+	handler := NewStreamHandler(events.NewBroker(10), time.Minute)
+	req := httptest.NewRequest("GET", "/api/events/stream?topics=books,authors", nil)
+	rr := httptest.NewRecorder()
+	handler.StreamEvents(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, esperava 400", rr.Code)
+	}
+}
@@ -0,0 +1,131 @@
+package http
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	apperrors "projeto_livros/internal/domain/errors"
+	"projeto_livros/internal/domain/models"
+	repositories "projeto_livros/internal/repository"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type SupplierHandler struct {
+	suppliers repositories.SupplierRepository
+}
+
+func NewSupplierHandler(db *sql.DB) *SupplierHandler {
+	return &SupplierHandler{suppliers: repositories.NewPostgresSupplierRepository(db)}
+}
+
+// decodeSupplier lê e valida o corpo de criação/atualização de fornecedor
+func decodeSupplier(w http.ResponseWriter, r *http.Request) (*models.Supplier, bool) {
+	var supplier models.Supplier
+	if err := json.NewDecoder(r.Body).Decode(&supplier); err != nil {
+		sendError(w, r, apperrors.CodeInvalidBody)
+		return nil, false
+	}
+	supplier.Name = strings.TrimSpace(supplier.Name)
+	supplier.Email = strings.TrimSpace(supplier.Email)
+	supplier.Phone = strings.TrimSpace(supplier.Phone)
+	if supplier.Name == "" {
+		sendError(w, r, apperrors.CodeSupplierNameRequired)
+		return nil, false
+	}
+	return &supplier, true
+}
+
+func (h *SupplierHandler) GetAllSuppliers(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	suppliers, err := h.suppliers.FindAll()
+	if err != nil {
+		log.Printf("Erro ao buscar fornecedores: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(suppliers)
+}
+
+func (h *SupplierHandler) GetSupplier(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	supplier, err := h.suppliers.FindByID(chi.URLParam(r, "id"))
+	if err == sql.ErrNoRows {
+		sendError(w, r, apperrors.CodeSupplierNotFound)
+		return
+	} else if err != nil {
+		log.Printf("Erro ao buscar fornecedor: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(supplier)
+}
+
+func (h *SupplierHandler) CreateSupplier(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	supplier, ok := decodeSupplier(w, r)
+	if !ok {
+		return
+	}
+	if err := h.suppliers.Create(supplier); err != nil {
+		if repositories.IsUniqueViolation(err) {
+			sendError(w, r, apperrors.CodeSupplierNameConflict)
+			return
+		}
+		log.Printf("Erro ao criar fornecedor: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(supplier)
+}
+
+func (h *SupplierHandler) UpdateSupplier(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	supplier, ok := decodeSupplier(w, r)
+	if !ok {
+		return
+	}
+	supplier.ID = chi.URLParam(r, "id")
+	rowsAffected, err := h.suppliers.Update(supplier)
+	if err != nil {
+		if repositories.IsUniqueViolation(err) {
+			sendError(w, r, apperrors.CodeSupplierNameConflict)
+			return
+		}
+		log.Printf("Erro ao atualizar fornecedor: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+	if rowsAffected == 0 {
+		sendError(w, r, apperrors.CodeSupplierNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(supplier)
+}
+
+// DeleteSupplier remove um fornecedor que ainda não tenha pedidos
+func (h *SupplierHandler) DeleteSupplier(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	rowsAffected, err := h.suppliers.Delete(chi.URLParam(r, "id"))
+	if err != nil {
+		if repositories.IsForeignKeyViolation(err) {
+			sendError(w, r, apperrors.CodeSupplierInUse)
+			return
+		}
+		log.Printf("Erro ao remover fornecedor: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+	if rowsAffected == 0 {
+		sendError(w, r, apperrors.CodeSupplierNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Fornecedor removido com sucesso"})
+}
@@ -0,0 +1,78 @@
+package http
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"projeto_livros/internal/delivery/middleware"
+	apperrors "projeto_livros/internal/domain/errors"
+	"projeto_livros/internal/domain/models"
+	repositories "projeto_livros/internal/repository"
+	"strconv"
+	"time"
+)
+
+type TrashHandler struct {
+	trash     repositories.TrashRepository
+	retention time.Duration
+}
+
+// NewTrashHandler cria o handler da lixeira. retention é o tempo até a limpeza
+// automática, usado para informar quando cada item será apagado.
+func NewTrashHandler(db *sql.DB, retention time.Duration) *TrashHandler {
+	return &TrashHandler{trash: repositories.NewPostgresTrashRepository(db), retention: retention}
+}
+
+// hardDeleteRequested lê a opção hard=true das remoções. Apenas
+// administradores podem apagar definitivamente: para os demais a resposta 403
+// já é enviada e ok é false.
+func hardDeleteRequested(w http.ResponseWriter, r *http.Request) (hard bool, ok bool) {
+	if r.URL.Query().Get("hard") != "true" {
+		return false, true
+	}
+	if !middleware.IsAdmin(r.Context()) {
+		sendError(w, r, apperrors.CodeAdminRequired)
+		return false, false
+	}
+	return true, true
+}
+
+// GetTrash lista os livros e gêneros na lixeira, com filtro opcional type
+// (book ou genre) e paginação
+func (h *TrashHandler) GetTrash(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	itemType := r.URL.Query().Get("type")
+	if itemType != "" && itemType != models.TrashTypeBook && itemType != models.TrashTypeGenre {
+		sendError(w, r, apperrors.CodeInvalidParameter, "type", "book, genre")
+		return
+	}
+	page := 1
+	perPage := 20
+	if p, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && p > 0 {
+		page = p
+	}
+	if pp, err := strconv.Atoi(r.URL.Query().Get("per_page")); err == nil && pp > 0 {
+		perPage = pp
+	}
+
+	items, total, err := h.trash.FindAll(itemType, perPage, (page-1)*perPage)
+	if err != nil {
+		log.Printf("Erro ao buscar itens da lixeira: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+	for i := range items {
+		items[i].PurgeAt = items[i].DeletedAt.Add(h.retention)
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data":        items,
+		"page":        page,
+		"per_page":    perPage,
+		"total_items": total,
+		"total_pages": (total + perPage - 1) / perPage,
+	})
+}
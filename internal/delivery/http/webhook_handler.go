@@ -0,0 +1,322 @@
+package http
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/url"
+	"projeto_livros/internal/delivery/middleware"
+	apperrors "projeto_livros/internal/domain/errors"
+	"projeto_livros/internal/domain/models"
+	repositories "projeto_livros/internal/repository"
+	"projeto_livros/internal/webhooks"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type WebhookHandler struct {
+	webhooks repositories.WebhookRepository
+}
+
+func NewWebhookHandler(db *sql.DB) *WebhookHandler {
+	return &WebhookHandler{webhooks: repositories.NewPostgresWebhookRepository(db)}
+}
+
+// WebhookRequest é o corpo de criação e atualização de uma assinatura.
+// Events vazio assina todos os eventos; Secret vazio gera (na criação) ou
+// mantém (na atualização) o segredo.
+type WebhookRequest struct {
+	URL         string   `json:"url"`
+	Description string   `json:"description"`
+	Events      []string `json:"events"`
+	Active      *bool    `json:"active"`
+	Secret      string   `json:"secret"`
+}
+
+// requireWebhookAdmin recusa com 403 quem não é administrador: as
+// assinaturas enviam dados do catálogo para URLs externas
+func requireWebhookAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if !middleware.IsAdmin(r.Context()) {
+		sendError(w, r, apperrors.CodeAdminRequired)
+		return false
+	}
+	return true
+}
+
+// decodeWebhook lê e valida o corpo de uma assinatura
+func decodeWebhook(w http.ResponseWriter, r *http.Request) (*models.WebhookSubscription, bool) {
+	var req WebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendError(w, r, apperrors.CodeInvalidBody)
+		return nil, false
+	}
+	req.URL = strings.TrimSpace(req.URL)
+	target, err := url.Parse(req.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		sendError(w, r, apperrors.CodeWebhookURLInvalid)
+		return nil, false
+	}
+	sub := &models.WebhookSubscription{
+		URL:         req.URL,
+		Description: strings.TrimSpace(req.Description),
+		Events:      []string{},
+		Active:      req.Active == nil || *req.Active,
+		Secret:      req.Secret,
+	}
+	seen := map[string]bool{}
+	for _, event := range req.Events {
+		if !models.IsEventType(event) {
+			sendError(w, r, apperrors.CodeWebhookEventUnknown, event, strings.Join(models.EventTypes, ", "))
+			return nil, false
+		}
+		if !seen[event] {
+			seen[event] = true
+			sub.Events = append(sub.Events, event)
+		}
+	}
+	return sub, true
+}
+
+func (h *WebhookHandler) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if !requireWebhookAdmin(w, r) {
+		return
+	}
+	page, perPage := webhookPage(r)
+	subs, total, err := h.webhooks.FindAll(perPage, (page-1)*perPage)
+	if err != nil {
+		log.Printf("Erro ao buscar webhooks: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data":        subs,
+		"page":        page,
+		"per_page":    perPage,
+		"total_items": total,
+		"total_pages": (total + perPage - 1) / perPage,
+	})
+}
+
+// CreateWebhook cria uma assinatura. O segredo só é devolvido nesta resposta.
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if !requireWebhookAdmin(w, r) {
+		return
+	}
+	sub, ok := decodeWebhook(w, r)
+	if !ok {
+		return
+	}
+	if sub.Secret == "" {
+		secret, err := webhooks.NewSecret()
+		if err != nil {
+			log.Printf("Erro ao gerar segredo do webhook: %v", err)
+			sendError(w, r, apperrors.CodeInternalError)
+			return
+		}
+		sub.Secret = secret
+	}
+	if err := h.webhooks.Create(sub); err != nil {
+		log.Printf("Erro ao criar webhook: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(sub)
+}
+
+// findWebhook busca a assinatura {id}, respondendo 404 quando não existe
+func (h *WebhookHandler) findWebhook(w http.ResponseWriter, r *http.Request, id string) (*models.WebhookSubscription, bool) {
+	sub, err := h.webhooks.FindByID(id)
+	if err == sql.ErrNoRows {
+		sendError(w, r, apperrors.CodeWebhookNotFound)
+		return nil, false
+	} else if err != nil {
+		log.Printf("Erro ao buscar webhook: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return nil, false
+	}
+	return sub, true
+}
+
+func (h *WebhookHandler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if !requireWebhookAdmin(w, r) {
+		return
+	}
+	sub, ok := h.findWebhook(w, r, chi.URLParam(r, "id"))
+	if !ok {
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(sub)
+}
+
+// UpdateWebhook substitui URL, descrição, eventos e situação da assinatura.
+// Reativar (active: true) zera as falhas consecutivas.
+func (h *WebhookHandler) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if !requireWebhookAdmin(w, r) {
+		return
+	}
+	sub, ok := decodeWebhook(w, r)
+	if !ok {
+		return
+	}
+	sub.ID = chi.URLParam(r, "id")
+	rowsAffected, err := h.webhooks.Update(sub)
+	if err != nil {
+		log.Printf("Erro ao atualizar webhook: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+	if rowsAffected == 0 {
+		sendError(w, r, apperrors.CodeWebhookNotFound)
+		return
+	}
+	updated, ok := h.findWebhook(w, r, sub.ID)
+	if !ok {
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(updated)
+}
+
+// DeleteWebhook remove a assinatura e o seu registro de entregas
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if !requireWebhookAdmin(w, r) {
+		return
+	}
+	rowsAffected, err := h.webhooks.Delete(chi.URLParam(r, "id"))
+	if err != nil {
+		log.Printf("Erro ao remover webhook: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+	if rowsAffected == 0 {
+		sendError(w, r, apperrors.CodeWebhookNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PingWebhook agenda uma entrega de teste (evento Ping) para a assinatura
+func (h *WebhookHandler) PingWebhook(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if !requireWebhookAdmin(w, r) {
+		return
+	}
+	sub, ok := h.findWebhook(w, r, chi.URLParam(r, "id"))
+	if !ok {
+		return
+	}
+	delivery, err := h.webhooks.EnqueuePing(sub.ID)
+	if err != nil {
+		log.Printf("Erro ao agendar ping do webhook: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(delivery)
+}
+
+// GetWebhookDeliveries lista as entregas da assinatura, com filtro status
+// (pending, succeeded ou failed) e paginação
+func (h *WebhookHandler) GetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if !requireWebhookAdmin(w, r) {
+		return
+	}
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", models.WebhookDeliveryPending, models.WebhookDeliverySucceeded, models.WebhookDeliveryFailed:
+	default:
+		sendError(w, r, apperrors.CodeInvalidParameter, "status", "pending, succeeded, failed")
+		return
+	}
+	sub, ok := h.findWebhook(w, r, chi.URLParam(r, "id"))
+	if !ok {
+		return
+	}
+	page, perPage := webhookPage(r)
+	deliveries, total, err := h.webhooks.FindDeliveries(sub.ID, status, perPage, (page-1)*perPage)
+	if err != nil {
+		log.Printf("Erro ao buscar entregas do webhook: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data":        deliveries,
+		"page":        page,
+		"per_page":    perPage,
+		"total_items": total,
+		"total_pages": (total + perPage - 1) / perPage,
+	})
+}
+
+// GetWebhookDelivery devolve uma entrega com todas as tentativas e respostas
+func (h *WebhookHandler) GetWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if !requireWebhookAdmin(w, r) {
+		return
+	}
+	delivery, err := h.webhooks.FindDelivery(chi.URLParam(r, "id"), chi.URLParam(r, "deliveryID"))
+	if err == sql.ErrNoRows {
+		sendError(w, r, apperrors.CodeDeliveryNotFound)
+		return
+	} else if err != nil {
+		log.Printf("Erro ao buscar entrega do webhook: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(delivery)
+}
+
+// RedeliverWebhook agenda uma nova tentativa imediata da entrega. Entregas de
+// assinaturas desativadas só saem depois que a assinatura for reativada.
+func (h *WebhookHandler) RedeliverWebhook(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if !requireWebhookAdmin(w, r) {
+		return
+	}
+	id, deliveryID := chi.URLParam(r, "id"), chi.URLParam(r, "deliveryID")
+	rowsAffected, err := h.webhooks.Redeliver(id, deliveryID)
+	if err != nil {
+		log.Printf("Erro ao reagendar entrega do webhook: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+	if rowsAffected == 0 {
+		sendError(w, r, apperrors.CodeDeliveryNotFound)
+		return
+	}
+	delivery, err := h.webhooks.FindDelivery(id, deliveryID)
+	if err != nil {
+		log.Printf("Erro ao buscar entrega do webhook: %v", err)
+		sendError(w, r, apperrors.CodeInternalError)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(delivery)
+}
+
+// webhookPage lê page e per_page (padrão 20)
+func webhookPage(r *http.Request) (int, int) {
+	page := 1
+	perPage := 20
+	if p, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && p > 0 {
+		page = p
+	}
+	if pp, err := strconv.Atoi(r.URL.Query().Get("per_page")); err == nil && pp > 0 {
+		perPage = pp
+	}
+	return page, perPage
+}
@@ -0,0 +1,64 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"projeto_livros/internal/delivery/middleware"
+	"projeto_livros/internal/domain/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func asAdmin(req *http.Request) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), middleware.RoleKey, middleware.RoleAdmin))
+}
+
+func TestCreateWebhook(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Erro ao criar mock do banco de dados: %v", err)
+	}
+	defer db.Close()
+	handler := NewWebhookHandler(db)
+
+	// Sem papel de administrador
+	body := `{"url": "https://loja.example.com/hooks", "events": ["StockChanged"]}`
+	rr := httptest.NewRecorder()
+	handler.CreateWebhook(rr, httptest.NewRequest(http.MethodPost, "/api/webhooks", strings.NewReader(body)))
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("status sem admin = %d, esperava %d", rr.Code, http.StatusForbidden)
+	}
+
+	// Tipo de evento desconhecido
+	rr = httptest.NewRecorder()
+	handler.CreateWebhook(rr, asAdmin(httptest.NewRequest(http.MethodPost, "/api/webhooks",
+		strings.NewReader(`{"url": "https://loja.example.com/hooks", "events": ["StockUpdated"]}`))))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("status com evento desconhecido = %d, esperava %d", rr.Code, http.StatusBadRequest)
+	}
+
+	mock.ExpectQuery("INSERT INTO webhook_subscriptions").
+		WithArgs(sqlmock.AnyArg(), "https://loja.example.com/hooks", "", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at"}).AddRow(time.Now(), time.Now()))
+	rr = httptest.NewRecorder()
+	handler.CreateWebhook(rr, asAdmin(httptest.NewRequest(http.MethodPost, "/api/webhooks", strings.NewReader(body))))
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("status = %d, esperava %d: %s", rr.Code, http.StatusCreated, rr.Body.String())
+	}
+	var created models.WebhookSubscription
+	if err := json.NewDecoder(rr.Body).Decode(&created); err != nil {
+		t.Fatalf("Erro ao decodificar resposta: %v", err)
+	}
+	if !strings.HasPrefix(created.Secret, "whsec_") || !created.Active ||
+		len(created.Events) != 1 || created.Events[0] != models.EventStockChanged {
+		t.Errorf("assinatura criada = %+v", created)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas não atendidas: %s", err)
+	}
+}
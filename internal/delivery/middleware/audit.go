@@ -0,0 +1,175 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net"
+	"net/http"
+	"projeto_livros/internal/domain/models"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// maxAuditBody limita quanto da resposta é guardado para identificar a entidade criada
+const maxAuditBody = 64 << 10
+
+// AuditRecorder grava as entradas do log de auditoria
+type AuditRecorder interface {
+	Append(entry *models.AuditEntry) error
+}
+
+// AuditLoader carrega o estado atual de uma entidade pelo ID, para registrar
+// o antes e o depois da requisição. Devolve nil quando a entidade não existe.
+type AuditLoader func(id string) (interface{}, error)
+
+// Audit registra no log de auditoria toda requisição POST, PUT, PATCH ou
+// DELETE. A rota é resolvida em router antes do handler, para obter o padrão
+// (/api/books/{id}) e o ID da entidade; loaders, indexado pelo segmento que
+// segue /api/ (books, genres...), lê o estado da entidade antes e depois.
+// Falhas na gravação do log são registradas e não afetam a resposta.
+func Audit(router chi.Routes, recorder AuditRecorder, loaders map[string]AuditLoader) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+			default:
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			rctx := chi.NewRouteContext()
+			route := router.Find(rctx, r.Method, r.URL.Path)
+			entity := auditEntity(r.URL.Path)
+			entityID := rctx.URLParam("id")
+			loader := loaders[entity]
+
+			entry := &models.AuditEntry{
+				CreatedAt: time.Now(),
+				Actor:     GetUserID(r.Context()),
+				Method:    r.Method,
+				Route:     route,
+				Path:      r.URL.Path,
+				Entity:    entity,
+				EntityID:  entityID,
+				ClientIP:  clientIP(r),
+				RequestID: chimiddleware.GetReqID(r.Context()),
+			}
+			if entry.Route == "" {
+				entry.Route = r.URL.Path
+			}
+			if loader != nil && entityID != "" {
+				entry.Before = loadAuditState(loader, entityID)
+			}
+
+			rec := &auditResponseWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+
+			entry.StatusCode = rec.status
+			entry.Outcome = models.AuditOutcomeSuccess
+			if rec.status >= http.StatusBadRequest {
+				entry.Outcome = models.AuditOutcomeFailure
+			} else {
+				body := compactJSON(rec.body.Bytes())
+				if entry.EntityID == "" && body != nil {
+					// Criações: o ID da nova entidade vem na resposta
+					var created struct {
+						ID interface{} `json:"id"`
+					}
+					if json.Unmarshal(body, &created) == nil && created.ID != nil {
+						if id, ok := created.ID.(string); ok {
+							entry.EntityID = id
+						}
+					}
+				}
+				if loader != nil && entry.EntityID != "" {
+					entry.After = loadAuditState(loader, entry.EntityID)
+				} else {
+					entry.After = body
+				}
+			}
+
+			if err := recorder.Append(entry); err != nil {
+				log.Printf("Erro ao gravar log de auditoria (%s %s): %v", r.Method, r.URL.Path, err)
+			}
+		})
+	}
+}
+
+// auditEntity devolve o segmento que segue /api/ no caminho
+func auditEntity(path string) string {
+	rest := strings.TrimPrefix(path, "/api/")
+	if rest == path {
+		return ""
+	}
+	if i := strings.Index(rest, "/"); i >= 0 {
+		rest = rest[:i]
+	}
+	return rest
+}
+
+func loadAuditState(loader AuditLoader, id string) json.RawMessage {
+	state, err := loader(id)
+	if err != nil {
+		log.Printf("Erro ao carregar estado para auditoria (id %s): %v", id, err)
+		return nil
+	}
+	if state == nil {
+		return nil
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// compactJSON devolve o corpo compactado, ou nil se ele não for JSON válido
+// (inclusive quando foi truncado)
+func compactJSON(body []byte) json.RawMessage {
+	var buf bytes.Buffer
+	if len(body) == 0 || json.Compact(&buf, body) != nil {
+		return nil
+	}
+	return buf.Bytes()
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// auditResponseWriter guarda o status e o início do corpo da resposta
+type auditResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (w *auditResponseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *auditResponseWriter) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	if room := maxAuditBody - w.body.Len(); room > 0 {
+		if len(p) > room {
+			w.body.Write(p[:room])
+			w.body.WriteByte(0) // marca o corpo como truncado
+		} else {
+			w.body.Write(p)
+		}
+	}
+	return w.ResponseWriter.Write(p)
+}
@@ -1,66 +1,106 @@
 package middleware
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
+	"projeto_livros/internal/delivery/problem"
+	apperrors "projeto_livros/internal/domain/errors"
 	"strings"
 	"time"
 	"github.com/golang-jwt/jwt/v5"
 )
 type contextKey string
 const UserIDKey contextKey = "userID"
+const RoleKey contextKey = "role"
+// RoleAdmin é o papel que libera operações administrativas, como apagar definitivamente
+const RoleAdmin = "admin"
 type Claims struct {
 	UserID string `json:"user_id"`
+	Role   string `json:"role,omitempty"`
 	jwt.RegisteredClaims
 }
 func GetUserID(ctx context.Context) string {
 	userID, _ := ctx.Value(UserIDKey).(string)
 	return userID
 }
+func GetRole(ctx context.Context) string {
+	role, _ := ctx.Value(RoleKey).(string)
+	return role
+}
+// IsAdmin indica se a requisição foi autenticada com um token de administrador
+func IsAdmin(ctx context.Context) bool {
+	return GetRole(ctx) == RoleAdmin
+}
+// OptionalAuth autentica a requisição quando há um token; sem o cabeçalho
+// Authorization, a requisição segue anônima. Tokens inválidos são recusados.
+func OptionalAuth(next http.Handler) http.Handler {
+	authenticated := AuthMiddleware(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		authenticated.ServeHTTP(w, r)
+	})
+}
+// ParseToken valida o token JWT (sem o prefixo Bearer) e devolve suas claims.
+// As mensagens de erro podem ser devolvidas ao cliente.
+func ParseToken(token string) (*Claims, error) {
+	secretKey := os.Getenv("JWT_SECRET")
+	if secretKey == "" {
+		secretKey = "sua_chave_secreta_para_desenvolvimento" 
+	}
+	claims := &Claims{}
+	parsedToken, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("método de assinatura inesperado: %v", token.Header["alg"])
+		}
+		return []byte(secretKey), nil
+	})
+	if err != nil {
+		if err == jwt.ErrSignatureInvalid {
+			return nil, errors.New("Assinatura do token inválida")
+		}
+		return nil, errors.New("Token inválido: " + err.Error())
+	}
+	if !parsedToken.Valid {
+		return nil, errors.New("Token inválido")
+	}
+	if claims.ExpiresAt != nil {
+		expirationTime := claims.ExpiresAt.Time
+		if time.Now().After(expirationTime) {
+			return nil, errors.New("Token expirado")
+		}
+	}
+	return claims, nil
+}
+// WithClaims guarda no contexto o usuário e o papel das claims
+func WithClaims(ctx context.Context, claims *Claims) context.Context {
+	ctx = context.WithValue(ctx, UserIDKey, claims.UserID)
+	return context.WithValue(ctx, RoleKey, claims.Role)
+}
 func AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		token := r.Header.Get("Authorization")
 		token = strings.TrimPrefix(token, "Bearer ")
 		if token == "" {
-			http.Error(w, "Não autorizado", http.StatusUnauthorized)
+			problem.Send(w, r, apperrors.CodeUnauthorized)
 			return
 		}
-		secretKey := os.Getenv("JWT_SECRET")
-		if secretKey == "" {
-			secretKey = "sua_chave_secreta_para_desenvolvimento" 
-		}
-		claims := &Claims{}
-		parsedToken, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("método de assinatura inesperado: %v", token.Header["alg"])
-			}
-			return []byte(secretKey), nil
-		})
+		claims, err := ParseToken(token)
 		if err != nil {
-			if err == jwt.ErrSignatureInvalid {
-				http.Error(w, "Assinatura do token inválida", http.StatusUnauthorized)
-				return
-			}
-			http.Error(w, "Token inválido: "+err.Error(), http.StatusUnauthorized)
-			return
-		}
-		if !parsedToken.Valid {
-			http.Error(w, "Token inválido", http.StatusUnauthorized)
+			problem.Send(w, r, apperrors.CodeInvalidToken)
 			return
 		}
-		if claims.ExpiresAt != nil {
-			expirationTime := claims.ExpiresAt.Time
-			if time.Now().After(expirationTime) {
-				http.Error(w, "Token expirado", http.StatusUnauthorized)
-				return
-			}
-		}
-		ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID)
-		next.ServeHTTP(w, r.WithContext(ctx))
+		next.ServeHTTP(w, r.WithContext(WithClaims(r.Context(), claims)))
 	})
 }
 func GenerateToken(userID string) (string, error) {
+	return GenerateTokenWithRole(userID, "")
+}
+func GenerateTokenWithRole(userID, role string) (string, error) {
 	secretKey := os.Getenv("JWT_SECRET")
 	if secretKey == "" {
 		secretKey = "sua_chave_secreta_para_desenvolvimento" 
@@ -68,6 +108,7 @@ func GenerateToken(userID string) (string, error) {
 	expirationTime := time.Now().Add(24 * time.Hour)
 	claims := &Claims{
 		UserID: userID,
+		Role:   role,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
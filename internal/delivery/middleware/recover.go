@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"projeto_livros/internal/delivery/problem"
+	apperrors "projeto_livros/internal/domain/errors"
+	"runtime/debug"
+)
+
+// Recoverer substitui o middleware.Recoverer do chi: um panic em um handler é
+// registrado no log com a pilha e respondido como problema INTERNAL_ERROR, no
+// mesmo formato dos demais erros. http.ErrAbortHandler segue adiante, pois
+// indica uma conexão abandonada de propósito.
+func Recoverer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+			log.Printf("Panic em %s %s: %v\n%s", r.Method, r.URL.Path, rec, debug.Stack())
+			problem.Send(w, r, apperrors.CodeInternalError)
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
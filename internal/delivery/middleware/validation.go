@@ -0,0 +1,234 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"projeto_livros/docs"
+	"projeto_livros/internal/delivery/problem"
+	apperrors "projeto_livros/internal/domain/errors"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// DefaultMaxBody é o limite dos corpos JSON quando ValidateRequests recebe
+// maxBody <= 0 (10 MiB).
+const DefaultMaxBody int64 = 10 << 20
+
+// ValidateRequests confere os parâmetros de caminho, os parâmetros de
+// consulta e o corpo JSON de cada requisição com a operação da especificação
+// OpenAPI. A rota é resolvida em router antes do handler, como em Audit; rotas
+// sem operação documentada passam direto. Todas as violações são devolvidas
+// de uma vez, em um problema VALIDATION_FAILED com a lista errors.
+//
+// Os corpos JSON são lidos com o limite de maxBody bytes (DefaultMaxBody
+// quando maxBody <= 0); acima dele, a resposta é um problema PAYLOAD_TOO_LARGE
+// (413), antes de qualquer handler.
+//
+// Com validateResponses, as respostas JSON também são conferidas com o schema
+// do status enviado; uma resposta fora da especificação vira um problema
+// RESPONSE_INVALID (500) com as violações (location "response"). É um modo
+// para os testes, pois guarda a resposta inteira antes de enviá-la.
+func ValidateRequests(router chi.Routes, spec *docs.Spec, validateResponses bool, maxBody int64) func(http.Handler) http.Handler {
+	if maxBody <= 0 {
+		maxBody = DefaultMaxBody
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rctx := chi.NewRouteContext()
+			route := router.Find(rctx, r.Method, r.URL.Path)
+			var op *docs.Operation
+			if route != "" {
+				op = spec.Operation(r.Method, route)
+			}
+			if op == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			doc := spec.Document()
+			fields := validateParameters(doc, op, rctx, r)
+			bodyFields, err := validateBody(doc, op, w, r, maxBody)
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				problem.Send(w, r, apperrors.CodePayloadTooLarge, tooLarge.Limit)
+				return
+			} else if err != nil {
+				problem.Send(w, r, apperrors.CodeInvalidBody)
+				return
+			}
+			if fields = append(fields, bodyFields...); len(fields) > 0 {
+				problem.Write(w, problem.FromError(r, apperrors.NewValidationError(fields)))
+				return
+			}
+
+			if !validateResponses || streams(op) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			rec := &bufferedResponseWriter{header: http.Header{}, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+			if fields := validateResponse(doc, op, rec); len(fields) > 0 {
+				log.Printf("Resposta fora da especificação (%s %s, status %d): %+v", r.Method, route, rec.status, fields)
+				p := problem.New(r, apperrors.CodeResponseInvalid)
+				p.SetErrors(fields)
+				problem.Write(w, p)
+				return
+			}
+			for key, values := range rec.header {
+				w.Header()[key] = values
+			}
+			w.WriteHeader(rec.status)
+			w.Write(rec.body.Bytes())
+		})
+	}
+}
+
+func validateParameters(doc *docs.Document, op *docs.Operation, rctx *chi.Context, r *http.Request) []docs.FieldError {
+	var fields []docs.FieldError
+	query := r.URL.Query()
+	for _, param := range op.Parameters {
+		var text string
+		var present bool
+		switch param.In {
+		case "path":
+			text = rctx.URLParam(param.Name)
+			present = text != ""
+		case "query":
+			text = query.Get(param.Name)
+			present = text != ""
+		default:
+			continue
+		}
+		if !present {
+			if param.Required {
+				fields = append(fields, apperrors.NewFieldError(param.Name, param.In, "validation.parameter_required"))
+			}
+			continue
+		}
+		value := doc.ParseParameter(param.Schema, text)
+		fields = append(fields, doc.Validate(param.Schema, value, param.Name, param.In)...)
+	}
+	return fields
+}
+
+// validateBody confere o corpo das operações que recebem JSON, lido até
+// maxBody bytes. Corpos em outros formatos (CSV, MARC) ficam com o handler. O
+// corpo lido é devolvido à requisição para o handler.
+func validateBody(doc *docs.Document, op *docs.Operation, w http.ResponseWriter, r *http.Request, maxBody int64) ([]docs.FieldError, error) {
+	if op.RequestBody == nil {
+		return nil, nil
+	}
+	media, ok := op.RequestBody.Content["application/json"]
+	if !ok || media.Schema == nil {
+		return nil, nil
+	}
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, _ := mime.ParseMediaType(contentType)
+		if mediaType != "application/json" {
+			if _, other := op.RequestBody.Content[mediaType]; other {
+				return nil, nil
+			}
+		}
+	}
+
+	var body []byte
+	if r.Body != nil {
+		var err error
+		if body, err = io.ReadAll(http.MaxBytesReader(w, r.Body, maxBody)); err != nil {
+			return nil, err
+		}
+		r.Body.Close()
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		if op.RequestBody.Required {
+			return []docs.FieldError{apperrors.NewFieldError("", "body", "validation.body_required")}, nil
+		}
+		return nil, nil
+	}
+	value, err := decodeJSON(body)
+	if err != nil {
+		return []docs.FieldError{apperrors.NewFieldError("", "body", "validation.invalid_json", err.Error())}, nil
+	}
+	return doc.Validate(media.Schema, value, "", "body"), nil
+}
+
+func decodeJSON(body []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// streams indica as operações que respondem com Server-Sent Events, que não
+// podem ser guardadas inteiras
+func streams(op *docs.Operation) bool {
+	for _, response := range op.Responses {
+		if _, ok := response.Content["text/event-stream"]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// validateResponse confere o corpo JSON com o schema do status enviado
+func validateResponse(doc *docs.Document, op *docs.Operation, rec *bufferedResponseWriter) []docs.FieldError {
+	response, ok := op.Responses[strconv.Itoa(rec.status)]
+	if !ok {
+		return []docs.FieldError{apperrors.NewFieldError("", "response", "validation.status_undocumented", rec.status)}
+	}
+	if response.Ref != "" {
+		response = doc.Components.Responses[strings.TrimPrefix(response.Ref, "#/components/responses/")]
+	}
+	mediaType, _, _ := mime.ParseMediaType(rec.header.Get("Content-Type"))
+	if (mediaType != "application/json" && mediaType != problem.ContentType) || response == nil {
+		return nil
+	}
+	media, ok := response.Content[mediaType]
+	if !ok {
+		return []docs.FieldError{apperrors.NewFieldError("", "response", "validation.response_undocumented")}
+	}
+	if media.Schema == nil {
+		return nil
+	}
+	value, err := decodeJSON(rec.body.Bytes())
+	if err != nil {
+		return []docs.FieldError{apperrors.NewFieldError("", "response", "validation.invalid_json", err.Error())}
+	}
+	return doc.Validate(media.Schema, value, "", "response")
+}
+
+// bufferedResponseWriter guarda a resposta inteira para validá-la antes do envio
+type bufferedResponseWriter struct {
+	header      http.Header
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (w *bufferedResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *bufferedResponseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+}
+
+func (w *bufferedResponseWriter) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	return w.body.Write(p)
+}
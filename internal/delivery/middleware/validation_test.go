@@ -0,0 +1,181 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"projeto_livros/docs"
+	"projeto_livros/internal/delivery/problem"
+	apperrors "projeto_livros/internal/domain/errors"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// newValidatedRouter registra rotas documentadas com handlers que respondem
+// o corpo indicado, atrás do middleware de validação
+func newValidatedRouter(validateResponses bool, response string) *chi.Mux {
+	r := chi.NewRouter()
+	spec := docs.NewSpec(r)
+	r.Use(ValidateRequests(r, spec, validateResponses, 0))
+	respond := func(status int) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			w.Write([]byte(response))
+		}
+	}
+	r.Get("/api/books", respond(http.StatusOK))
+	r.Post("/api/books", respond(http.StatusCreated))
+	r.Get("/api/books/{id}", respond(http.StatusOK))
+	r.Get("/api/books/{id}/history/{rev}", respond(http.StatusOK))
+	r.Get("/api/events/stream", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte(": ok\n\n"))
+	})
+	return r
+}
+
+func decodeValidationError(t *testing.T, rr *httptest.ResponseRecorder) map[string]apperrors.FieldError {
+	t.Helper()
+	if contentType := rr.Header().Get("Content-Type"); contentType != problem.ContentType {
+		t.Errorf("Content-Type = %q, esperava %s", contentType, problem.ContentType)
+	}
+	var resp problem.Problem
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Resposta de erro inválida: %v (%s)", err, rr.Body.String())
+	}
+	if resp.Status != rr.Code {
+		t.Errorf("Status no corpo %d diferente do status %d", resp.Status, rr.Code)
+	}
+	if resp.Type != problem.TypeURI(resp.Code) {
+		t.Errorf("Tipo %q não corresponde ao código %s", resp.Type, resp.Code)
+	}
+	fields := map[string]apperrors.FieldError{}
+	for _, field := range resp.Errors {
+		fields[field.Location+":"+field.Field] = field
+	}
+	return fields
+}
+
+func TestValidateRequestBodyReportsAllFields(t *testing.T) {
+	router := newValidatedRouter(false, `{}`)
+	body := `{"quantity": 0, "title": 7, "authors": [{"role": "autor"}], "publication_year": null}`
+	req := httptest.NewRequest(http.MethodPost, "/api/books?enrich=talvez", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("Status esperado 400, obtido %d", rr.Code)
+	}
+	fields := decodeValidationError(t, rr)
+	expected := map[string]string{
+		"body:quantity":        "deve ser maior ou igual a 1",
+		"body:title":           "deve ser do tipo texto",
+		"body:authors[0]":      "informe id ou name",
+		"body:authors[0].role": "deve ser um dos valores",
+		"query:enrich":         "deve ser um dos valores",
+	}
+	for key, message := range expected {
+		if field, ok := fields[key]; !ok || !strings.HasPrefix(field.Message, message) {
+			t.Errorf("Violação %s: esperado %q, obtido %+v", key, message, field)
+		}
+	}
+	if _, ok := fields["body:publication_year"]; ok {
+		t.Error("publication_year aceita null")
+	}
+}
+
+func TestValidateRequestBodyMissingOrInvalid(t *testing.T) {
+	router := newValidatedRouter(false, `{}`)
+	for _, tc := range []struct{ name, body, message string }{
+		{"vazio", "", "corpo da requisição obrigatório"},
+		{"JSON inválido", `{"quantity":`, "JSON inválido"},
+		{"tipo errado", `[1]`, "deve ser do tipo objeto"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/books", strings.NewReader(tc.body)))
+			fields := decodeValidationError(t, rr)
+			if rr.Code != http.StatusBadRequest || !strings.HasPrefix(fields["body:"].Message, tc.message) {
+				t.Errorf("Esperado 400 com %q, obtido %d %+v", tc.message, rr.Code, fields)
+			}
+		})
+	}
+}
+
+func TestValidateRequestBodyTooLarge(t *testing.T) {
+	r := chi.NewRouter()
+	r.Use(ValidateRequests(r, docs.NewSpec(r), false, 16))
+	called := false
+	r.Post("/api/books", func(w http.ResponseWriter, r *http.Request) { called = true })
+
+	body := `{"title": "` + strings.Repeat("a", 32) + `", "quantity": 1}`
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/books", strings.NewReader(body)))
+
+	if rr.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("Status esperado 413, obtido %d", rr.Code)
+	}
+	decodeValidationError(t, rr)
+	if called {
+		t.Error("O handler não deveria ser chamado com o corpo acima do limite")
+	}
+}
+
+func TestValidateRequestParameters(t *testing.T) {
+	router := newValidatedRouter(false, `{}`)
+	for _, tc := range []struct{ url, field string }{
+		{"/api/books?page=abc", "query:page"},
+		{"/api/books?per_page=0", "query:per_page"},
+		{"/api/books?sort_field=price", "query:sort_field"},
+		{"/api/books/1/history/0", "path:rev"},
+	} {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tc.url, nil))
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: status esperado 400, obtido %d", tc.url, rr.Code)
+			continue
+		}
+		if _, ok := decodeValidationError(t, rr)[tc.field]; !ok {
+			t.Errorf("%s: violação %s ausente: %s", tc.url, tc.field, rr.Body.String())
+		}
+	}
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/books?page=2&sort_direction=desc", nil))
+	if rr.Code != http.StatusOK {
+		t.Errorf("Parâmetros válidos recusados: %d %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestValidateResponses(t *testing.T) {
+	// Book.id é texto: a resposta está fora da especificação
+	router := newValidatedRouter(true, `{"id": 1}`)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/books/1", nil))
+	if rr.Code != http.StatusInternalServerError {
+		t.Fatalf("Status esperado 500, obtido %d", rr.Code)
+	}
+	if field, ok := decodeValidationError(t, rr)["response:id"]; !ok || field.Message != "deve ser do tipo texto" {
+		t.Errorf("Violação da resposta incorreta: %s", rr.Body.String())
+	}
+
+	// Sem o modo de respostas, o corpo passa como veio
+	router = newValidatedRouter(false, `{"id": 1}`)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/books/1", nil))
+	if rr.Code != http.StatusOK || rr.Body.String() != `{"id": 1}` {
+		t.Errorf("Resposta alterada sem o modo de validação: %d %s", rr.Code, rr.Body.String())
+	}
+
+	// Streams não são guardados nem validados
+	router = newValidatedRouter(true, "")
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/events/stream", nil))
+	if rr.Code != http.StatusOK || rr.Body.String() != ": ok\n\n" {
+		t.Errorf("Stream alterado pela validação: %d %q", rr.Code, rr.Body.String())
+	}
+}
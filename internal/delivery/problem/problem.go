@@ -0,0 +1,154 @@
+// Package problem envia os erros da API no formato application/problem+json
+// (RFC 7807), com o código do catálogo de erros em code. O título, o detalhe
+// e as violações de campo seguem o idioma negociado pelo Accept-Language.
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	apperrors "projeto_livros/internal/domain/errors"
+	"projeto_livros/internal/i18n"
+	"strings"
+)
+
+// ContentType é o tipo de mídia das respostas de erro
+const ContentType = "application/problem+json"
+
+// TypePrefix é o início do URI de tipo de cada problema; o catálogo inteiro
+// fica em /api/problems
+const TypePrefix = "/api/problems/"
+
+// Problem é o corpo de uma resposta de erro. Extensions leva os membros
+// próprios de alguns problemas (existing_id, duplicates, total_books), no
+// mesmo nível dos demais.
+type Problem struct {
+	Type       string                 `json:"type"`
+	Title      string                 `json:"title"`
+	Status     int                    `json:"status"`
+	Detail     string                 `json:"detail,omitempty"`
+	Instance   string                 `json:"instance,omitempty"`
+	Code       string                 `json:"code"`
+	Errors     []apperrors.FieldError `json:"errors,omitempty"`
+	Extensions map[string]interface{} `json:"-"`
+	Language   string                 `json:"-"` // Idioma do título e das mensagens
+}
+
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	type problem Problem
+	data, err := json.Marshal((*problem)(p))
+	if err != nil || len(p.Extensions) == 0 {
+		return data, err
+	}
+	members := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+	for key, value := range p.Extensions {
+		if _, reserved := members[key]; reserved {
+			continue
+		}
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		members[key] = raw
+	}
+	return json.Marshal(members)
+}
+
+// TypeURI é o URI de tipo de um código: BOOK_NOT_FOUND vira
+// /api/problems/book-not-found
+func TypeURI(code string) string {
+	return TypePrefix + strings.ToLower(strings.ReplaceAll(code, "_", "-"))
+}
+
+// CodeFromType desfaz TypeURI a partir do último segmento
+func CodeFromType(slug string) string {
+	return strings.ToUpper(strings.ReplaceAll(slug, "-", "_"))
+}
+
+// Language devolve o idioma negociado para a resposta de r
+func Language(r *http.Request) string {
+	if r == nil {
+		return i18n.Default
+	}
+	return i18n.Negotiate(r.Header.Get("Accept-Language"))
+}
+
+// FromError monta o problema de um erro do catálogo, na requisição r e no
+// idioma pedido por ela
+func FromError(r *http.Request, err apperrors.APIError) *Problem {
+	lang := Language(r)
+	title := ""
+	if d, ok := apperrors.LookupIn(lang, err.Code); ok {
+		title = d.Title
+	}
+	p := &Problem{
+		Type:     TypeURI(err.Code),
+		Title:    title,
+		Status:   err.Status,
+		Detail:   err.Localize(lang),
+		Code:     err.Code,
+		Language: lang,
+	}
+	p.SetErrors(err.Fields)
+	if r != nil {
+		p.Instance = r.URL.Path
+	}
+	return p
+}
+
+// SetErrors troca as violações de campo do problema, com as mensagens no
+// idioma dele
+func (p *Problem) SetErrors(fields []apperrors.FieldError) {
+	p.Errors = nil
+	for _, field := range fields {
+		field.Message = field.Localize(p.Language)
+		p.Errors = append(p.Errors, field)
+	}
+}
+
+// New monta o problema de um código do catálogo
+func New(r *http.Request, code string, args ...interface{}) *Problem {
+	return FromError(r, apperrors.New(code, args...))
+}
+
+// With acrescenta um membro de extensão ao problema
+func (p *Problem) With(key string, value interface{}) *Problem {
+	if p.Extensions == nil {
+		p.Extensions = map[string]interface{}{}
+	}
+	p.Extensions[key] = value
+	return p
+}
+
+// Write envia o problema com o status dele
+func Write(w http.ResponseWriter, p *Problem) {
+	w.Header().Set("Content-Type", ContentType)
+	if p.Language != "" {
+		w.Header().Set("Content-Language", p.Language)
+	}
+	w.Header().Add("Vary", "Accept-Language")
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
+
+// Send envia o problema de um código do catálogo
+func Send(w http.ResponseWriter, r *http.Request, code string, args ...interface{}) {
+	Write(w, New(r, code, args...))
+}
+
+// SendError envia err como problema. Erros do catálogo (errors.APIError)
+// seguem com o próprio código; os demais são registrados no log com context e
+// enviados como INTERNAL_ERROR, sem expor a causa.
+func SendError(w http.ResponseWriter, r *http.Request, err error, context string) {
+	var apiErr apperrors.APIError
+	if errors.As(err, &apiErr) {
+		Write(w, FromError(r, apiErr))
+		return
+	}
+	log.Printf("%s: %v", context, err)
+	Send(w, r, apperrors.CodeInternalError)
+}
@@ -0,0 +1,311 @@
+package errors
+
+import (
+	"net/http"
+	"projeto_livros/internal/i18n"
+	"sort"
+)
+
+// Códigos do catálogo de erros da API. Cada código tem um status HTTP, um
+// título fixo e a mensagem de detalhe, no formato do fmt, preenchida com os
+// argumentos de New; os textos estão nos catálogos de i18n.
+const (
+	// Genéricos
+	CodeBadRequest         = "BAD_REQUEST"
+	CodeUnauthorized       = "UNAUTHORIZED"
+	CodeInvalidToken       = "INVALID_TOKEN"
+	CodeForbidden          = "FORBIDDEN"
+	CodeAdminRequired      = "ADMIN_REQUIRED"
+	CodeNotFound           = "NOT_FOUND"
+	CodeMethodNotAllowed   = "METHOD_NOT_ALLOWED"
+	CodeConflict           = "CONFLICT"
+	CodeInternalError      = "INTERNAL_ERROR"
+	CodeInvalidBody        = "INVALID_BODY"
+	CodePayloadTooLarge    = "PAYLOAD_TOO_LARGE"
+	CodeValidationFailed   = "VALIDATION_FAILED"
+	CodeResponseInvalid    = "RESPONSE_INVALID"
+	CodeInvalidParameter   = "INVALID_PARAMETER"
+	CodeInvalidDate        = "INVALID_DATE"
+	CodeInvalidSort        = "INVALID_SORT"
+	CodeInvalidInteger     = "INVALID_INTEGER"
+	CodeServiceUnavailable = "SERVICE_UNAVAILABLE"
+
+	// Livros e estoque
+	CodeBookNotFound        = "BOOK_NOT_FOUND"
+	CodeBookNotInTrash      = "BOOK_NOT_IN_TRASH"
+	CodeBookIDRequired      = "BOOK_ID_REQUIRED"
+	CodeBookTitleRequired   = "BOOK_TITLE_REQUIRED"
+	CodeBookAuthorRequired  = "BOOK_AUTHOR_REQUIRED"
+	CodeBookHasOrders       = "BOOK_HAS_ORDERS"
+	CodeISBNConflict        = "ISBN_CONFLICT"
+	CodeBatchISBNConflict   = "BATCH_ISBN_CONFLICT"
+	CodeMARCImportFailed    = "MARC_IMPORT_FAILED"
+	CodeISBNInvalidLength   = "ISBN_INVALID_LENGTH"
+	CodeISBNInvalidChecksum = "ISBN_INVALID_CHECKSUM"
+	CodeISBNMismatch        = "ISBN_MISMATCH"
+	CodeQuantityNotPositive = "QUANTITY_NOT_POSITIVE"
+	CodeQuantityNegative    = "QUANTITY_NEGATIVE"
+	CodeQuantityInvalid     = "QUANTITY_INVALID"
+	CodeMinQuantityNegative = "MIN_QUANTITY_NEGATIVE"
+	CodeStockUnderflow      = "STOCK_UNDERFLOW"
+	CodeStockDeltaZero      = "STOCK_DELTA_ZERO"
+
+	// Histórico de revisões
+	CodeBookHistoryEmpty     = "BOOK_HISTORY_EMPTY"
+	CodeRevisionNotFound     = "REVISION_NOT_FOUND"
+	CodeRevisionInvalid      = "REVISION_INVALID"
+	CodeRevisionRange        = "REVISION_RANGE_REQUIRED"
+	CodeRevertToDelete       = "REVERT_TO_DELETE"
+	CodeRevisionGenreMissing = "REVISION_GENRE_MISSING"
+
+	// Gêneros
+	CodeGenreNotFound          = "GENRE_NOT_FOUND"
+	CodeGenreNotInTrash        = "GENRE_NOT_IN_TRASH"
+	CodeGenreIDRequired        = "GENRE_ID_REQUIRED"
+	CodeGenreReferenceNotFound = "GENRE_REFERENCE_NOT_FOUND"
+	CodeParentGenreNotFound    = "PARENT_GENRE_NOT_FOUND"
+	CodeGenreNameRequired      = "GENRE_NAME_REQUIRED"
+	CodeGenreNameConflict      = "GENRE_NAME_CONFLICT"
+	CodeGenreCycle             = "GENRE_CYCLE"
+	CodeGenreInUse             = "GENRE_IN_USE"
+	CodeGenreReferenced        = "GENRE_REFERENCED"
+	CodeReassignSameGenre      = "REASSIGN_SAME_GENRE"
+	CodeReassignTargetNotFound = "REASSIGN_TARGET_NOT_FOUND"
+	CodeMergeTargetRequired    = "MERGE_TARGET_REQUIRED"
+	CodeMergeIntoSelf          = "MERGE_INTO_SELF"
+	CodeMergeIntoDescendant    = "MERGE_INTO_DESCENDANT"
+
+	// Autores
+	CodeAuthorNotFound          = "AUTHOR_NOT_FOUND"
+	CodeAuthorReferenceNotFound = "AUTHOR_REFERENCE_NOT_FOUND"
+	CodeAuthorReferenceRequired = "AUTHOR_REFERENCE_REQUIRED"
+	CodeAuthorNameRequired      = "AUTHOR_NAME_REQUIRED"
+	CodeAuthorNameConflict      = "AUTHOR_NAME_CONFLICT"
+	CodeAuthorInUse             = "AUTHOR_IN_USE"
+	CodeAuthorRoleInvalid       = "AUTHOR_ROLE_INVALID"
+
+	// Fornecedores e pedidos de compra
+	CodeSupplierNotFound         = "SUPPLIER_NOT_FOUND"
+	CodeSupplierNameRequired     = "SUPPLIER_NAME_REQUIRED"
+	CodeSupplierNameConflict     = "SUPPLIER_NAME_CONFLICT"
+	CodeSupplierInUse            = "SUPPLIER_IN_USE"
+	CodeOrderNotFound            = "ORDER_NOT_FOUND"
+	CodeOrderReferenceNotFound   = "ORDER_REFERENCE_NOT_FOUND"
+	CodeOrderNotDraft            = "ORDER_NOT_DRAFT"
+	CodeOrderEmpty               = "ORDER_EMPTY"
+	CodeOrderNotReceivable       = "ORDER_NOT_RECEIVABLE"
+	CodeOrderSupplierRequired    = "ORDER_SUPPLIER_REQUIRED"
+	CodeOrderLineBookRequired    = "ORDER_LINE_BOOK_REQUIRED"
+	CodeOrderLineQuantityInvalid = "ORDER_LINE_QUANTITY_INVALID"
+	CodeOrderLineCostNegative    = "ORDER_LINE_COST_NEGATIVE"
+	CodeOrderLineDuplicateBook   = "ORDER_LINE_DUPLICATE_BOOK"
+	CodeOrderLineNotFound        = "ORDER_LINE_NOT_FOUND"
+	CodeReceiptLineInvalid       = "RECEIPT_LINE_INVALID"
+	CodeReceiptExceedsOrdered    = "RECEIPT_EXCEEDS_ORDERED"
+
+	// Alertas, metadados, webhooks e eventos
+	CodeAlertNotFound         = "ALERT_NOT_FOUND"
+	CodeDigestNotConfigured   = "DIGEST_NOT_CONFIGURED"
+	CodeDigestFailed          = "DIGEST_FAILED"
+	CodeMetadataNotConfigured = "METADATA_NOT_CONFIGURED"
+	CodeMetadataNotFound      = "METADATA_NOT_FOUND"
+	CodeWebhookNotFound       = "WEBHOOK_NOT_FOUND"
+	CodeWebhookURLInvalid     = "WEBHOOK_URL_INVALID"
+	CodeWebhookEventUnknown   = "WEBHOOK_EVENT_UNKNOWN"
+	CodeDeliveryNotFound      = "DELIVERY_NOT_FOUND"
+	CodeStreamTopicInvalid    = "STREAM_TOPIC_INVALID"
+)
+
+// Definition descreve um código do catálogo em um idioma
+type Definition struct {
+	Code    string `json:"code"`
+	Status  int    `json:"status"`
+	Title   string `json:"title"`   // Resumo fixo do tipo de problema
+	Message string `json:"message"` // Detalhe; formato do fmt com os argumentos de New
+}
+
+// statuses é o status HTTP de cada código. Os títulos e as mensagens ficam
+// nos catálogos do pacote i18n, nas chaves <código>.title e <código>.detail.
+var statuses = map[string]int{
+	CodeBadRequest:         http.StatusBadRequest,
+	CodeUnauthorized:       http.StatusUnauthorized,
+	CodeInvalidToken:       http.StatusUnauthorized,
+	CodeForbidden:          http.StatusForbidden,
+	CodeAdminRequired:      http.StatusForbidden,
+	CodeNotFound:           http.StatusNotFound,
+	CodeMethodNotAllowed:   http.StatusMethodNotAllowed,
+	CodeConflict:           http.StatusConflict,
+	CodeInternalError:      http.StatusInternalServerError,
+	CodeInvalidBody:        http.StatusBadRequest,
+	CodePayloadTooLarge:    http.StatusRequestEntityTooLarge,
+	CodeValidationFailed:   http.StatusBadRequest,
+	CodeResponseInvalid:    http.StatusInternalServerError,
+	CodeInvalidParameter:   http.StatusBadRequest,
+	CodeInvalidDate:        http.StatusBadRequest,
+	CodeInvalidSort:        http.StatusBadRequest,
+	CodeInvalidInteger:     http.StatusBadRequest,
+	CodeServiceUnavailable: http.StatusServiceUnavailable,
+
+	CodeBookNotFound:        http.StatusNotFound,
+	CodeBookNotInTrash:      http.StatusNotFound,
+	CodeBookIDRequired:      http.StatusBadRequest,
+	CodeBookTitleRequired:   http.StatusBadRequest,
+	CodeBookAuthorRequired:  http.StatusBadRequest,
+	CodeBookHasOrders:       http.StatusConflict,
+	CodeISBNConflict:        http.StatusConflict,
+	CodeBatchISBNConflict:   http.StatusConflict,
+	CodeMARCImportFailed:    http.StatusUnprocessableEntity,
+	CodeISBNInvalidLength:   http.StatusBadRequest,
+	CodeISBNInvalidChecksum: http.StatusBadRequest,
+	CodeISBNMismatch:        http.StatusBadRequest,
+	CodeQuantityNotPositive: http.StatusBadRequest,
+	CodeQuantityNegative:    http.StatusBadRequest,
+	CodeQuantityInvalid:     http.StatusBadRequest,
+	CodeMinQuantityNegative: http.StatusBadRequest,
+	CodeStockUnderflow:      http.StatusBadRequest,
+	CodeStockDeltaZero:      http.StatusBadRequest,
+
+	CodeBookHistoryEmpty:     http.StatusNotFound,
+	CodeRevisionNotFound:     http.StatusNotFound,
+	CodeRevisionInvalid:      http.StatusBadRequest,
+	CodeRevisionRange:        http.StatusBadRequest,
+	CodeRevertToDelete:       http.StatusBadRequest,
+	CodeRevisionGenreMissing: http.StatusConflict,
+
+	CodeGenreNotFound:          http.StatusNotFound,
+	CodeGenreNotInTrash:        http.StatusNotFound,
+	CodeGenreIDRequired:        http.StatusBadRequest,
+	CodeGenreReferenceNotFound: http.StatusBadRequest,
+	CodeParentGenreNotFound:    http.StatusBadRequest,
+	CodeGenreNameRequired:      http.StatusBadRequest,
+	CodeGenreNameConflict:      http.StatusConflict,
+	CodeGenreCycle:             http.StatusBadRequest,
+	CodeGenreInUse:             http.StatusConflict,
+	CodeGenreReferenced:        http.StatusConflict,
+	CodeReassignSameGenre:      http.StatusBadRequest,
+	CodeReassignTargetNotFound: http.StatusBadRequest,
+	CodeMergeTargetRequired:    http.StatusBadRequest,
+	CodeMergeIntoSelf:          http.StatusBadRequest,
+	CodeMergeIntoDescendant:    http.StatusBadRequest,
+
+	CodeAuthorNotFound:          http.StatusNotFound,
+	CodeAuthorReferenceNotFound: http.StatusBadRequest,
+	CodeAuthorReferenceRequired: http.StatusBadRequest,
+	CodeAuthorNameRequired:      http.StatusBadRequest,
+	CodeAuthorNameConflict:      http.StatusConflict,
+	CodeAuthorInUse:             http.StatusConflict,
+	CodeAuthorRoleInvalid:       http.StatusBadRequest,
+
+	CodeSupplierNotFound:         http.StatusNotFound,
+	CodeSupplierNameRequired:     http.StatusBadRequest,
+	CodeSupplierNameConflict:     http.StatusConflict,
+	CodeSupplierInUse:            http.StatusConflict,
+	CodeOrderNotFound:            http.StatusNotFound,
+	CodeOrderReferenceNotFound:   http.StatusBadRequest,
+	CodeOrderNotDraft:            http.StatusConflict,
+	CodeOrderEmpty:               http.StatusConflict,
+	CodeOrderNotReceivable:       http.StatusConflict,
+	CodeOrderSupplierRequired:    http.StatusBadRequest,
+	CodeOrderLineBookRequired:    http.StatusBadRequest,
+	CodeOrderLineQuantityInvalid: http.StatusBadRequest,
+	CodeOrderLineCostNegative:    http.StatusBadRequest,
+	CodeOrderLineDuplicateBook:   http.StatusBadRequest,
+	CodeOrderLineNotFound:        http.StatusBadRequest,
+	CodeReceiptLineInvalid:       http.StatusBadRequest,
+	CodeReceiptExceedsOrdered:    http.StatusBadRequest,
+
+	CodeAlertNotFound:         http.StatusNotFound,
+	CodeDigestNotConfigured:   http.StatusServiceUnavailable,
+	CodeDigestFailed:          http.StatusBadGateway,
+	CodeMetadataNotConfigured: http.StatusServiceUnavailable,
+	CodeMetadataNotFound:      http.StatusNotFound,
+	CodeWebhookNotFound:       http.StatusNotFound,
+	CodeWebhookURLInvalid:     http.StatusBadRequest,
+	CodeWebhookEventUnknown:   http.StatusBadRequest,
+	CodeDeliveryNotFound:      http.StatusNotFound,
+	CodeStreamTopicInvalid:    http.StatusBadRequest,
+}
+
+// Lookup devolve a definição de um código no idioma padrão
+func Lookup(code string) (Definition, bool) {
+	return LookupIn(i18n.Default, code)
+}
+
+// LookupIn devolve a definição de um código no idioma lang
+func LookupIn(lang, code string) (Definition, bool) {
+	status, ok := statuses[code]
+	return Definition{
+		Code:    code,
+		Status:  status,
+		Title:   i18n.T(lang, code+".title"),
+		Message: i18n.T(lang, code+".detail"),
+	}, ok
+}
+
+// Codes devolve os códigos do catálogo, ordenados
+func Codes() []string {
+	codes := make([]string, 0, len(statuses))
+	for code := range statuses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// Definitions devolve o catálogo inteiro no idioma lang, ordenado pelo código
+func Definitions(lang string) []Definition {
+	defs := make([]Definition, 0, len(statuses))
+	for _, code := range Codes() {
+		d, _ := LookupIn(lang, code)
+		defs = append(defs, d)
+	}
+	return defs
+}
+
+// New cria o erro de um código do catálogo, com o status dele e a mensagem no
+// idioma padrão. Códigos fora do catálogo viram INTERNAL_ERROR.
+func New(code string, args ...interface{}) APIError {
+	if _, ok := statuses[code]; !ok {
+		code, args = CodeInternalError, nil
+	}
+	return APIError{Status: statuses[code], Code: code, Message: i18n.T(i18n.Default, code+".detail", args...), Args: args}
+}
+
+// Localize devolve a mensagem do erro em lang. Erros montados fora do
+// catálogo, com uma mensagem própria, seguem como vieram.
+func (e APIError) Localize(lang string) string {
+	if _, ok := statuses[e.Code]; !ok || e.Message != i18n.T(i18n.Default, e.Code+".detail", e.Args...) {
+		return e.Message
+	}
+	return i18n.T(lang, e.Code+".detail", e.Args...)
+}
+
+// FieldError é uma violação em um campo da requisição ou da resposta. Key e
+// Args guardam a mensagem do catálogo de i18n, para traduzi-la na resposta.
+type FieldError struct {
+	Field    string        `json:"field"`    // Caminho do campo, como authors[0].role; vazio para o corpo inteiro
+	Location string        `json:"location"` // path, query, body ou response
+	Message  string        `json:"message"`
+	Key      string        `json:"-"`
+	Args     []interface{} `json:"-"`
+}
+
+// NewFieldError cria a violação com a mensagem da chave key no idioma padrão
+func NewFieldError(field, location, key string, args ...interface{}) FieldError {
+	return FieldError{Field: field, Location: location, Message: i18n.T(i18n.Default, key, args...), Key: key, Args: args}
+}
+
+// Localize devolve a mensagem da violação em lang
+func (f FieldError) Localize(lang string) string {
+	if f.Key == "" {
+		return f.Message
+	}
+	return i18n.T(lang, f.Key, f.Args...)
+}
+
+// NewValidationError reúne as violações de campo em um VALIDATION_FAILED
+func NewValidationError(fields []FieldError) APIError {
+	err := New(CodeValidationFailed)
+	err.Fields = fields
+	return err
+}
@@ -1,12 +1,13 @@
 package errors
 import (
-	"encoding/json"
 	"net/http"
 )
 type APIError struct {
 	Status  int    `json:"-"`
 	Code    string `json:"code"`
 	Message string `json:"message"`
+	Args    []interface{} `json:"-"` // Argumentos da mensagem do catálogo
+	Fields  []FieldError  `json:"fields,omitempty"`
 }
 func (e APIError) Error() string {
 	return e.Message
@@ -14,27 +15,28 @@ func (e APIError) Error() string {
 func NewNotFoundError(message string) APIError {
 	return APIError{
 		Status:  http.StatusNotFound,
-		Code:    "NOT_FOUND",
+		Code:    CodeNotFound,
 		Message: message,
 	}
 }
 func NewBadRequestError(message string) APIError {
 	return APIError{
 		Status:  http.StatusBadRequest,
-		Code:    "BAD_REQUEST",
+		Code:    CodeBadRequest,
 		Message: message,
 	}
 }
-func RespondWithError(w http.ResponseWriter, err error) {
-	apiErr, ok := err.(APIError)
-	if !ok {
-		apiErr = APIError{
-			Status:  http.StatusInternalServerError,
-			Code:    "INTERNAL_SERVER_ERROR",
-			Message: "Erro interno do servidor",
-		}
+func NewConflictError(message string) APIError {
+	return APIError{
+		Status:  http.StatusConflict,
+		Code:    CodeConflict,
+		Message: message,
+	}
+}
+func NewForbiddenError(message string) APIError {
+	return APIError{
+		Status:  http.StatusForbidden,
+		Code:    CodeForbidden,
+		Message: message,
 	}
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(apiErr.Status)
-	json.NewEncoder(w).Encode(apiErr)
 }
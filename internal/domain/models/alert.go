@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Situações de um alerta de estoque baixo, usadas no filtro da listagem
+const (
+	AlertStatusOpen         = "open"         // Estoque ainda abaixo do mínimo e alerta não reconhecido
+	AlertStatusAcknowledged = "acknowledged" // Reconhecido, mas o estoque ainda não foi reposto
+	AlertStatusResolved     = "resolved"     // Estoque voltou ao mínimo
+	AlertStatusAll          = "all"
+)
+
+// LowStockAlert é registrado quando a quantidade de um livro fica abaixo do
+// seu min_quantity. Quantity e MinQuantity são os valores no momento do alerta.
+type LowStockAlert struct {
+	ID              string     `json:"id"`
+	BookID          string     `json:"book_id"`
+	BookName        string     `json:"book_name"`
+	Quantity        int        `json:"quantity"`
+	MinQuantity     int        `json:"min_quantity"`
+	CurrentQuantity int        `json:"current_quantity"`
+	CreatedAt       time.Time  `json:"created_at"`
+	AcknowledgedAt  *time.Time `json:"acknowledged_at,omitempty"`
+	AcknowledgedBy  string     `json:"acknowledged_by,omitempty"`
+	ResolvedAt      *time.Time `json:"resolved_at,omitempty"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Resultados de uma requisição auditada
+const (
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeFailure = "failure"
+)
+
+// AuditEntry é uma requisição de escrita registrada no log de auditoria.
+// Cada entrada guarda o hash da anterior (PrevHash), formando uma cadeia:
+// alterar ou remover uma entrada quebra a verificação das seguintes.
+type AuditEntry struct {
+	Seq        int64           `json:"seq"`
+	CreatedAt  time.Time       `json:"created_at"`
+	Actor      string          `json:"actor,omitempty"`
+	Method     string          `json:"method"`
+	Route      string          `json:"route"` // Padrão da rota, como /api/books/{id}
+	Path       string          `json:"path"`
+	Entity     string          `json:"entity,omitempty"`
+	EntityID   string          `json:"entity_id,omitempty"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	ClientIP   string          `json:"client_ip"`
+	RequestID  string          `json:"request_id,omitempty"`
+	StatusCode int             `json:"status_code"`
+	Outcome    string          `json:"outcome"`
+	PrevHash   string          `json:"prev_hash"`
+	Hash       string          `json:"hash"`
+}
+
+// AuditFilter são os filtros da consulta ao log de auditoria; campos vazios não filtram
+type AuditFilter struct {
+	Actor     string
+	Entity    string
+	EntityID  string
+	Method    string
+	Outcome   string
+	RequestID string
+	From      *time.Time
+	To        *time.Time
+}
@@ -0,0 +1,31 @@
+package models
+
+// Papéis de um autor em um livro
+const (
+	RoleAuthor      = "author"
+	RoleTranslator  = "translator"
+	RoleIllustrator = "illustrator"
+)
+
+// AuthorRoles lista os papéis aceitos em book_authors
+var AuthorRoles = []string{RoleAuthor, RoleTranslator, RoleIllustrator}
+
+type Author struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	TotalBooks int    `json:"total_books"`
+}
+
+// BookAuthor é a participação de um autor em um livro. Na entrada, basta
+// informar o id de um autor existente ou o nome (que é criado se não existir).
+type BookAuthor struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+// AuthorBook é um livro na listagem de livros de um autor, com o papel do autor nele
+type AuthorBook struct {
+	Book
+	Role string `json:"role"`
+}
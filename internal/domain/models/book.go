@@ -4,8 +4,36 @@ package models
 type Book struct {
 	ID       string  `json:"id"`
 	Name     string  `json:"name"`
-	Title    string  `json:"title,omitempty"` // Mantido para compatibilidade com o frontend
-	Author   string  `json:"author"`
-	Quantity int     `json:"quantity"` // Garantir que é tratado como um único valor
-	GenreID  *string `json:"genre_id,omitempty"`
+	Title    string  `json:"title,omitempty"`    // Mantido para compatibilidade com o frontend
+	Author   string  `json:"author"`             // Nomes dos autores (papel "author"), calculado a partir de Authors
+	Quantity int     `json:"quantity"`           // Garantir que é tratado como um único valor
+	GenreID  *string `json:"genre_id,omitempty"` // Gênero principal: o primeiro de Genres
+
+	// Estoque mínimo: abaixo dele é registrado um alerta de estoque baixo
+	MinQuantity *int `json:"min_quantity,omitempty"`
+
+	// Campos bibliográficos usados no intercâmbio de registros (MARC21)
+	ISBN            string   `json:"isbn,omitempty"`    // ISBN-13 normalizado, usado como chave única
+	ISBN10          string   `json:"isbn_10,omitempty"` // Derivado do ISBN-13 quando o prefixo é 978
+	ISBN13          string   `json:"isbn_13,omitempty"`
+	Publisher       string   `json:"publisher,omitempty"`
+	PublicationYear *int     `json:"publication_year,omitempty"`
+	Edition         string   `json:"edition,omitempty"`
+	Subjects        []string `json:"subjects,omitempty"`
+
+	Authors []BookAuthor `json:"authors,omitempty"`
+	Genres  []BookGenre  `json:"genres,omitempty"`
+}
+
+// BookFilter são os filtros da busca de livros; campos vazios não filtram
+type BookFilter struct {
+	Search             string // Trecho do nome, do autor ou do ISBN
+	GenreID            string
+	IncludeDescendants bool // Com GenreID, inclui os livros dos subgêneros
+	AuthorID           string
+	MinQuantity        *int
+	MaxQuantity        *int
+	LowStock           bool   // Só livros abaixo do próprio estoque mínimo
+	SortField          string // name (padrão), quantity ou publication_year
+	SortDesc           bool
 }
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Tipos de evento de domínio publicados pelo outbox
+const (
+	EventBookCreated   = "BookCreated"
+	EventBookUpdated   = "BookUpdated"
+	EventBookDeleted   = "BookDeleted"
+	EventBookRestored  = "BookRestored"
+	EventStockChanged  = "StockChanged"
+	EventGenreCreated  = "GenreCreated"
+	EventGenreUpdated  = "GenreUpdated"
+	EventGenreDeleted  = "GenreDeleted"
+	EventGenreRestored = "GenreRestored"
+	EventGenreMerged   = "GenreMerged"
+)
+
+// EventTypes lista os tipos de evento publicados, na ordem da documentação
+var EventTypes = []string{
+	EventBookCreated, EventBookUpdated, EventBookDeleted, EventBookRestored, EventStockChanged,
+	EventGenreCreated, EventGenreUpdated, EventGenreDeleted, EventGenreRestored, EventGenreMerged,
+}
+
+// IsEventType indica se name é um dos tipos de evento publicados
+func IsEventType(name string) bool {
+	for _, t := range EventTypes {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Tipos de agregado que originam eventos
+const (
+	AggregateBook  = "book"
+	AggregateGenre = "genre"
+)
+
+// Event é um evento de domínio gravado no outbox na mesma transação da
+// alteração que o originou. ID cresce com a gravação e define a ordem de
+// entrega; consumidores podem usá-lo para descartar entregas repetidas.
+type Event struct {
+	ID            int64           `json:"id"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	Actor         string          `json:"actor,omitempty"`
+	Payload       json.RawMessage `json:"payload"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+
+	Attempts      int       `json:"-"` // Tentativas de entrega que falharam
+	NextAttemptAt time.Time `json:"-"` // Antes disso a entrega não é tentada de novo
+}
+
+// BookEventPayload é o conteúdo dos eventos de livro: o estado do livro
+// após a alteração e a revisão correspondente no histórico
+type BookEventPayload struct {
+	Book          Book     `json:"book"`
+	Rev           int      `json:"rev"`
+	ChangedFields []string `json:"changed_fields"`
+	RevertedFrom  *int     `json:"reverted_from,omitempty"`
+}
+
+// StockChangedPayload é o conteúdo do evento StockChanged
+type StockChangedPayload struct {
+	BookID string `json:"book_id"`
+	From   int    `json:"from"`
+	To     int    `json:"to"`
+	Delta  int    `json:"delta"`
+	Rev    int    `json:"rev"`
+	// Gêneros do livro, para que consumidores possam filtrar por gênero
+	GenreIDs []string `json:"genre_ids,omitempty"`
+}
+
+// GenreEventPayload é o conteúdo dos eventos de gênero. Em GenreMerged,
+// Genre é o gênero de origem, já removido, e MergedInto o destino.
+type GenreEventPayload struct {
+	Genre      Genre  `json:"genre"`
+	Hard       bool   `json:"hard,omitempty"` // Remoção definitiva
+	MergedInto string `json:"merged_into,omitempty"`
+	MovedBooks int64  `json:"moved_books,omitempty"`
+}
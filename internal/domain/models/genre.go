@@ -1,11 +1,24 @@
 package models
 type Genre struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	ParentID    *string `json:"parent_id"` // Gênero pai na hierarquia; nil para gêneros raiz
 }
 type GenreWithBooks struct {
 	Name       string `json:"name"`
 	TotalBooks int    `json:"total_books"`
 	Books      []Book `json:"books"`
 }
+
+// GenreNode é um gênero na árvore devolvida por /api/genres/tree
+type GenreNode struct {
+	Genre
+	Children []GenreNode `json:"children"`
+}
+
+// BookGenre é um dos gêneros de um livro. Na entrada, basta o id.
+type BookGenre struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+}
@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// Situações de um pedido de compra
+const (
+	OrderStatusDraft             = "draft"              // Em elaboração; as linhas podem ser alteradas
+	OrderStatusSent              = "sent"               // Enviado ao fornecedor, aguardando entrega
+	OrderStatusPartiallyReceived = "partially_received" // Parte dos itens recebida
+	OrderStatusReceived          = "received"           // Todos os itens recebidos
+)
+
+type Supplier struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email,omitempty"`
+	Phone string `json:"phone,omitempty"`
+	Notes string `json:"notes,omitempty"`
+}
+
+type PurchaseOrder struct {
+	ID           string              `json:"id"`
+	SupplierID   string              `json:"supplier_id"`
+	SupplierName string              `json:"supplier_name,omitempty"`
+	Status       string              `json:"status"`
+	Notes        string              `json:"notes,omitempty"`
+	Total        float64             `json:"total"` // Soma de quantidade x custo unitário das linhas
+	CreatedAt    time.Time           `json:"created_at"`
+	SentAt       *time.Time          `json:"sent_at,omitempty"`
+	ReceivedAt   *time.Time          `json:"received_at,omitempty"`
+	Lines        []PurchaseOrderLine `json:"lines,omitempty"`
+	Receipts     []StockReceipt      `json:"receipts,omitempty"`
+}
+
+// PurchaseOrderLine é um livro do pedido, com a quantidade pedida e a já recebida
+type PurchaseOrderLine struct {
+	ID               string  `json:"id"`
+	BookID           string  `json:"book_id"`
+	BookName         string  `json:"book_name,omitempty"`
+	Quantity         int     `json:"quantity"`
+	UnitCost         float64 `json:"unit_cost"`
+	ReceivedQuantity int     `json:"received_quantity"`
+}
+
+// StockReceipt registra uma entrada de estoque feita pelo recebimento de uma
+// linha de pedido, para que o aumento de estoque possa ser rastreado
+type StockReceipt struct {
+	ID         string    `json:"id"`
+	OrderID    string    `json:"order_id"`
+	LineID     string    `json:"line_id"`
+	BookID     string    `json:"book_id"`
+	Quantity   int       `json:"quantity"`
+	ReceivedAt time.Time `json:"received_at"`
+	ReceivedBy string    `json:"received_by,omitempty"`
+
+	// QuantityBefore é o estoque do livro antes deste recebimento; só existe
+	// durante a gravação, para o StockChanged
+	QuantityBefore int `json:"-"`
+}
+
+// ReceiveLine é um item do corpo de POST /api/purchase-orders/{id}/receive
+type ReceiveLine struct {
+	LineID   string `json:"line_id"`
+	Quantity int    `json:"quantity"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// Ações registradas no histórico de revisões de um livro
+const (
+	RevisionCreate  = "create"
+	RevisionUpdate  = "update"
+	RevisionDelete  = "delete"
+	RevisionRestore = "restore"
+	RevisionRevert  = "revert"
+)
+
+// BookRevision é o estado completo de um livro depois de uma alteração.
+// ChangedFields lista os campos que mudaram em relação à revisão anterior.
+type BookRevision struct {
+	ID            string    `json:"id"`
+	BookID        string    `json:"book_id"`
+	Rev           int       `json:"rev"`
+	Action        string    `json:"action"`
+	Actor         string    `json:"actor,omitempty"`
+	ChangedFields []string  `json:"changed_fields"`
+	RevertedFrom  *int      `json:"reverted_from,omitempty"` // Revisão restaurada, nas ações revert
+	Snapshot      Book      `json:"snapshot"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// FieldChange é a diferença de um campo entre duas revisões
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// CatalogStats é o resumo do catálogo devolvido por /api/stats
+type CatalogStats struct {
+	Totals      CatalogTotals `json:"totals"`
+	Genres      []GenreStats  `json:"genres"`
+	LowStock    []Book        `json:"low_stock"`
+	RecentBooks []Book        `json:"recent_books"`
+	GeneratedAt time.Time     `json:"generated_at"`
+}
+
+type CatalogTotals struct {
+	Titles             int `json:"titles"`
+	Copies             int `json:"copies"`
+	Genres             int `json:"genres"`
+	BooksWithoutGenre  int `json:"books_without_genre"`
+	BooksWithoutAuthor int `json:"books_without_author"`
+	LowStockTitles     int `json:"low_stock_titles"`
+	LowStockThreshold  int `json:"low_stock_threshold"`
+}
+
+// GenreStats traz os livros de um gênero. Um livro com vários gêneros é
+// contado em cada um deles.
+type GenreStats struct {
+	ID       string  `json:"id"`
+	Name     string  `json:"name"`
+	ParentID *string `json:"parent_id"`
+	Titles   int     `json:"titles"`
+	Copies   int     `json:"copies"`
+}
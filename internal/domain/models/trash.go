@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Tipos de item da lixeira
+const (
+	TrashTypeBook  = "book"
+	TrashTypeGenre = "genre"
+)
+
+// TrashItem é um livro ou gênero removido, que pode ser restaurado até ser
+// apagado definitivamente pela limpeza da lixeira em PurgeAt
+type TrashItem struct {
+	Type      string    `json:"type"`
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Situações de uma entrega de webhook
+const (
+	WebhookDeliveryPending   = "pending"   // Aguardando a primeira tentativa ou uma nova tentativa
+	WebhookDeliverySucceeded = "succeeded" // O destino respondeu 2xx
+	WebhookDeliveryFailed    = "failed"    // Tentativas esgotadas
+)
+
+// EventWebhookPing é o evento de teste enviado por POST /api/webhooks/{id}/ping
+const EventWebhookPing = "Ping"
+
+// WebhookSubscription é um destino que recebe, por POST, os eventos de
+// domínio dos tipos em Events (todos, quando vazio). Secret assina as
+// entregas e só é devolvido na criação.
+type WebhookSubscription struct {
+	ID             string     `json:"id"`
+	URL            string     `json:"url"`
+	Description    string     `json:"description,omitempty"`
+	Events         []string   `json:"events"`
+	Secret         string     `json:"secret,omitempty"`
+	Active         bool       `json:"active"`
+	FailureCount   int        `json:"failure_count"` // Falhas consecutivas desde a última entrega bem-sucedida
+	DisabledAt     *time.Time `json:"disabled_at,omitempty"`
+	DisabledReason string     `json:"disabled_reason,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// WebhookDelivery é um evento a entregar a uma assinatura, com o resultado
+// da última tentativa. AttemptLog só é preenchido na consulta de uma entrega.
+type WebhookDelivery struct {
+	ID             string           `json:"id"`
+	SubscriptionID string           `json:"subscription_id"`
+	EventID        *int64           `json:"event_id,omitempty"` // Vazio no ping
+	EventType      string           `json:"event_type"`
+	Payload        json.RawMessage  `json:"payload"`
+	Status         string           `json:"status"`
+	Attempts       int              `json:"attempts"`
+	NextAttemptAt  *time.Time       `json:"next_attempt_at,omitempty"`
+	LastStatusCode *int             `json:"last_status_code,omitempty"`
+	LastError      string           `json:"last_error,omitempty"`
+	CreatedAt      time.Time        `json:"created_at"`
+	DeliveredAt    *time.Time       `json:"delivered_at,omitempty"`
+	AttemptLog     []WebhookAttempt `json:"attempt_log,omitempty"`
+
+	URL    string `json:"-"` // Destino e segredo da assinatura, lidos para a entrega
+	Secret string `json:"-"`
+}
+
+// WebhookAttempt é uma tentativa de entrega, com a resposta do destino
+type WebhookAttempt struct {
+	ID           string    `json:"id"`
+	DeliveryID   string    `json:"delivery_id"`
+	AttemptedAt  time.Time `json:"attempted_at"`
+	StatusCode   *int      `json:"status_code,omitempty"` // Vazio quando não houve resposta
+	Error        string    `json:"error,omitempty"`
+	ResponseBody string    `json:"response_body,omitempty"` // Início da resposta do destino
+	DurationMs   int64     `json:"duration_ms"`
+}
@@ -0,0 +1,50 @@
+package validators
+
+import (
+	"projeto_livros/internal/domain/errors"
+	"projeto_livros/internal/domain/models"
+	"strings"
+)
+
+// NormalizeAuthorName remove espaços nas pontas e espaços repetidos do nome e
+// compõe os caracteres Unicode (Normalize). A chave devolvida (nome em
+// minúsculas) identifica o autor na deduplicação, de modo que "Machado de
+// Assis" e "machado  de Assis " são o mesmo autor.
+func NormalizeAuthorName(name string) (display, key string) {
+	display = Normalize(strings.Join(strings.Fields(name), " "))
+	return display, strings.ToLower(display)
+}
+
+// ValidateBookAuthors normaliza nomes e papéis dos autores de um livro,
+// usando "author" como papel padrão e descartando entradas repetidas.
+func ValidateBookAuthors(authors []models.BookAuthor) ([]models.BookAuthor, error) {
+	result := []models.BookAuthor{}
+	seen := map[string]bool{}
+	for _, a := range authors {
+		a.Name, _ = NormalizeAuthorName(a.Name)
+		a.ID = strings.TrimSpace(a.ID)
+		if a.ID == "" && a.Name == "" {
+			return nil, errors.New(errors.CodeAuthorReferenceRequired)
+		}
+		a.Role = strings.ToLower(strings.TrimSpace(a.Role))
+		if a.Role == "" {
+			a.Role = models.RoleAuthor
+		}
+		valid := false
+		for _, role := range models.AuthorRoles {
+			if a.Role == role {
+				valid = true
+			}
+		}
+		if !valid {
+			return nil, errors.New(errors.CodeAuthorRoleInvalid, a.Role, strings.Join(models.AuthorRoles, ", "))
+		}
+		key := a.ID + "|" + strings.ToLower(a.Name) + "|" + a.Role
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, a)
+	}
+	return result, nil
+}
@@ -0,0 +1,37 @@
+package validators
+
+import (
+	"projeto_livros/internal/domain/models"
+	"testing"
+)
+
+func TestNormalizeAuthorName(t *testing.T) {
+	display, key := NormalizeAuthorName("  Machado   de Assis ")
+	if display != "Machado de Assis" || key != "machado de assis" {
+		t.Errorf("NormalizeAuthorName = (%q, %q)", display, key)
+	}
+}
+
+func TestValidateBookAuthors(t *testing.T) {
+	authors, err := ValidateBookAuthors([]models.BookAuthor{
+		{Name: "Machado de Assis"},
+		{Name: "machado  de assis", Role: "AUTHOR"},
+		{Name: "Fulano", Role: "translator"},
+	})
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if len(authors) != 2 {
+		t.Fatalf("esperava 2 autores após remover repetidos, obteve %d: %+v", len(authors), authors)
+	}
+	if authors[0].Role != models.RoleAuthor || authors[1].Role != models.RoleTranslator {
+		t.Errorf("papéis incorretos: %+v", authors)
+	}
+
+	if _, err := ValidateBookAuthors([]models.BookAuthor{{Name: "Fulano", Role: "editor"}}); err == nil {
+		t.Error("esperava erro para papel inválido")
+	}
+	if _, err := ValidateBookAuthors([]models.BookAuthor{{Role: "author"}}); err == nil {
+		t.Error("esperava erro para autor sem id e sem nome")
+	}
+}
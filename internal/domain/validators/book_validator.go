@@ -1,32 +1,50 @@
 package validators
 
 import (
-	"encoding/json"
-	"net/http"
 	"projeto_livros/internal/domain/errors"
 	"projeto_livros/internal/domain/models"
+)
 
-	"strings"
+// Limites das colunas de livros e autores (db/migrations/init.sql)
+const (
+	maxTextLength      = 255 // livros.name, livros.author, livros.publisher, authors.name
+	maxEditionLength   = 100 // livros.edition
+	maxPublicationYear = 9999
 )
 
-func ValidateBookInput(r *http.Request) (*models.Book, error) {
-	defer r.Body.Close()
-	var book models.Book
-	if err := json.NewDecoder(r.Body).Decode(&book); err != nil {
-		return nil, errors.NewBadRequestError("Formato de JSON inválido: " + err.Error())
-	}
-	trimmedTitle := strings.TrimSpace(book.Title)
-	if trimmedTitle == "" {
-		return nil, errors.NewBadRequestError("O campo 'title' é obrigatório")
-	}
-	book.Title = trimmedTitle
-	trimmedAuthor := strings.TrimSpace(book.Author)
-	if trimmedAuthor == "" {
-		return nil, errors.NewBadRequestError("O campo 'author' é obrigatório")
-	}
-	book.Author = trimmedAuthor
-	if book.Quantity < 0 {
-		return nil, errors.NewBadRequestError("A quantidade não pode ser negativa")
+// NewBookSchema valida um livro novo, que precisa de ao menos um exemplar
+var NewBookSchema = bookSchema(1)
+
+// BookSchema valida a atualização de um livro, em que o estoque pode zerar
+var BookSchema = bookSchema(0)
+
+// bookSchema descreve os campos de models.Book. O ISBN tem as próprias regras
+// em NormalizeBookISBN, e os papéis dos autores em ValidateBookAuthors.
+func bookSchema(minQuantity int) Schema[models.Book] {
+	return Schema[models.Book]{
+		// Title é aceito no lugar de name, para compatibilidade com o frontend
+		func(b *models.Book) []errors.FieldError {
+			if Normalize(b.Name) == "" {
+				b.Name = b.Title
+			}
+			return nil
+		},
+		Text("id", func(b *models.Book) *string { return &b.ID }, KSUID()),
+		Text("name", func(b *models.Book) *string { return &b.Name }, Required(), MaxLength(maxTextLength)),
+		Text("author", func(b *models.Book) *string { return &b.Author }, MaxLength(maxTextLength)),
+		Int("quantity", func(b *models.Book) *int { return &b.Quantity }, Min(minQuantity)),
+		OptionalInt("min_quantity", func(b *models.Book) **int { return &b.MinQuantity }, Min(0)),
+		OptionalText("genre_id", func(b *models.Book) **string { return &b.GenreID }, KSUID()),
+		Text("publisher", func(b *models.Book) *string { return &b.Publisher }, MaxLength(maxTextLength)),
+		OptionalInt("publication_year", func(b *models.Book) **int { return &b.PublicationYear }, Min(1), Max(maxPublicationYear)),
+		Text("edition", func(b *models.Book) *string { return &b.Edition }, MaxLength(maxEditionLength)),
+		Texts("subjects", func(b *models.Book) *[]string { return &b.Subjects }),
+		Each("authors", func(b *models.Book) []models.BookAuthor { return b.Authors },
+			Text("id", func(a *models.BookAuthor) *string { return &a.ID }, KSUID()),
+			Text("name", func(a *models.BookAuthor) *string { return &a.Name }, MaxLength(maxTextLength)),
+		),
+		Each("genres", func(b *models.Book) []models.BookGenre { return b.Genres },
+			Text("id", func(g *models.BookGenre) *string { return &g.ID }, Required(), KSUID()),
+		),
 	}
-	return &book, nil
 }
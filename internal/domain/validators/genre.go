@@ -0,0 +1,14 @@
+package validators
+
+import "projeto_livros/internal/domain/models"
+
+// maxGenreNameLength é o tamanho de genres.name (VARCHAR(100))
+const maxGenreNameLength = 100
+
+// GenreSchema descreve os campos de models.Genre
+var GenreSchema = Schema[models.Genre]{
+	Text("id", func(g *models.Genre) *string { return &g.ID }, KSUID()),
+	Text("name", func(g *models.Genre) *string { return &g.Name }, Required(), MaxLength(maxGenreNameLength)),
+	Text("description", func(g *models.Genre) *string { return &g.Description }),
+	OptionalText("parent_id", func(g *models.Genre) **string { return &g.ParentID }, KSUID()),
+}
@@ -0,0 +1,155 @@
+package validators
+
+import (
+	"projeto_livros/internal/domain/errors"
+	"projeto_livros/internal/domain/models"
+	"strings"
+)
+
+// CleanISBN remove hífens, espaços e o prefixo "ISBN" e converte o "x" final
+// do ISBN-10 para maiúsculo, sem validar o resultado.
+func CleanISBN(isbn string) string {
+	isbn = strings.ToUpper(strings.TrimSpace(isbn))
+	isbn = strings.TrimPrefix(isbn, "ISBN-13")
+	isbn = strings.TrimPrefix(isbn, "ISBN-10")
+	isbn = strings.TrimPrefix(isbn, "ISBN")
+	var b strings.Builder
+	for _, c := range isbn {
+		if (c >= '0' && c <= '9') || c == 'X' {
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
+
+// IsValidISBN10 verifica o formato e o dígito verificador de um ISBN-10 já limpo
+func IsValidISBN10(isbn string) bool {
+	if len(isbn) != 10 {
+		return false
+	}
+	sum := 0
+	for i := 0; i < 10; i++ {
+		c := isbn[i]
+		var digit int
+		switch {
+		case c >= '0' && c <= '9':
+			digit = int(c - '0')
+		case c == 'X' && i == 9:
+			digit = 10
+		default:
+			return false
+		}
+		sum += digit * (10 - i)
+	}
+	return sum%11 == 0
+}
+
+// IsValidISBN13 verifica o formato e o dígito verificador de um ISBN-13 já limpo
+func IsValidISBN13(isbn string) bool {
+	if len(isbn) != 13 || !(strings.HasPrefix(isbn, "978") || strings.HasPrefix(isbn, "979")) {
+		return false
+	}
+	for i := 0; i < 13; i++ {
+		if isbn[i] < '0' || isbn[i] > '9' {
+			return false
+		}
+	}
+	return isbn13CheckDigit(isbn[:12]) == isbn[12]
+}
+
+func isbn13CheckDigit(first12 string) byte {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		digit := int(first12[i] - '0')
+		if i%2 == 1 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+func isbn10CheckDigit(first9 string) byte {
+	sum := 0
+	for i := 0; i < 9; i++ {
+		sum += int(first9[i]-'0') * (10 - i)
+	}
+	check := (11 - sum%11) % 11
+	if check == 10 {
+		return 'X'
+	}
+	return byte('0' + check)
+}
+
+// ISBN10To13 converte um ISBN-10 válido para ISBN-13 com o prefixo 978
+func ISBN10To13(isbn10 string) (string, bool) {
+	isbn10 = CleanISBN(isbn10)
+	if !IsValidISBN10(isbn10) {
+		return "", false
+	}
+	first12 := "978" + isbn10[:9]
+	return first12 + string(isbn13CheckDigit(first12)), true
+}
+
+// ISBN13To10 converte um ISBN-13 para ISBN-10. Só é possível para o prefixo
+// 978; ISBNs 979 não têm equivalente de 10 dígitos.
+func ISBN13To10(isbn13 string) (string, bool) {
+	isbn13 = CleanISBN(isbn13)
+	if !IsValidISBN13(isbn13) || !strings.HasPrefix(isbn13, "978") {
+		return "", false
+	}
+	first9 := isbn13[3:12]
+	return first9 + string(isbn10CheckDigit(first9)), true
+}
+
+// NormalizeISBN valida um ISBN-10 ou ISBN-13, com ou sem hífens, e devolve a
+// forma canônica de 13 dígitos usada para armazenamento e deduplicação.
+func NormalizeISBN(isbn string) (string, error) {
+	clean := CleanISBN(isbn)
+	switch len(clean) {
+	case 10:
+		if isbn13, ok := ISBN10To13(clean); ok {
+			return isbn13, nil
+		}
+		return "", errors.New(errors.CodeISBNInvalidChecksum, 10)
+	case 13:
+		if IsValidISBN13(clean) {
+			return clean, nil
+		}
+		return "", errors.New(errors.CodeISBNInvalidChecksum, 13)
+	}
+	return "", errors.New(errors.CodeISBNInvalidLength)
+}
+
+// NormalizeBookISBN unifica os campos isbn, isbn_10 e isbn_13 recebidos do
+// cliente. Se mais de um for informado, todos precisam representar o mesmo
+// livro. Ao final, ISBN e ISBN13 guardam a forma canônica e ISBN10 a forma
+// de 10 dígitos, quando existir.
+func NormalizeBookISBN(book *models.Book) error {
+	normalized := ""
+	for _, candidate := range []string{book.ISBN, book.ISBN13, book.ISBN10} {
+		if strings.TrimSpace(candidate) == "" {
+			continue
+		}
+		isbn, err := NormalizeISBN(candidate)
+		if err != nil {
+			return err
+		}
+		if normalized != "" && normalized != isbn {
+			return errors.New(errors.CodeISBNMismatch)
+		}
+		normalized = isbn
+	}
+	SetISBNForms(book, normalized)
+	return nil
+}
+
+// SetISBNForms preenche ISBN, ISBN13 e ISBN10 a partir do ISBN-13 canônico
+func SetISBNForms(book *models.Book, isbn13 string) {
+	book.ISBN = isbn13
+	book.ISBN13 = isbn13
+	book.ISBN10 = ""
+	if isbn10, ok := ISBN13To10(isbn13); ok {
+		book.ISBN10 = isbn10
+	}
+}
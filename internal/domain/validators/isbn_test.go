@@ -0,0 +1,58 @@
+package validators
+
+import (
+	"projeto_livros/internal/domain/models"
+	"testing"
+)
+
+func TestNormalizeISBN(t *testing.T) {
+	cases := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"978-85-359-1066-7", "9788535910667", false},
+		{"ISBN 85-359-1066-2", "9788535910667", false},
+		{"0-306-40615-2", "9780306406157", false},
+		{"080442957X", "9780804429573", false},
+		{"979-10-90636-07-1", "9791090636071", false},
+		{"978-85-359-1066-3", "", true},
+		{"0-306-40615-3", "", true},
+		{"12345", "", true},
+		{"X123456789", "", true},
+	}
+	for _, c := range cases {
+		got, err := NormalizeISBN(c.in)
+		if (err != nil) != c.wantErr {
+			t.Errorf("NormalizeISBN(%q) erro = %v, esperava erro = %v", c.in, err, c.wantErr)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("NormalizeISBN(%q) = %q, esperava %q", c.in, got, c.want)
+		}
+	}
+}
+
+func TestISBN13To10(t *testing.T) {
+	if got, ok := ISBN13To10("9780804429573"); !ok || got != "080442957X" {
+		t.Errorf("ISBN13To10 = %q, %v", got, ok)
+	}
+	if _, ok := ISBN13To10("9791090636071"); ok {
+		t.Error("ISBN 979 não deveria ter equivalente de 10 dígitos")
+	}
+}
+
+func TestNormalizeBookISBN(t *testing.T) {
+	book := models.Book{ISBN10: "8535910662"}
+	if err := NormalizeBookISBN(&book); err != nil {
+		t.Fatal(err)
+	}
+	if book.ISBN != "9788535910667" || book.ISBN13 != book.ISBN || book.ISBN10 != "8535910662" {
+		t.Errorf("formas do ISBN incorretas: %+v", book)
+	}
+
+	conflicting := models.Book{ISBN: "9788535910667", ISBN10: "0306406152"}
+	if err := NormalizeBookISBN(&conflicting); err == nil {
+		t.Error("esperava erro para ISBNs de livros diferentes")
+	}
+}
@@ -0,0 +1,234 @@
+package validators
+
+import (
+	"fmt"
+	"projeto_livros/internal/domain/errors"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Violation é uma regra descumprida: a chave da mensagem no catálogo de i18n
+// (validation.*) e os argumentos dela
+type Violation struct {
+	Key  string
+	Args []interface{}
+}
+
+func violation(key string, args ...interface{}) *Violation {
+	return &Violation{Key: key, Args: args}
+}
+
+// TextRule confere um texto já normalizado; nil indica que ele é válido
+type TextRule func(text string) *Violation
+
+// IntRule confere um número inteiro; nil indica que ele é válido
+type IntRule func(n int) *Violation
+
+// Required recusa o texto vazio
+func Required() TextRule {
+	return func(text string) *Violation {
+		if text == "" {
+			return violation("validation.required")
+		}
+		return nil
+	}
+}
+
+// MaxLength limita o texto a n caracteres, como as colunas VARCHAR(n)
+func MaxLength(n int) TextRule {
+	return func(text string) *Violation {
+		if utf8.RuneCountInString(text) > n {
+			return violation("validation.max_length", n)
+		}
+		return nil
+	}
+}
+
+// KSUID exige o formato dos ids: 27 caracteres base62, como os KSUIDs
+// gerados pela API. Não confere o conteúdo do KSUID porque os registros
+// criados pelas migrações usam os 27 primeiros caracteres de
+// gen_random_uuid() sem hífens, um UUID truncado e não um KSUID, que ksuid.Parse recusa quando começa por
+// b-f. O texto vazio passa, para que a obrigatoriedade fique com Required
+func KSUID() TextRule {
+	return func(text string) *Violation {
+		if text == "" {
+			return nil
+		}
+		if len(text) != ksuidLength || strings.IndexFunc(text, notBase62) >= 0 {
+			return violation("validation.ksuid")
+		}
+		return nil
+	}
+}
+
+// ksuidLength é o tamanho de um KSUID em base62
+const ksuidLength = 27
+
+func notBase62(r rune) bool {
+	return !(r >= '0' && r <= '9' || r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z')
+}
+
+// Min exige n >= min
+func Min(min int) IntRule {
+	return func(n int) *Violation {
+		if n < min {
+			return violation("validation.minimum", strconv.Itoa(min))
+		}
+		return nil
+	}
+}
+
+// Max exige n <= max
+func Max(max int) IntRule {
+	return func(n int) *Violation {
+		if n > max {
+			return violation("validation.maximum", strconv.Itoa(max))
+		}
+		return nil
+	}
+}
+
+// Normalize remove os espaços das pontas e compõe os caracteres Unicode (NFC),
+// para que "é" digitado como e + acento seja gravado e comparado como "é"
+func Normalize(text string) string {
+	return norm.NFC.String(strings.TrimSpace(text))
+}
+
+// Field é a regra de um campo de T: normaliza o valor no próprio modelo e
+// devolve as violações, com o caminho do campo a partir de T
+type Field[T any] func(model *T) []errors.FieldError
+
+// Schema é a lista declarativa de campos de um modelo
+type Schema[T any] []Field[T]
+
+// Check normaliza o modelo e devolve todas as violações, na ordem dos campos
+func (s Schema[T]) Check(model *T) []errors.FieldError {
+	var fields []errors.FieldError
+	for _, field := range s {
+		fields = append(fields, field(model)...)
+	}
+	return fields
+}
+
+// Validate normaliza o modelo e reúne as violações em um único erro
+// VALIDATION_FAILED; sem violações, devolve nil
+func (s Schema[T]) Validate(model *T) error {
+	if fields := s.Check(model); len(fields) > 0 {
+		return errors.NewValidationError(fields)
+	}
+	return nil
+}
+
+func fieldError(name string, v *Violation) []errors.FieldError {
+	return []errors.FieldError{errors.NewFieldError(name, "body", v.Key, v.Args...)}
+}
+
+func checkText(name, text string, rules []TextRule) []errors.FieldError {
+	for _, rule := range rules {
+		if v := rule(text); v != nil {
+			return fieldError(name, v)
+		}
+	}
+	return nil
+}
+
+func checkInt(name string, n int, rules []IntRule) []errors.FieldError {
+	for _, rule := range rules {
+		if v := rule(n); v != nil {
+			return fieldError(name, v)
+		}
+	}
+	return nil
+}
+
+// Text normaliza um campo de texto e aplica as regras até a primeira violação
+func Text[T any](name string, value func(*T) *string, rules ...TextRule) Field[T] {
+	return func(model *T) []errors.FieldError {
+		text := value(model)
+		*text = Normalize(*text)
+		return checkText(name, *text, rules)
+	}
+}
+
+// OptionalText é um texto anulável: depois de normalizado, o texto vazio vira
+// nil e não passa pelas regras
+func OptionalText[T any](name string, value func(*T) **string, rules ...TextRule) Field[T] {
+	return func(model *T) []errors.FieldError {
+		text := value(model)
+		if *text == nil {
+			return nil
+		}
+		normalized := Normalize(**text)
+		if normalized == "" {
+			*text = nil
+			return nil
+		}
+		*text = &normalized
+		return checkText(name, normalized, rules)
+	}
+}
+
+// Texts normaliza cada texto de uma lista, descarta os vazios e aplica as
+// regras a cada um (name[i])
+func Texts[T any](name string, value func(*T) *[]string, rules ...TextRule) Field[T] {
+	return func(model *T) []errors.FieldError {
+		list := value(model)
+		if *list == nil {
+			return nil
+		}
+		var fields []errors.FieldError
+		kept := make([]string, 0, len(*list))
+		for _, text := range *list {
+			if text = Normalize(text); text == "" {
+				continue
+			}
+			fields = append(fields, checkText(fmt.Sprintf("%s[%d]", name, len(kept)), text, rules)...)
+			kept = append(kept, text)
+		}
+		*list = kept
+		return fields
+	}
+}
+
+// Int aplica as regras a um campo inteiro
+func Int[T any](name string, value func(*T) *int, rules ...IntRule) Field[T] {
+	return func(model *T) []errors.FieldError {
+		return checkInt(name, *value(model), rules)
+	}
+}
+
+// OptionalInt aplica as regras a um inteiro anulável, quando informado
+func OptionalInt[T any](name string, value func(*T) **int, rules ...IntRule) Field[T] {
+	return func(model *T) []errors.FieldError {
+		if n := *value(model); n != nil {
+			return checkInt(name, *n, rules)
+		}
+		return nil
+	}
+}
+
+// Each aplica os campos de E a cada item de uma lista de T; as violações vêm
+// com o caminho name[i].campo
+func Each[T, E any](name string, items func(*T) []E, fields ...Field[E]) Field[T] {
+	return func(model *T) []errors.FieldError {
+		var result []errors.FieldError
+		list := items(model)
+		for i := range list {
+			for _, f := range Schema[E](fields).Check(&list[i]) {
+				f.Field = fmt.Sprintf("%s[%d]", name, i) + joinPath(f.Field)
+				result = append(result, f)
+			}
+		}
+		return result
+	}
+}
+
+func joinPath(field string) string {
+	if field == "" {
+		return ""
+	}
+	return "." + field
+}
@@ -0,0 +1,158 @@
+package validators
+
+import (
+	stderrors "errors"
+	"projeto_livros/internal/domain/errors"
+	"projeto_livros/internal/domain/models"
+	"strings"
+	"testing"
+
+	"github.com/segmentio/ksuid"
+)
+
+func fieldNames(fields []errors.FieldError) []string {
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = f.Field
+	}
+	return names
+}
+
+func TestNormalize(t *testing.T) {
+	if got := Normalize("  José de Alencar \n"); got != "José de Alencar" {
+		t.Errorf("Normalize = %q, esperava o texto aparado", got)
+	}
+	if got := Normalize("Jose\u0301"); got != "José" {
+		t.Errorf("Normalize = %q, esperava o acento composto (NFC)", got)
+	}
+}
+
+func TestBookSchemaNormalizesFields(t *testing.T) {
+	publisher := "  Companhia das Letras "
+	book := models.Book{
+		Title:     "  Memórias Póstumas ",
+		Quantity:  1,
+		Publisher: publisher,
+		GenreID:   new(string),
+		Subjects:  []string{" Romance ", "", "  "},
+	}
+	if fields := NewBookSchema.Check(&book); len(fields) > 0 {
+		t.Fatalf("violações inesperadas: %+v", fields)
+	}
+	if book.Name != "Memórias Póstumas" {
+		t.Errorf("name = %q, esperava o título normalizado", book.Name)
+	}
+	if book.Publisher != "Companhia das Letras" {
+		t.Errorf("publisher = %q", book.Publisher)
+	}
+	if book.GenreID != nil {
+		t.Errorf("genre_id vazio deveria virar nil, obteve %q", *book.GenreID)
+	}
+	if len(book.Subjects) != 1 || book.Subjects[0] != "Romance" {
+		t.Errorf("subjects = %q", book.Subjects)
+	}
+}
+
+func TestBookSchemaReportsAllViolations(t *testing.T) {
+	year := 10000
+	minQuantity := -1
+	genreID := "abc"
+	book := models.Book{
+		ID:              "123",
+		Name:            strings.Repeat("a", 256),
+		Quantity:        0,
+		MinQuantity:     &minQuantity,
+		GenreID:         &genreID,
+		PublicationYear: &year,
+		Edition:         strings.Repeat("é", 101),
+		Authors:         []models.BookAuthor{{Name: "Fulano"}, {ID: "x"}},
+		Genres:          []models.BookGenre{{}},
+	}
+	fields := NewBookSchema.Check(&book)
+	want := []string{"id", "name", "quantity", "min_quantity", "genre_id", "publication_year", "edition", "authors[1].id", "genres[0].id"}
+	if got := fieldNames(fields); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("campos = %v, esperava %v", got, want)
+	}
+	if fields[1].Message != "deve ter no máximo 255 caracteres" {
+		t.Errorf("mensagem de name = %q", fields[1].Message)
+	}
+	if fields[6].Message != "deve ter no máximo 100 caracteres" {
+		t.Errorf("mensagem de edition = %q", fields[6].Message)
+	}
+	if fields[0].Location != "body" {
+		t.Errorf("location = %q, esperava body", fields[0].Location)
+	}
+
+	err := NewBookSchema.Validate(&book)
+	var apiErr errors.APIError
+	if !stderrors.As(err, &apiErr) || apiErr.Code != errors.CodeValidationFailed || len(apiErr.Fields) != len(want) {
+		t.Fatalf("Validate = %v, esperava VALIDATION_FAILED com %d campos", err, len(want))
+	}
+}
+
+func TestBookSchemaQuantity(t *testing.T) {
+	book := models.Book{Name: "Dom Casmurro"}
+	if err := NewBookSchema.Validate(&book); err == nil {
+		t.Error("livro novo sem exemplares deveria ser recusado")
+	}
+	if err := BookSchema.Validate(&book); err != nil {
+		t.Errorf("atualização com estoque zerado deveria passar: %v", err)
+	}
+}
+
+func TestBookSchemaAcceptsKSUIDs(t *testing.T) {
+	id := ksuid.New().String()
+	book := models.Book{
+		ID:       id,
+		Name:     strings.Repeat("á", 255),
+		Quantity: 3,
+		GenreID:  &id,
+		Authors:  []models.BookAuthor{{ID: id}},
+		Genres:   []models.BookGenre{{ID: id}},
+	}
+	if fields := BookSchema.Check(&book); len(fields) > 0 {
+		t.Errorf("violações inesperadas: %+v", fields)
+	}
+}
+
+func TestBookSchemaAcceptsSQLGeneratedIDs(t *testing.T) {
+	// ids das migrações: left(replace(gen_random_uuid()::text, '-', ''), 27)
+	id := strings.ReplaceAll("e3b1c7a2-5d4f-4a8e-9b6c-2f1d0e7a9c3b", "-", "")[:27]
+	if _, err := ksuid.Parse(id); err == nil {
+		t.Fatalf("o id %q deveria ser recusado por ksuid.Parse", id)
+	}
+	book := models.Book{
+		ID:       id,
+		Name:     "Dom Casmurro",
+		Quantity: 1,
+		Authors:  []models.BookAuthor{{ID: id}},
+		Genres:   []models.BookGenre{{ID: id}},
+	}
+	if fields := BookSchema.Check(&book); len(fields) > 0 {
+		t.Errorf("violações inesperadas: %+v", fields)
+	}
+	for _, invalid := range []string{"f3a1c2d4e5b60718293a4b5c6d", "f3a1c2d4e5b60718293a4b5c6d7e", "f3a1c2d4-5b60718293a4b5c6d7"} {
+		book.ID = invalid
+		if got := fieldNames(BookSchema.Check(&book)); strings.Join(got, ",") != "id" {
+			t.Errorf("id %q: campos = %v, esperava id", invalid, got)
+		}
+	}
+}
+
+func TestGenreSchema(t *testing.T) {
+	empty := " "
+	genre := models.Genre{Name: "  Ficção ", ParentID: &empty}
+	if err := GenreSchema.Validate(&genre); err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if genre.Name != "Ficção" || genre.ParentID != nil {
+		t.Errorf("gênero normalizado = %q, parent_id = %v", genre.Name, genre.ParentID)
+	}
+
+	parent := "pai"
+	genre = models.Genre{ID: "x", Name: strings.Repeat("a", 101), ParentID: &parent}
+	want := []string{"id", "name", "parent_id"}
+	if got := fieldNames(GenreSchema.Check(&genre)); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("campos = %v, esperava %v", got, want)
+	}
+}
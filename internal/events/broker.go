@@ -0,0 +1,216 @@
+package events
+
+import (
+	"encoding/json"
+	"projeto_livros/internal/domain/models"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// subscriberBuffer é quantos eventos podem esperar por um assinante lento;
+// além disso o assinante é desconectado e retoma depois pelo Last-Event-ID
+const subscriberBuffer = 64
+
+// Broker distribui os eventos publicados aos assinantes conectados nesta
+// instância e guarda os últimos eventos para que clientes reconectados
+// retomem a partir do último evento recebido.
+type Broker struct {
+	mu     sync.Mutex
+	replay []models.Event // Em ordem de publicação, no máximo size eventos
+	size   int
+	seen   map[int64]bool // IDs presentes em replay
+	lastID int64          // Maior ID já publicado
+	subs   map[*Subscription]struct{}
+}
+
+// NewBroker cria o distribuidor com um histórico de até size eventos
+func NewBroker(size int) *Broker {
+	return &Broker{size: size, seen: map[int64]bool{}, subs: map[*Subscription]struct{}{}}
+}
+
+// Subscription recebe os eventos que atendem ao seu filtro
+type Subscription struct {
+	broker *Broker
+	filter Filter
+	events chan models.Event
+	closed bool
+}
+
+// Events devolve o canal de eventos, fechado quando a assinatura termina
+// (inclusive quando o assinante não acompanhou o ritmo dos eventos)
+func (s *Subscription) Events() <-chan models.Event {
+	return s.events
+}
+
+// Close encerra a assinatura
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	s.broker.remove(s)
+}
+
+// LastID devolve o maior ID de evento já publicado
+func (b *Broker) LastID() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.lastID
+}
+
+// Publish guarda o evento no histórico e o envia aos assinantes interessados.
+// Eventos já publicados (como na retomada após reconexão) são ignorados.
+func (b *Broker) Publish(event models.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.seen[event.ID] {
+		return
+	}
+	b.replay = append(b.replay, event)
+	b.seen[event.ID] = true
+	if len(b.replay) > b.size {
+		delete(b.seen, b.replay[0].ID)
+		b.replay = b.replay[1:]
+	}
+	if event.ID > b.lastID {
+		b.lastID = event.ID
+	}
+	for sub := range b.subs {
+		if !sub.filter.Match(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			b.remove(sub)
+		}
+	}
+}
+
+func (b *Broker) remove(sub *Subscription) {
+	if sub.closed {
+		return
+	}
+	sub.closed = true
+	delete(b.subs, sub)
+	close(sub.events)
+}
+
+// Subscribe registra um assinante. Com lastEventID, devolve em replay os
+// eventos publicados depois dele que atendem ao filtro; resumed é false
+// quando o evento já saiu do histórico e o cliente deve recarregar o estado.
+func (b *Broker) Subscribe(filter Filter, lastEventID string) (sub *Subscription, replay []models.Event, resumed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	sub = &Subscription{broker: b, filter: filter, events: make(chan models.Event, subscriberBuffer)}
+	b.subs[sub] = struct{}{}
+
+	if lastEventID == "" {
+		return sub, nil, true
+	}
+	id, err := strconv.ParseInt(lastEventID, 10, 64)
+	if err != nil || !b.seen[id] {
+		return sub, nil, false
+	}
+	// O histórico está na ordem de publicação, que pode diferir da ordem dos IDs
+	start := 0
+	for i, event := range b.replay {
+		if event.ID == id {
+			start = i + 1
+			break
+		}
+	}
+	for _, event := range b.replay[start:] {
+		if filter.Match(event) {
+			replay = append(replay, event)
+		}
+	}
+	return sub, replay, true
+}
+
+// Filter seleciona eventos por tópico. Tópicos aceitos: books e genres (todos
+// os eventos de livros ou de gêneros), stock (StockChanged), book:<id> (um
+// livro), genre:<id> (o gênero e os livros dele) e type:<tipo>. Um filtro
+// vazio aceita tudo; com vários tópicos, basta atender a um deles.
+type Filter struct {
+	Topics []string
+}
+
+// ParseFilter lê tópicos separados por vírgula
+func ParseFilter(topics string) Filter {
+	var f Filter
+	for _, topic := range strings.Split(topics, ",") {
+		if topic = strings.TrimSpace(topic); topic != "" {
+			f.Topics = append(f.Topics, topic)
+		}
+	}
+	return f
+}
+
+// ValidTopic indica se o tópico tem um dos formatos aceitos
+func ValidTopic(topic string) bool {
+	switch topic {
+	case "books", "genres", "stock":
+		return true
+	}
+	kind, value, ok := strings.Cut(topic, ":")
+	return ok && value != "" && (kind == "book" || kind == "genre" || kind == "type")
+}
+
+func (f Filter) Match(event models.Event) bool {
+	if len(f.Topics) == 0 {
+		return true
+	}
+	for _, topic := range f.Topics {
+		if matchTopic(topic, event) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchTopic(topic string, event models.Event) bool {
+	switch topic {
+	case "books":
+		return event.AggregateType == models.AggregateBook
+	case "genres":
+		return event.AggregateType == models.AggregateGenre
+	case "stock":
+		return event.Type == models.EventStockChanged
+	}
+	kind, value, _ := strings.Cut(topic, ":")
+	switch kind {
+	case "type":
+		return event.Type == value
+	case "book":
+		return event.AggregateType == models.AggregateBook && event.AggregateID == value
+	case "genre":
+		if event.AggregateType == models.AggregateGenre {
+			return event.AggregateID == value
+		}
+		for _, id := range eventGenreIDs(event) {
+			if id == value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// eventGenreIDs devolve os gêneros do livro de um evento de livro
+func eventGenreIDs(event models.Event) []string {
+	var payload struct {
+		Book     models.Book `json:"book"`
+		GenreIDs []string    `json:"genre_ids"`
+	}
+	if json.Unmarshal(event.Payload, &payload) != nil {
+		return nil
+	}
+	ids := payload.GenreIDs
+	if payload.Book.GenreID != nil {
+		ids = append(ids, *payload.Book.GenreID)
+	}
+	for _, genre := range payload.Book.Genres {
+		ids = append(ids, genre.ID)
+	}
+	return ids
+}
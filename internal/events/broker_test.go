@@ -0,0 +1,129 @@
+package events
+
+import (
+	"encoding/json"
+	"projeto_livros/internal/domain/models"
+	"testing"
+)
+
+func bookEvent(id int64, eventType, bookID string, payload interface{}) models.Event {
+	data, _ := json.Marshal(payload)
+	return models.Event{ID: id, Type: eventType, AggregateType: models.AggregateBook, AggregateID: bookID, Payload: data}
+}
+
+func eventIDs(events []models.Event) []int64 {
+	ids := []int64{}
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+	return ids
+}
+
+func TestBrokerReplaysAfterLastEventID(t *testing.T) {
+	broker := NewBroker(10)
+	broker.Publish(bookEvent(1, models.EventBookCreated, "b1", nil))
+	broker.Publish(bookEvent(3, models.EventBookUpdated, "b2", nil))
+	// Transações concluídas fora de ordem: o 2 chega depois do 3
+	broker.Publish(bookEvent(2, models.EventBookUpdated, "b1", nil))
+	broker.Publish(bookEvent(3, models.EventBookUpdated, "b2", nil)) // Duplicado é ignorado
+
+	sub, replay, resumed := broker.Subscribe(Filter{}, "1")
+	defer sub.Close()
+	if !resumed {
+		t.Fatal("esperava retomar a partir do evento 1")
+	}
+	if got := eventIDs(replay); len(got) != 2 || got[0] != 3 || got[1] != 2 {
+		t.Errorf("replay = %v, esperava [3 2]", got)
+	}
+	if broker.LastID() != 3 {
+		t.Errorf("LastID = %d", broker.LastID())
+	}
+}
+
+func TestBrokerRequiresResetWhenEventLeftHistory(t *testing.T) {
+	broker := NewBroker(2)
+	for id := int64(1); id <= 3; id++ {
+		broker.Publish(bookEvent(id, models.EventBookUpdated, "b1", nil))
+	}
+	for _, lastID := range []string{"1", "99", "abc"} {
+		sub, replay, resumed := broker.Subscribe(Filter{}, lastID)
+		sub.Close()
+		if resumed || len(replay) != 0 {
+			t.Errorf("Last-Event-ID %s: resumed = %v, replay = %v", lastID, resumed, eventIDs(replay))
+		}
+	}
+}
+
+func TestBrokerFiltersByTopic(t *testing.T) {
+	genreID := "g1"
+	published := []models.Event{
+		bookEvent(1, models.EventBookUpdated, "b1", models.BookEventPayload{Book: models.Book{GenreID: &genreID}}),
+		bookEvent(2, models.EventStockChanged, "b2", models.StockChangedPayload{BookID: "b2", GenreIDs: []string{"g1"}}),
+		bookEvent(3, models.EventBookUpdated, "b3", models.BookEventPayload{}),
+		{ID: 4, Type: models.EventGenreUpdated, AggregateType: models.AggregateGenre, AggregateID: "g1"},
+		{ID: 5, Type: models.EventGenreUpdated, AggregateType: models.AggregateGenre, AggregateID: "g2"},
+	}
+	tests := []struct {
+		topics string
+		want   []int64
+	}{
+		{"", []int64{1, 2, 3, 4, 5}},
+		{"books", []int64{1, 2, 3}},
+		{"genres", []int64{4, 5}},
+		{"stock", []int64{2}},
+		{"book:b3", []int64{3}},
+		{"genre:g1", []int64{1, 2, 4}},
+		{"book:b1, type:GenreUpdated", []int64{1, 4, 5}},
+	}
+	for _, tt := range tests {
+		broker := NewBroker(10)
+		sub, _, _ := broker.Subscribe(ParseFilter(tt.topics), "")
+		for _, event := range published {
+			broker.Publish(event)
+		}
+		sub.Close()
+		var got []models.Event
+		for event := range sub.Events() {
+			got = append(got, event)
+		}
+		if ids := eventIDs(got); len(ids) != len(tt.want) {
+			t.Errorf("topics %q: recebeu %v, esperava %v", tt.topics, ids, tt.want)
+		} else {
+			for i := range ids {
+				if ids[i] != tt.want[i] {
+					t.Errorf("topics %q: recebeu %v, esperava %v", tt.topics, ids, tt.want)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestBrokerDropsSlowSubscriber(t *testing.T) {
+	broker := NewBroker(10)
+	sub, _, _ := broker.Subscribe(Filter{}, "")
+	for id := int64(1); id <= subscriberBuffer+1; id++ {
+		broker.Publish(bookEvent(id, models.EventBookUpdated, "b1", nil))
+	}
+	received := 0
+	for range sub.Events() {
+		received++
+	}
+	if received != subscriberBuffer {
+		t.Errorf("recebeu %d eventos antes de ser desconectado, esperava %d", received, subscriberBuffer)
+	}
+	sub.Close() // Fechar de novo não deve entrar em pânico
+}
+
+func TestValidTopic(t *testing.T) {
+	for _, topic := range []string{"books", "genres", "stock", "book:x", "genre:y", "type:BookCreated"} {
+		if !ValidTopic(topic) {
+			t.Errorf("%q deveria ser válido", topic)
+		}
+	}
+	for _, topic := range []string{"authors", "book:", "foo:bar"} {
+		if ValidTopic(topic) {
+			t.Errorf("%q deveria ser inválido", topic)
+		}
+	}
+}
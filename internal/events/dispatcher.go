@@ -0,0 +1,124 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	repositories "projeto_livros/internal/repository"
+	"time"
+)
+
+const (
+	// dispatchBatchSize é o número máximo de eventos entregues por transação
+	dispatchBatchSize = 100
+	// retryBaseDelay e retryMaxDelay limitam a espera entre tentativas, que dobra a cada falha
+	retryBaseDelay = time.Second
+	retryMaxDelay  = 5 * time.Minute
+	// pruneInterval é o intervalo entre as limpezas dos eventos já entregues
+	pruneInterval = time.Hour
+)
+
+// Dispatcher entrega os eventos do outbox ao destino, em ordem de gravação.
+// Um evento só é marcado como entregue depois que o destino o aceitou, na
+// mesma transação que o leu: se o processo cair antes do commit, o evento é
+// entregue de novo (pelo menos uma vez). Quando uma entrega falha, os eventos
+// seguintes esperam, para que a ordem seja preservada.
+type Dispatcher struct {
+	db        *sql.DB
+	outbox    repositories.OutboxRepository
+	sink      Sink
+	interval  time.Duration
+	retention time.Duration
+	now       func() time.Time
+}
+
+// NewDispatcher cria o despachante. interval é o intervalo entre as buscas
+// por eventos novos e retention o tempo que os eventos entregues são mantidos.
+func NewDispatcher(db *sql.DB, sink Sink, interval, retention time.Duration) *Dispatcher {
+	return &Dispatcher{
+		db:        db,
+		outbox:    repositories.NewPostgresOutboxRepository(db),
+		sink:      sink,
+		interval:  interval,
+		retention: retention,
+		now:       time.Now,
+	}
+}
+
+// Run entrega os eventos pendentes a cada intervalo, até ctx ser cancelado
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	lastPrune := time.Time{}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// Enquanto houver lotes cheios, continua sem esperar o próximo tique
+			for {
+				sent, err := d.Dispatch(ctx)
+				if err != nil {
+					log.Printf("Erro ao despachar eventos: %v", err)
+				}
+				if err != nil || sent < dispatchBatchSize || ctx.Err() != nil {
+					break
+				}
+			}
+			if d.now().Sub(lastPrune) >= pruneInterval {
+				lastPrune = d.now()
+				if removed, err := d.outbox.Prune(lastPrune.Add(-d.retention)); err != nil {
+					log.Printf("Erro ao limpar eventos entregues: %v", err)
+				} else if removed > 0 {
+					log.Printf("Outbox: %d evento(s) entregue(s) removido(s)", removed)
+				}
+			}
+		}
+	}
+}
+
+// Dispatch entrega um lote de eventos pendentes e devolve quantos foram
+// entregues. Se outra instância já estiver despachando, nada é feito.
+func (d *Dispatcher) Dispatch(ctx context.Context) (int, error) {
+	sent := 0
+	err := repositories.RunInTx(d.db, func(tx *sql.Tx) error {
+		outbox := d.outbox.WithTx(tx)
+		locked, err := outbox.TryLock()
+		if err != nil || !locked {
+			return err
+		}
+		pending, err := outbox.Pending(dispatchBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, event := range pending {
+			if event.NextAttemptAt.After(d.now()) {
+				break
+			}
+			if err := d.sink.Send(ctx, event); err != nil {
+				retryAt := d.now().Add(retryDelay(event.Attempts + 1))
+				log.Printf("Falha ao entregar o evento %d (%s), tentativa %d; nova tentativa em %s: %v",
+					event.ID, event.Type, event.Attempts+1, retryAt.Format(time.RFC3339), err)
+				return outbox.MarkFailed(event.ID, err.Error(), retryAt)
+			}
+			if err := outbox.MarkDispatched(event.ID); err != nil {
+				return err
+			}
+			sent++
+		}
+		return nil
+	})
+	return sent, err
+}
+
+// retryDelay devolve a espera antes da tentativa seguinte à falha número attempts
+func retryDelay(attempts int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempts && delay < retryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+	return delay
+}
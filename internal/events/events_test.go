@@ -0,0 +1,197 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"projeto_livros/internal/domain/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+var pendingColumns = []string{"id", "event_type", "aggregate_type", "aggregate_id", "actor", "payload",
+	"created_at", "attempts", "next_attempt_at"}
+
+// recordingSink guarda os eventos recebidos e falha nos IDs de failOn
+type recordingSink struct {
+	sent   []int64
+	failOn map[int64]bool
+}
+
+func (s *recordingSink) Send(ctx context.Context, event models.Event) error {
+	if s.failOn[event.ID] {
+		return errors.New("destino indisponível")
+	}
+	s.sent = append(s.sent, event.ID)
+	return nil
+}
+
+func TestDispatchStopsAtFailureToKeepOrder(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Erro ao criar mock do banco de dados: %v", err)
+	}
+	defer db.Close()
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("pg_try_advisory_xact_lock").WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(true))
+	mock.ExpectQuery("FROM outbox_events").WithArgs(dispatchBatchSize).WillReturnRows(sqlmock.NewRows(pendingColumns).
+		AddRow(1, models.EventBookCreated, "book", "b1", "", `{}`, now, 0, now).
+		AddRow(2, models.EventStockChanged, "book", "b1", "", `{}`, now, 2, now).
+		AddRow(3, models.EventBookUpdated, "book", "b1", "", `{}`, now, 0, now))
+	mock.ExpectExec("UPDATE outbox_events SET dispatched_at").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE outbox_events SET attempts").
+		WithArgs(2, "destino indisponível", now.Add(4*time.Second)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	sink := &recordingSink{failOn: map[int64]bool{2: true}}
+	d := NewDispatcher(db, sink, time.Second, time.Hour)
+	d.now = func() time.Time { return now }
+
+	sent, err := d.Dispatch(context.Background())
+	if err != nil {
+		t.Fatalf("Erro ao despachar: %v", err)
+	}
+	if sent != 1 || len(sink.sent) != 1 || sink.sent[0] != 1 {
+		t.Errorf("entregues = %d %v, esperava apenas o evento 1", sent, sink.sent)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas não atendidas: %s", err)
+	}
+}
+
+func TestDispatchWaitsForRetryTime(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Erro ao criar mock do banco de dados: %v", err)
+	}
+	defer db.Close()
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("pg_try_advisory_xact_lock").WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(true))
+	mock.ExpectQuery("FROM outbox_events").WillReturnRows(sqlmock.NewRows(pendingColumns).
+		AddRow(5, models.EventGenreCreated, "genre", "g1", "", `{}`, now, 1, now.Add(time.Minute)).
+		AddRow(6, models.EventGenreUpdated, "genre", "g1", "", `{}`, now, 0, now))
+	mock.ExpectCommit()
+
+	sink := &recordingSink{}
+	d := NewDispatcher(db, sink, time.Second, time.Hour)
+	d.now = func() time.Time { return now }
+
+	if sent, err := d.Dispatch(context.Background()); err != nil || sent != 0 {
+		t.Fatalf("Dispatch = %d, %v; esperava nenhuma entrega", sent, err)
+	}
+	if len(sink.sent) != 0 {
+		t.Errorf("eventos entregues fora de ordem: %v", sink.sent)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas não atendidas: %s", err)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	cases := map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 4: 8 * time.Second, 30: retryMaxDelay}
+	for attempts, want := range cases {
+		if got := retryDelay(attempts); got != want {
+			t.Errorf("retryDelay(%d) = %v, esperava %v", attempts, got, want)
+		}
+	}
+}
+
+func TestWebhookSink(t *testing.T) {
+	var received models.Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Event-ID") != "7" || r.Header.Get("X-Event-Type") != models.EventBookCreated {
+			t.Errorf("cabeçalhos = %v", r.Header)
+		}
+		json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	sink, err := New("webhook", Options{WebhookURL: server.URL})
+	if err != nil {
+		t.Fatalf("Erro ao criar destino: %v", err)
+	}
+	event := models.Event{ID: 7, Type: models.EventBookCreated, AggregateID: "b1", Payload: json.RawMessage(`{"rev":1}`)}
+	if err := sink.Send(context.Background(), event); err != nil {
+		t.Fatalf("Erro ao enviar: %v", err)
+	}
+	if received.ID != 7 || received.AggregateID != "b1" || string(received.Payload) != `{"rev":1}` {
+		t.Errorf("evento recebido = %+v", received)
+	}
+}
+
+func TestFileSinkAppendsLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	sink := &FileSink{Path: path}
+	for id := int64(1); id <= 2; id++ {
+		if err := sink.Send(context.Background(), models.Event{ID: id, Type: models.EventGenreCreated, Payload: json.RawMessage(`{}`)}); err != nil {
+			t.Fatalf("Erro ao gravar: %v", err)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Erro ao ler arquivo: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[1], `"id":2`) {
+		t.Errorf("conteúdo = %q", data)
+	}
+}
+
+func TestNATSSinkPublishes(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Erro ao abrir porta: %v", err)
+	}
+	defer listener.Close()
+
+	published := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.Write([]byte("INFO {\"server_id\":\"teste\"}\r\n"))
+		reader := bufio.NewReader(conn)
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			switch {
+			case strings.HasPrefix(line, "PUB "):
+				payload, _ := reader.ReadString('\n')
+				published <- strings.TrimSpace(line) + " " + strings.TrimSpace(payload)
+			case strings.HasPrefix(line, "PING"):
+				conn.Write([]byte("PONG\r\n"))
+			}
+		}
+	}()
+
+	sink, err := New("nats", Options{NATSURL: "nats://" + listener.Addr().String(), NATSSubject: "livros"})
+	if err != nil {
+		t.Fatalf("Erro ao criar destino: %v", err)
+	}
+	defer sink.(*NATSSink).Close()
+	if err := sink.Send(context.Background(), models.Event{ID: 1, Type: models.EventStockChanged, Payload: json.RawMessage(`{}`)}); err != nil {
+		t.Fatalf("Erro ao publicar: %v", err)
+	}
+	got := <-published
+	if !strings.HasPrefix(got, "PUB livros.StockChanged ") || !strings.Contains(got, `"type":"StockChanged"`) {
+		t.Errorf("publicação = %q", got)
+	}
+}
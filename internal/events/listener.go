@@ -0,0 +1,82 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	repositories "projeto_livros/internal/repository"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// NotifyChannel é o canal do Postgres em que o gatilho do outbox anuncia o
+// ID de cada evento gravado. O NOTIFY só é entregue no commit, então todas
+// as instâncias da API recebem apenas eventos de alterações confirmadas.
+const NotifyChannel = "outbox_events"
+
+// catchUpLimit limita quantos eventos são lidos de uma vez após uma reconexão
+const catchUpLimit = 1000
+
+// Listen publica no broker os eventos anunciados pelo banco, até ctx ser
+// cancelado. Antes de escutar, carrega os eventos mais recentes no histórico
+// do broker; após uma queda da conexão, lê os eventos gravados no intervalo.
+func Listen(ctx context.Context, dsn string, outbox repositories.OutboxRepository, broker *Broker) {
+	recent, err := outbox.Recent(broker.size)
+	if err != nil {
+		log.Printf("Erro ao carregar eventos recentes: %v", err)
+	}
+	for _, event := range recent {
+		broker.Publish(event)
+	}
+
+	listener := pq.NewListener(dsn, time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Printf("Escuta de eventos do banco: %v", err)
+		}
+	})
+	defer listener.Close()
+	if err := listener.Listen(NotifyChannel); err != nil {
+		log.Printf("Erro ao escutar o canal %s: %v", NotifyChannel, err)
+		return
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case n := <-listener.Notify:
+			if n == nil {
+				// Conexão restabelecida: notificações do intervalo foram perdidas
+				catchUp(outbox, broker)
+				continue
+			}
+			id, err := strconv.ParseInt(n.Extra, 10, 64)
+			if err != nil {
+				continue
+			}
+			event, err := outbox.FindByID(id)
+			if err == sql.ErrNoRows {
+				continue
+			} else if err != nil {
+				log.Printf("Erro ao carregar o evento %d: %v", id, err)
+				continue
+			}
+			broker.Publish(*event)
+		case <-time.After(90 * time.Second):
+			go listener.Ping()
+		}
+	}
+}
+
+func catchUp(outbox repositories.OutboxRepository, broker *Broker) {
+	events, err := outbox.After(broker.LastID(), catchUpLimit)
+	if err != nil {
+		log.Printf("Erro ao recuperar eventos após reconexão: %v", err)
+		return
+	}
+	for _, event := range events {
+		broker.Publish(event)
+	}
+}
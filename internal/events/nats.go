@@ -0,0 +1,128 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"projeto_livros/internal/domain/models"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NATSSink publica cada evento no assunto Subject.<tipo> de um servidor
+// compatível com o protocolo NATS. Depois de cada PUB é enviado um PING, e a
+// entrega só é confirmada quando o PONG chega, o que garante que o servidor
+// processou a publicação. A conexão é reaberta após qualquer erro.
+type NATSSink struct {
+	URL     string
+	Subject string
+	Timeout time.Duration
+
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func (s *NATSSink) Send(ctx context.Context, event models.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		if err := s.connect(ctx); err != nil {
+			return err
+		}
+	}
+	if err := s.publish(s.Subject+"."+event.Type, payload); err != nil {
+		s.close()
+		return err
+	}
+	return nil
+}
+
+// Close encerra a conexão com o servidor
+func (s *NATSSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.close()
+	return nil
+}
+
+func (s *NATSSink) close() {
+	if s.conn != nil {
+		s.conn.Close()
+		s.conn = nil
+		s.reader = nil
+	}
+}
+
+func (s *NATSSink) connect(ctx context.Context) error {
+	addr := s.URL
+	connectOpts := map[string]interface{}{"verbose": false, "pedantic": false, "name": "projeto_livros"}
+	if strings.Contains(addr, "://") {
+		u, err := url.Parse(addr)
+		if err != nil {
+			return fmt.Errorf("URL do NATS inválida: %w", err)
+		}
+		addr = u.Host
+		if u.User != nil {
+			connectOpts["user"] = u.User.Username()
+			connectOpts["pass"], _ = u.User.Password()
+		}
+	}
+	dialer := net.Dialer{Timeout: s.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	s.conn = conn
+	s.reader = bufio.NewReader(conn)
+	s.conn.SetDeadline(time.Now().Add(s.Timeout))
+
+	// O servidor se apresenta com INFO assim que a conexão é aberta
+	line, err := s.reader.ReadString('\n')
+	if err != nil || !strings.HasPrefix(line, "INFO") {
+		s.close()
+		if err == nil {
+			err = fmt.Errorf("resposta inesperada do servidor NATS: %q", strings.TrimSpace(line))
+		}
+		return err
+	}
+	options, _ := json.Marshal(connectOpts)
+	if _, err := fmt.Fprintf(s.conn, "CONNECT %s\r\n", options); err != nil {
+		s.close()
+		return err
+	}
+	return nil
+}
+
+func (s *NATSSink) publish(subject string, payload []byte) error {
+	s.conn.SetDeadline(time.Now().Add(s.Timeout))
+	if _, err := fmt.Fprintf(s.conn, "PUB %s %d\r\n%s\r\nPING\r\n", subject, len(payload), payload); err != nil {
+		return err
+	}
+	for {
+		line, err := s.reader.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "PONG":
+			return nil
+		case line == "PING":
+			if _, err := s.conn.Write([]byte("PONG\r\n")); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			return fmt.Errorf("servidor NATS recusou a publicação: %s", line)
+		}
+		// +OK e INFO são ignorados
+	}
+}
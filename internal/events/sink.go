@@ -0,0 +1,151 @@
+// Package events entrega os eventos de domínio gravados no outbox a um
+// destino configurável (stdout, arquivo, webhook ou servidor NATS).
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"projeto_livros/internal/domain/models"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Sink entrega um evento. Só deve devolver nil depois que o destino aceitou
+// o evento; em caso de erro a entrega é repetida mais tarde.
+type Sink interface {
+	Send(ctx context.Context, event models.Event) error
+}
+
+// Options reúne as configurações dos destinos
+type Options struct {
+	FilePath    string
+	WebhookURL  string
+	NATSURL     string // nats://[usuario:senha@]host:porta
+	NATSSubject string // Prefixo do assunto; o tipo do evento é acrescentado
+}
+
+// New cria o destino do tipo informado: "stdout" (padrão), "file", "webhook" ou "nats"
+func New(kind string, opts Options) (Sink, error) {
+	switch strings.ToLower(kind) {
+	case "", "stdout":
+		return &WriterSink{Out: os.Stdout}, nil
+	case "file":
+		if opts.FilePath == "" {
+			return nil, fmt.Errorf("destino file requer o caminho do arquivo")
+		}
+		return &FileSink{Path: opts.FilePath}, nil
+	case "webhook":
+		if opts.WebhookURL == "" {
+			return nil, fmt.Errorf("destino webhook requer a URL do webhook")
+		}
+		return &WebhookSink{URL: opts.WebhookURL, Client: &http.Client{Timeout: 10 * time.Second}}, nil
+	case "nats":
+		if opts.NATSURL == "" {
+			return nil, fmt.Errorf("destino nats requer a URL do servidor")
+		}
+		subject := opts.NATSSubject
+		if subject == "" {
+			subject = "livros.events"
+		}
+		return &NATSSink{URL: opts.NATSURL, Subject: subject, Timeout: 5 * time.Second}, nil
+	}
+	return nil, fmt.Errorf("destino de eventos desconhecido: %s", kind)
+}
+
+// MultiSink entrega cada evento a todos os destinos. Se algum falhar, a
+// entrega inteira é repetida depois, inclusive nos destinos que já aceitaram.
+type MultiSink []Sink
+
+func (m MultiSink) Send(ctx context.Context, event models.Event) error {
+	var errs []error
+	for _, sink := range m {
+		if err := sink.Send(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WriterSink escreve cada evento como uma linha JSON
+type WriterSink struct {
+	Out io.Writer
+	mu  sync.Mutex
+}
+
+func (s *WriterSink) Send(ctx context.Context, event models.Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.Out.Write(append(line, '\n'))
+	return err
+}
+
+// FileSink acrescenta cada evento como uma linha JSON ao fim de um arquivo,
+// gravando em disco antes de confirmar a entrega
+type FileSink struct {
+	Path string
+	mu   sync.Mutex
+}
+
+func (s *FileSink) Send(ctx context.Context, event models.Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, err := os.OpenFile(s.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// WebhookSink envia cada evento em JSON por POST para uma URL. Os cabeçalhos
+// X-Event-ID e X-Event-Type permitem ao destino descartar entregas repetidas.
+type WebhookSink struct {
+	URL    string
+	Client *http.Client
+}
+
+func (s *WebhookSink) Send(ctx context.Context, event models.Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-ID", strconv.FormatInt(event.ID, 10))
+	req.Header.Set("X-Event-Type", event.Type)
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook respondeu com status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package i18n
+
+// en é o catálogo em inglês
+var en = Catalog{
+	"BAD_REQUEST.title":                  "Bad request",
+	"BAD_REQUEST.detail":                 "Bad request",
+	"UNAUTHORIZED.title":                 "Unauthorized",
+	"UNAUTHORIZED.detail":                "Unauthorized",
+	"INVALID_TOKEN.title":                "Invalid token",
+	"INVALID_TOKEN.detail":               "Access token is invalid or expired",
+	"FORBIDDEN.title":                    "Forbidden",
+	"FORBIDDEN.detail":                   "Access denied",
+	"ADMIN_REQUIRED.title":               "Administrators only",
+	"ADMIN_REQUIRED.detail":              "Only administrators can perform this operation",
+	"NOT_FOUND.title":                    "Not found",
+	"NOT_FOUND.detail":                   "Resource not found",
+	"METHOD_NOT_ALLOWED.title":           "Method not allowed",
+	"METHOD_NOT_ALLOWED.detail":          "Method %s is not allowed for this route",
+	"CONFLICT.title":                     "Conflict",
+	"CONFLICT.detail":                    "The operation conflicts with the current state of the resource",
+	"INTERNAL_ERROR.title":               "Internal error",
+	"INTERNAL_ERROR.detail":              "Internal server error",
+	"INVALID_BODY.title":                 "Invalid body",
+	"INVALID_BODY.detail":                "Could not read the request body",
+	"PAYLOAD_TOO_LARGE.title":            "Payload too large",
+	"PAYLOAD_TOO_LARGE.detail":           "The request body exceeds the limit of %d bytes",
+	"VALIDATION_FAILED.title":            "Invalid data",
+	"VALIDATION_FAILED.detail":           "The request has invalid fields",
+	"RESPONSE_INVALID.title":             "Response out of specification",
+	"RESPONSE_INVALID.detail":            "The response does not follow the OpenAPI specification",
+	"INVALID_PARAMETER.title":            "Invalid parameter",
+	"INVALID_PARAMETER.detail":           "Invalid value for %s. Use: %s",
+	"INVALID_DATE.title":                 "Invalid date",
+	"INVALID_DATE.detail":                "Invalid date in %s. Use the RFC 3339 format",
+	"INVALID_SORT.title":                 "Invalid sort",
+	"INVALID_SORT.detail":                "Invalid sort. Use: %s",
+	"INVALID_INTEGER.title":              "Invalid parameter",
+	"INVALID_INTEGER.detail":             "The %s parameter must be an integer",
+	"SERVICE_UNAVAILABLE.title":          "Service unavailable",
+	"SERVICE_UNAVAILABLE.detail":         "Service unavailable",
+	"BOOK_NOT_FOUND.title":               "Book not found",
+	"BOOK_NOT_FOUND.detail":              "Book not found",
+	"BOOK_NOT_IN_TRASH.title":            "Book not in trash",
+	"BOOK_NOT_IN_TRASH.detail":           "Book not found in the trash",
+	"BOOK_ID_REQUIRED.title":             "Missing book ID",
+	"BOOK_ID_REQUIRED.detail":            "Book ID not provided",
+	"BOOK_TITLE_REQUIRED.title":          "Missing title",
+	"BOOK_TITLE_REQUIRED.detail":         "The book title (name) is required",
+	"BOOK_AUTHOR_REQUIRED.title":         "Missing author",
+	"BOOK_AUTHOR_REQUIRED.detail":        "The book author is required",
+	"BOOK_HAS_ORDERS.title":              "Book has orders",
+	"BOOK_HAS_ORDERS.detail":             "The book has purchase orders and cannot be permanently deleted",
+	"ISBN_CONFLICT.title":                "ISBN already registered",
+	"ISBN_CONFLICT.detail":               "Another book already has this ISBN",
+	"BATCH_ISBN_CONFLICT.title":          "ISBN already registered or repeated",
+	"BATCH_ISBN_CONFLICT.detail":         "Books with an ISBN already registered or repeated in the batch",
+	"MARC_IMPORT_FAILED.title":           "No records imported",
+	"MARC_IMPORT_FAILED.detail":          "No MARC record could be imported; see the per-record errors in records",
+	"ISBN_INVALID_LENGTH.title":          "Invalid ISBN",
+	"ISBN_INVALID_LENGTH.detail":         "Invalid ISBN: use 10 or 13 digits",
+	"ISBN_INVALID_CHECKSUM.title":        "Invalid ISBN",
+	"ISBN_INVALID_CHECKSUM.detail":       "Invalid ISBN-%d: check digit does not match",
+	"ISBN_MISMATCH.title":                "Mismatched ISBNs",
+	"ISBN_MISMATCH.detail":               "The isbn, isbn_10 and isbn_13 fields refer to different books",
+	"QUANTITY_NOT_POSITIVE.title":        "Invalid quantity",
+	"QUANTITY_NOT_POSITIVE.detail":       "The quantity must be greater than zero",
+	"QUANTITY_NEGATIVE.title":            "Invalid quantity",
+	"QUANTITY_NEGATIVE.detail":           "The quantity cannot be negative",
+	"QUANTITY_INVALID.title":             "Invalid quantity",
+	"QUANTITY_INVALID.detail":            "The quantity must be an integer",
+	"MIN_QUANTITY_NEGATIVE.title":        "Invalid minimum stock",
+	"MIN_QUANTITY_NEGATIVE.detail":       "The minimum stock (min_quantity) cannot be negative",
+	"STOCK_UNDERFLOW.title":              "Insufficient stock",
+	"STOCK_UNDERFLOW.detail":             "Insufficient stock: the book has %d units",
+	"STOCK_DELTA_ZERO.title":             "Empty stock adjustment",
+	"STOCK_DELTA_ZERO.detail":            "The stock adjustment (delta) cannot be zero",
+	"BOOK_HISTORY_EMPTY.title":           "Empty history",
+	"BOOK_HISTORY_EMPTY.detail":          "No revisions found for the book",
+	"REVISION_NOT_FOUND.title":           "Revision not found",
+	"REVISION_NOT_FOUND.detail":          "Revision %d not found",
+	"REVISION_INVALID.title":             "Invalid revision",
+	"REVISION_INVALID.detail":            "Invalid revision number: %s",
+	"REVISION_RANGE_REQUIRED.title":      "Missing revisions",
+	"REVISION_RANGE_REQUIRED.detail":     "Provide the revisions to compare in from and to",
+	"REVERT_TO_DELETE.title":             "Invalid revert",
+	"REVERT_TO_DELETE.detail":            "Cannot revert to a deletion revision",
+	"REVISION_GENRE_MISSING.title":       "Genre removed",
+	"REVISION_GENRE_MISSING.detail":      "One of the revision's genres no longer exists",
+	"GENRE_NOT_FOUND.title":              "Genre not found",
+	"GENRE_NOT_FOUND.detail":             "Genre not found",
+	"GENRE_NOT_IN_TRASH.title":           "Genre not in trash",
+	"GENRE_NOT_IN_TRASH.detail":          "Genre not found in the trash",
+	"GENRE_ID_REQUIRED.title":            "Missing genre ID",
+	"GENRE_ID_REQUIRED.detail":           "Genre ID is required",
+	"GENRE_REFERENCE_NOT_FOUND.title":    "Unknown genre",
+	"GENRE_REFERENCE_NOT_FOUND.detail":   "One of the given genres does not exist",
+	"PARENT_GENRE_NOT_FOUND.title":       "Unknown parent genre",
+	"PARENT_GENRE_NOT_FOUND.detail":      "Parent genre not found",
+	"GENRE_NAME_REQUIRED.title":          "Missing name",
+	"GENRE_NAME_REQUIRED.detail":         "The genre name is required",
+	"GENRE_NAME_CONFLICT.title":          "Genre name in use",
+	"GENRE_NAME_CONFLICT.detail":         "A genre with this name already exists (check the trash as well)",
+	"GENRE_CYCLE.title":                  "Invalid hierarchy",
+	"GENRE_CYCLE.detail":                 "The parent genre cannot be the genre itself or one of its subgenres",
+	"GENRE_IN_USE.title":                 "Genre has books",
+	"GENRE_IN_USE.detail":                "The genre has %d books. Use reassign_to to move them before deleting",
+	"GENRE_REFERENCED.title":             "Genre referenced",
+	"GENRE_REFERENCED.detail":            "The genre is still referenced and cannot be deleted",
+	"REASSIGN_SAME_GENRE.title":          "Invalid reassignment",
+	"REASSIGN_SAME_GENRE.detail":         "reassign_to must be a genre other than the one being deleted",
+	"REASSIGN_TARGET_NOT_FOUND.title":    "Unknown target genre",
+	"REASSIGN_TARGET_NOT_FOUND.detail":   "Target genre (reassign_to) not found",
+	"MERGE_TARGET_REQUIRED.title":        "Missing target",
+	"MERGE_TARGET_REQUIRED.detail":       "Provide target_id, the genre that will receive the books",
+	"MERGE_INTO_SELF.title":              "Invalid merge",
+	"MERGE_INTO_SELF.detail":             "A genre cannot be merged into itself",
+	"MERGE_INTO_DESCENDANT.title":        "Invalid merge",
+	"MERGE_INTO_DESCENDANT.detail":       "A genre cannot be merged into one of its subgenres",
+	"AUTHOR_NOT_FOUND.title":             "Author not found",
+	"AUTHOR_NOT_FOUND.detail":            "Author not found",
+	"AUTHOR_REFERENCE_NOT_FOUND.title":   "Unknown author",
+	"AUTHOR_REFERENCE_NOT_FOUND.detail":  "One of the given authors does not exist",
+	"AUTHOR_REFERENCE_REQUIRED.title":    "Incomplete author",
+	"AUTHOR_REFERENCE_REQUIRED.detail":   "Each author needs an 'id' or a 'name'",
+	"AUTHOR_NAME_REQUIRED.title":         "Missing name",
+	"AUTHOR_NAME_REQUIRED.detail":        "The author name is required",
+	"AUTHOR_NAME_CONFLICT.title":         "Author name in use",
+	"AUTHOR_NAME_CONFLICT.detail":        "An author with this name already exists",
+	"AUTHOR_IN_USE.title":                "Author has books",
+	"AUTHOR_IN_USE.detail":               "The author is linked to books and cannot be deleted",
+	"AUTHOR_ROLE_INVALID.title":          "Invalid role",
+	"AUTHOR_ROLE_INVALID.detail":         "Invalid author role: %s. Use one of: %s",
+	"SUPPLIER_NOT_FOUND.title":           "Supplier not found",
+	"SUPPLIER_NOT_FOUND.detail":          "Supplier not found",
+	"SUPPLIER_NAME_REQUIRED.title":       "Missing name",
+	"SUPPLIER_NAME_REQUIRED.detail":      "The supplier name is required",
+	"SUPPLIER_NAME_CONFLICT.title":       "Supplier name in use",
+	"SUPPLIER_NAME_CONFLICT.detail":      "A supplier with this name already exists",
+	"SUPPLIER_IN_USE.title":              "Supplier has orders",
+	"SUPPLIER_IN_USE.detail":             "The supplier has orders and cannot be deleted",
+	"ORDER_NOT_FOUND.title":              "Order not found",
+	"ORDER_NOT_FOUND.detail":             "Order not found",
+	"ORDER_REFERENCE_NOT_FOUND.title":    "Unknown reference",
+	"ORDER_REFERENCE_NOT_FOUND.detail":   "Supplier or book not found",
+	"ORDER_NOT_DRAFT.title":              "Order is not a draft",
+	"ORDER_NOT_DRAFT.detail":             "Only draft orders can be changed, deleted or sent",
+	"ORDER_EMPTY.title":                  "Empty order",
+	"ORDER_EMPTY.detail":                 "The order has no lines",
+	"ORDER_NOT_RECEIVABLE.title":         "Order not sent",
+	"ORDER_NOT_RECEIVABLE.detail":        "Only sent or partially received orders can be received",
+	"ORDER_SUPPLIER_REQUIRED.title":      "Missing supplier",
+	"ORDER_SUPPLIER_REQUIRED.detail":     "The supplier (supplier_id) is required",
+	"ORDER_LINE_BOOK_REQUIRED.title":     "Line without book",
+	"ORDER_LINE_BOOK_REQUIRED.detail":    "Each line needs the book (book_id)",
+	"ORDER_LINE_QUANTITY_INVALID.title":  "Invalid quantity",
+	"ORDER_LINE_QUANTITY_INVALID.detail": "The quantity of each line must be greater than zero",
+	"ORDER_LINE_COST_NEGATIVE.title":     "Invalid cost",
+	"ORDER_LINE_COST_NEGATIVE.detail":    "The unit cost cannot be negative",
+	"ORDER_LINE_DUPLICATE_BOOK.title":    "Repeated book",
+	"ORDER_LINE_DUPLICATE_BOOK.detail":   "Book %s appears in more than one order line",
+	"ORDER_LINE_NOT_FOUND.title":         "Unknown line",
+	"ORDER_LINE_NOT_FOUND.detail":        "Line not found in the order",
+	"RECEIPT_LINE_INVALID.title":         "Invalid receipt",
+	"RECEIPT_LINE_INVALID.detail":        "Each received line needs line_id and a quantity greater than zero",
+	"RECEIPT_EXCEEDS_ORDERED.title":      "Receipt above order",
+	"RECEIPT_EXCEEDS_ORDERED.detail":     "Received quantity exceeds the line's outstanding balance",
+	"ALERT_NOT_FOUND.title":              "Alert not found",
+	"ALERT_NOT_FOUND.detail":             "Alert not found",
+	"DIGEST_NOT_CONFIGURED.title":        "Digest unavailable",
+	"DIGEST_NOT_CONFIGURED.detail":       "Alert digest is not configured",
+	"DIGEST_FAILED.title":                "Delivery failed",
+	"DIGEST_FAILED.detail":               "Failed to send the alert digest",
+	"METADATA_NOT_CONFIGURED.title":      "Metadata unavailable",
+	"METADATA_NOT_CONFIGURED.detail":     "Metadata service is not configured",
+	"METADATA_NOT_FOUND.title":           "Metadata not found",
+	"METADATA_NOT_FOUND.detail":          "No metadata found for the ISBN",
+	"WEBHOOK_NOT_FOUND.title":            "Webhook not found",
+	"WEBHOOK_NOT_FOUND.detail":           "Webhook not found",
+	"WEBHOOK_URL_INVALID.title":          "Invalid URL",
+	"WEBHOOK_URL_INVALID.detail":         "Provide a valid http or https URL",
+	"WEBHOOK_EVENT_UNKNOWN.title":        "Unknown event",
+	"WEBHOOK_EVENT_UNKNOWN.detail":       "Unknown event type: %s. Use one of: %s",
+	"DELIVERY_NOT_FOUND.title":           "Delivery not found",
+	"DELIVERY_NOT_FOUND.detail":          "Delivery not found",
+	"STREAM_TOPIC_INVALID.title":         "Invalid topic",
+	"STREAM_TOPIC_INVALID.detail":        "Invalid topic: %s. Use books, genres, stock, book:<id>, genre:<id> or type:<type>",
+
+	// Violações de campo e termos usados nas mensagens
+	"validation.required":              "required field",
+	"validation.parameter_required":    "required parameter",
+	"validation.body_required":         "request body is required",
+	"validation.invalid_json":          "invalid JSON: %s",
+	"validation.not_null":              "must not be null",
+	"validation.type.string":           "must be a string",
+	"validation.type.integer":          "must be an integer",
+	"validation.type.number":           "must be a number",
+	"validation.type.boolean":          "must be a boolean",
+	"validation.type.array":            "must be an array",
+	"validation.type.object":           "must be an object",
+	"validation.minimum":               "must be greater than or equal to %s",
+	"validation.maximum":               "must be less than or equal to %s",
+	"validation.min_items":             "must have at least %d items",
+	"validation.any_of":                "provide %s",
+	"validation.enum":                  "must be one of: %s",
+	"validation.min_length":            "must have at least %d characters",
+	"validation.max_length":            "must have at most %d characters",
+	"validation.ksuid":                 "must be an identifier of 27 letters and digits",
+	"validation.pattern":               "invalid format",
+	"validation.date_time":             "invalid date; use the RFC 3339 format",
+	"validation.status_undocumented":   "status %d not documented",
+	"validation.response_undocumented": "JSON response not documented",
+	"list.or":                          " or ",
+}
@@ -0,0 +1,126 @@
+// Package i18n guarda os catálogos de mensagens da API e escolhe o idioma
+// de cada resposta pelo cabeçalho Accept-Language.
+//
+// As chaves dos erros seguem o código do catálogo de erros: <CODE>.title é o
+// resumo do tipo de problema e <CODE>.detail a mensagem de detalhe, no formato
+// do fmt. As violações de campo usam as chaves validation.*.
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Idiomas com catálogo
+const (
+	PtBR = "pt-BR"
+	En   = "en"
+
+	// Default é o idioma das respostas sem Accept-Language ou sem idioma aceito
+	Default = PtBR
+)
+
+// Catalog associa cada chave ao texto em um idioma
+type Catalog map[string]string
+
+var catalogs = map[string]Catalog{
+	PtBR: ptBR,
+	En:   en,
+}
+
+// Languages devolve os idiomas com catálogo, o padrão primeiro
+func Languages() []string {
+	return []string{PtBR, En}
+}
+
+// Keys devolve as chaves do catálogo de lang, ordenadas
+func Keys(lang string) []string {
+	keys := make([]string, 0, len(catalogs[lang]))
+	for key := range catalogs[lang] {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Has indica se a chave existe no catálogo padrão
+func Has(key string) bool {
+	_, ok := ptBR[key]
+	return ok
+}
+
+// Localizer é um argumento de mensagem que depende do idioma, como a lista de
+// alternativas de Either
+type Localizer interface {
+	Localize(lang string) string
+}
+
+// Either junta as alternativas com "ou" no idioma da mensagem
+type Either []string
+
+func (e Either) Localize(lang string) string {
+	return strings.Join(e, T(lang, "list.or"))
+}
+
+// T devolve o texto da chave em lang, preenchido com args. Chaves ausentes em
+// lang caem no catálogo padrão; ausentes nos dois, na própria chave.
+func T(lang, key string, args ...interface{}) string {
+	format, ok := catalogs[lang][key]
+	if !ok {
+		if format, ok = ptBR[key]; !ok {
+			return key
+		}
+	}
+	if len(args) == 0 {
+		return format
+	}
+	localized := make([]interface{}, len(args))
+	for i, arg := range args {
+		if l, ok := arg.(Localizer); ok {
+			arg = l.Localize(lang)
+		}
+		localized[i] = arg
+	}
+	return fmt.Sprintf(format, localized...)
+}
+
+// Negotiate escolhe o idioma da resposta pelo valor de Accept-Language, como
+// "en-US,en;q=0.9,pt;q=0.5". Vale o idioma aceito de maior peso; qualquer
+// variante de pt vira pt-BR e qualquer variante de en vira en. Sem idioma
+// aceito, fica o padrão.
+func Negotiate(header string) string {
+	best, bestWeight := Default, 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		weight := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			weight = parsed
+		}
+		lang := match(tag)
+		if lang == "" || weight <= bestWeight {
+			continue
+		}
+		best, bestWeight = lang, weight
+	}
+	return best
+}
+
+// match associa uma etiqueta de idioma ao catálogo correspondente
+func match(tag string) string {
+	primary, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+	switch primary {
+	case "pt":
+		return PtBR
+	case "en":
+		return En
+	case "*":
+		return Default
+	}
+	return ""
+}
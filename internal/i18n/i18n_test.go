@@ -0,0 +1,81 @@
+package i18n
+
+import (
+	"regexp"
+	"testing"
+)
+
+var verbs = regexp.MustCompile(`%[a-z]`)
+
+// Toda chave precisa existir em todos os catálogos, com os mesmos verbos do
+// fmt na mesma ordem, para que os argumentos sirvam a qualquer idioma
+func TestCatalogsHaveTheSameKeys(t *testing.T) {
+	for _, lang := range Languages() {
+		for _, other := range Languages() {
+			if lang == other {
+				continue
+			}
+			for _, key := range Keys(lang) {
+				text, ok := catalogs[other][key]
+				if !ok {
+					t.Errorf("Chave %s do catálogo %s ausente em %s", key, lang, other)
+					continue
+				}
+				if got, want := verbs.FindAllString(text, -1), verbs.FindAllString(catalogs[lang][key], -1); !equal(got, want) {
+					t.Errorf("Chave %s: verbos %v em %s, %v em %s", key, want, lang, got, other)
+				}
+			}
+		}
+		for _, key := range Keys(lang) {
+			if catalogs[lang][key] == "" {
+				t.Errorf("Chave %s vazia em %s", key, lang)
+			}
+		}
+	}
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestNegotiate(t *testing.T) {
+	for header, want := range map[string]string{
+		"":                            PtBR,
+		"en":                          En,
+		"en-US,en;q=0.9":              En,
+		"pt-PT":                       PtBR,
+		"fr-FR, en;q=0.5":             En,
+		"fr-FR, de;q=0.8":             PtBR,
+		"en;q=0.4, pt-BR;q=0.8":       PtBR,
+		"de, *;q=0.1":                 PtBR,
+		"en;q=abc, pt;q=0.3":          PtBR,
+		"EN-gb;q=1.0, pt-BR;q=0.9, *": En,
+	} {
+		if got := Negotiate(header); got != want {
+			t.Errorf("Negotiate(%q) = %s, esperava %s", header, got, want)
+		}
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	if got := T(En, "STOCK_UNDERFLOW.detail", 3); got != "Insufficient stock: the book has 3 units" {
+		t.Errorf("mensagem em inglês incorreta: %q", got)
+	}
+	if got := T(PtBR, "validation.any_of", Either{"id", "name"}); got != "informe id ou name" {
+		t.Errorf("alternativas em português incorretas: %q", got)
+	}
+	if got := T(En, "validation.any_of", Either{"id", "name"}); got != "provide id or name" {
+		t.Errorf("alternativas em inglês incorretas: %q", got)
+	}
+	if got := T("fr", "BOOK_NOT_FOUND.title"); got != "Livro não encontrado" {
+		t.Errorf("idioma sem catálogo deveria usar o padrão: %q", got)
+	}
+}
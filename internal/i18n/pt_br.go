@@ -0,0 +1,213 @@
+package i18n
+
+// ptBR é o catálogo padrão, em português do Brasil
+var ptBR = Catalog{
+	"BAD_REQUEST.title":                  "Requisição inválida",
+	"BAD_REQUEST.detail":                 "Requisição inválida",
+	"UNAUTHORIZED.title":                 "Não autorizado",
+	"UNAUTHORIZED.detail":                "Não autorizado",
+	"INVALID_TOKEN.title":                "Token inválido",
+	"INVALID_TOKEN.detail":               "Token de acesso inválido ou expirado",
+	"FORBIDDEN.title":                    "Acesso negado",
+	"FORBIDDEN.detail":                   "Acesso negado",
+	"ADMIN_REQUIRED.title":               "Acesso restrito a administradores",
+	"ADMIN_REQUIRED.detail":              "Apenas administradores podem realizar esta operação",
+	"NOT_FOUND.title":                    "Não encontrado",
+	"NOT_FOUND.detail":                   "Recurso não encontrado",
+	"METHOD_NOT_ALLOWED.title":           "Método não permitido",
+	"METHOD_NOT_ALLOWED.detail":          "Método %s não permitido para esta rota",
+	"CONFLICT.title":                     "Conflito",
+	"CONFLICT.detail":                    "A operação conflita com o estado atual do recurso",
+	"INTERNAL_ERROR.title":               "Erro interno",
+	"INTERNAL_ERROR.detail":              "Erro interno do servidor",
+	"INVALID_BODY.title":                 "Corpo inválido",
+	"INVALID_BODY.detail":                "Não foi possível ler o corpo da requisição",
+	"PAYLOAD_TOO_LARGE.title":            "Corpo grande demais",
+	"PAYLOAD_TOO_LARGE.detail":           "O corpo da requisição passa do limite de %d bytes",
+	"VALIDATION_FAILED.title":            "Dados inválidos",
+	"VALIDATION_FAILED.detail":           "A requisição tem campos inválidos",
+	"RESPONSE_INVALID.title":             "Resposta fora da especificação",
+	"RESPONSE_INVALID.detail":            "A resposta não segue a especificação OpenAPI",
+	"INVALID_PARAMETER.title":            "Parâmetro inválido",
+	"INVALID_PARAMETER.detail":           "Valor inválido para %s. Use: %s",
+	"INVALID_DATE.title":                 "Data inválida",
+	"INVALID_DATE.detail":                "Data inválida em %s. Use o formato RFC 3339",
+	"INVALID_SORT.title":                 "Ordenação inválida",
+	"INVALID_SORT.detail":                "Ordenação inválida. Use: %s",
+	"INVALID_INTEGER.title":              "Parâmetro inválido",
+	"INVALID_INTEGER.detail":             "O parâmetro %s deve ser um número inteiro",
+	"SERVICE_UNAVAILABLE.title":          "Serviço indisponível",
+	"SERVICE_UNAVAILABLE.detail":         "Serviço indisponível",
+	"BOOK_NOT_FOUND.title":               "Livro não encontrado",
+	"BOOK_NOT_FOUND.detail":              "Livro não encontrado",
+	"BOOK_NOT_IN_TRASH.title":            "Livro fora da lixeira",
+	"BOOK_NOT_IN_TRASH.detail":           "Livro não encontrado na lixeira",
+	"BOOK_ID_REQUIRED.title":             "ID do livro ausente",
+	"BOOK_ID_REQUIRED.detail":            "ID do livro não fornecido",
+	"BOOK_TITLE_REQUIRED.title":          "Título ausente",
+	"BOOK_TITLE_REQUIRED.detail":         "O título (name) do livro é obrigatório",
+	"BOOK_AUTHOR_REQUIRED.title":         "Autor ausente",
+	"BOOK_AUTHOR_REQUIRED.detail":        "O autor do livro é obrigatório",
+	"BOOK_HAS_ORDERS.title":              "Livro com pedidos",
+	"BOOK_HAS_ORDERS.detail":             "O livro possui pedidos de compra e não pode ser apagado definitivamente",
+	"ISBN_CONFLICT.title":                "ISBN já cadastrado",
+	"ISBN_CONFLICT.detail":               "Já existe outro livro com este ISBN",
+	"BATCH_ISBN_CONFLICT.title":          "ISBN já cadastrado ou repetido",
+	"BATCH_ISBN_CONFLICT.detail":         "Livros com ISBN já cadastrado ou repetido no lote",
+	"MARC_IMPORT_FAILED.title":           "Nenhum registro importado",
+	"MARC_IMPORT_FAILED.detail":          "Nenhum registro MARC pôde ser importado; veja os erros de cada registro em records",
+	"ISBN_INVALID_LENGTH.title":          "ISBN inválido",
+	"ISBN_INVALID_LENGTH.detail":         "ISBN inválido: informe 10 ou 13 dígitos",
+	"ISBN_INVALID_CHECKSUM.title":        "ISBN inválido",
+	"ISBN_INVALID_CHECKSUM.detail":       "ISBN-%d inválido: dígito verificador não confere",
+	"ISBN_MISMATCH.title":                "ISBNs divergentes",
+	"ISBN_MISMATCH.detail":               "Os campos isbn, isbn_10 e isbn_13 informam livros diferentes",
+	"QUANTITY_NOT_POSITIVE.title":        "Quantidade inválida",
+	"QUANTITY_NOT_POSITIVE.detail":       "A quantidade deve ser maior que zero",
+	"QUANTITY_NEGATIVE.title":            "Quantidade inválida",
+	"QUANTITY_NEGATIVE.detail":           "A quantidade não pode ser negativa",
+	"QUANTITY_INVALID.title":             "Quantidade inválida",
+	"QUANTITY_INVALID.detail":            "A quantidade deve ser um número inteiro",
+	"MIN_QUANTITY_NEGATIVE.title":        "Estoque mínimo inválido",
+	"MIN_QUANTITY_NEGATIVE.detail":       "O estoque mínimo (min_quantity) não pode ser negativo",
+	"STOCK_UNDERFLOW.title":              "Estoque insuficiente",
+	"STOCK_UNDERFLOW.detail":             "Estoque insuficiente: o livro tem %d unidades",
+	"STOCK_DELTA_ZERO.title":             "Ajuste de estoque vazio",
+	"STOCK_DELTA_ZERO.detail":            "O ajuste de estoque (delta) não pode ser zero",
+	"BOOK_HISTORY_EMPTY.title":           "Histórico vazio",
+	"BOOK_HISTORY_EMPTY.detail":          "Nenhuma revisão encontrada para o livro",
+	"REVISION_NOT_FOUND.title":           "Revisão não encontrada",
+	"REVISION_NOT_FOUND.detail":          "Revisão %d não encontrada",
+	"REVISION_INVALID.title":             "Revisão inválida",
+	"REVISION_INVALID.detail":            "Número de revisão inválido: %s",
+	"REVISION_RANGE_REQUIRED.title":      "Revisões ausentes",
+	"REVISION_RANGE_REQUIRED.detail":     "Informe as revisões a comparar em from e to",
+	"REVERT_TO_DELETE.title":             "Reversão inválida",
+	"REVERT_TO_DELETE.detail":            "Não é possível reverter para uma revisão de remoção",
+	"REVISION_GENRE_MISSING.title":       "Gênero removido",
+	"REVISION_GENRE_MISSING.detail":      "Um dos gêneros da revisão não existe mais",
+	"GENRE_NOT_FOUND.title":              "Gênero não encontrado",
+	"GENRE_NOT_FOUND.detail":             "Gênero não encontrado",
+	"GENRE_NOT_IN_TRASH.title":           "Gênero fora da lixeira",
+	"GENRE_NOT_IN_TRASH.detail":          "Gênero não encontrado na lixeira",
+	"GENRE_ID_REQUIRED.title":            "ID do gênero ausente",
+	"GENRE_ID_REQUIRED.detail":           "ID do gênero é obrigatório",
+	"GENRE_REFERENCE_NOT_FOUND.title":    "Gênero inexistente",
+	"GENRE_REFERENCE_NOT_FOUND.detail":   "Um dos gêneros informados não existe",
+	"PARENT_GENRE_NOT_FOUND.title":       "Gênero pai inexistente",
+	"PARENT_GENRE_NOT_FOUND.detail":      "Gênero pai não encontrado",
+	"GENRE_NAME_REQUIRED.title":          "Nome ausente",
+	"GENRE_NAME_REQUIRED.detail":         "O nome do gênero é obrigatório",
+	"GENRE_NAME_CONFLICT.title":          "Nome de gênero em uso",
+	"GENRE_NAME_CONFLICT.detail":         "Já existe um gênero com este nome (verifique também a lixeira)",
+	"GENRE_CYCLE.title":                  "Hierarquia inválida",
+	"GENRE_CYCLE.detail":                 "O gênero pai não pode ser o próprio gênero nem um de seus subgêneros",
+	"GENRE_IN_USE.title":                 "Gênero com livros",
+	"GENRE_IN_USE.detail":                "O gênero possui %d livros. Informe reassign_to para transferi-los antes de remover",
+	"GENRE_REFERENCED.title":             "Gênero referenciado",
+	"GENRE_REFERENCED.detail":            "O gênero ainda é referenciado e não pode ser apagado",
+	"REASSIGN_SAME_GENRE.title":          "Transferência inválida",
+	"REASSIGN_SAME_GENRE.detail":         "reassign_to deve ser um gênero diferente do removido",
+	"REASSIGN_TARGET_NOT_FOUND.title":    "Gênero de destino inexistente",
+	"REASSIGN_TARGET_NOT_FOUND.detail":   "Gênero de destino (reassign_to) não encontrado",
+	"MERGE_TARGET_REQUIRED.title":        "Destino ausente",
+	"MERGE_TARGET_REQUIRED.detail":       "Informe target_id, o gênero que receberá os livros",
+	"MERGE_INTO_SELF.title":              "Mesclagem inválida",
+	"MERGE_INTO_SELF.detail":             "Não é possível mesclar um gênero nele mesmo",
+	"MERGE_INTO_DESCENDANT.title":        "Mesclagem inválida",
+	"MERGE_INTO_DESCENDANT.detail":       "Não é possível mesclar um gênero em um de seus subgêneros",
+	"AUTHOR_NOT_FOUND.title":             "Autor não encontrado",
+	"AUTHOR_NOT_FOUND.detail":            "Autor não encontrado",
+	"AUTHOR_REFERENCE_NOT_FOUND.title":   "Autor inexistente",
+	"AUTHOR_REFERENCE_NOT_FOUND.detail":  "Um dos autores informados não existe",
+	"AUTHOR_REFERENCE_REQUIRED.title":    "Autor incompleto",
+	"AUTHOR_REFERENCE_REQUIRED.detail":   "Cada autor precisa de 'id' ou 'name'",
+	"AUTHOR_NAME_REQUIRED.title":         "Nome ausente",
+	"AUTHOR_NAME_REQUIRED.detail":        "O nome do autor é obrigatório",
+	"AUTHOR_NAME_CONFLICT.title":         "Nome de autor em uso",
+	"AUTHOR_NAME_CONFLICT.detail":        "Já existe um autor com este nome",
+	"AUTHOR_IN_USE.title":                "Autor com livros",
+	"AUTHOR_IN_USE.detail":               "O autor está vinculado a livros e não pode ser removido",
+	"AUTHOR_ROLE_INVALID.title":          "Papel inválido",
+	"AUTHOR_ROLE_INVALID.detail":         "Papel de autor inválido: %s. Use um de: %s",
+	"SUPPLIER_NOT_FOUND.title":           "Fornecedor não encontrado",
+	"SUPPLIER_NOT_FOUND.detail":          "Fornecedor não encontrado",
+	"SUPPLIER_NAME_REQUIRED.title":       "Nome ausente",
+	"SUPPLIER_NAME_REQUIRED.detail":      "O nome do fornecedor é obrigatório",
+	"SUPPLIER_NAME_CONFLICT.title":       "Nome de fornecedor em uso",
+	"SUPPLIER_NAME_CONFLICT.detail":      "Já existe um fornecedor com este nome",
+	"SUPPLIER_IN_USE.title":              "Fornecedor com pedidos",
+	"SUPPLIER_IN_USE.detail":             "O fornecedor possui pedidos e não pode ser removido",
+	"ORDER_NOT_FOUND.title":              "Pedido não encontrado",
+	"ORDER_NOT_FOUND.detail":             "Pedido não encontrado",
+	"ORDER_REFERENCE_NOT_FOUND.title":    "Referência inexistente",
+	"ORDER_REFERENCE_NOT_FOUND.detail":   "Fornecedor ou livro não encontrado",
+	"ORDER_NOT_DRAFT.title":              "Pedido fora do rascunho",
+	"ORDER_NOT_DRAFT.detail":             "Apenas pedidos em rascunho podem ser alterados, removidos ou enviados",
+	"ORDER_EMPTY.title":                  "Pedido vazio",
+	"ORDER_EMPTY.detail":                 "O pedido não tem linhas",
+	"ORDER_NOT_RECEIVABLE.title":         "Pedido não enviado",
+	"ORDER_NOT_RECEIVABLE.detail":        "Apenas pedidos enviados ou parcialmente recebidos podem ser recebidos",
+	"ORDER_SUPPLIER_REQUIRED.title":      "Fornecedor ausente",
+	"ORDER_SUPPLIER_REQUIRED.detail":     "O fornecedor (supplier_id) é obrigatório",
+	"ORDER_LINE_BOOK_REQUIRED.title":     "Linha sem livro",
+	"ORDER_LINE_BOOK_REQUIRED.detail":    "Cada linha precisa do livro (book_id)",
+	"ORDER_LINE_QUANTITY_INVALID.title":  "Quantidade inválida",
+	"ORDER_LINE_QUANTITY_INVALID.detail": "A quantidade de cada linha deve ser maior que zero",
+	"ORDER_LINE_COST_NEGATIVE.title":     "Custo inválido",
+	"ORDER_LINE_COST_NEGATIVE.detail":    "O custo unitário não pode ser negativo",
+	"ORDER_LINE_DUPLICATE_BOOK.title":    "Livro repetido",
+	"ORDER_LINE_DUPLICATE_BOOK.detail":   "O livro %s aparece em mais de uma linha do pedido",
+	"ORDER_LINE_NOT_FOUND.title":         "Linha inexistente",
+	"ORDER_LINE_NOT_FOUND.detail":        "Linha não encontrada no pedido",
+	"RECEIPT_LINE_INVALID.title":         "Recebimento inválido",
+	"RECEIPT_LINE_INVALID.detail":        "Cada linha recebida precisa de line_id e quantidade maior que zero",
+	"RECEIPT_EXCEEDS_ORDERED.title":      "Recebimento acima do pedido",
+	"RECEIPT_EXCEEDS_ORDERED.detail":     "Quantidade recebida maior que o saldo pendente da linha",
+	"ALERT_NOT_FOUND.title":              "Alerta não encontrado",
+	"ALERT_NOT_FOUND.detail":             "Alerta não encontrado",
+	"DIGEST_NOT_CONFIGURED.title":        "Resumo indisponível",
+	"DIGEST_NOT_CONFIGURED.detail":       "Resumo de alertas não configurado",
+	"DIGEST_FAILED.title":                "Falha no envio",
+	"DIGEST_FAILED.detail":               "Erro ao enviar resumo de alertas",
+	"METADATA_NOT_CONFIGURED.title":      "Metadados indisponíveis",
+	"METADATA_NOT_CONFIGURED.detail":     "Serviço de metadados não configurado",
+	"METADATA_NOT_FOUND.title":           "Metadados não encontrados",
+	"METADATA_NOT_FOUND.detail":          "Metadados não encontrados para o ISBN",
+	"WEBHOOK_NOT_FOUND.title":            "Webhook não encontrado",
+	"WEBHOOK_NOT_FOUND.detail":           "Webhook não encontrado",
+	"WEBHOOK_URL_INVALID.title":          "URL inválida",
+	"WEBHOOK_URL_INVALID.detail":         "Informe uma URL http ou https válida",
+	"WEBHOOK_EVENT_UNKNOWN.title":        "Evento desconhecido",
+	"WEBHOOK_EVENT_UNKNOWN.detail":       "Tipo de evento desconhecido: %s. Use um de: %s",
+	"DELIVERY_NOT_FOUND.title":           "Entrega não encontrada",
+	"DELIVERY_NOT_FOUND.detail":          "Entrega não encontrada",
+	"STREAM_TOPIC_INVALID.title":         "Tópico inválido",
+	"STREAM_TOPIC_INVALID.detail":        "Tópico inválido: %s. Use books, genres, stock, book:<id>, genre:<id> ou type:<tipo>",
+
+	// Violações de campo e termos usados nas mensagens
+	"validation.required":              "campo obrigatório",
+	"validation.parameter_required":    "parâmetro obrigatório",
+	"validation.body_required":         "corpo da requisição obrigatório",
+	"validation.invalid_json":          "JSON inválido: %s",
+	"validation.not_null":              "não pode ser nulo",
+	"validation.type.string":           "deve ser do tipo texto",
+	"validation.type.integer":          "deve ser do tipo número inteiro",
+	"validation.type.number":           "deve ser do tipo número",
+	"validation.type.boolean":          "deve ser do tipo booleano",
+	"validation.type.array":            "deve ser do tipo lista",
+	"validation.type.object":           "deve ser do tipo objeto",
+	"validation.minimum":               "deve ser maior ou igual a %s",
+	"validation.maximum":               "deve ser menor ou igual a %s",
+	"validation.min_items":             "deve ter pelo menos %d itens",
+	"validation.any_of":                "informe %s",
+	"validation.enum":                  "deve ser um dos valores: %s",
+	"validation.min_length":            "deve ter pelo menos %d caracteres",
+	"validation.max_length":            "deve ter no máximo %d caracteres",
+	"validation.ksuid":                 "deve ser um identificador de 27 letras e dígitos",
+	"validation.pattern":               "formato inválido",
+	"validation.date_time":             "data inválida; use o formato RFC 3339",
+	"validation.status_undocumented":   "status %d não documentado",
+	"validation.response_undocumented": "resposta JSON não documentada",
+	"list.or":                          " ou ",
+}
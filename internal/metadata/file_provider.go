@@ -0,0 +1,310 @@
+package metadata
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"projeto_livros/internal/domain/validators"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Formatos de arquivo aceitos pelo FileProvider
+const (
+	// Dump de edições/autores da Open Library (https://openlibrary.org/developers/dumps),
+	// descompactado: linhas com tipo, chave, revisão, data e JSON separados por tabulação
+	FormatOpenLibrary = "openlibrary"
+	// CSV com cabeçalho contendo ao menos isbn e title; authors e subjects
+	// aceitam vários valores separados por ";". Um registro por linha.
+	FormatCSV = "csv"
+)
+
+var yearPattern = regexp.MustCompile(`\d{4}`)
+
+// FileProvider responde consultas a partir de um dump armazenado localmente.
+// Na criação o arquivo é lido uma vez para montar um índice ISBN → posição;
+// cada consulta lê apenas a linha correspondente, sem manter o dump em memória.
+type FileProvider struct {
+	path    string
+	format  string
+	columns map[string]int
+
+	mu      sync.Mutex
+	file    *os.File
+	isbns   map[string]int64
+	authors map[string]int64
+}
+
+// NewFileProvider abre e indexa o arquivo. O formato é detectado pela primeira linha.
+func NewFileProvider(path string) (*FileProvider, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir dump de metadados: %v", err)
+	}
+	p := &FileProvider{
+		path:    path,
+		file:    file,
+		isbns:   map[string]int64{},
+		authors: map[string]int64{},
+	}
+	if err := p.buildIndex(); err != nil {
+		file.Close()
+		return nil, err
+	}
+	log.Printf("Dump de metadados %s indexado (%s): %d ISBNs, %d autores",
+		path, p.format, len(p.isbns), len(p.authors))
+	return p, nil
+}
+
+// Close fecha o arquivo do dump
+func (p *FileProvider) Close() error {
+	return p.file.Close()
+}
+
+// Len devolve a quantidade de ISBNs indexados
+func (p *FileProvider) Len() int {
+	return len(p.isbns)
+}
+
+func (p *FileProvider) buildIndex() error {
+	reader := bufio.NewReaderSize(p.file, 1<<20)
+	var offset int64
+	first := true
+	for {
+		line, err := reader.ReadString('\n')
+		if len(line) > 0 {
+			lineOffset := offset
+			offset += int64(len(line))
+			line = strings.TrimRight(line, "\r\n")
+			if first {
+				first = false
+				if p.detectCSV(line) {
+					continue
+				}
+				p.format = FormatOpenLibrary
+			}
+			p.indexLine(line, lineOffset)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("erro ao indexar dump de metadados: %v", err)
+		}
+	}
+	if p.format == "" {
+		return fmt.Errorf("dump de metadados vazio: %s", p.path)
+	}
+	return nil
+}
+
+// detectCSV reconhece o cabeçalho do formato CSV e guarda a posição das colunas
+func (p *FileProvider) detectCSV(header string) bool {
+	if strings.Contains(header, "\t") {
+		return false
+	}
+	fields, err := csv.NewReader(strings.NewReader(header)).Read()
+	if err != nil {
+		return false
+	}
+	columns := map[string]int{}
+	for i, name := range fields {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	_, hasISBN := columns["isbn"]
+	_, hasTitle := columns["title"]
+	if !hasISBN || !hasTitle {
+		return false
+	}
+	p.format = FormatCSV
+	p.columns = columns
+	return true
+}
+
+func (p *FileProvider) indexLine(line string, offset int64) {
+	switch p.format {
+	case FormatCSV:
+		record, err := csv.NewReader(strings.NewReader(line)).Read()
+		if err != nil {
+			return
+		}
+		p.indexISBN(p.csvValue(record, "isbn"), offset)
+	case FormatOpenLibrary:
+		parts := strings.SplitN(line, "\t", 5)
+		if len(parts) != 5 {
+			return
+		}
+		switch parts[0] {
+		case "/type/author":
+			p.authors[parts[1]] = offset
+		case "/type/edition":
+			var edition struct {
+				ISBN10 []string `json:"isbn_10"`
+				ISBN13 []string `json:"isbn_13"`
+			}
+			if err := json.Unmarshal([]byte(parts[4]), &edition); err != nil {
+				return
+			}
+			for _, isbn := range append(edition.ISBN13, edition.ISBN10...) {
+				p.indexISBN(isbn, offset)
+			}
+		}
+	}
+}
+
+func (p *FileProvider) indexISBN(raw string, offset int64) {
+	isbn, err := validators.NormalizeISBN(raw)
+	if err != nil {
+		return
+	}
+	// Em caso de repetição, vale a primeira ocorrência do dump
+	if _, exists := p.isbns[isbn]; !exists {
+		p.isbns[isbn] = offset
+	}
+}
+
+func (p *FileProvider) csvValue(record []string, column string) string {
+	i, ok := p.columns[column]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+// readLine lê a linha que começa na posição informada
+func (p *FileProvider) readLine(offset int64) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	reader := bufio.NewReader(io.NewSectionReader(p.file, offset, 1<<30))
+	line, err := reader.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// LookupISBN implementa Provider
+func (p *FileProvider) LookupISBN(ctx context.Context, isbn string) (*Metadata, error) {
+	offset, ok := p.isbns[isbn]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	line, err := p.readLine(offset)
+	if err != nil {
+		return nil, err
+	}
+	if p.format == FormatCSV {
+		return p.parseCSV(line, isbn)
+	}
+	return p.parseOpenLibrary(line, isbn)
+}
+
+func (p *FileProvider) parseCSV(line, isbn string) (*Metadata, error) {
+	record, err := csv.NewReader(strings.NewReader(line)).Read()
+	if err != nil {
+		return nil, err
+	}
+	meta := &Metadata{
+		ISBN:      isbn,
+		Title:     p.csvValue(record, "title"),
+		Authors:   splitList(p.csvValue(record, "authors")),
+		Publisher: p.csvValue(record, "publisher"),
+		Subjects:  splitList(p.csvValue(record, "subjects")),
+		Source:    FormatCSV,
+	}
+	meta.PublicationYear = parseYear(p.csvValue(record, "year"))
+	return meta, nil
+}
+
+func (p *FileProvider) parseOpenLibrary(line, isbn string) (*Metadata, error) {
+	parts := strings.SplitN(line, "\t", 5)
+	if len(parts) != 5 {
+		return nil, fmt.Errorf("linha inválida no dump da Open Library")
+	}
+	var edition struct {
+		Title       string   `json:"title"`
+		Subtitle    string   `json:"subtitle"`
+		Publishers  []string `json:"publishers"`
+		PublishDate string   `json:"publish_date"`
+		Subjects    []string `json:"subjects"`
+		ByStatement string   `json:"by_statement"`
+		Authors     []struct {
+			Key string `json:"key"`
+		} `json:"authors"`
+	}
+	if err := json.Unmarshal([]byte(parts[4]), &edition); err != nil {
+		return nil, err
+	}
+
+	meta := &Metadata{
+		ISBN:            isbn,
+		Title:           edition.Title,
+		Subjects:        edition.Subjects,
+		PublicationYear: parseYear(edition.PublishDate),
+		Source:          FormatOpenLibrary,
+	}
+	if edition.Subtitle != "" {
+		meta.Title += ": " + edition.Subtitle
+	}
+	if len(edition.Publishers) > 0 {
+		meta.Publisher = edition.Publishers[0]
+	}
+	// Os nomes dos autores vêm dos registros /type/author do mesmo dump, quando presentes
+	for _, author := range edition.Authors {
+		if name := p.authorName(author.Key); name != "" {
+			meta.Authors = append(meta.Authors, name)
+		}
+	}
+	if len(meta.Authors) == 0 && edition.ByStatement != "" {
+		meta.Authors = []string{strings.TrimSuffix(strings.TrimSpace(edition.ByStatement), ".")}
+	}
+	return meta, nil
+}
+
+func (p *FileProvider) authorName(key string) string {
+	offset, ok := p.authors[key]
+	if !ok {
+		return ""
+	}
+	line, err := p.readLine(offset)
+	if err != nil {
+		return ""
+	}
+	parts := strings.SplitN(line, "\t", 5)
+	if len(parts) != 5 {
+		return ""
+	}
+	var author struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal([]byte(parts[4]), &author); err != nil {
+		return ""
+	}
+	return author.Name
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ";") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func parseYear(value string) *int {
+	y := yearPattern.FindString(value)
+	if y == "" {
+		return nil
+	}
+	year, _ := strconv.Atoi(y)
+	return &year
+}
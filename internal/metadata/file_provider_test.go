@@ -0,0 +1,63 @@
+package metadata
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeDump(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "dump.txt")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFileProviderOpenLibrary(t *testing.T) {
+	dump := "/type/author\t/authors/OL1A\t1\t2020-01-01T00:00:00\t{\"name\": \"Machado de Assis\"}\n" +
+		"/type/edition\t/books/OL1M\t3\t2020-01-01T00:00:00\t{\"title\": \"Dom Casmurro\", " +
+		"\"isbn_10\": [\"85-359-1066-2\"], \"publishers\": [\"Companhia das Letras\"], " +
+		"\"publish_date\": \"março de 2008\", \"subjects\": [\"Ficção brasileira\"], " +
+		"\"authors\": [{\"key\": \"/authors/OL1A\"}]}\n"
+	p, err := NewFileProvider(writeDump(t, dump))
+	if err != nil {
+		t.Fatalf("erro ao indexar dump: %v", err)
+	}
+	defer p.Close()
+
+	meta, err := p.LookupISBN(context.Background(), "9788535910667")
+	if err != nil {
+		t.Fatalf("erro na consulta: %v", err)
+	}
+	if meta.Title != "Dom Casmurro" || len(meta.Authors) != 1 || meta.Authors[0] != "Machado de Assis" {
+		t.Errorf("metadados incorretos: %+v", meta)
+	}
+	if meta.PublicationYear == nil || *meta.PublicationYear != 2008 {
+		t.Errorf("ano incorreto: %v", meta.PublicationYear)
+	}
+
+	if _, err := p.LookupISBN(context.Background(), "9780306406157"); err != ErrNotFound {
+		t.Errorf("esperava ErrNotFound, obteve %v", err)
+	}
+}
+
+func TestFileProviderCSV(t *testing.T) {
+	dump := "isbn,title,authors,publisher,year,subjects\n" +
+		"978-0-306-40615-7,\"Física, volume 1\",Autor A; Autor B,Editora X,1999,Física;Ensino\n"
+	p, err := NewFileProvider(writeDump(t, dump))
+	if err != nil {
+		t.Fatalf("erro ao indexar dump: %v", err)
+	}
+	defer p.Close()
+
+	meta, err := p.LookupISBN(context.Background(), "9780306406157")
+	if err != nil {
+		t.Fatalf("erro na consulta: %v", err)
+	}
+	if meta.Title != "Física, volume 1" || len(meta.Authors) != 2 || len(meta.Subjects) != 2 {
+		t.Errorf("metadados incorretos: %+v", meta)
+	}
+}
@@ -0,0 +1,53 @@
+// Package metadata fornece dados bibliográficos (título, autores, editora,
+// assuntos) a partir do ISBN, para preencher o cadastro de livros.
+package metadata
+
+import (
+	"context"
+	"errors"
+	"projeto_livros/internal/domain/models"
+	"strings"
+)
+
+// ErrNotFound indica que o provedor não conhece o ISBN consultado
+var ErrNotFound = errors.New("metadados não encontrados para o ISBN")
+
+// Metadata são os dados bibliográficos de uma edição
+type Metadata struct {
+	ISBN            string   `json:"isbn"`
+	Title           string   `json:"title"`
+	Authors         []string `json:"authors"`
+	Publisher       string   `json:"publisher,omitempty"`
+	PublicationYear *int     `json:"publication_year,omitempty"`
+	Subjects        []string `json:"subjects,omitempty"`
+	Source          string   `json:"source"`
+}
+
+// Provider consulta metadados por ISBN-13 normalizado. A implementação
+// atual lê um arquivo local (FileProvider), para funcionar sem acesso à
+// internet; um provedor HTTP pode implementar a mesma interface.
+type Provider interface {
+	LookupISBN(ctx context.Context, isbn string) (*Metadata, error)
+}
+
+// Enrich preenche os campos vazios do livro com os metadados, sem
+// sobrescrever o que já foi informado pelo usuário.
+func Enrich(book *models.Book, meta *Metadata) {
+	if book.Name == "" && book.Title == "" {
+		book.Name = meta.Title
+		book.Title = meta.Title
+	}
+	if book.Author == "" && len(meta.Authors) > 0 {
+		book.Author = strings.Join(meta.Authors, ", ")
+	}
+	if book.Publisher == "" {
+		book.Publisher = meta.Publisher
+	}
+	if book.PublicationYear == nil && meta.PublicationYear != nil {
+		year := *meta.PublicationYear
+		book.PublicationYear = &year
+	}
+	if len(book.Subjects) == 0 {
+		book.Subjects = meta.Subjects
+	}
+}
@@ -0,0 +1,77 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"projeto_livros/internal/domain/models"
+	repositories "projeto_livros/internal/repository"
+	"time"
+)
+
+// digestLimit é o número máximo de alertas listados em um resumo
+const digestLimit = 200
+
+// DigestJob envia, uma vez por dia, o resumo dos alertas de estoque baixo em aberto
+type DigestJob struct {
+	alerts   repositories.AlertRepository
+	notifier Notifier
+	at       time.Duration // Horário do envio, contado a partir da meia-noite
+}
+
+// NewDigestJob cria o job de resumo diário. at é o horário do envio no formato HH:MM.
+func NewDigestJob(alerts repositories.AlertRepository, notifier Notifier, at string) (*DigestJob, error) {
+	t, err := time.Parse("15:04", at)
+	if err != nil {
+		return nil, fmt.Errorf("horário do resumo inválido (use HH:MM): %s", at)
+	}
+	return &DigestJob{
+		alerts:   alerts,
+		notifier: notifier,
+		at:       time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute,
+	}, nil
+}
+
+// NextRun devolve o próximo horário de envio depois de now
+func (j *DigestJob) NextRun(now time.Time) time.Time {
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	next := midnight.Add(j.at)
+	if !next.After(now) {
+		next = midnight.AddDate(0, 0, 1).Add(j.at)
+	}
+	return next
+}
+
+// Run envia o resumo todos os dias no horário configurado, até ctx ser cancelado
+func (j *DigestJob) Run(ctx context.Context) {
+	for {
+		timer := time.NewTimer(time.Until(j.NextRun(time.Now())))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			if _, err := j.Send(ctx); err != nil {
+				log.Printf("Erro ao enviar resumo de estoque baixo: %v", err)
+			}
+		}
+	}
+}
+
+// Send envia imediatamente o resumo dos alertas em aberto e devolve quantos
+// alertas havia. Sem alertas em aberto, nada é enviado.
+func (j *DigestJob) Send(ctx context.Context) (int, error) {
+	alerts, total, err := j.alerts.FindLowStock(models.AlertStatusOpen, digestLimit, 0)
+	if err != nil {
+		return 0, err
+	}
+	if total == 0 {
+		log.Printf("Resumo de estoque baixo: nenhum alerta em aberto")
+		return 0, nil
+	}
+	digest := Digest{GeneratedAt: time.Now(), Total: total, Alerts: alerts}
+	if err := j.notifier.Notify(ctx, digest); err != nil {
+		return total, err
+	}
+	return total, nil
+}
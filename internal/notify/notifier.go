@@ -0,0 +1,131 @@
+// Package notify envia o resumo diário dos alertas de estoque baixo por um
+// canal configurável (log, webhook ou e-mail).
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/smtp"
+	"projeto_livros/internal/domain/models"
+	"strings"
+	"time"
+)
+
+// Digest é o resumo dos alertas de estoque baixo em aberto
+type Digest struct {
+	GeneratedAt time.Time              `json:"generated_at"`
+	Total       int                    `json:"total"` // Total de alertas em aberto; Alerts pode trazer menos
+	Alerts      []models.LowStockAlert `json:"alerts"`
+}
+
+// Notifier entrega o resumo de alertas
+type Notifier interface {
+	Notify(ctx context.Context, digest Digest) error
+}
+
+// Options reúne as configurações dos notificadores
+type Options struct {
+	WebhookURL   string
+	SMTPAddr     string // host:porta
+	SMTPUser     string
+	SMTPPassword string
+	SMTPFrom     string
+	SMTPTo       []string
+}
+
+// New cria o notificador do tipo informado: "log" (padrão), "webhook" ou "smtp"
+func New(kind string, opts Options) (Notifier, error) {
+	switch strings.ToLower(kind) {
+	case "", "log":
+		return LogNotifier{}, nil
+	case "webhook":
+		if opts.WebhookURL == "" {
+			return nil, fmt.Errorf("notificador webhook requer a URL do webhook")
+		}
+		return &WebhookNotifier{URL: opts.WebhookURL, Client: &http.Client{Timeout: 10 * time.Second}}, nil
+	case "smtp":
+		if opts.SMTPAddr == "" || opts.SMTPFrom == "" || len(opts.SMTPTo) == 0 {
+			return nil, fmt.Errorf("notificador smtp requer servidor, remetente e destinatários")
+		}
+		notifier := &SMTPNotifier{Addr: opts.SMTPAddr, From: opts.SMTPFrom, To: opts.SMTPTo}
+		if opts.SMTPUser != "" {
+			host := strings.Split(opts.SMTPAddr, ":")[0]
+			notifier.Auth = smtp.PlainAuth("", opts.SMTPUser, opts.SMTPPassword, host)
+		}
+		return notifier, nil
+	}
+	return nil, fmt.Errorf("notificador desconhecido: %s", kind)
+}
+
+// FormatDigest gera o texto do resumo, usado no log e no e-mail
+func FormatDigest(digest Digest) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Resumo de estoque baixo (%s): %d alerta(s) em aberto\n",
+		digest.GeneratedAt.Format("02/01/2006 15:04"), digest.Total)
+	for _, a := range digest.Alerts {
+		fmt.Fprintf(&b, "- %s: %d em estoque (mínimo %d), alerta desde %s\n",
+			a.BookName, a.CurrentQuantity, a.MinQuantity, a.CreatedAt.Format("02/01/2006"))
+	}
+	if omitted := digest.Total - len(digest.Alerts); omitted > 0 {
+		fmt.Fprintf(&b, "... e mais %d alerta(s)\n", omitted)
+	}
+	return b.String()
+}
+
+// LogNotifier escreve o resumo no log da aplicação
+type LogNotifier struct{}
+
+func (LogNotifier) Notify(ctx context.Context, digest Digest) error {
+	log.Print(FormatDigest(digest))
+	return nil
+}
+
+// WebhookNotifier envia o resumo em JSON por POST para uma URL
+type WebhookNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, digest Digest) error {
+	body, err := json.Marshal(digest)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook respondeu com status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SMTPNotifier envia o resumo por e-mail
+type SMTPNotifier struct {
+	Addr string
+	Auth smtp.Auth // nil quando o servidor não exige autenticação
+	From string
+	To   []string
+}
+
+func (n *SMTPNotifier) Notify(ctx context.Context, digest Digest) error {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", n.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(n.To, ", "))
+	fmt.Fprintf(&msg, "Subject: Estoque baixo: %d alerta(s) em aberto\r\n", digest.Total)
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(FormatDigest(digest), "\n", "\r\n"))
+	return smtp.SendMail(n.Addr, n.Auth, n.From, n.To, []byte(msg.String()))
+}
@@ -0,0 +1,129 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"projeto_livros/internal/domain/models"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeAlertRepository devolve sempre os mesmos alertas em aberto
+type fakeAlertRepository struct {
+	alerts []models.LowStockAlert
+	status string
+}
+
+func (f *fakeAlertRepository) FindLowStock(status string, limit, offset int) ([]models.LowStockAlert, int, error) {
+	f.status = status
+	return f.alerts, len(f.alerts), nil
+}
+
+func (f *fakeAlertRepository) Acknowledge(id, userID string) (int64, error) {
+	return 0, nil
+}
+
+// recordingNotifier guarda os resumos recebidos
+type recordingNotifier struct {
+	digests []Digest
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, digest Digest) error {
+	n.digests = append(n.digests, digest)
+	return nil
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var received Digest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Content-Type = %q", r.Header.Get("Content-Type"))
+		}
+		json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	notifier, err := New("webhook", Options{WebhookURL: server.URL})
+	if err != nil {
+		t.Fatalf("erro ao criar notificador: %v", err)
+	}
+	digest := Digest{Total: 1, Alerts: []models.LowStockAlert{{BookName: "Dom Casmurro", MinQuantity: 3}}}
+	if err := notifier.Notify(context.Background(), digest); err != nil {
+		t.Fatalf("erro ao notificar: %v", err)
+	}
+	if received.Total != 1 || len(received.Alerts) != 1 || received.Alerts[0].BookName != "Dom Casmurro" {
+		t.Errorf("resumo recebido incorreto: %+v", received)
+	}
+}
+
+func TestWebhookNotifierErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	notifier, _ := New("webhook", Options{WebhookURL: server.URL})
+	if err := notifier.Notify(context.Background(), Digest{}); err == nil {
+		t.Error("esperava erro para resposta 500 do webhook")
+	}
+}
+
+func TestNewNotifierValidation(t *testing.T) {
+	if _, err := New("webhook", Options{}); err == nil {
+		t.Error("esperava erro para webhook sem URL")
+	}
+	if _, err := New("smtp", Options{SMTPAddr: "localhost:25"}); err == nil {
+		t.Error("esperava erro para smtp sem remetente e destinatários")
+	}
+	if _, err := New("pombo-correio", Options{}); err == nil {
+		t.Error("esperava erro para notificador desconhecido")
+	}
+}
+
+func TestDigestJobNextRun(t *testing.T) {
+	job, err := NewDigestJob(&fakeAlertRepository{}, LogNotifier{}, "08:30")
+	if err != nil {
+		t.Fatalf("erro ao criar job: %v", err)
+	}
+	before := time.Date(2024, 5, 10, 7, 0, 0, 0, time.UTC)
+	if got := job.NextRun(before); !got.Equal(time.Date(2024, 5, 10, 8, 30, 0, 0, time.UTC)) {
+		t.Errorf("NextRun antes do horário = %v", got)
+	}
+	after := time.Date(2024, 5, 10, 8, 30, 0, 0, time.UTC)
+	if got := job.NextRun(after); !got.Equal(time.Date(2024, 5, 11, 8, 30, 0, 0, time.UTC)) {
+		t.Errorf("NextRun no horário = %v", got)
+	}
+
+	if _, err := NewDigestJob(&fakeAlertRepository{}, LogNotifier{}, "8h"); err == nil {
+		t.Error("esperava erro para horário inválido")
+	}
+}
+
+func TestDigestJobSend(t *testing.T) {
+	repo := &fakeAlertRepository{alerts: []models.LowStockAlert{
+		{BookName: "Iracema", CurrentQuantity: 1, MinQuantity: 2, CreatedAt: time.Now()},
+	}}
+	notifier := &recordingNotifier{}
+	job, _ := NewDigestJob(repo, notifier, "08:00")
+
+	total, err := job.Send(context.Background())
+	if err != nil || total != 1 {
+		t.Fatalf("Send = (%d, %v)", total, err)
+	}
+	if repo.status != models.AlertStatusOpen {
+		t.Errorf("o resumo deve listar os alertas em aberto, buscou %q", repo.status)
+	}
+	if len(notifier.digests) != 1 || !strings.Contains(FormatDigest(notifier.digests[0]), "Iracema") {
+		t.Errorf("resumo não enviado corretamente: %+v", notifier.digests)
+	}
+
+	// Sem alertas em aberto nada é enviado
+	repo.alerts = nil
+	if _, err := job.Send(context.Background()); err != nil || len(notifier.digests) != 1 {
+		t.Errorf("não deveria enviar resumo vazio: %v, %d envios", err, len(notifier.digests))
+	}
+}
@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"projeto_livros/internal/domain/models"
+)
+
+// alertStatusFilters traduz a situação pedida na listagem para a cláusula WHERE
+var alertStatusFilters = map[string]string{
+	models.AlertStatusOpen:         "a.resolved_at IS NULL AND a.acknowledged_at IS NULL",
+	models.AlertStatusAcknowledged: "a.resolved_at IS NULL AND a.acknowledged_at IS NOT NULL",
+	models.AlertStatusResolved:     "a.resolved_at IS NOT NULL",
+	models.AlertStatusAll:          "TRUE",
+}
+
+type AlertRepository interface {
+	FindLowStock(status string, limit, offset int) ([]models.LowStockAlert, int, error)
+	Acknowledge(id, userID string) (int64, error)
+}
+
+type PostgresAlertRepository struct {
+	db DBTX
+}
+
+func NewPostgresAlertRepository(db *sql.DB) AlertRepository {
+	return &PostgresAlertRepository{db: db}
+}
+
+// FindLowStock lista os alertas de estoque baixo na situação informada, dos
+// mais recentes para os mais antigos. Alertas de livros na lixeira são omitidos.
+func (r *PostgresAlertRepository) FindLowStock(status string, limit, offset int) ([]models.LowStockAlert, int, error) {
+	where, ok := alertStatusFilters[status]
+	if !ok {
+		return nil, 0, fmt.Errorf("situação de alerta desconhecida: %s", status)
+	}
+
+	var total int
+	if err := r.db.QueryRow(`
+		SELECT COUNT(*) FROM low_stock_alerts a
+		JOIN livros l ON l.id = a.book_id
+		WHERE l.deleted_at IS NULL AND ` + where).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Query(`
+		SELECT a.id, a.book_id, l.name, a.quantity, a.min_quantity, l.quantity,
+			a.created_at, a.acknowledged_at, COALESCE(a.acknowledged_by, ''), a.resolved_at
+		FROM low_stock_alerts a
+		JOIN livros l ON l.id = a.book_id
+		WHERE l.deleted_at IS NULL AND `+where+`
+		ORDER BY a.created_at DESC, a.id
+		LIMIT $1 OFFSET $2`, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	alerts := []models.LowStockAlert{}
+	for rows.Next() {
+		var a models.LowStockAlert
+		if err := rows.Scan(&a.ID, &a.BookID, &a.BookName, &a.Quantity, &a.MinQuantity, &a.CurrentQuantity,
+			&a.CreatedAt, &a.AcknowledgedAt, &a.AcknowledgedBy, &a.ResolvedAt); err != nil {
+			return nil, 0, err
+		}
+		alerts = append(alerts, a)
+	}
+	return alerts, total, rows.Err()
+}
+
+// Acknowledge marca o alerta como reconhecido por userID. Alertas já
+// reconhecidos mantêm a data e o usuário do primeiro reconhecimento.
+func (r *PostgresAlertRepository) Acknowledge(id, userID string) (int64, error) {
+	result, err := r.db.Exec(`
+		UPDATE low_stock_alerts
+		SET acknowledged_at = COALESCE(acknowledged_at, CURRENT_TIMESTAMP),
+			acknowledged_by = COALESCE(acknowledged_by, NULLIF($2, ''))
+		WHERE id = $1`, id, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,164 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"projeto_livros/internal/audit"
+	"projeto_livros/internal/domain/models"
+	"strings"
+)
+
+// auditLockKey identifica o advisory lock que serializa as gravações no log de
+// auditoria, para que a numeração e a cadeia de hashes não tenham lacunas
+const auditLockKey = 7283001
+
+const auditColumns = `seq, created_at, COALESCE(actor, ''), method, route, path, COALESCE(entity, ''),
+	COALESCE(entity_id, ''), before_state, after_state, client_ip, COALESCE(request_id, ''),
+	status_code, outcome, prev_hash, hash`
+
+type AuditRepository interface {
+	Append(entry *models.AuditEntry) error
+	FindAll(filter models.AuditFilter, limit, offset int) ([]models.AuditEntry, int, error)
+	Each(fn func(entry *models.AuditEntry) error) error
+}
+
+type PostgresAuditRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresAuditRepository(db *sql.DB) AuditRepository {
+	return &PostgresAuditRepository{db: db}
+}
+
+// Append encadeia a entrada à última gravada e a insere no log
+func (r *PostgresAuditRepository) Append(entry *models.AuditEntry) error {
+	return RunInTx(r.db, func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`SELECT pg_advisory_xact_lock($1)`, auditLockKey); err != nil {
+			return err
+		}
+		var lastSeq int64
+		var lastHash string
+		err := tx.QueryRow(`SELECT seq, hash FROM audit_log ORDER BY seq DESC LIMIT 1`).Scan(&lastSeq, &lastHash)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		if err := audit.Chain(entry, lastSeq, lastHash); err != nil {
+			return err
+		}
+		_, err = tx.Exec(`
+			INSERT INTO audit_log (seq, created_at, actor, method, route, path, entity, entity_id,
+				before_state, after_state, client_ip, request_id, status_code, outcome, prev_hash, hash)
+			VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, NULLIF($7, ''), NULLIF($8, ''),
+				$9, $10, $11, NULLIF($12, ''), $13, $14, $15, $16)`,
+			entry.Seq, entry.CreatedAt, entry.Actor, entry.Method, entry.Route, entry.Path, entry.Entity,
+			entry.EntityID, nullableJSON(entry.Before), nullableJSON(entry.After), entry.ClientIP,
+			entry.RequestID, entry.StatusCode, entry.Outcome, entry.PrevHash, entry.Hash)
+		return err
+	})
+}
+
+// nullableJSON grava NULL para um JSON ausente
+func nullableJSON(data []byte) interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+	return string(data)
+}
+
+func scanAuditEntry(s Scanner) (*models.AuditEntry, error) {
+	var e models.AuditEntry
+	var before, after sql.NullString
+	if err := s.Scan(&e.Seq, &e.CreatedAt, &e.Actor, &e.Method, &e.Route, &e.Path, &e.Entity, &e.EntityID,
+		&before, &after, &e.ClientIP, &e.RequestID, &e.StatusCode, &e.Outcome, &e.PrevHash, &e.Hash); err != nil {
+		return nil, err
+	}
+	if before.Valid {
+		e.Before = []byte(before.String)
+	}
+	if after.Valid {
+		e.After = []byte(after.String)
+	}
+	audit.Normalize(&e)
+	return &e, nil
+}
+
+// FindAll lista as entradas que atendem ao filtro, das mais recentes para as mais antigas
+func (r *PostgresAuditRepository) FindAll(filter models.AuditFilter, limit, offset int) ([]models.AuditEntry, int, error) {
+	var conditions []string
+	var args []interface{}
+	add := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.Actor != "" {
+		add("actor = $%d", filter.Actor)
+	}
+	if filter.Entity != "" {
+		add("entity = $%d", filter.Entity)
+	}
+	if filter.EntityID != "" {
+		add("entity_id = $%d", filter.EntityID)
+	}
+	if filter.Method != "" {
+		add("method = $%d", strings.ToUpper(filter.Method))
+	}
+	if filter.Outcome != "" {
+		add("outcome = $%d", filter.Outcome)
+	}
+	if filter.RequestID != "" {
+		add("request_id = $%d", filter.RequestID)
+	}
+	if filter.From != nil {
+		add("created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		add("created_at < $%d", *filter.To)
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM audit_log `+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, limit, offset)
+	rows, err := r.db.Query(fmt.Sprintf(`
+		SELECT %s FROM audit_log %s
+		ORDER BY seq DESC
+		LIMIT $%d OFFSET $%d`, auditColumns, where, len(args)-1, len(args)), args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	entries := []models.AuditEntry{}
+	for rows.Next() {
+		entry, err := scanAuditEntry(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		entries = append(entries, *entry)
+	}
+	return entries, total, rows.Err()
+}
+
+// Each percorre todo o log em ordem de seq, para a verificação da cadeia
+func (r *PostgresAuditRepository) Each(fn func(entry *models.AuditEntry) error) error {
+	rows, err := r.db.Query(`SELECT ` + auditColumns + ` FROM audit_log ORDER BY seq`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		entry, err := scanAuditEntry(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,249 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"projeto_livros/internal/domain/models"
+	"projeto_livros/internal/domain/validators"
+
+	"github.com/lib/pq"
+	"github.com/segmentio/ksuid"
+)
+
+// ErrAuthorNotFound indica que um autor referenciado por id não existe
+var ErrAuthorNotFound = errors.New("autor não encontrado")
+
+// authorDisplayQuery calcula o campo livros.author: os nomes dos autores com
+// papel "author", na ordem de cadastro. $1 é o id do livro.
+const authorDisplayQuery = `
+	SELECT string_agg(a.name, ', ' ORDER BY ba.position)
+	FROM book_authors ba
+	JOIN authors a ON a.id = ba.author_id
+	WHERE ba.book_id = livros.id AND ba.role = 'author'`
+
+type AuthorRepository interface {
+	WithTx(tx *sql.Tx) AuthorRepository
+	Create(author *models.Author) error
+	FindAll(search string, limit, offset int) ([]models.Author, int, error)
+	FindByID(id string) (*models.Author, error)
+	Update(author *models.Author) (int64, error)
+	Delete(id string) (int64, error)
+	FindBooks(authorID string) ([]models.AuthorBook, error)
+	FindBookAuthors(bookID string) ([]models.BookAuthor, error)
+	SetBookAuthors(bookID string, authors []models.BookAuthor) ([]models.BookAuthor, string, error)
+	FindByBooks(bookIDs []string) (map[string][]models.BookAuthor, error)
+}
+
+type PostgresAuthorRepository struct {
+	db DBTX
+}
+
+func NewPostgresAuthorRepository(db *sql.DB) AuthorRepository {
+	return &PostgresAuthorRepository{db: db}
+}
+
+// WithTx devolve uma cópia do repositório que executa as consultas na transação
+func (r *PostgresAuthorRepository) WithTx(tx *sql.Tx) AuthorRepository {
+	return &PostgresAuthorRepository{db: tx}
+}
+
+// Create grava um autor novo. Um nome já cadastrado (após normalização)
+// viola a restrição UNIQUE de normalized_name.
+func (r *PostgresAuthorRepository) Create(author *models.Author) error {
+	name, key := validators.NormalizeAuthorName(author.Name)
+	author.Name = name
+	author.ID = ksuid.New().String()
+	_, err := r.db.Exec(`INSERT INTO authors (id, name, normalized_name) VALUES ($1, $2, $3)`,
+		author.ID, name, key)
+	return err
+}
+
+func (r *PostgresAuthorRepository) FindAll(search string, limit, offset int) ([]models.Author, int, error) {
+	_, key := validators.NormalizeAuthorName(search)
+	pattern := "%" + key + "%"
+
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM authors WHERE normalized_name LIKE $1`, pattern).
+		Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Query(`
+		SELECT a.id, a.name, (SELECT COUNT(DISTINCT ba.book_id) FROM book_authors ba WHERE ba.author_id = a.id)
+		FROM authors a
+		WHERE a.normalized_name LIKE $1
+		ORDER BY a.name
+		LIMIT $2 OFFSET $3`, pattern, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	authors := []models.Author{}
+	for rows.Next() {
+		var a models.Author
+		if err := rows.Scan(&a.ID, &a.Name, &a.TotalBooks); err != nil {
+			return nil, 0, err
+		}
+		authors = append(authors, a)
+	}
+	return authors, total, rows.Err()
+}
+
+func (r *PostgresAuthorRepository) FindByID(id string) (*models.Author, error) {
+	var a models.Author
+	err := r.db.QueryRow(`
+		SELECT a.id, a.name, (SELECT COUNT(DISTINCT ba.book_id) FROM book_authors ba WHERE ba.author_id = a.id)
+		FROM authors a WHERE a.id = $1`, id).Scan(&a.ID, &a.Name, &a.TotalBooks)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// Update renomeia o autor e recalcula o campo author dos seus livros
+func (r *PostgresAuthorRepository) Update(author *models.Author) (int64, error) {
+	name, key := validators.NormalizeAuthorName(author.Name)
+	author.Name = name
+	result, err := r.db.Exec(`
+		UPDATE authors SET name = $1, normalized_name = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $3`, name, key, author.ID)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil || affected == 0 {
+		return affected, err
+	}
+	_, err = r.db.Exec(`
+		UPDATE livros SET author = (`+authorDisplayQuery+`)
+		WHERE id IN (SELECT book_id FROM book_authors WHERE author_id = $1)`, author.ID)
+	return affected, err
+}
+
+// Delete remove um autor sem livros. A restrição de chave estrangeira de
+// book_authors impede a remoção de autores ainda vinculados.
+func (r *PostgresAuthorRepository) Delete(id string) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM authors WHERE id = $1`, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// FindBooks lista os livros do autor com o papel dele em cada um
+func (r *PostgresAuthorRepository) FindBooks(authorID string) ([]models.AuthorBook, error) {
+	rows, err := r.db.Query(`
+		SELECT `+BookColumns+`, ba.role
+		FROM book_authors ba
+		JOIN livros l ON l.id = ba.book_id
+		WHERE ba.author_id = $1 AND l.deleted_at IS NULL
+		ORDER BY l.name, ba.role`, authorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	books := []models.AuthorBook{}
+	for rows.Next() {
+		var role string
+		book, err := ScanBook(rows, &role)
+		if err != nil {
+			return nil, err
+		}
+		books = append(books, models.AuthorBook{Book: *book, Role: role})
+	}
+	return books, rows.Err()
+}
+
+// FindBookAuthors lista os autores de um livro na ordem de cadastro
+func (r *PostgresAuthorRepository) FindBookAuthors(bookID string) ([]models.BookAuthor, error) {
+	rows, err := r.db.Query(`
+		SELECT a.id, a.name, ba.role
+		FROM book_authors ba
+		JOIN authors a ON a.id = ba.author_id
+		WHERE ba.book_id = $1
+		ORDER BY ba.position`, bookID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	authors := []models.BookAuthor{}
+	for rows.Next() {
+		var a models.BookAuthor
+		if err := rows.Scan(&a.ID, &a.Name, &a.Role); err != nil {
+			return nil, err
+		}
+		authors = append(authors, a)
+	}
+	return authors, rows.Err()
+}
+
+// FindByBooks lista os autores de cada um dos livros, na ordem de cadastro
+func (r *PostgresAuthorRepository) FindByBooks(bookIDs []string) (map[string][]models.BookAuthor, error) {
+	rows, err := r.db.Query(`
+		SELECT ba.book_id, a.id, a.name, ba.role
+		FROM book_authors ba
+		JOIN authors a ON a.id = ba.author_id
+		WHERE ba.book_id = ANY($1)
+		ORDER BY ba.book_id, ba.position`, pq.Array(bookIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	authors := map[string][]models.BookAuthor{}
+	for rows.Next() {
+		var bookID string
+		var a models.BookAuthor
+		if err := rows.Scan(&bookID, &a.ID, &a.Name, &a.Role); err != nil {
+			return nil, err
+		}
+		authors[bookID] = append(authors[bookID], a)
+	}
+	return authors, rows.Err()
+}
+
+// SetBookAuthors substitui os autores do livro. Autores informados por nome
+// são reaproveitados se já existirem (pelo nome normalizado) ou criados.
+// Devolve os autores resolvidos e o novo valor do campo author do livro.
+func (r *PostgresAuthorRepository) SetBookAuthors(bookID string, authors []models.BookAuthor) ([]models.BookAuthor, string, error) {
+	resolved := make([]models.BookAuthor, 0, len(authors))
+	for _, a := range authors {
+		if a.ID != "" {
+			if err := r.db.QueryRow(`SELECT name FROM authors WHERE id = $1`, a.ID).Scan(&a.Name); err != nil {
+				if err == sql.ErrNoRows {
+					return nil, "", ErrAuthorNotFound
+				}
+				return nil, "", err
+			}
+		} else {
+			name, key := validators.NormalizeAuthorName(a.Name)
+			// O DO UPDATE sem efeito faz o RETURNING devolver também o autor já existente
+			err := r.db.QueryRow(`
+				INSERT INTO authors (id, name, normalized_name) VALUES ($1, $2, $3)
+				ON CONFLICT (normalized_name) DO UPDATE SET normalized_name = EXCLUDED.normalized_name
+				RETURNING id, name`, ksuid.New().String(), name, key).Scan(&a.ID, &a.Name)
+			if err != nil {
+				return nil, "", err
+			}
+		}
+		resolved = append(resolved, a)
+	}
+
+	if _, err := r.db.Exec(`DELETE FROM book_authors WHERE book_id = $1`, bookID); err != nil {
+		return nil, "", err
+	}
+	for position, a := range resolved {
+		if _, err := r.db.Exec(`
+			INSERT INTO book_authors (book_id, author_id, role, position) VALUES ($1, $2, $3, $4)
+			ON CONFLICT DO NOTHING`, bookID, a.ID, a.Role, position); err != nil {
+			return nil, "", err
+		}
+	}
+
+	var display sql.NullString
+	err := r.db.QueryRow(`UPDATE livros SET author = (`+authorDisplayQuery+`) WHERE id = $1 RETURNING author`, bookID).
+		Scan(&display)
+	if err != nil {
+		return nil, "", err
+	}
+	return resolved, display.String, nil
+}
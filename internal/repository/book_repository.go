@@ -2,30 +2,99 @@ package repositories
 
 import (
 	"database/sql"
+	"fmt"
 	"projeto_livros/internal/domain/models"
+	"projeto_livros/internal/domain/validators"
+	"strings"
+
+	"github.com/lib/pq"
 )
 
+// BookColumns é a lista de colunas de livros lida por ScanBook, na mesma ordem.
+// As consultas devem usar o alias "l" para a tabela livros.
+const BookColumns = `l.id, l.name, l.quantity, l.genre_id, COALESCE(l.author, ''),
+	COALESCE(l.isbn, ''), COALESCE(l.publisher, ''), l.publication_year,
+	COALESCE(l.edition, ''), COALESCE(l.subjects, '{}'), l.min_quantity`
+
+// Scanner é satisfeito por *sql.Row e *sql.Rows
+type Scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// ScanBook lê um livro selecionado com BookColumns. Colunas adicionais
+// selecionadas depois de BookColumns são lidas em extra, na ordem.
+func ScanBook(s Scanner, extra ...interface{}) (*models.Book, error) {
+	var book models.Book
+	var year sql.NullInt64
+	var subjects pq.StringArray
+	var minQuantity sql.NullInt64
+	dest := []interface{}{
+		&book.ID,
+		&book.Name,
+		&book.Quantity,
+		&book.GenreID,
+		&book.Author,
+		&book.ISBN,
+		&book.Publisher,
+		&year,
+		&book.Edition,
+		&subjects,
+		&minQuantity,
+	}
+	if err := s.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	if year.Valid {
+		y := int(year.Int64)
+		book.PublicationYear = &y
+	}
+	if minQuantity.Valid {
+		m := int(minQuantity.Int64)
+		book.MinQuantity = &m
+	}
+	book.Subjects = []string(subjects)
+	validators.SetISBNForms(&book, book.ISBN)
+	// Set title equal to name for frontend compatibility
+	book.Title = book.Name
+	return &book, nil
+}
+
 type BookRepository interface {
+	WithTx(tx *sql.Tx) BookRepository
 	Create(book *models.Book) error
 	FindAll(limit, offset int) ([]models.Book, error)
 	FindByID(id string) (*models.Book, error)
+	FindByISBN(isbn string) (*models.Book, error)
 	Update(book *models.Book) (int64, error)
+	LockQuantity(id string) (int, error)
 	Delete(id string) (int64, error)
+	Restore(id string) (int64, error)
+	HardDelete(id string) (int64, error)
 	Count() (int, error)
+	Search(filter models.BookFilter, limit, offset int) ([]models.Book, int, error)
+	FindByGenres(genreIDs []string, limit int) (map[string][]models.Book, error)
+	CountByGenres(genreIDs []string) (map[string]int, error)
 }
 type PostgresBookRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
 func NewPostgresBookRepository(db *sql.DB) BookRepository {
 	return &PostgresBookRepository{db: db}
 }
 
+// WithTx devolve uma cópia do repositório que executa as consultas na transação
+func (r *PostgresBookRepository) WithTx(tx *sql.Tx) BookRepository {
+	return &PostgresBookRepository{db: tx}
+}
+
 // Remove the first implementation and keep only this one
 func (r *PostgresBookRepository) Create(book *models.Book) error {
 	// Remover o campo title da query, já que estamos usando apenas name
-	query := `INSERT INTO livros (id, name, quantity, genre_id, author) 
-              VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	query := `INSERT INTO livros (id, name, quantity, genre_id, author,
+                  isbn, publisher, publication_year, edition, subjects, min_quantity)
+              VALUES ($1, $2, $3, $4, NULLIF($5, ''),
+                  NULLIF($6, ''), NULLIF($7, ''), $8, NULLIF($9, ''), $10, $11) RETURNING id`
 	var returnedID string
 	err := r.db.QueryRow(
 		query,
@@ -34,15 +103,22 @@ func (r *PostgresBookRepository) Create(book *models.Book) error {
 		book.Quantity,
 		book.GenreID,
 		book.Author,
+		book.ISBN,
+		book.Publisher,
+		book.PublicationYear,
+		book.Edition,
+		pq.Array(subjectsOrEmpty(book.Subjects)),
+		book.MinQuantity,
 	).Scan(&returnedID)
 	return err
 }
 
 func (r *PostgresBookRepository) FindAll(limit, offset int) ([]models.Book, error) {
 	query := `
-        SELECT id, name, quantity, genre_id, author
-        FROM livros 
-        ORDER BY id 
+        SELECT ` + BookColumns + `
+        FROM livros l
+        WHERE l.deleted_at IS NULL
+        ORDER BY l.id
         LIMIT $1 OFFSET $2`
 	rows, err := r.db.Query(query, limit, offset)
 	if err != nil {
@@ -51,13 +127,11 @@ func (r *PostgresBookRepository) FindAll(limit, offset int) ([]models.Book, erro
 	defer rows.Close()
 	var books []models.Book
 	for rows.Next() {
-		var book models.Book
-		if err := rows.Scan(&book.ID, &book.Name, &book.Quantity, &book.GenreID, &book.Author); err != nil {
+		book, err := ScanBook(rows)
+		if err != nil {
 			return nil, err
 		}
-		// Set title equal to name for frontend compatibility
-		book.Title = book.Name
-		books = append(books, book)
+		books = append(books, *book)
 	}
 	if err = rows.Err(); err != nil {
 		return nil, err
@@ -66,34 +140,44 @@ func (r *PostgresBookRepository) FindAll(limit, offset int) ([]models.Book, erro
 }
 
 func (r *PostgresBookRepository) FindByID(id string) (*models.Book, error) {
-	query := `SELECT id, name, quantity, genre_id, author FROM livros WHERE id = $1`
-	var book models.Book
-	err := r.db.QueryRow(query, id).Scan(
-		&book.ID,
-		&book.Name,
-		&book.Quantity,
-		&book.GenreID,
-		&book.Author,
-	)
-	if err != nil {
-		return nil, err
-	}
-	// Set title equal to name for frontend compatibility
-	book.Title = book.Name
-	return &book, nil
+	query := `SELECT ` + BookColumns + ` FROM livros l WHERE l.id = $1 AND l.deleted_at IS NULL`
+	return ScanBook(r.db.QueryRow(query, id))
+}
+
+// FindByISBN busca um livro pelo ISBN-13 normalizado
+func (r *PostgresBookRepository) FindByISBN(isbn string) (*models.Book, error) {
+	query := `SELECT ` + BookColumns + ` FROM livros l WHERE l.isbn = $1 AND l.deleted_at IS NULL`
+	return ScanBook(r.db.QueryRow(query, isbn))
+}
+
+// LockQuantity bloqueia o livro até o fim da transação e devolve a quantidade
+// anterior à escrita, usada no StockChanged. Devolve sql.ErrNoRows se o livro
+// não existir ou estiver na lixeira.
+func (r *PostgresBookRepository) LockQuantity(id string) (int, error) {
+	var quantity int
+	err := r.db.QueryRow("SELECT quantity FROM livros WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", id).Scan(&quantity)
+	return quantity, err
 }
 
 func (r *PostgresBookRepository) Update(book *models.Book) (int64, error) {
 	// Remover o campo title da query, já que estamos usando apenas name
-	query := `UPDATE livros 
-              SET name = $1, quantity = $2, genre_id = $3, author = $4 
-              WHERE id = $5`
+	query := `UPDATE livros
+              SET name = $1, quantity = $2, genre_id = $3, author = NULLIF($4, ''),
+                  isbn = NULLIF($5, ''), publisher = NULLIF($6, ''), publication_year = $7,
+                  edition = NULLIF($8, ''), subjects = $9, min_quantity = $10
+              WHERE id = $11 AND deleted_at IS NULL`
 	result, err := r.db.Exec(
 		query,
 		book.Name,
 		book.Quantity,
 		book.GenreID,
 		book.Author,
+		book.ISBN,
+		book.Publisher,
+		book.PublicationYear,
+		book.Edition,
+		pq.Array(subjectsOrEmpty(book.Subjects)),
+		book.MinQuantity,
 		book.ID,
 	)
 	if err != nil {
@@ -102,8 +186,10 @@ func (r *PostgresBookRepository) Update(book *models.Book) (int64, error) {
 	return result.RowsAffected()
 }
 
+// Delete move o livro para a lixeira; ele some das listagens até ser
+// restaurado ou apagado definitivamente
 func (r *PostgresBookRepository) Delete(id string) (int64, error) {
-	query := `DELETE FROM livros WHERE id = $1`
+	query := `UPDATE livros SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL`
 	result, err := r.db.Exec(query, id)
 	if err != nil {
 		return 0, err
@@ -111,8 +197,175 @@ func (r *PostgresBookRepository) Delete(id string) (int64, error) {
 	return result.RowsAffected()
 }
 
+// Restore tira o livro da lixeira. Se outro livro ativo passou a usar o mesmo
+// ISBN, o índice único devolve uma violação de unicidade.
+func (r *PostgresBookRepository) Restore(id string) (int64, error) {
+	result, err := r.db.Exec(`UPDATE livros SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// HardDelete apaga o livro definitivamente, esteja ele na lixeira ou não.
+// Livros com pedidos de compra são protegidos pela chave estrangeira.
+func (r *PostgresBookRepository) HardDelete(id string) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM livros WHERE id = $1`, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 func (r *PostgresBookRepository) Count() (int, error) {
 	var count int
-	err := r.db.QueryRow("SELECT COUNT(*) FROM livros").Scan(&count)
+	err := r.db.QueryRow("SELECT COUNT(*) FROM livros WHERE deleted_at IS NULL").Scan(&count)
 	return count, err
 }
+
+// bookSortColumns são as ordenações aceitas por Search
+var bookSortColumns = map[string]string{
+	"":                 "l.name",
+	"name":             "l.name",
+	"quantity":         "l.quantity",
+	"publication_year": "l.publication_year",
+}
+
+// Search lista os livros que atendem ao filtro e devolve também o total
+func (r *PostgresBookRepository) Search(filter models.BookFilter, limit, offset int) ([]models.Book, int, error) {
+	sortColumn, ok := bookSortColumns[filter.SortField]
+	if !ok {
+		return nil, 0, fmt.Errorf("ordenação inválida: %s", filter.SortField)
+	}
+	conditions := []string{"l.deleted_at IS NULL"}
+	var args []interface{}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	if filter.Search != "" {
+		p := arg("%" + filter.Search + "%")
+		conditions = append(conditions, "(l.name ILIKE "+p+" OR l.author ILIKE "+p+" OR l.isbn ILIKE "+p+")")
+	}
+	if filter.GenreID != "" {
+		genres := arg(filter.GenreID)
+		if filter.IncludeDescendants {
+			genres = strings.ReplaceAll(GenreSubtreeQuery, "$1", genres)
+		}
+		conditions = append(conditions,
+			"EXISTS (SELECT 1 FROM book_genres bg WHERE bg.book_id = l.id AND bg.genre_id IN ("+genres+"))")
+	}
+	if filter.AuthorID != "" {
+		conditions = append(conditions,
+			"EXISTS (SELECT 1 FROM book_authors ba WHERE ba.book_id = l.id AND ba.author_id = "+arg(filter.AuthorID)+")")
+	}
+	if filter.MinQuantity != nil {
+		conditions = append(conditions, "l.quantity >= "+arg(*filter.MinQuantity))
+	}
+	if filter.MaxQuantity != nil {
+		conditions = append(conditions, "l.quantity <= "+arg(*filter.MaxQuantity))
+	}
+	if filter.LowStock {
+		conditions = append(conditions, "l.quantity < l.min_quantity")
+	}
+	where := strings.Join(conditions, " AND ")
+
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM livros l WHERE `+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	direction := "ASC"
+	if filter.SortDesc {
+		direction = "DESC"
+	}
+	query := fmt.Sprintf(`SELECT %s FROM livros l WHERE %s ORDER BY %s %s NULLS LAST, l.id LIMIT %s OFFSET %s`,
+		BookColumns, where, sortColumn, direction, arg(limit), arg(offset))
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	books := []models.Book{}
+	for rows.Next() {
+		book, err := ScanBook(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		books = append(books, *book)
+	}
+	return books, total, rows.Err()
+}
+
+// FindByGenres lista, em uma única consulta, até limit livros de cada gênero,
+// ordenados pelo nome
+func (r *PostgresBookRepository) FindByGenres(genreIDs []string, limit int) (map[string][]models.Book, error) {
+	rows, err := r.db.Query(`
+		SELECT `+BookColumns+`, ranked.genre_id
+		FROM (
+			SELECT bg.book_id, bg.genre_id, ROW_NUMBER() OVER (PARTITION BY bg.genre_id ORDER BY b.name, b.id) AS n
+			FROM book_genres bg
+			JOIN livros b ON b.id = bg.book_id AND b.deleted_at IS NULL
+			WHERE bg.genre_id = ANY($1)
+		) ranked
+		JOIN livros l ON l.id = ranked.book_id
+		WHERE ranked.n <= $2
+		ORDER BY ranked.genre_id, ranked.n`, pq.Array(genreIDs), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	books := map[string][]models.Book{}
+	for rows.Next() {
+		var genreID string
+		book, err := ScanBook(rows, &genreID)
+		if err != nil {
+			return nil, err
+		}
+		books[genreID] = append(books[genreID], *book)
+	}
+	return books, rows.Err()
+}
+
+// CountByGenres conta os livros fora da lixeira de cada gênero
+func (r *PostgresBookRepository) CountByGenres(genreIDs []string) (map[string]int, error) {
+	rows, err := r.db.Query(`
+		SELECT bg.genre_id, COUNT(*)
+		FROM book_genres bg
+		JOIN livros l ON l.id = bg.book_id AND l.deleted_at IS NULL
+		WHERE bg.genre_id = ANY($1)
+		GROUP BY bg.genre_id`, pq.Array(genreIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	counts := map[string]int{}
+	for rows.Next() {
+		var id string
+		var count int
+		if err := rows.Scan(&id, &count); err != nil {
+			return nil, err
+		}
+		counts[id] = count
+	}
+	return counts, rows.Err()
+}
+
+// IsUniqueViolation indica se o erro veio de uma restrição UNIQUE do Postgres
+func IsUniqueViolation(err error) bool {
+	pqErr, ok := err.(*pq.Error)
+	return ok && pqErr.Code == "23505"
+}
+
+// IsForeignKeyViolation indica se o erro veio de uma chave estrangeira do Postgres
+func IsForeignKeyViolation(err error) bool {
+	pqErr, ok := err.(*pq.Error)
+	return ok && pqErr.Code == "23503"
+}
+
+// subjectsOrEmpty evita gravar NULL na coluna subjects, que é NOT NULL
+func subjectsOrEmpty(subjects []string) []string {
+	if subjects == nil {
+		return []string{}
+	}
+	return subjects
+}
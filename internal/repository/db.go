@@ -0,0 +1,25 @@
+package repositories
+
+import "database/sql"
+
+// DBTX é satisfeito por *sql.DB e *sql.Tx, para que os repositórios
+// funcionem dentro ou fora de uma transação.
+type DBTX interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// RunInTx executa fn em uma transação. O commit é feito se fn terminar sem
+// erro; caso contrário a transação é desfeita e o erro de fn é devolvido.
+func RunInTx(db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
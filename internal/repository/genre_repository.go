@@ -0,0 +1,342 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"projeto_livros/internal/domain/models"
+
+	"github.com/lib/pq"
+)
+
+// ErrGenreNotFound indica que um gênero referenciado por id não existe
+var ErrGenreNotFound = errors.New("gênero não encontrado")
+
+// GenreSubtreeQuery seleciona o id de $1 e de todos os seus descendentes
+// que não estão na lixeira
+const GenreSubtreeQuery = `
+	WITH RECURSIVE subtree AS (
+		SELECT id FROM genres WHERE id = $1 AND deleted_at IS NULL
+		UNION
+		SELECT g.id FROM genres g JOIN subtree s ON g.parent_id = s.id WHERE g.deleted_at IS NULL
+	)
+	SELECT id FROM subtree`
+
+type GenreRepository interface {
+	WithTx(tx *sql.Tx) GenreRepository
+	FindAll() ([]models.Genre, error)
+	FindByID(id string) (*models.Genre, error)
+	Update(genre *models.Genre) (int64, error)
+	Delete(id string) (int64, error)
+	Restore(id string) (int64, error)
+	HardDelete(id string) (int64, error)
+	CountBooks(id string) (int, error)
+	IsDescendant(id, ancestorID string) (bool, error)
+	MoveBooks(fromID, toID string) ([]string, error)
+	MoveChildren(fromID string, toID *string) error
+	FindBookGenres(bookID string) ([]models.BookGenre, error)
+	SetBookGenres(bookID string, genreIDs []string) ([]models.BookGenre, error)
+	FindByIDs(ids []string) (map[string]models.Genre, error)
+	FindChildren(parentIDs []string) (map[string][]models.Genre, error)
+	FindByBooks(bookIDs []string) (map[string][]models.Genre, error)
+}
+
+type PostgresGenreRepository struct {
+	db DBTX
+}
+
+func NewPostgresGenreRepository(db *sql.DB) GenreRepository {
+	return &PostgresGenreRepository{db: db}
+}
+
+// WithTx devolve uma cópia do repositório que executa as consultas na transação
+func (r *PostgresGenreRepository) WithTx(tx *sql.Tx) GenreRepository {
+	return &PostgresGenreRepository{db: tx}
+}
+
+func (r *PostgresGenreRepository) FindAll() ([]models.Genre, error) {
+	rows, err := r.db.Query(`SELECT id, name, COALESCE(description, ''), parent_id FROM genres WHERE deleted_at IS NULL ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	genres := []models.Genre{}
+	for rows.Next() {
+		var g models.Genre
+		if err := rows.Scan(&g.ID, &g.Name, &g.Description, &g.ParentID); err != nil {
+			return nil, err
+		}
+		genres = append(genres, g)
+	}
+	return genres, rows.Err()
+}
+
+func (r *PostgresGenreRepository) FindByID(id string) (*models.Genre, error) {
+	var g models.Genre
+	err := r.db.QueryRow(`SELECT id, name, COALESCE(description, ''), parent_id FROM genres WHERE id = $1 AND deleted_at IS NULL`, id).
+		Scan(&g.ID, &g.Name, &g.Description, &g.ParentID)
+	if err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+func (r *PostgresGenreRepository) Update(genre *models.Genre) (int64, error) {
+	result, err := r.db.Exec(`
+		UPDATE genres SET name = $1, description = $2, parent_id = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4 AND deleted_at IS NULL`, genre.Name, genre.Description, genre.ParentID, genre.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// Delete move o gênero para a lixeira. Os livros e subgêneros devem ter sido
+// movidos antes, para que nada ativo aponte para um gênero removido.
+func (r *PostgresGenreRepository) Delete(id string) (int64, error) {
+	result, err := r.db.Exec(`
+		UPDATE genres SET deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND deleted_at IS NULL`, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// Restore tira o gênero da lixeira. Se o pai ainda estiver na lixeira (ou já
+// tiver sido apagado), o gênero volta como raiz.
+func (r *PostgresGenreRepository) Restore(id string) (int64, error) {
+	result, err := r.db.Exec(`
+		UPDATE genres g SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP,
+			parent_id = (SELECT p.id FROM genres p WHERE p.id = g.parent_id AND p.deleted_at IS NULL)
+		WHERE g.id = $1 AND g.deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// HardDelete apaga o gênero definitivamente, esteja ele na lixeira ou não
+func (r *PostgresGenreRepository) HardDelete(id string) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM genres WHERE id = $1`, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// CountBooks conta os livros que têm o gênero, como principal ou não. Livros na
+// lixeira também contam: ao serem restaurados, não podem apontar para um
+// gênero removido.
+func (r *PostgresGenreRepository) CountBooks(id string) (int, error) {
+	var count int
+	err := r.db.QueryRow(`
+		SELECT COUNT(*) FROM livros l
+		WHERE l.genre_id = $1 OR EXISTS (SELECT 1 FROM book_genres bg WHERE bg.book_id = l.id AND bg.genre_id = $1)`,
+		id).Scan(&count)
+	return count, err
+}
+
+// IsDescendant indica se id está na subárvore de ancestorID (incluindo o próprio)
+func (r *PostgresGenreRepository) IsDescendant(id, ancestorID string) (bool, error) {
+	var found bool
+	err := r.db.QueryRow(`SELECT $2 IN (`+GenreSubtreeQuery+`)`, ancestorID, id).Scan(&found)
+	return found, err
+}
+
+// MoveBooks passa os livros do gênero fromID para toID, preservando a posição.
+// Livros que já tinham os dois gêneros ficam apenas com toID. Devolve os ids
+// dos livros que tinham fromID, para que quem chama registre as revisões.
+func (r *PostgresGenreRepository) MoveBooks(fromID, toID string) ([]string, error) {
+	rows, err := r.db.Query(`
+		SELECT l.id FROM livros l
+		WHERE l.genre_id = $1 OR EXISTS (SELECT 1 FROM book_genres bg WHERE bg.book_id = l.id AND bg.genre_id = $1)
+		ORDER BY l.id`, fromID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var bookIDs []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		bookIDs = append(bookIDs, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	_, err = r.db.Exec(`
+		DELETE FROM book_genres bg
+		WHERE bg.genre_id = $1
+		  AND EXISTS (SELECT 1 FROM book_genres other WHERE other.book_id = bg.book_id AND other.genre_id = $2)`,
+		fromID, toID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = r.db.Exec(`UPDATE book_genres SET genre_id = $2 WHERE genre_id = $1`, fromID, toID); err != nil {
+		return nil, err
+	}
+	// O gênero principal é o de menor posição em book_genres
+	_, err = r.db.Exec(`
+		UPDATE livros l SET genre_id = (
+			SELECT bg.genre_id FROM book_genres bg WHERE bg.book_id = l.id ORDER BY bg.position LIMIT 1
+		)
+		WHERE l.genre_id = $1 OR l.genre_id = $2`, fromID, toID)
+	return bookIDs, err
+}
+
+// MoveChildren passa os subgêneros diretos de fromID para toID (nil os torna raízes)
+func (r *PostgresGenreRepository) MoveChildren(fromID string, toID *string) error {
+	_, err := r.db.Exec(`UPDATE genres SET parent_id = $2, updated_at = CURRENT_TIMESTAMP WHERE parent_id = $1`,
+		fromID, toID)
+	return err
+}
+
+// FindBookGenres lista os gêneros de um livro; o primeiro é o gênero principal
+func (r *PostgresGenreRepository) FindBookGenres(bookID string) ([]models.BookGenre, error) {
+	rows, err := r.db.Query(`
+		SELECT g.id, g.name
+		FROM book_genres bg
+		JOIN genres g ON g.id = bg.genre_id
+		WHERE bg.book_id = $1
+		ORDER BY bg.position`, bookID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	genres := []models.BookGenre{}
+	for rows.Next() {
+		var g models.BookGenre
+		if err := rows.Scan(&g.ID, &g.Name); err != nil {
+			return nil, err
+		}
+		genres = append(genres, g)
+	}
+	return genres, rows.Err()
+}
+
+// SetBookGenres substitui os gêneros do livro, na ordem informada, e grava o
+// primeiro em livros.genre_id. Devolve ErrGenreNotFound se algum não existir.
+func (r *PostgresGenreRepository) SetBookGenres(bookID string, genreIDs []string) ([]models.BookGenre, error) {
+	names := map[string]string{}
+	if len(genreIDs) > 0 {
+		rows, err := r.db.Query(`SELECT id, name FROM genres WHERE id = ANY($1) AND deleted_at IS NULL`, pq.Array(genreIDs))
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var id, name string
+			if err := rows.Scan(&id, &name); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			names[id] = name
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	genres := make([]models.BookGenre, 0, len(genreIDs))
+	for _, id := range genreIDs {
+		name, ok := names[id]
+		if !ok {
+			return nil, ErrGenreNotFound
+		}
+		genres = append(genres, models.BookGenre{ID: id, Name: name})
+	}
+
+	if _, err := r.db.Exec(`DELETE FROM book_genres WHERE book_id = $1`, bookID); err != nil {
+		return nil, err
+	}
+	for position, g := range genres {
+		if _, err := r.db.Exec(`INSERT INTO book_genres (book_id, genre_id, position) VALUES ($1, $2, $3)`,
+			bookID, g.ID, position); err != nil {
+			return nil, err
+		}
+	}
+
+	var primary *string
+	if len(genres) > 0 {
+		primary = &genres[0].ID
+	}
+	_, err := r.db.Exec(`UPDATE livros SET genre_id = $1 WHERE id = $2`, primary, bookID)
+	return genres, err
+}
+
+// genreColumns é a lista de colunas lida por scanGenre; o alias da tabela é "g"
+const genreColumns = `g.id, g.name, COALESCE(g.description, ''), g.parent_id`
+
+func scanGenre(s Scanner, extra ...interface{}) (models.Genre, error) {
+	var g models.Genre
+	err := s.Scan(append([]interface{}{&g.ID, &g.Name, &g.Description, &g.ParentID}, extra...)...)
+	return g, err
+}
+
+// FindByIDs busca vários gêneros fora da lixeira de uma vez, indexados pelo id
+func (r *PostgresGenreRepository) FindByIDs(ids []string) (map[string]models.Genre, error) {
+	rows, err := r.db.Query(`SELECT `+genreColumns+` FROM genres g WHERE g.id = ANY($1) AND g.deleted_at IS NULL`,
+		pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	genres := map[string]models.Genre{}
+	for rows.Next() {
+		g, err := scanGenre(rows)
+		if err != nil {
+			return nil, err
+		}
+		genres[g.ID] = g
+	}
+	return genres, rows.Err()
+}
+
+// FindChildren lista os subgêneros diretos de cada um dos gêneros, pelo nome
+func (r *PostgresGenreRepository) FindChildren(parentIDs []string) (map[string][]models.Genre, error) {
+	rows, err := r.db.Query(`
+		SELECT `+genreColumns+` FROM genres g
+		WHERE g.parent_id = ANY($1) AND g.deleted_at IS NULL
+		ORDER BY g.name`, pq.Array(parentIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	children := map[string][]models.Genre{}
+	for rows.Next() {
+		g, err := scanGenre(rows)
+		if err != nil {
+			return nil, err
+		}
+		children[*g.ParentID] = append(children[*g.ParentID], g)
+	}
+	return children, rows.Err()
+}
+
+// FindByBooks lista os gêneros de cada um dos livros; o primeiro de cada
+// livro é o gênero principal
+func (r *PostgresGenreRepository) FindByBooks(bookIDs []string) (map[string][]models.Genre, error) {
+	rows, err := r.db.Query(`
+		SELECT `+genreColumns+`, bg.book_id
+		FROM book_genres bg
+		JOIN genres g ON g.id = bg.genre_id
+		WHERE bg.book_id = ANY($1)
+		ORDER BY bg.book_id, bg.position`, pq.Array(bookIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	genres := map[string][]models.Genre{}
+	for rows.Next() {
+		var bookID string
+		g, err := scanGenre(rows, &bookID)
+		if err != nil {
+			return nil, err
+		}
+		genres[bookID] = append(genres[bookID], g)
+	}
+	return genres, rows.Err()
+}
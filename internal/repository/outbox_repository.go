@@ -0,0 +1,133 @@
+package repositories
+
+import (
+	"database/sql"
+	"encoding/json"
+	"projeto_livros/internal/domain/models"
+	"time"
+)
+
+// outboxLockKey identifica o advisory lock que garante um único despachante
+// do outbox por vez, preservando a ordem de entrega entre instâncias
+const outboxLockKey = 7283002
+
+type OutboxRepository interface {
+	WithTx(tx *sql.Tx) OutboxRepository
+	Add(eventType, aggregateType, aggregateID, actor string, payload interface{}) error
+	TryLock() (bool, error)
+	Pending(limit int) ([]models.Event, error)
+	MarkDispatched(id int64) error
+	MarkFailed(id int64, lastError string, retryAt time.Time) error
+	Prune(before time.Time) (int64, error)
+	FindByID(id int64) (*models.Event, error)
+	Recent(limit int) ([]models.Event, error)
+	After(id int64, limit int) ([]models.Event, error)
+}
+
+type PostgresOutboxRepository struct {
+	db DBTX
+}
+
+func NewPostgresOutboxRepository(db *sql.DB) OutboxRepository {
+	return &PostgresOutboxRepository{db: db}
+}
+
+// WithTx devolve uma cópia do repositório que executa as consultas na transação
+func (r *PostgresOutboxRepository) WithTx(tx *sql.Tx) OutboxRepository {
+	return &PostgresOutboxRepository{db: tx}
+}
+
+// Add grava um evento no outbox. Deve ser chamado na transação da alteração,
+// para que o evento exista se e somente se a alteração foi confirmada.
+func (r *PostgresOutboxRepository) Add(eventType, aggregateType, aggregateID, actor string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.Exec(`
+		INSERT INTO outbox_events (event_type, aggregate_type, aggregate_id, actor, payload)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5)`, eventType, aggregateType, aggregateID, actor, string(data))
+	return err
+}
+
+// TryLock tenta obter o lock do despachante até o fim da transação
+func (r *PostgresOutboxRepository) TryLock() (bool, error) {
+	var locked bool
+	err := r.db.QueryRow(`SELECT pg_try_advisory_xact_lock($1)`, outboxLockKey).Scan(&locked)
+	return locked, err
+}
+
+const outboxColumns = `id, event_type, aggregate_type, aggregate_id, COALESCE(actor, ''), payload,
+	created_at, attempts, next_attempt_at`
+
+func scanEvent(s Scanner) (*models.Event, error) {
+	var e models.Event
+	var payload []byte
+	if err := s.Scan(&e.ID, &e.Type, &e.AggregateType, &e.AggregateID, &e.Actor, &payload,
+		&e.OccurredAt, &e.Attempts, &e.NextAttemptAt); err != nil {
+		return nil, err
+	}
+	e.Payload = payload
+	return &e, nil
+}
+
+func (r *PostgresOutboxRepository) queryEvents(query string, args ...interface{}) ([]models.Event, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	events := []models.Event{}
+	for rows.Next() {
+		e, err := scanEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, *e)
+	}
+	return events, rows.Err()
+}
+
+// Pending lista os eventos ainda não entregues, na ordem de gravação
+func (r *PostgresOutboxRepository) Pending(limit int) ([]models.Event, error) {
+	return r.queryEvents(`SELECT `+outboxColumns+` FROM outbox_events
+		WHERE dispatched_at IS NULL ORDER BY id LIMIT $1`, limit)
+}
+
+func (r *PostgresOutboxRepository) FindByID(id int64) (*models.Event, error) {
+	return scanEvent(r.db.QueryRow(`SELECT `+outboxColumns+` FROM outbox_events WHERE id = $1`, id))
+}
+
+// Recent lista os últimos limit eventos gravados, em ordem de gravação
+func (r *PostgresOutboxRepository) Recent(limit int) ([]models.Event, error) {
+	return r.queryEvents(`SELECT * FROM (SELECT `+outboxColumns+` FROM outbox_events
+		ORDER BY id DESC LIMIT $1) recent ORDER BY id`, limit)
+}
+
+// After lista, em ordem, até limit eventos gravados depois do evento id
+func (r *PostgresOutboxRepository) After(id int64, limit int) ([]models.Event, error) {
+	return r.queryEvents(`SELECT `+outboxColumns+` FROM outbox_events
+		WHERE id > $1 ORDER BY id LIMIT $2`, id, limit)
+}
+
+func (r *PostgresOutboxRepository) MarkDispatched(id int64) error {
+	_, err := r.db.Exec(`UPDATE outbox_events SET dispatched_at = CURRENT_TIMESTAMP, last_error = NULL WHERE id = $1`, id)
+	return err
+}
+
+// MarkFailed registra uma tentativa de entrega que falhou e quando tentar de novo
+func (r *PostgresOutboxRepository) MarkFailed(id int64, lastError string, retryAt time.Time) error {
+	_, err := r.db.Exec(`
+		UPDATE outbox_events SET attempts = attempts + 1, last_error = $2, next_attempt_at = $3
+		WHERE id = $1`, id, lastError, retryAt)
+	return err
+}
+
+// Prune apaga os eventos entregues antes de before
+func (r *PostgresOutboxRepository) Prune(before time.Time) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM outbox_events WHERE dispatched_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,265 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"projeto_livros/internal/domain/models"
+
+	"github.com/segmentio/ksuid"
+)
+
+// ErrReceiveExceedsOrdered indica um recebimento maior que o saldo da linha do pedido
+var ErrReceiveExceedsOrdered = errors.New("quantidade recebida maior que o saldo pendente da linha")
+
+// ErrOrderLineNotFound indica uma linha que não pertence ao pedido
+var ErrOrderLineNotFound = errors.New("linha não encontrada no pedido")
+
+type PurchaseOrderRepository interface {
+	WithTx(tx *sql.Tx) PurchaseOrderRepository
+	Create(order *models.PurchaseOrder) error
+	FindAll(status, supplierID string, limit, offset int) ([]models.PurchaseOrder, int, error)
+	FindByID(id string) (*models.PurchaseOrder, error)
+	LockStatus(id string) (string, error)
+	Update(order *models.PurchaseOrder) error
+	SetStatus(id, status string) error
+	Delete(id string) (int64, error)
+	ReceiveLine(orderID, lineID string, quantity int, userID string) (*models.StockReceipt, error)
+	PendingLines(orderID string) ([]models.PurchaseOrderLine, error)
+}
+
+type PostgresPurchaseOrderRepository struct {
+	db DBTX
+}
+
+func NewPostgresPurchaseOrderRepository(db *sql.DB) PurchaseOrderRepository {
+	return &PostgresPurchaseOrderRepository{db: db}
+}
+
+// WithTx devolve uma cópia do repositório que executa as consultas na transação
+func (r *PostgresPurchaseOrderRepository) WithTx(tx *sql.Tx) PurchaseOrderRepository {
+	return &PostgresPurchaseOrderRepository{db: tx}
+}
+
+const orderColumns = `o.id, o.supplier_id, s.name, o.status, COALESCE(o.notes, ''),
+	COALESCE((SELECT SUM(pl.quantity * pl.unit_cost) FROM purchase_order_lines pl WHERE pl.order_id = o.id), 0),
+	o.created_at, o.sent_at, o.received_at`
+
+func scanOrder(s Scanner) (*models.PurchaseOrder, error) {
+	var o models.PurchaseOrder
+	err := s.Scan(&o.ID, &o.SupplierID, &o.SupplierName, &o.Status, &o.Notes, &o.Total,
+		&o.CreatedAt, &o.SentAt, &o.ReceivedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+// Create grava o pedido como rascunho, com as suas linhas
+func (r *PostgresPurchaseOrderRepository) Create(order *models.PurchaseOrder) error {
+	order.ID = ksuid.New().String()
+	order.Status = models.OrderStatusDraft
+	_, err := r.db.Exec(`INSERT INTO purchase_orders (id, supplier_id, status, notes) VALUES ($1, $2, $3, NULLIF($4, ''))`,
+		order.ID, order.SupplierID, order.Status, order.Notes)
+	if err != nil {
+		return err
+	}
+	return r.insertLines(order)
+}
+
+func (r *PostgresPurchaseOrderRepository) insertLines(order *models.PurchaseOrder) error {
+	for i := range order.Lines {
+		line := &order.Lines[i]
+		line.ID = ksuid.New().String()
+		line.ReceivedQuantity = 0
+		if _, err := r.db.Exec(`
+			INSERT INTO purchase_order_lines (id, order_id, book_id, quantity, unit_cost)
+			VALUES ($1, $2, $3, $4, $5)`, line.ID, order.ID, line.BookID, line.Quantity, line.UnitCost); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *PostgresPurchaseOrderRepository) FindAll(status, supplierID string, limit, offset int) ([]models.PurchaseOrder, int, error) {
+	where := `WHERE ($1 = '' OR o.status = $1) AND ($2 = '' OR o.supplier_id = $2)`
+
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM purchase_orders o `+where, status, supplierID).
+		Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Query(`
+		SELECT `+orderColumns+`
+		FROM purchase_orders o
+		JOIN suppliers s ON s.id = o.supplier_id
+		`+where+`
+		ORDER BY o.created_at DESC, o.id
+		LIMIT $3 OFFSET $4`, status, supplierID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	orders := []models.PurchaseOrder{}
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		orders = append(orders, *order)
+	}
+	return orders, total, rows.Err()
+}
+
+// FindByID busca o pedido com as suas linhas e os recebimentos já registrados
+func (r *PostgresPurchaseOrderRepository) FindByID(id string) (*models.PurchaseOrder, error) {
+	order, err := scanOrder(r.db.QueryRow(`
+		SELECT `+orderColumns+`
+		FROM purchase_orders o
+		JOIN suppliers s ON s.id = o.supplier_id
+		WHERE o.id = $1`, id))
+	if err != nil {
+		return nil, err
+	}
+	if order.Lines, err = r.findLines(id, false); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(`
+		SELECT id, order_id, line_id, book_id, quantity, received_at, COALESCE(received_by, '')
+		FROM stock_receipts
+		WHERE order_id = $1
+		ORDER BY received_at, id`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	order.Receipts = []models.StockReceipt{}
+	for rows.Next() {
+		var rc models.StockReceipt
+		if err := rows.Scan(&rc.ID, &rc.OrderID, &rc.LineID, &rc.BookID, &rc.Quantity, &rc.ReceivedAt, &rc.ReceivedBy); err != nil {
+			return nil, err
+		}
+		order.Receipts = append(order.Receipts, rc)
+	}
+	return order, rows.Err()
+}
+
+func (r *PostgresPurchaseOrderRepository) findLines(orderID string, pendingOnly bool) ([]models.PurchaseOrderLine, error) {
+	query := `
+		SELECT pl.id, pl.book_id, l.name, pl.quantity, pl.unit_cost, pl.received_quantity
+		FROM purchase_order_lines pl
+		JOIN livros l ON l.id = pl.book_id
+		WHERE pl.order_id = $1`
+	if pendingOnly {
+		query += ` AND pl.received_quantity < pl.quantity`
+	}
+	rows, err := r.db.Query(query+` ORDER BY l.name, pl.id`, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	lines := []models.PurchaseOrderLine{}
+	for rows.Next() {
+		var line models.PurchaseOrderLine
+		if err := rows.Scan(&line.ID, &line.BookID, &line.BookName, &line.Quantity, &line.UnitCost,
+			&line.ReceivedQuantity); err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+	return lines, rows.Err()
+}
+
+// PendingLines lista as linhas do pedido que ainda têm saldo a receber
+func (r *PostgresPurchaseOrderRepository) PendingLines(orderID string) ([]models.PurchaseOrderLine, error) {
+	return r.findLines(orderID, true)
+}
+
+// LockStatus bloqueia o pedido até o fim da transação e devolve a sua
+// situação, para que alterações e recebimentos simultâneos não se misturem
+func (r *PostgresPurchaseOrderRepository) LockStatus(id string) (string, error) {
+	var status string
+	err := r.db.QueryRow(`SELECT status FROM purchase_orders WHERE id = $1 FOR UPDATE`, id).Scan(&status)
+	return status, err
+}
+
+// Update substitui fornecedor, observações e linhas de um pedido em rascunho
+func (r *PostgresPurchaseOrderRepository) Update(order *models.PurchaseOrder) error {
+	if _, err := r.db.Exec(`
+		UPDATE purchase_orders SET supplier_id = $1, notes = NULLIF($2, ''), updated_at = CURRENT_TIMESTAMP
+		WHERE id = $3`, order.SupplierID, order.Notes, order.ID); err != nil {
+		return err
+	}
+	if _, err := r.db.Exec(`DELETE FROM purchase_order_lines WHERE order_id = $1`, order.ID); err != nil {
+		return err
+	}
+	return r.insertLines(order)
+}
+
+// SetStatus muda a situação do pedido, registrando a data de envio ou de recebimento total
+func (r *PostgresPurchaseOrderRepository) SetStatus(id, status string) error {
+	_, err := r.db.Exec(`
+		UPDATE purchase_orders
+		SET status = $2,
+			sent_at = CASE WHEN $2 = 'sent' THEN CURRENT_TIMESTAMP ELSE sent_at END,
+			received_at = CASE WHEN $2 = 'received' THEN CURRENT_TIMESTAMP ELSE received_at END,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1`, id, status)
+	return err
+}
+
+func (r *PostgresPurchaseOrderRepository) Delete(id string) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM purchase_orders WHERE id = $1`, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// ReceiveLine registra o recebimento de quantity unidades de uma linha: soma
+// ao recebido da linha, aumenta o estoque do livro e grava o recebimento
+func (r *PostgresPurchaseOrderRepository) ReceiveLine(orderID, lineID string, quantity int, userID string) (*models.StockReceipt, error) {
+	if quantity <= 0 {
+		return nil, fmt.Errorf("quantidade recebida deve ser maior que zero")
+	}
+	receipt := &models.StockReceipt{
+		ID:         ksuid.New().String(),
+		OrderID:    orderID,
+		LineID:     lineID,
+		Quantity:   quantity,
+		ReceivedBy: userID,
+	}
+
+	var pending int
+	err := r.db.QueryRow(`
+		SELECT book_id, quantity - received_quantity FROM purchase_order_lines
+		WHERE id = $1 AND order_id = $2`, lineID, orderID).Scan(&receipt.BookID, &pending)
+	if err == sql.ErrNoRows {
+		return nil, ErrOrderLineNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	if quantity > pending {
+		return nil, ErrReceiveExceedsOrdered
+	}
+
+	if _, err := r.db.Exec(`UPDATE purchase_order_lines SET received_quantity = received_quantity + $1 WHERE id = $2`,
+		quantity, lineID); err != nil {
+		return nil, err
+	}
+	if err := r.db.QueryRow(`UPDATE livros SET quantity = quantity + $1 WHERE id = $2 RETURNING quantity - $1`,
+		quantity, receipt.BookID).Scan(&receipt.QuantityBefore); err != nil {
+		return nil, err
+	}
+	err = r.db.QueryRow(`
+		INSERT INTO stock_receipts (id, order_id, line_id, book_id, quantity, received_by)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''))
+		RETURNING received_at`, receipt.ID, orderID, lineID, receipt.BookID, quantity, userID).
+		Scan(&receipt.ReceivedAt)
+	if err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
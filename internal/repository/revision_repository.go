@@ -0,0 +1,283 @@
+package repositories
+
+import (
+	"database/sql"
+	"encoding/json"
+	"projeto_livros/internal/domain/models"
+	"reflect"
+	"sort"
+
+	"github.com/lib/pq"
+	"github.com/segmentio/ksuid"
+)
+
+// revisionIgnoredFields são campos derivados de outros e que não entram na
+// comparação entre revisões
+var revisionIgnoredFields = map[string]bool{"id": true, "title": true, "isbn_10": true, "isbn_13": true}
+
+type RevisionRepository interface {
+	WithTx(tx *sql.Tx) RevisionRepository
+	Record(bookID, action, actor string, revertedFrom *int) (*models.BookRevision, error)
+	RecordStockChange(bookID, actor string, quantityBefore int) (*models.BookRevision, error)
+	FindByBook(bookID string, limit, offset int) ([]models.BookRevision, int, error)
+	FindByRev(bookID string, rev int) (*models.BookRevision, error)
+}
+
+type PostgresRevisionRepository struct {
+	db DBTX
+}
+
+func NewPostgresRevisionRepository(db *sql.DB) RevisionRepository {
+	return &PostgresRevisionRepository{db: db}
+}
+
+// WithTx devolve uma cópia do repositório que executa as consultas na transação
+func (r *PostgresRevisionRepository) WithTx(tx *sql.Tx) RevisionRepository {
+	return &PostgresRevisionRepository{db: tx}
+}
+
+// DiffBooks compara dois estados de um livro campo a campo, pela sua
+// representação JSON. from nil equivale a um livro sem nenhum campo preenchido.
+func DiffBooks(from, to *models.Book) ([]models.FieldChange, error) {
+	before, err := bookFields(from)
+	if err != nil {
+		return nil, err
+	}
+	after, err := bookFields(to)
+	if err != nil {
+		return nil, err
+	}
+	names := map[string]bool{}
+	for name := range before {
+		names[name] = true
+	}
+	for name := range after {
+		names[name] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		if !revisionIgnoredFields[name] {
+			sorted = append(sorted, name)
+		}
+	}
+	sort.Strings(sorted)
+
+	changes := []models.FieldChange{}
+	for _, name := range sorted {
+		if !reflect.DeepEqual(before[name], after[name]) {
+			changes = append(changes, models.FieldChange{Field: name, From: before[name], To: after[name]})
+		}
+	}
+	return changes, nil
+}
+
+func bookFields(book *models.Book) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+	if book == nil {
+		return fields, nil
+	}
+	data, err := json.Marshal(book)
+	if err != nil {
+		return nil, err
+	}
+	return fields, json.Unmarshal(data, &fields)
+}
+
+// Record grava uma revisão com o estado atual do livro, incluindo autores e
+// gêneros, e publica no outbox os eventos correspondentes. Na remoção
+// definitiva deve ser chamado antes do DELETE. Uma atualização que não mudou
+// nenhum campo não gera revisão nem eventos (devolve nil, nil).
+func (r *PostgresRevisionRepository) Record(bookID, action, actor string, revertedFrom *int) (*models.BookRevision, error) {
+	return r.record(bookID, action, actor, revertedFrom, nil)
+}
+
+// RecordStockChange grava a revisão de uma atualização que pode ter mudado o
+// estoque. quantityBefore é a quantidade lida (com FOR UPDATE) antes da escrita,
+// na mesma transação: com ela o StockChanged sai mesmo para livros ainda sem
+// revisão, como os cadastrados antes do histórico.
+func (r *PostgresRevisionRepository) RecordStockChange(bookID, actor string, quantityBefore int) (*models.BookRevision, error) {
+	return r.record(bookID, models.RevisionUpdate, actor, nil, &quantityBefore)
+}
+
+func (r *PostgresRevisionRepository) record(bookID, action, actor string, revertedFrom, quantityBefore *int) (*models.BookRevision, error) {
+	// O FOR UPDATE serializa as revisões do mesmo livro dentro de transações
+	book, err := ScanBook(r.db.QueryRow(`SELECT `+BookColumns+` FROM livros l WHERE l.id = $1 FOR UPDATE`, bookID))
+	if err != nil {
+		return nil, err
+	}
+	if book.Authors, err = (&PostgresAuthorRepository{db: r.db}).FindBookAuthors(bookID); err != nil {
+		return nil, err
+	}
+	if book.Genres, err = (&PostgresGenreRepository{db: r.db}).FindBookGenres(bookID); err != nil {
+		return nil, err
+	}
+
+	var previous *models.Book
+	var lastRev int
+	var data []byte
+	err = r.db.QueryRow(`SELECT rev, snapshot FROM book_revisions WHERE book_id = $1 ORDER BY rev DESC LIMIT 1`, bookID).
+		Scan(&lastRev, &data)
+	if err == nil {
+		previous = &models.Book{}
+		if err := json.Unmarshal(data, previous); err != nil {
+			return nil, err
+		}
+	} else if err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	changes, err := DiffBooks(previous, book)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 && action == models.RevisionUpdate {
+		return nil, nil
+	}
+	revision := &models.BookRevision{
+		ID:            ksuid.New().String(),
+		BookID:        bookID,
+		Rev:           lastRev + 1,
+		Action:        action,
+		Actor:         actor,
+		ChangedFields: make([]string, len(changes)),
+		RevertedFrom:  revertedFrom,
+		Snapshot:      *book,
+	}
+	for i, change := range changes {
+		revision.ChangedFields[i] = change.Field
+	}
+	snapshot, err := json.Marshal(book)
+	if err != nil {
+		return nil, err
+	}
+	err = r.db.QueryRow(`
+		INSERT INTO book_revisions (id, book_id, rev, action, actor, changed_fields, reverted_from, snapshot)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8)
+		RETURNING created_at`,
+		revision.ID, bookID, revision.Rev, action, actor, pq.Array(revision.ChangedFields), revertedFrom, snapshot).
+		Scan(&revision.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if quantityBefore == nil && previous != nil {
+		quantityBefore = &previous.Quantity
+	}
+	if err := r.addEvents(revision, quantityBefore); err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
+
+// revisionEvents associa cada ação do histórico ao evento de domínio publicado
+var revisionEvents = map[string]string{
+	models.RevisionCreate:  models.EventBookCreated,
+	models.RevisionUpdate:  models.EventBookUpdated,
+	models.RevisionRevert:  models.EventBookUpdated,
+	models.RevisionDelete:  models.EventBookDeleted,
+	models.RevisionRestore: models.EventBookRestored,
+}
+
+// addEvents grava no outbox, na mesma transação da revisão, o evento do livro
+// e, quando a quantidade mudou, um StockChanged. A quantidade de origem vem de
+// quem chamou ou, na falta dela, da revisão anterior; sem nenhuma das duas o
+// StockChanged não é gerado.
+func (r *PostgresRevisionRepository) addEvents(revision *models.BookRevision, quantityBefore *int) error {
+	outbox := &PostgresOutboxRepository{db: r.db}
+	err := outbox.Add(revisionEvents[revision.Action], models.AggregateBook, revision.BookID, revision.Actor,
+		models.BookEventPayload{
+			Book:          revision.Snapshot,
+			Rev:           revision.Rev,
+			ChangedFields: revision.ChangedFields,
+			RevertedFrom:  revision.RevertedFrom,
+		})
+	if err != nil {
+		return err
+	}
+	if quantityBefore == nil || *quantityBefore == revision.Snapshot.Quantity ||
+		(revision.Action != models.RevisionUpdate && revision.Action != models.RevisionRevert) {
+		return nil
+	}
+	return outbox.Add(models.EventStockChanged, models.AggregateBook, revision.BookID, revision.Actor,
+		models.StockChangedPayload{
+			BookID:   revision.BookID,
+			From:     *quantityBefore,
+			To:       revision.Snapshot.Quantity,
+			Delta:    revision.Snapshot.Quantity - *quantityBefore,
+			Rev:      revision.Rev,
+			GenreIDs: bookGenreIDs(&revision.Snapshot),
+		})
+}
+
+// bookGenreIDs devolve os IDs dos gêneros do livro, incluindo o principal
+func bookGenreIDs(book *models.Book) []string {
+	var ids []string
+	seen := map[string]bool{}
+	add := func(id string) {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if book.GenreID != nil {
+		add(*book.GenreID)
+	}
+	for _, genre := range book.Genres {
+		add(genre.ID)
+	}
+	return ids
+}
+
+const revisionColumns = `id, book_id, rev, action, COALESCE(actor, ''), changed_fields, reverted_from, snapshot, created_at`
+
+func scanRevision(s Scanner) (*models.BookRevision, error) {
+	var rev models.BookRevision
+	var changed pq.StringArray
+	var revertedFrom sql.NullInt64
+	var snapshot []byte
+	if err := s.Scan(&rev.ID, &rev.BookID, &rev.Rev, &rev.Action, &rev.Actor, &changed, &revertedFrom,
+		&snapshot, &rev.CreatedAt); err != nil {
+		return nil, err
+	}
+	rev.ChangedFields = []string(changed)
+	if revertedFrom.Valid {
+		n := int(revertedFrom.Int64)
+		rev.RevertedFrom = &n
+	}
+	if err := json.Unmarshal(snapshot, &rev.Snapshot); err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
+
+// FindByBook lista as revisões do livro, da mais recente para a mais antiga
+func (r *PostgresRevisionRepository) FindByBook(bookID string, limit, offset int) ([]models.BookRevision, int, error) {
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM book_revisions WHERE book_id = $1`, bookID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	rows, err := r.db.Query(`
+		SELECT `+revisionColumns+`
+		FROM book_revisions
+		WHERE book_id = $1
+		ORDER BY rev DESC
+		LIMIT $2 OFFSET $3`, bookID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	revisions := []models.BookRevision{}
+	for rows.Next() {
+		rev, err := scanRevision(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		revisions = append(revisions, *rev)
+	}
+	return revisions, total, rows.Err()
+}
+
+// FindByRev busca uma revisão pelo número; devolve sql.ErrNoRows se não existir
+func (r *PostgresRevisionRepository) FindByRev(bookID string, rev int) (*models.BookRevision, error) {
+	return scanRevision(r.db.QueryRow(`SELECT `+revisionColumns+` FROM book_revisions WHERE book_id = $1 AND rev = $2`,
+		bookID, rev))
+}
@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"database/sql"
+	"projeto_livros/internal/domain/models"
+	"time"
+)
+
+type StatsRepository interface {
+	CatalogStats(lowStockThreshold, listLimit int) (*models.CatalogStats, error)
+}
+
+type PostgresStatsRepository struct {
+	db DBTX
+}
+
+func NewPostgresStatsRepository(db *sql.DB) StatsRepository {
+	return &PostgresStatsRepository{db: db}
+}
+
+// lowStockCondition seleciona os livros com estoque baixo: abaixo do
+// min_quantity do livro ou, sem ele, até o limite geral ($1)
+const lowStockCondition = `(l.quantity < l.min_quantity OR (l.min_quantity IS NULL AND l.quantity <= $1))`
+
+// CatalogStats calcula os totais do catálogo. Os livros sem min_quantity são
+// considerados com estoque baixo com quantidade até lowStockThreshold; as
+// listas de estoque baixo e de livros recentes trazem no máximo listLimit livros.
+func (r *PostgresStatsRepository) CatalogStats(lowStockThreshold, listLimit int) (*models.CatalogStats, error) {
+	stats := &models.CatalogStats{GeneratedAt: time.Now()}
+	totals := &stats.Totals
+	totals.LowStockThreshold = lowStockThreshold
+
+	err := r.db.QueryRow(`
+		SELECT COUNT(*),
+			COALESCE(SUM(l.quantity), 0),
+			(SELECT COUNT(*) FROM genres WHERE deleted_at IS NULL),
+			COUNT(*) FILTER (WHERE NOT EXISTS (SELECT 1 FROM book_genres bg WHERE bg.book_id = l.id)),
+			COUNT(*) FILTER (WHERE COALESCE(trim(l.author), '') = ''),
+			COUNT(*) FILTER (WHERE `+lowStockCondition+`)
+		FROM livros l
+		WHERE l.deleted_at IS NULL`, lowStockThreshold).Scan(
+		&totals.Titles,
+		&totals.Copies,
+		&totals.Genres,
+		&totals.BooksWithoutGenre,
+		&totals.BooksWithoutAuthor,
+		&totals.LowStockTitles,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(`
+		SELECT g.id, g.name, g.parent_id, COUNT(l.id), COALESCE(SUM(l.quantity), 0)
+		FROM genres g
+		LEFT JOIN book_genres bg ON bg.genre_id = g.id
+		LEFT JOIN livros l ON l.id = bg.book_id AND l.deleted_at IS NULL
+		WHERE g.deleted_at IS NULL
+		GROUP BY g.id, g.name, g.parent_id
+		ORDER BY g.name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	stats.Genres = []models.GenreStats{}
+	for rows.Next() {
+		var g models.GenreStats
+		if err := rows.Scan(&g.ID, &g.Name, &g.ParentID, &g.Titles, &g.Copies); err != nil {
+			return nil, err
+		}
+		stats.Genres = append(stats.Genres, g)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if stats.LowStock, err = r.findBooks(`AND `+lowStockCondition+` ORDER BY l.quantity, l.name LIMIT $2`,
+		lowStockThreshold, listLimit); err != nil {
+		return nil, err
+	}
+	if stats.RecentBooks, err = r.findBooks(`ORDER BY l.created_at DESC NULLS LAST, l.id DESC LIMIT $1`,
+		listLimit); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// findBooks lista os livros fora da lixeira; clause continua a cláusula WHERE
+// (com AND) ou traz apenas a ordenação e o limite
+func (r *PostgresStatsRepository) findBooks(clause string, args ...interface{}) ([]models.Book, error) {
+	rows, err := r.db.Query(`SELECT `+BookColumns+` FROM livros l WHERE l.deleted_at IS NULL `+clause, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	books := []models.Book{}
+	for rows.Next() {
+		book, err := ScanBook(rows)
+		if err != nil {
+			return nil, err
+		}
+		books = append(books, *book)
+	}
+	return books, rows.Err()
+}
@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"database/sql"
+	"projeto_livros/internal/domain/models"
+
+	"github.com/segmentio/ksuid"
+)
+
+type SupplierRepository interface {
+	Create(supplier *models.Supplier) error
+	FindAll() ([]models.Supplier, error)
+	FindByID(id string) (*models.Supplier, error)
+	Update(supplier *models.Supplier) (int64, error)
+	Delete(id string) (int64, error)
+}
+
+type PostgresSupplierRepository struct {
+	db DBTX
+}
+
+func NewPostgresSupplierRepository(db *sql.DB) SupplierRepository {
+	return &PostgresSupplierRepository{db: db}
+}
+
+const supplierColumns = `id, name, COALESCE(email, ''), COALESCE(phone, ''), COALESCE(notes, '')`
+
+func scanSupplier(s Scanner) (*models.Supplier, error) {
+	var supplier models.Supplier
+	if err := s.Scan(&supplier.ID, &supplier.Name, &supplier.Email, &supplier.Phone, &supplier.Notes); err != nil {
+		return nil, err
+	}
+	return &supplier, nil
+}
+
+func (r *PostgresSupplierRepository) Create(supplier *models.Supplier) error {
+	supplier.ID = ksuid.New().String()
+	_, err := r.db.Exec(`
+		INSERT INTO suppliers (id, name, email, phone, notes)
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, ''))`,
+		supplier.ID, supplier.Name, supplier.Email, supplier.Phone, supplier.Notes)
+	return err
+}
+
+func (r *PostgresSupplierRepository) FindAll() ([]models.Supplier, error) {
+	rows, err := r.db.Query(`SELECT ` + supplierColumns + ` FROM suppliers ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	suppliers := []models.Supplier{}
+	for rows.Next() {
+		supplier, err := scanSupplier(rows)
+		if err != nil {
+			return nil, err
+		}
+		suppliers = append(suppliers, *supplier)
+	}
+	return suppliers, rows.Err()
+}
+
+func (r *PostgresSupplierRepository) FindByID(id string) (*models.Supplier, error) {
+	return scanSupplier(r.db.QueryRow(`SELECT `+supplierColumns+` FROM suppliers WHERE id = $1`, id))
+}
+
+func (r *PostgresSupplierRepository) Update(supplier *models.Supplier) (int64, error) {
+	result, err := r.db.Exec(`
+		UPDATE suppliers
+		SET name = $1, email = NULLIF($2, ''), phone = NULLIF($3, ''), notes = NULLIF($4, ''),
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $5`, supplier.Name, supplier.Email, supplier.Phone, supplier.Notes, supplier.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// Delete remove um fornecedor sem pedidos; a chave estrangeira de
+// purchase_orders impede a remoção dos demais
+func (r *PostgresSupplierRepository) Delete(id string) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM suppliers WHERE id = $1`, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"projeto_livros/internal/domain/models"
+	"time"
+)
+
+// trashQuery une os livros e os gêneros na lixeira; $1 filtra o tipo (vazio para todos)
+const trashQuery = `
+	SELECT type, id, name, deleted_at FROM (
+		SELECT 'book' AS type, id, name, deleted_at FROM livros WHERE deleted_at IS NOT NULL
+		UNION ALL
+		SELECT 'genre', id, name, deleted_at FROM genres WHERE deleted_at IS NOT NULL
+	) trash
+	WHERE $1 = '' OR type = $1`
+
+type TrashRepository interface {
+	FindAll(itemType string, limit, offset int) ([]models.TrashItem, int, error)
+	Purge(before time.Time) (books int64, genres int64, err error)
+}
+
+type PostgresTrashRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresTrashRepository(db *sql.DB) TrashRepository {
+	return &PostgresTrashRepository{db: db}
+}
+
+// FindAll lista os itens na lixeira, dos removidos mais recentemente para os
+// mais antigos. PurgeAt não é preenchido: depende da retenção configurada.
+func (r *PostgresTrashRepository) FindAll(itemType string, limit, offset int) ([]models.TrashItem, int, error) {
+	switch itemType {
+	case "", models.TrashTypeBook, models.TrashTypeGenre:
+	default:
+		return nil, 0, fmt.Errorf("tipo de item desconhecido: %s", itemType)
+	}
+
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM (`+trashQuery+`) t`, itemType).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Query(trashQuery+` ORDER BY deleted_at DESC, id LIMIT $2 OFFSET $3`, itemType, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	items := []models.TrashItem{}
+	for rows.Next() {
+		var item models.TrashItem
+		if err := rows.Scan(&item.Type, &item.ID, &item.Name, &item.DeletedAt); err != nil {
+			return nil, 0, err
+		}
+		items = append(items, item)
+	}
+	return items, total, rows.Err()
+}
+
+// Purge apaga definitivamente os itens que estão na lixeira desde antes de
+// before. Livros com pedidos de compra e gêneros ainda referenciados ficam na
+// lixeira, já que apagá-los violaria as chaves estrangeiras.
+func (r *PostgresTrashRepository) Purge(before time.Time) (int64, int64, error) {
+	var books, genres int64
+	err := RunInTx(r.db, func(tx *sql.Tx) error {
+		result, err := tx.Exec(`
+			DELETE FROM livros l
+			WHERE l.deleted_at < $1
+			  AND NOT EXISTS (SELECT 1 FROM purchase_order_lines pl WHERE pl.book_id = l.id)
+			  AND NOT EXISTS (SELECT 1 FROM stock_receipts sr WHERE sr.book_id = l.id)`, before)
+		if err != nil {
+			return err
+		}
+		if books, err = result.RowsAffected(); err != nil {
+			return err
+		}
+		result, err = tx.Exec(`
+			DELETE FROM genres g
+			WHERE g.deleted_at < $1
+			  AND NOT EXISTS (SELECT 1 FROM genres c WHERE c.parent_id = g.id)
+			  AND NOT EXISTS (SELECT 1 FROM book_genres bg WHERE bg.genre_id = g.id)
+			  AND NOT EXISTS (SELECT 1 FROM livros l WHERE l.genre_id = g.id)`, before)
+		if err != nil {
+			return err
+		}
+		genres, err = result.RowsAffected()
+		return err
+	})
+	return books, genres, err
+}
@@ -0,0 +1,362 @@
+package repositories
+
+import (
+	"database/sql"
+	"encoding/json"
+	"projeto_livros/internal/domain/models"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/segmentio/ksuid"
+)
+
+type WebhookRepository interface {
+	Create(sub *models.WebhookSubscription) error
+	FindAll(limit, offset int) ([]models.WebhookSubscription, int, error)
+	FindByID(id string) (*models.WebhookSubscription, error)
+	Update(sub *models.WebhookSubscription) (int64, error)
+	Delete(id string) (int64, error)
+	Enqueue(event models.Event) (int64, error)
+	EnqueuePing(subscriptionID string) (*models.WebhookDelivery, error)
+	FindDeliveries(subscriptionID, status string, limit, offset int) ([]models.WebhookDelivery, int, error)
+	FindDelivery(subscriptionID, deliveryID string) (*models.WebhookDelivery, error)
+	Redeliver(subscriptionID, deliveryID string) (int64, error)
+	Claim(limit int, lease time.Duration) ([]models.WebhookDelivery, error)
+	RecordAttempt(delivery *models.WebhookDelivery, attempt *models.WebhookAttempt, disableAfter int) (bool, error)
+}
+
+type PostgresWebhookRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresWebhookRepository(db *sql.DB) WebhookRepository {
+	return &PostgresWebhookRepository{db: db}
+}
+
+const webhookColumns = `id, url, COALESCE(description, ''), event_types, active, failure_count,
+	disabled_at, COALESCE(disabled_reason, ''), created_at, updated_at`
+
+func scanWebhook(s Scanner) (*models.WebhookSubscription, error) {
+	var sub models.WebhookSubscription
+	var events pq.StringArray
+	if err := s.Scan(&sub.ID, &sub.URL, &sub.Description, &events, &sub.Active, &sub.FailureCount,
+		&sub.DisabledAt, &sub.DisabledReason, &sub.CreatedAt, &sub.UpdatedAt); err != nil {
+		return nil, err
+	}
+	sub.Events = []string(events)
+	return &sub, nil
+}
+
+// Create grava a assinatura ativa. O segredo deve vir preenchido.
+func (r *PostgresWebhookRepository) Create(sub *models.WebhookSubscription) error {
+	sub.ID = ksuid.New().String()
+	sub.Active = true
+	return r.db.QueryRow(`
+		INSERT INTO webhook_subscriptions (id, url, description, event_types, secret)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5)
+		RETURNING created_at, updated_at`,
+		sub.ID, sub.URL, sub.Description, pq.Array(subjectsOrEmpty(sub.Events)), sub.Secret).
+		Scan(&sub.CreatedAt, &sub.UpdatedAt)
+}
+
+func (r *PostgresWebhookRepository) FindAll(limit, offset int) ([]models.WebhookSubscription, int, error) {
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM webhook_subscriptions`).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	rows, err := r.db.Query(`SELECT `+webhookColumns+` FROM webhook_subscriptions
+		ORDER BY created_at, id LIMIT $1 OFFSET $2`, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	subs := []models.WebhookSubscription{}
+	for rows.Next() {
+		sub, err := scanWebhook(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		subs = append(subs, *sub)
+	}
+	return subs, total, rows.Err()
+}
+
+func (r *PostgresWebhookRepository) FindByID(id string) (*models.WebhookSubscription, error) {
+	return scanWebhook(r.db.QueryRow(`SELECT `+webhookColumns+` FROM webhook_subscriptions WHERE id = $1`, id))
+}
+
+// Update altera URL, descrição, eventos e situação. Reativar uma assinatura
+// zera as falhas consecutivas; desativá-la manualmente não registra motivo.
+// Um segredo preenchido substitui o atual.
+func (r *PostgresWebhookRepository) Update(sub *models.WebhookSubscription) (int64, error) {
+	result, err := r.db.Exec(`
+		UPDATE webhook_subscriptions
+		SET url = $2, description = NULLIF($3, ''), event_types = $4, active = $5,
+			secret = COALESCE(NULLIF($6, ''), secret),
+			failure_count = CASE WHEN $5 AND NOT active THEN 0 ELSE failure_count END,
+			disabled_at = CASE WHEN $5 THEN NULL ELSE disabled_at END,
+			disabled_reason = CASE WHEN $5 THEN NULL ELSE disabled_reason END,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1`,
+		sub.ID, sub.URL, sub.Description, pq.Array(subjectsOrEmpty(sub.Events)), sub.Active, sub.Secret)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// Delete remove a assinatura com as suas entregas
+func (r *PostgresWebhookRepository) Delete(id string) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM webhook_subscriptions WHERE id = $1`, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// Enqueue cria uma entrega do evento para cada assinatura ativa interessada
+// no seu tipo. Repetir o mesmo evento não cria entregas duplicadas.
+func (r *PostgresWebhookRepository) Enqueue(event models.Event) (int64, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := r.db.Query(`
+		SELECT id FROM webhook_subscriptions
+		WHERE active AND (event_types = '{}' OR $1 = ANY(event_types))`, event.Type)
+	if err != nil {
+		return 0, err
+	}
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	var created int64
+	for _, id := range ids {
+		result, err := r.db.Exec(`
+			INSERT INTO webhook_deliveries (id, subscription_id, event_id, event_type, payload)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (subscription_id, event_id) DO NOTHING`,
+			ksuid.New().String(), id, event.ID, event.Type, string(payload))
+		if err != nil {
+			return created, err
+		}
+		n, _ := result.RowsAffected()
+		created += n
+	}
+	return created, nil
+}
+
+// EnqueuePing cria uma entrega de teste para a assinatura, mesmo desativada
+func (r *PostgresWebhookRepository) EnqueuePing(subscriptionID string) (*models.WebhookDelivery, error) {
+	now := time.Now()
+	payload, err := json.Marshal(models.Event{
+		Type:          models.EventWebhookPing,
+		AggregateType: "webhook",
+		AggregateID:   subscriptionID,
+		Payload:       json.RawMessage(`{}`),
+		OccurredAt:    now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	delivery := &models.WebhookDelivery{
+		ID:             ksuid.New().String(),
+		SubscriptionID: subscriptionID,
+		EventType:      models.EventWebhookPing,
+		Payload:        payload,
+		Status:         models.WebhookDeliveryPending,
+	}
+	err = r.db.QueryRow(`
+		INSERT INTO webhook_deliveries (id, subscription_id, event_type, payload)
+		VALUES ($1, $2, $3, $4)
+		RETURNING created_at, next_attempt_at`,
+		delivery.ID, subscriptionID, delivery.EventType, string(payload)).
+		Scan(&delivery.CreatedAt, &delivery.NextAttemptAt)
+	if err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+const deliveryColumns = `d.id, d.subscription_id, d.event_id, d.event_type, d.payload, d.status, d.attempts,
+	d.next_attempt_at, d.last_status_code, COALESCE(d.last_error, ''), d.created_at, d.delivered_at`
+
+func scanDelivery(s Scanner, extra ...interface{}) (*models.WebhookDelivery, error) {
+	var d models.WebhookDelivery
+	var eventID sql.NullInt64
+	var statusCode sql.NullInt64
+	var payload []byte
+	dest := []interface{}{&d.ID, &d.SubscriptionID, &eventID, &d.EventType, &payload, &d.Status, &d.Attempts,
+		&d.NextAttemptAt, &statusCode, &d.LastError, &d.CreatedAt, &d.DeliveredAt}
+	if err := s.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	d.Payload = payload
+	if eventID.Valid {
+		d.EventID = &eventID.Int64
+	}
+	if statusCode.Valid {
+		code := int(statusCode.Int64)
+		d.LastStatusCode = &code
+	}
+	return &d, nil
+}
+
+// FindDeliveries lista as entregas da assinatura, das mais recentes para as
+// mais antigas, com filtro opcional por situação (vazio para todas)
+func (r *PostgresWebhookRepository) FindDeliveries(subscriptionID, status string, limit, offset int) ([]models.WebhookDelivery, int, error) {
+	where := `WHERE d.subscription_id = $1 AND ($2 = '' OR d.status = $2)`
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM webhook_deliveries d `+where, subscriptionID, status).
+		Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	rows, err := r.db.Query(`SELECT `+deliveryColumns+` FROM webhook_deliveries d `+where+`
+		ORDER BY d.created_at DESC, d.id DESC LIMIT $3 OFFSET $4`, subscriptionID, status, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		deliveries = append(deliveries, *d)
+	}
+	return deliveries, total, rows.Err()
+}
+
+// FindDelivery busca uma entrega da assinatura com todas as tentativas
+func (r *PostgresWebhookRepository) FindDelivery(subscriptionID, deliveryID string) (*models.WebhookDelivery, error) {
+	d, err := scanDelivery(r.db.QueryRow(`SELECT `+deliveryColumns+` FROM webhook_deliveries d
+		WHERE d.id = $1 AND d.subscription_id = $2`, deliveryID, subscriptionID))
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.db.Query(`
+		SELECT id, delivery_id, attempted_at, status_code, COALESCE(error, ''), COALESCE(response_body, ''), duration_ms
+		FROM webhook_attempts WHERE delivery_id = $1
+		ORDER BY attempted_at, id`, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	d.AttemptLog = []models.WebhookAttempt{}
+	for rows.Next() {
+		var a models.WebhookAttempt
+		var statusCode sql.NullInt64
+		if err := rows.Scan(&a.ID, &a.DeliveryID, &a.AttemptedAt, &statusCode, &a.Error, &a.ResponseBody,
+			&a.DurationMs); err != nil {
+			return nil, err
+		}
+		if statusCode.Valid {
+			code := int(statusCode.Int64)
+			a.StatusCode = &code
+		}
+		d.AttemptLog = append(d.AttemptLog, a)
+	}
+	return d, rows.Err()
+}
+
+// Redeliver agenda uma nova tentativa imediata de uma entrega, qualquer que
+// seja a sua situação. As tentativas anteriores continuam no registro.
+func (r *PostgresWebhookRepository) Redeliver(subscriptionID, deliveryID string) (int64, error) {
+	result, err := r.db.Exec(`
+		UPDATE webhook_deliveries SET status = 'pending', next_attempt_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND subscription_id = $2`, deliveryID, subscriptionID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// Claim reserva até limit entregas pendentes e vencidas de assinaturas ativas,
+// adiando a próxima tentativa por lease para que outras instâncias não as
+// peguem enquanto a entrega está em andamento
+func (r *PostgresWebhookRepository) Claim(limit int, lease time.Duration) ([]models.WebhookDelivery, error) {
+	rows, err := r.db.Query(`
+		WITH due AS (
+			SELECT d.id FROM webhook_deliveries d
+			JOIN webhook_subscriptions s ON s.id = d.subscription_id
+			WHERE d.status = 'pending' AND d.next_attempt_at <= CURRENT_TIMESTAMP AND s.active
+			ORDER BY d.next_attempt_at, d.id
+			LIMIT $1
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		UPDATE webhook_deliveries d
+		SET next_attempt_at = CURRENT_TIMESTAMP + $2 * INTERVAL '1 millisecond'
+		FROM due, webhook_subscriptions s
+		WHERE d.id = due.id AND s.id = d.subscription_id
+		RETURNING `+deliveryColumns+`, s.url, s.secret`, limit, lease.Milliseconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		var url, secret string
+		d, err := scanDelivery(rows, &url, &secret)
+		if err != nil {
+			return nil, err
+		}
+		d.URL, d.Secret = url, secret
+		deliveries = append(deliveries, *d)
+	}
+	return deliveries, rows.Err()
+}
+
+// RecordAttempt grava a tentativa e o novo estado da entrega (Status,
+// Attempts, NextAttemptAt e DeliveredAt já atualizados pelo chamador) e
+// atualiza as falhas consecutivas da assinatura. Ao chegar a disableAfter
+// falhas, a assinatura é desativada e disabled é true.
+func (r *PostgresWebhookRepository) RecordAttempt(delivery *models.WebhookDelivery, attempt *models.WebhookAttempt, disableAfter int) (bool, error) {
+	disabled := false
+	err := RunInTx(r.db, func(tx *sql.Tx) error {
+		attempt.ID = ksuid.New().String()
+		attempt.DeliveryID = delivery.ID
+		if _, err := tx.Exec(`
+			INSERT INTO webhook_attempts (id, delivery_id, attempted_at, status_code, error, response_body, duration_ms)
+			VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''), $7)`,
+			attempt.ID, delivery.ID, attempt.AttemptedAt, attempt.StatusCode, attempt.Error, attempt.ResponseBody,
+			attempt.DurationMs); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`
+			UPDATE webhook_deliveries
+			SET status = $2, attempts = $3, next_attempt_at = $4, last_status_code = $5,
+				last_error = NULLIF($6, ''), delivered_at = $7
+			WHERE id = $1`,
+			delivery.ID, delivery.Status, delivery.Attempts, delivery.NextAttemptAt, attempt.StatusCode,
+			attempt.Error, delivery.DeliveredAt); err != nil {
+			return err
+		}
+		if delivery.Status == models.WebhookDeliverySucceeded {
+			_, err := tx.Exec(`UPDATE webhook_subscriptions SET failure_count = 0 WHERE id = $1`, delivery.SubscriptionID)
+			return err
+		}
+		return tx.QueryRow(`
+			UPDATE webhook_subscriptions
+			SET failure_count = failure_count + 1,
+				active = active AND failure_count + 1 < $2,
+				disabled_at = CASE WHEN active AND failure_count + 1 >= $2 THEN CURRENT_TIMESTAMP ELSE disabled_at END,
+				disabled_reason = CASE WHEN active AND failure_count + 1 >= $2
+					THEN 'Desativada após ' || (failure_count + 1) || ' falhas consecutivas' ELSE disabled_reason END
+			WHERE id = $1
+			RETURNING disabled_at IS NOT NULL AND NOT active AND failure_count = $2`,
+			delivery.SubscriptionID, disableAfter).Scan(&disabled)
+	})
+	return disabled, err
+}
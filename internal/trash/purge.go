@@ -0,0 +1,64 @@
+// Package trash cuida da limpeza periódica da lixeira de livros e gêneros.
+package trash
+
+import (
+	"context"
+	"fmt"
+	"log"
+	repositories "projeto_livros/internal/repository"
+	"time"
+)
+
+// PurgeJob apaga definitivamente, a cada interval, os itens que estão na
+// lixeira há mais de retention
+type PurgeJob struct {
+	trash     repositories.TrashRepository
+	retention time.Duration
+	interval  time.Duration
+	now       func() time.Time
+}
+
+// NewPurgeJob cria o job de limpeza da lixeira
+func NewPurgeJob(trash repositories.TrashRepository, retention, interval time.Duration) (*PurgeJob, error) {
+	if retention <= 0 {
+		return nil, fmt.Errorf("retenção da lixeira deve ser positiva: %s", retention)
+	}
+	if interval <= 0 {
+		return nil, fmt.Errorf("intervalo da limpeza da lixeira deve ser positivo: %s", interval)
+	}
+	return &PurgeJob{trash: trash, retention: retention, interval: interval, now: time.Now}, nil
+}
+
+// Retention devolve por quanto tempo os itens ficam na lixeira
+func (j *PurgeJob) Retention() time.Duration {
+	return j.retention
+}
+
+// Run executa a limpeza imediatamente e depois a cada intervalo, até ctx ser cancelado
+func (j *PurgeJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+	for {
+		if _, _, err := j.Purge(); err != nil {
+			log.Printf("Erro ao limpar a lixeira: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge apaga os itens removidos antes de agora menos a retenção e devolve
+// quantos livros e gêneros foram apagados
+func (j *PurgeJob) Purge() (int64, int64, error) {
+	books, genres, err := j.trash.Purge(j.now().Add(-j.retention))
+	if err != nil {
+		return 0, 0, err
+	}
+	if books > 0 || genres > 0 {
+		log.Printf("Lixeira: %d livros e %d gêneros apagados definitivamente", books, genres)
+	}
+	return books, genres, nil
+}
@@ -0,0 +1,50 @@
+package trash
+
+import (
+	"projeto_livros/internal/domain/models"
+	"testing"
+	"time"
+)
+
+type fakeTrashRepository struct {
+	before time.Time
+}
+
+func (f *fakeTrashRepository) FindAll(itemType string, limit, offset int) ([]models.TrashItem, int, error) {
+	return nil, 0, nil
+}
+
+func (f *fakeTrashRepository) Purge(before time.Time) (int64, int64, error) {
+	f.before = before
+	return 2, 1, nil
+}
+
+func TestPurgeUsesRetention(t *testing.T) {
+	repo := &fakeTrashRepository{}
+	job, err := NewPurgeJob(repo, 30*24*time.Hour, time.Hour)
+	if err != nil {
+		t.Fatalf("Erro ao criar job: %v", err)
+	}
+	now := time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC)
+	job.now = func() time.Time { return now }
+
+	books, genres, err := job.Purge()
+	if err != nil {
+		t.Fatalf("Erro na limpeza: %v", err)
+	}
+	if books != 2 || genres != 1 {
+		t.Errorf("apagados = %d livros, %d gêneros", books, genres)
+	}
+	if want := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC); !repo.before.Equal(want) {
+		t.Errorf("limite = %v, esperava %v", repo.before, want)
+	}
+}
+
+func TestNewPurgeJobRejectsInvalidDurations(t *testing.T) {
+	if _, err := NewPurgeJob(&fakeTrashRepository{}, 0, time.Hour); err == nil {
+		t.Error("esperava erro para retenção zero")
+	}
+	if _, err := NewPurgeJob(&fakeTrashRepository{}, time.Hour, 0); err == nil {
+		t.Error("esperava erro para intervalo zero")
+	}
+}
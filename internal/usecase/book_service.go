@@ -5,6 +5,7 @@ import (
 	"log"
 	"projeto_livros/internal/domain/errors"
 	"projeto_livros/internal/domain/models"
+	"projeto_livros/internal/domain/validators"
 
 	"github.com/segmentio/ksuid"
 )
@@ -26,15 +27,11 @@ func NewBookService(db *sql.DB) BookService {
 
 // No método CreateBook
 func (s *BookServiceImpl) CreateBook(book *models.Book) error {
-	if book.Title == "" {
-		return errors.NewBadRequestError("O título do livro é obrigatório")
+	if err := validators.NewBookSchema.Validate(book); err != nil {
+		return err
 	}
 	if book.Author == "" {
-		return errors.NewBadRequestError("O autor do livro é obrigatório")
-	}
-	// Garantir que a quantidade é um número válido
-	if book.Quantity <= 0 {
-		return errors.NewBadRequestError("A quantidade deve ser maior que zero")
+		return errors.New(errors.CodeBookAuthorRequired)
 	}
 
 	// Adicionar log para debug
@@ -47,7 +44,7 @@ func (s *BookServiceImpl) CreateBook(book *models.Book) error {
 			return err
 		}
 		if count == 0 {
-			return errors.NewBadRequestError("Gênero não encontrado")
+			return errors.New(errors.CodeGenreReferenceNotFound)
 		}
 	}
 	book.ID = ksuid.New().String()
@@ -96,7 +93,7 @@ func (s *BookServiceImpl) GetAllBooks(page, perPage int) ([]models.Book, int, er
 }
 func (s *BookServiceImpl) GetBookByID(id string) (*models.Book, error) {
 	if id == "" {
-		return nil, errors.NewBadRequestError("ID não fornecido")
+		return nil, errors.New(errors.CodeBookIDRequired)
 	}
 	var book models.Book
 	err := s.db.QueryRow("SELECT id, name, author, quantity, genre_id FROM livros WHERE id = $1", id).
@@ -105,7 +102,7 @@ func (s *BookServiceImpl) GetBookByID(id string) (*models.Book, error) {
 	// Como title não existe na tabela, vamos usar o valor de name
 	book.Title = book.Name
 	if err == sql.ErrNoRows {
-		return nil, errors.NewNotFoundError("Livro não encontrado")
+		return nil, errors.New(errors.CodeBookNotFound)
 	} else if err != nil {
 		return nil, err
 	}
@@ -115,16 +112,13 @@ func (s *BookServiceImpl) GetBookByID(id string) (*models.Book, error) {
 // No método UpdateBook
 func (s *BookServiceImpl) UpdateBook(book *models.Book) error {
 	if book.ID == "" {
-		return errors.NewBadRequestError("ID não fornecido")
+		return errors.New(errors.CodeBookIDRequired)
 	}
-	if book.Title == "" {
-		return errors.NewBadRequestError("O título do livro é obrigatório")
+	if err := validators.BookSchema.Validate(book); err != nil {
+		return err
 	}
 	if book.Author == "" {
-		return errors.NewBadRequestError("O autor do livro é obrigatório")
-	}
-	if book.Quantity <= 0 {
-		return errors.NewBadRequestError("A quantidade deve ser maior que zero")
+		return errors.New(errors.CodeBookAuthorRequired)
 	}
 
 	// Log para debug da quantidade
@@ -136,7 +130,7 @@ func (s *BookServiceImpl) UpdateBook(book *models.Book) error {
 		return err
 	}
 	if !exists {
-		return errors.NewNotFoundError("Livro não encontrado")
+		return errors.New(errors.CodeBookNotFound)
 	}
 
 	// Verificar quantidade atual para comparação
@@ -178,7 +172,7 @@ func (s *BookServiceImpl) UpdateBook(book *models.Book) error {
 }
 func (s *BookServiceImpl) DeleteBook(id string) error {
 	if id == "" {
-		return errors.NewBadRequestError("ID não fornecido")
+		return errors.New(errors.CodeBookIDRequired)
 	}
 	result, err := s.db.Exec("DELETE FROM livros WHERE id = $1", id)
 	if err != nil {
@@ -189,7 +183,7 @@ func (s *BookServiceImpl) DeleteBook(id string) error {
 		return err
 	}
 	if rowsAffected == 0 {
-		return errors.NewNotFoundError("Livro não encontrado")
+		return errors.New(errors.CodeBookNotFound)
 	}
 	return nil
 }
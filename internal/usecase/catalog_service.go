@@ -0,0 +1,754 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"projeto_livros/internal/domain/errors"
+	"projeto_livros/internal/domain/models"
+	"projeto_livros/internal/domain/validators"
+	repositories "projeto_livros/internal/repository"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/segmentio/ksuid"
+)
+
+// CatalogService reúne as regras de leitura e escrita de livros e gêneros
+// compartilhadas pelas APIs REST, GraphQL e gRPC. Os erros de validação e de
+// conflito são errors.APIError; os demais vêm do banco e devem ser tratados
+// como erro interno. As escritas registram a revisão do livro e os eventos do
+// outbox na mesma transação, em nome do actor informado.
+type CatalogService struct {
+	db        *sql.DB
+	books     repositories.BookRepository
+	authors   repositories.AuthorRepository
+	genres    repositories.GenreRepository
+	revisions repositories.RevisionRepository
+	outbox    repositories.OutboxRepository
+}
+
+func NewCatalogService(db *sql.DB) *CatalogService {
+	return &CatalogService{
+		db:        db,
+		books:     repositories.NewPostgresBookRepository(db),
+		authors:   repositories.NewPostgresAuthorRepository(db),
+		genres:    repositories.NewPostgresGenreRepository(db),
+		revisions: repositories.NewPostgresRevisionRepository(db),
+		outbox:    repositories.NewPostgresOutboxRepository(db),
+	}
+}
+
+// BookRelations são os autores e gêneros a gravar junto com o livro. Um campo
+// nil mantém os vínculos atuais.
+type BookRelations struct {
+	Authors  []models.BookAuthor
+	GenreIDs []string
+}
+
+// BookAuthorsInput devolve os autores informados para o livro: a lista
+// "authors" quando presente ou, para clientes antigos, o campo "author" como
+// autor único. Devolve nil se nenhum dos dois foi informado.
+func BookAuthorsInput(book *models.Book) ([]models.BookAuthor, error) {
+	if len(book.Authors) > 0 {
+		return validators.ValidateBookAuthors(book.Authors)
+	}
+	if name, _ := validators.NormalizeAuthorName(book.Author); name != "" {
+		return []models.BookAuthor{{Name: name, Role: models.RoleAuthor}}, nil
+	}
+	return nil, nil
+}
+
+// BookGenresInput devolve os ids dos gêneros informados para o livro, sem
+// repetições: o genre_id (quando presente) seguido da lista "genres". O
+// primeiro passa a ser o genre_id do livro. Devolve nil se nenhum foi informado.
+func BookGenresInput(book *models.Book) []string {
+	var ids []string
+	seen := map[string]bool{}
+	add := func(id string) {
+		id = strings.TrimSpace(id)
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if book.GenreID != nil {
+		add(*book.GenreID)
+	}
+	for _, g := range book.Genres {
+		add(g.ID)
+	}
+	if len(ids) == 0 {
+		book.GenreID = nil
+		return nil
+	}
+	book.GenreID = &ids[0]
+	return ids
+}
+
+// GenresExist verifica se todos os gêneros informados existem
+func (s *CatalogService) GenresExist(ids []string) (bool, error) {
+	if len(ids) == 0 {
+		return true, nil
+	}
+	var count int
+	err := s.db.QueryRow("SELECT COUNT(*) FROM genres WHERE id = ANY($1) AND deleted_at IS NULL", pq.Array(ids)).Scan(&count)
+	return count == len(ids), err
+}
+
+// FindBookByISBN devolve o id do livro que já usa o ISBN informado, ignorando
+// excludeID (o próprio livro, em atualizações). Devolve "" se não houver.
+func (s *CatalogService) FindBookByISBN(isbn, excludeID string) (string, error) {
+	if isbn == "" {
+		return "", nil
+	}
+	existing, err := s.books.FindByISBN(isbn)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if existing.ID == excludeID {
+		return "", nil
+	}
+	return existing.ID, nil
+}
+
+// SaveBook grava o livro (criação ou atualização) e substitui, na mesma
+// transação, os autores e gêneros informados em rel. Os campos Author e
+// GenreID do livro passam a refletir os vínculos gravados. A revisão do
+// livro é registrada na mesma transação, em nome de actor. Não valida o
+// livro: quem chama deve ter feito as verificações de CreateBook/UpdateBook.
+func (s *CatalogService) SaveBook(book *models.Book, create bool, rel BookRelations, actor string) (int64, error) {
+	var rowsAffected int64
+	err := repositories.RunInTx(s.db, func(tx *sql.Tx) error {
+		var err error
+		rowsAffected, err = s.saveBook(tx, book, create, rel, actor)
+		return err
+	})
+	return rowsAffected, err
+}
+
+// saveBook faz a gravação de SaveBook na transação tx
+func (s *CatalogService) saveBook(tx *sql.Tx, book *models.Book, create bool, rel BookRelations, actor string) (int64, error) {
+	books := s.books.WithTx(tx)
+	var quantityBefore int
+	if create {
+		if err := books.Create(book); err != nil {
+			return 0, err
+		}
+	} else {
+		var err error
+		quantityBefore, err = books.LockQuantity(book.ID)
+		if err == sql.ErrNoRows {
+			return 0, nil
+		} else if err != nil {
+			return 0, err
+		}
+		affected, err := books.Update(book)
+		if err != nil || affected == 0 {
+			return affected, err
+		}
+	}
+	if rel.GenreIDs != nil {
+		genres, err := s.genres.WithTx(tx).SetBookGenres(book.ID, rel.GenreIDs)
+		if err != nil {
+			return 0, err
+		}
+		book.Genres = genres
+	}
+	if rel.Authors != nil {
+		resolved, display, err := s.authors.WithTx(tx).SetBookAuthors(book.ID, rel.Authors)
+		if err != nil {
+			return 0, err
+		}
+		book.Authors = resolved
+		book.Author = display
+	}
+	revisions := s.revisions.WithTx(tx)
+	var err error
+	if create {
+		_, err = revisions.Record(book.ID, models.RevisionCreate, actor, nil)
+	} else {
+		_, err = revisions.RecordStockChange(book.ID, actor, quantityBefore)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+// ListBooks busca os livros pelo filtro, com o total para a paginação
+func (s *CatalogService) ListBooks(filter models.BookFilter, limit, offset int) ([]models.Book, int, error) {
+	if _, ok := map[string]bool{"": true, "name": true, "quantity": true, "publication_year": true}[filter.SortField]; !ok {
+		return nil, 0, errors.New(errors.CodeInvalidSort, "name, quantity, publication_year")
+	}
+	return s.books.Search(filter, limit, offset)
+}
+
+// GetBook busca um livro com seus autores e gêneros
+func (s *CatalogService) GetBook(id string) (*models.Book, error) {
+	book, err := s.books.FindByID(id)
+	if err == sql.ErrNoRows {
+		return nil, errors.New(errors.CodeBookNotFound)
+	} else if err != nil {
+		return nil, err
+	}
+	if book.Authors, err = s.authors.FindBookAuthors(book.ID); err != nil {
+		return nil, err
+	}
+	if book.Genres, err = s.genres.FindBookGenres(book.ID); err != nil {
+		return nil, err
+	}
+	return book, nil
+}
+
+// validateBook faz as verificações comuns à criação e à atualização que
+// dependem do banco: ISBN (normalizado e único) e existência dos gêneros. Os
+// campos já foram conferidos por NewBookSchema ou BookSchema.
+func (s *CatalogService) validateBook(book *models.Book, genreIDs []string) error {
+	if err := validators.NormalizeBookISBN(book); err != nil {
+		return err
+	}
+	existingID, err := s.FindBookByISBN(book.ISBN, book.ID)
+	if err != nil {
+		return err
+	}
+	if existingID != "" {
+		return &ISBNConflictError{ExistingID: existingID}
+	}
+	ok, err := s.GenresExist(genreIDs)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New(errors.CodeGenreReferenceNotFound)
+	}
+	return nil
+}
+
+// ISBNConflictError é o ISBN_CONFLICT de um livro, com o id do livro que já
+// usa o ISBN quando ele é conhecido. Desembrulha para o errors.APIError do
+// código.
+type ISBNConflictError struct {
+	ExistingID string
+}
+
+func (e *ISBNConflictError) Error() string {
+	return e.Unwrap().Error()
+}
+
+func (e *ISBNConflictError) Unwrap() error {
+	return errors.New(errors.CodeISBNConflict)
+}
+
+// saveBookError converte os erros de SaveBook que são causados pelos dados
+// informados em book
+func (s *CatalogService) saveBookError(book *models.Book, err error) error {
+	if repositories.IsUniqueViolation(err) {
+		// Outra requisição pode ter gravado o mesmo ISBN depois de validateBook
+		existingID, _ := s.FindBookByISBN(book.ISBN, book.ID)
+		return &ISBNConflictError{ExistingID: existingID}
+	}
+	if err == repositories.ErrAuthorNotFound {
+		return errors.New(errors.CodeAuthorReferenceNotFound)
+	}
+	if err == repositories.ErrGenreNotFound {
+		return errors.New(errors.CodeGenreReferenceNotFound)
+	}
+	return err
+}
+
+// CreateBook valida e grava um livro novo, com os autores (Authors ou Author)
+// e gêneros (GenreID e Genres) informados nele
+func (s *CatalogService) CreateBook(book *models.Book, actor string) error {
+	book.ID = ""
+	if err := validators.NewBookSchema.Validate(book); err != nil {
+		return err
+	}
+	authors, err := BookAuthorsInput(book)
+	if err != nil {
+		return err
+	}
+	genres := BookGenresInput(book)
+	if err := s.validateBook(book, genres); err != nil {
+		return err
+	}
+	book.ID = ksuid.New().String()
+	if _, err := s.SaveBook(book, true, BookRelations{Authors: authors, GenreIDs: genres}, actor); err != nil {
+		return s.saveBookError(book, err)
+	}
+	book.Title = book.Name
+	return nil
+}
+
+// DuplicateISBN é um ISBN repetido no livro Index de um lote: no próprio lote
+// (sem ExistingID) ou já usado pelo livro ExistingID
+type DuplicateISBN struct {
+	Index      int    `json:"index"`
+	ISBN       string `json:"isbn"`
+	ExistingID string `json:"existing_id,omitempty"`
+}
+
+// BatchISBNConflictError interrompe um lote com ISBNs repetidos. Desembrulha
+// para o errors.APIError do código BATCH_ISBN_CONFLICT.
+type BatchISBNConflictError struct {
+	Duplicates []DuplicateISBN
+}
+
+func (e *BatchISBNConflictError) Error() string {
+	return e.Unwrap().Error()
+}
+
+func (e *BatchISBNConflictError) Unwrap() error {
+	return errors.New(errors.CodeBatchISBNConflict)
+}
+
+// indexedViolations converte o erro de validação do livro i de um lote em
+// violações com o índice no caminho; um erro sem violações de campo fica em
+// field
+func indexedViolations(i int, field string, err error) []errors.FieldError {
+	apiErr, ok := err.(errors.APIError)
+	if !ok {
+		apiErr = errors.New(errors.CodeInternalError)
+	}
+	if len(apiErr.Fields) == 0 {
+		return []errors.FieldError{errors.NewFieldError(fmt.Sprintf("[%d].%s", i, field), "body", apiErr.Code+".detail", apiErr.Args...)}
+	}
+	fields := make([]errors.FieldError, len(apiErr.Fields))
+	for j, f := range apiErr.Fields {
+		f.Field = fmt.Sprintf("[%d].%s", i, f.Field)
+		fields[j] = f
+	}
+	return fields
+}
+
+// CreateBooks valida e grava um lote de livros novos em uma só transação. As
+// violações de todos os livros vêm juntas em um VALIDATION_FAILED, com o
+// índice no caminho ([1].name); ISBNs repetidos no lote ou já usados no banco
+// recusam o lote com *BatchISBNConflictError. Se algum livro for recusado ou
+// a gravação falhar, nenhum livro é gravado.
+func (s *CatalogService) CreateBooks(books []models.Book, actor string) error {
+	var violations []errors.FieldError
+	duplicates := []DuplicateISBN{}
+	relations := make([]BookRelations, len(books))
+	seenISBN := map[string]bool{}
+	for i := range books {
+		book := &books[i]
+		book.ID = ""
+		isbnErr := validators.NormalizeBookISBN(book)
+		if isbnErr != nil {
+			violations = append(violations, indexedViolations(i, "isbn", isbnErr)...)
+		}
+		for _, field := range validators.NewBookSchema.Check(book) {
+			field.Field = fmt.Sprintf("[%d].%s", i, field.Field)
+			violations = append(violations, field)
+		}
+		authors, err := BookAuthorsInput(book)
+		if err != nil {
+			violations = append(violations, indexedViolations(i, "authors", err)...)
+		}
+		genres := BookGenresInput(book)
+		ok, err := s.GenresExist(genres)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			violations = append(violations, indexedViolations(i, "genres", errors.New(errors.CodeGenreReferenceNotFound))...)
+		}
+		relations[i] = BookRelations{Authors: authors, GenreIDs: genres}
+
+		if isbnErr != nil || book.ISBN == "" {
+			continue
+		}
+		if seenISBN[book.ISBN] {
+			duplicates = append(duplicates, DuplicateISBN{Index: i, ISBN: book.ISBN})
+			continue
+		}
+		seenISBN[book.ISBN] = true
+		existingID, err := s.FindBookByISBN(book.ISBN, "")
+		if err != nil {
+			return err
+		}
+		if existingID != "" {
+			duplicates = append(duplicates, DuplicateISBN{Index: i, ISBN: book.ISBN, ExistingID: existingID})
+		}
+	}
+	if len(violations) > 0 {
+		return errors.NewValidationError(violations)
+	}
+	if len(duplicates) > 0 {
+		return &BatchISBNConflictError{Duplicates: duplicates}
+	}
+
+	var failed *models.Book
+	err := repositories.RunInTx(s.db, func(tx *sql.Tx) error {
+		for i := range books {
+			failed = &books[i]
+			failed.ID = ksuid.New().String()
+			if _, err := s.saveBook(tx, failed, true, relations[i], actor); err != nil {
+				return err
+			}
+			failed.Title = failed.Name
+		}
+		return nil
+	})
+	if err != nil {
+		return s.saveBookError(failed, err)
+	}
+	return nil
+}
+
+// UpdateBook grava o novo estado de um livro existente. Os vínculos são
+// substituídos apenas quando informados em rel; com rel.GenreIDs, o primeiro
+// passa a ser o gênero principal.
+func (s *CatalogService) UpdateBook(book *models.Book, rel BookRelations, actor string) error {
+	if err := validators.BookSchema.Validate(book); err != nil {
+		return err
+	}
+	if rel.Authors != nil {
+		authors, err := validators.ValidateBookAuthors(rel.Authors)
+		if err != nil {
+			return err
+		}
+		rel.Authors = authors
+	}
+	if rel.GenreIDs != nil {
+		book.GenreID, book.Genres = nil, nil
+		for _, id := range rel.GenreIDs {
+			book.Genres = append(book.Genres, models.BookGenre{ID: id})
+		}
+		if rel.GenreIDs = BookGenresInput(book); rel.GenreIDs == nil {
+			rel.GenreIDs = []string{}
+		}
+	}
+	if err := s.validateBook(book, rel.GenreIDs); err != nil {
+		return err
+	}
+	rowsAffected, err := s.SaveBook(book, false, rel, actor)
+	if err != nil {
+		return s.saveBookError(book, err)
+	}
+	if rowsAffected == 0 {
+		return errors.New(errors.CodeBookNotFound)
+	}
+	book.Title = book.Name
+	return nil
+}
+
+// DeleteBook move o livro para a lixeira ou, com hard, apaga definitivamente,
+// inclusive livros que já estão na lixeira. A permissão para hard é
+// verificada por quem chama.
+func (s *CatalogService) DeleteBook(id string, hard bool, actor string) error {
+	var rowsAffected int64
+	err := repositories.RunInTx(s.db, func(tx *sql.Tx) error {
+		books := s.books.WithTx(tx)
+		revisions := s.revisions.WithTx(tx)
+		var err error
+		if hard {
+			// O estado final fica no histórico, que sobrevive à remoção definitiva
+			if _, err = revisions.Record(id, models.RevisionDelete, actor, nil); err != nil {
+				return err
+			}
+			rowsAffected, err = books.HardDelete(id)
+			return err
+		}
+		if rowsAffected, err = books.Delete(id); err != nil || rowsAffected == 0 {
+			return err
+		}
+		_, err = revisions.Record(id, models.RevisionDelete, actor, nil)
+		return err
+	})
+	if err == sql.ErrNoRows || (err == nil && rowsAffected == 0) {
+		return errors.New(errors.CodeBookNotFound)
+	}
+	if repositories.IsForeignKeyViolation(err) {
+		return errors.New(errors.CodeBookHasOrders)
+	}
+	return err
+}
+
+// AdjustStock soma delta (positivo ou negativo) ao estoque do livro e devolve
+// as quantidades antes e depois do ajuste. O estoque não pode ficar negativo.
+func (s *CatalogService) AdjustStock(id string, delta int, actor string) (previous, quantity int, err error) {
+	if delta == 0 {
+		return 0, 0, errors.New(errors.CodeStockDeltaZero)
+	}
+	err = repositories.RunInTx(s.db, func(tx *sql.Tx) error {
+		var err error
+		if previous, err = s.lockQuantity(tx, id); err != nil {
+			return err
+		}
+		quantity = previous + delta
+		if quantity < 0 {
+			return errors.New(errors.CodeStockUnderflow, previous)
+		}
+		return s.writeStock(tx, id, previous, quantity, actor)
+	})
+	return previous, quantity, err
+}
+
+// SetStock define a quantidade em estoque de um livro, para as rotas que
+// recebem o valor final em vez da variação. Como AdjustStock, grava a revisão
+// e os eventos na mesma transação da escrita e devolve a quantidade anterior.
+func (s *CatalogService) SetStock(id string, quantity int, actor string) (previous int, err error) {
+	if quantity < 0 {
+		return 0, errors.New(errors.CodeQuantityNegative)
+	}
+	err = repositories.RunInTx(s.db, func(tx *sql.Tx) error {
+		var err error
+		if previous, err = s.lockQuantity(tx, id); err != nil {
+			return err
+		}
+		return s.writeStock(tx, id, previous, quantity, actor)
+	})
+	return previous, err
+}
+
+// lockQuantity bloqueia o livro na transação e devolve a quantidade atual
+func (s *CatalogService) lockQuantity(tx *sql.Tx, id string) (int, error) {
+	quantity, err := s.books.WithTx(tx).LockQuantity(id)
+	if err == sql.ErrNoRows {
+		return 0, errors.New(errors.CodeBookNotFound)
+	}
+	return quantity, err
+}
+
+// writeStock grava a nova quantidade e a revisão com o StockChanged
+func (s *CatalogService) writeStock(tx *sql.Tx, id string, previous, quantity int, actor string) error {
+	if _, err := tx.Exec("UPDATE livros SET quantity = $1 WHERE id = $2", quantity, id); err != nil {
+		return err
+	}
+	_, err := s.revisions.WithTx(tx).RecordStockChange(id, actor, previous)
+	return err
+}
+
+// ListGenres lista os gêneros fora da lixeira, pelo nome
+func (s *CatalogService) ListGenres() ([]models.Genre, error) {
+	return s.genres.FindAll()
+}
+
+// GetGenre busca um gênero fora da lixeira
+func (s *CatalogService) GetGenre(id string) (*models.Genre, error) {
+	genre, err := s.genres.FindByID(id)
+	if err == sql.ErrNoRows {
+		return nil, errors.New(errors.CodeGenreNotFound)
+	}
+	return genre, err
+}
+
+// validateGenre normaliza o nome e verifica se o gênero pai existe e não cria
+// um ciclo na hierarquia (o pai não pode ser o próprio gênero nem um de seus
+// subgêneros)
+func (s *CatalogService) validateGenre(genre *models.Genre) error {
+	if err := validators.GenreSchema.Validate(genre); err != nil {
+		return err
+	}
+	if genre.ParentID == nil {
+		return nil
+	}
+	if _, err := s.genres.FindByID(*genre.ParentID); err == sql.ErrNoRows {
+		return errors.New(errors.CodeParentGenreNotFound)
+	} else if err != nil {
+		return err
+	}
+	if genre.ID == "" {
+		return nil
+	}
+	cycle, err := s.genres.IsDescendant(*genre.ParentID, genre.ID)
+	if err != nil {
+		return err
+	}
+	if cycle {
+		return errors.New(errors.CodeGenreCycle)
+	}
+	return nil
+}
+
+// addGenreEvent grava no outbox, na transação tx, um evento do gênero
+func (s *CatalogService) addGenreEvent(tx *sql.Tx, actor, eventType string, payload models.GenreEventPayload) error {
+	return s.outbox.WithTx(tx).Add(eventType, models.AggregateGenre, payload.Genre.ID, actor, payload)
+}
+
+// genreNameConflict é devolvido quando o nome já é usado, inclusive na lixeira
+var genreNameConflict = errors.New(errors.CodeGenreNameConflict)
+
+// CreateGenre valida e grava um gênero novo
+func (s *CatalogService) CreateGenre(genre *models.Genre, actor string) error {
+	genre.ID = ""
+	if err := s.validateGenre(genre); err != nil {
+		return err
+	}
+	genre.ID = ksuid.New().String()
+	err := repositories.RunInTx(s.db, func(tx *sql.Tx) error {
+		_, err := tx.Exec("INSERT INTO genres (id, name, description, parent_id) VALUES ($1, $2, $3, $4)",
+			genre.ID, genre.Name, genre.Description, genre.ParentID)
+		if err != nil {
+			return err
+		}
+		return s.addGenreEvent(tx, actor, models.EventGenreCreated, models.GenreEventPayload{Genre: *genre})
+	})
+	if repositories.IsUniqueViolation(err) {
+		return genreNameConflict
+	}
+	return err
+}
+
+// UpdateGenre grava o novo estado de um gênero existente
+func (s *CatalogService) UpdateGenre(genre *models.Genre, actor string) error {
+	if err := s.validateGenre(genre); err != nil {
+		return err
+	}
+	var rowsAffected int64
+	err := repositories.RunInTx(s.db, func(tx *sql.Tx) error {
+		var err error
+		if rowsAffected, err = s.genres.WithTx(tx).Update(genre); err != nil || rowsAffected == 0 {
+			return err
+		}
+		return s.addGenreEvent(tx, actor, models.EventGenreUpdated, models.GenreEventPayload{Genre: *genre})
+	})
+	if repositories.IsUniqueViolation(err) {
+		return genreNameConflict
+	}
+	if err == nil && rowsAffected == 0 {
+		return errors.New(errors.CodeGenreNotFound)
+	}
+	return err
+}
+
+// GenreInUseError interrompe a remoção de um gênero com livros sem reassignTo
+type GenreInUseError struct {
+	Books int
+}
+
+func (e *GenreInUseError) Error() string {
+	return "O gênero possui livros. Informe reassign_to para transferi-los antes de remover"
+}
+
+// DeleteGenre remove um gênero. Se houver livros com o gênero, a remoção é
+// recusada com *GenreInUseError, a menos que reassignTo indique o gênero que
+// os receberá. Os subgêneros passam a ser filhos do pai do gênero removido.
+// Com hard (verificado por quem chama), o gênero é apagado definitivamente,
+// mesmo que já esteja na lixeira. Devolve quantos livros foram transferidos.
+func (s *CatalogService) DeleteGenre(id, reassignTo string, hard bool, actor string) (int64, error) {
+	genre, err := s.genres.FindByID(id)
+	if err == sql.ErrNoRows && hard {
+		// Um gênero na lixeira já não tem livros nem subgêneros
+		return 0, s.hardDeleteTrashedGenre(id, actor)
+	} else if err == sql.ErrNoRows {
+		return 0, errors.New(errors.CodeGenreNotFound)
+	} else if err != nil {
+		return 0, err
+	}
+	if reassignTo == id {
+		return 0, errors.New(errors.CodeReassignSameGenre)
+	}
+	if reassignTo != "" {
+		if _, err := s.genres.FindByID(reassignTo); err == sql.ErrNoRows {
+			return 0, errors.New(errors.CodeReassignTargetNotFound)
+		} else if err != nil {
+			return 0, err
+		}
+	}
+
+	var moved int64
+	err = repositories.RunInTx(s.db, func(tx *sql.Tx) error {
+		genres := s.genres.WithTx(tx)
+		booksInUse, err := genres.CountBooks(id)
+		if err != nil {
+			return err
+		}
+		if booksInUse > 0 && reassignTo == "" {
+			return &GenreInUseError{Books: booksInUse}
+		}
+		if reassignTo != "" {
+			bookIDs, err := genres.MoveBooks(id, reassignTo)
+			if err != nil {
+				return err
+			}
+			moved = int64(len(bookIDs))
+			if err := s.recordGenreChange(tx, bookIDs, actor); err != nil {
+				return err
+			}
+		}
+		if err := genres.MoveChildren(id, genre.ParentID); err != nil {
+			return err
+		}
+		if hard {
+			_, err = genres.HardDelete(id)
+		} else {
+			_, err = genres.Delete(id)
+		}
+		if err != nil {
+			return err
+		}
+		return s.addGenreEvent(tx, actor, models.EventGenreDeleted,
+			models.GenreEventPayload{Genre: *genre, Hard: hard, MovedBooks: moved})
+	})
+	return moved, err
+}
+
+// recordGenreChange registra, na transação tx, a revisão (com o BookUpdated)
+// de cada livro que mudou de gênero
+func (s *CatalogService) recordGenreChange(tx *sql.Tx, bookIDs []string, actor string) error {
+	revisions := s.revisions.WithTx(tx)
+	for _, id := range bookIDs {
+		if _, err := revisions.Record(id, models.RevisionUpdate, actor, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hardDeleteTrashedGenre apaga definitivamente um gênero que já está na lixeira
+func (s *CatalogService) hardDeleteTrashedGenre(id, actor string) error {
+	var rowsAffected int64
+	err := repositories.RunInTx(s.db, func(tx *sql.Tx) error {
+		var err error
+		if rowsAffected, err = s.genres.WithTx(tx).HardDelete(id); err != nil || rowsAffected == 0 {
+			return err
+		}
+		return s.addGenreEvent(tx, actor, models.EventGenreDeleted,
+			models.GenreEventPayload{Genre: models.Genre{ID: id}, Hard: true})
+	})
+	if repositories.IsForeignKeyViolation(err) {
+		return errors.New(errors.CodeGenreReferenced)
+	}
+	if err == nil && rowsAffected == 0 {
+		return errors.New(errors.CodeGenreNotFound)
+	}
+	return err
+}
+
+// As consultas em lote abaixo atendem vários livros ou gêneros de uma vez e
+// evitam uma consulta por item ao montar respostas aninhadas
+
+// AuthorsByBooks lista os autores de cada livro
+func (s *CatalogService) AuthorsByBooks(bookIDs []string) (map[string][]models.BookAuthor, error) {
+	return s.authors.FindByBooks(bookIDs)
+}
+
+// GenresByBooks lista os gêneros de cada livro, o principal primeiro
+func (s *CatalogService) GenresByBooks(bookIDs []string) (map[string][]models.Genre, error) {
+	return s.genres.FindByBooks(bookIDs)
+}
+
+// GenresByIDs busca vários gêneros pelo id
+func (s *CatalogService) GenresByIDs(ids []string) (map[string]models.Genre, error) {
+	return s.genres.FindByIDs(ids)
+}
+
+// GenreChildren lista os subgêneros diretos de cada gênero
+func (s *CatalogService) GenreChildren(parentIDs []string) (map[string][]models.Genre, error) {
+	return s.genres.FindChildren(parentIDs)
+}
+
+// BooksByGenres lista até limit livros de cada gênero
+func (s *CatalogService) BooksByGenres(genreIDs []string, limit int) (map[string][]models.Book, error) {
+	return s.books.FindByGenres(genreIDs, limit)
+}
+
+// BookCountsByGenres conta os livros de cada gênero
+func (s *CatalogService) BookCountsByGenres(genreIDs []string) (map[string]int, error) {
+	return s.books.CountByGenres(genreIDs)
+}
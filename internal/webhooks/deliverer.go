@@ -0,0 +1,179 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"projeto_livros/internal/domain/models"
+	"strconv"
+	"time"
+)
+
+const (
+	// deliveryBatchSize é o número máximo de entregas reservadas por vez
+	deliveryBatchSize = 50
+	// claimLease é por quanto tempo uma entrega reservada fica fora da fila
+	claimLease = time.Minute
+	// maxResponseBody limita o trecho da resposta guardado no registro
+	maxResponseBody = 1024
+)
+
+// Store é a parte do repositório de webhooks usada nas entregas
+type Store interface {
+	Enqueue(event models.Event) (int64, error)
+	Claim(limit int, lease time.Duration) ([]models.WebhookDelivery, error)
+	RecordAttempt(delivery *models.WebhookDelivery, attempt *models.WebhookAttempt, disableAfter int) (bool, error)
+}
+
+// Deliverer envia as entregas pendentes. Uma entrega que falha (erro de rede
+// ou resposta fora de 2xx) é repetida com espera exponencial, de BaseDelay
+// até MaxDelay, e marcada como failed após MaxAttempts tentativas. A
+// assinatura é desativada após DisableAfter falhas consecutivas.
+type Deliverer struct {
+	store        Store
+	client       *http.Client
+	interval     time.Duration
+	MaxAttempts  int
+	DisableAfter int
+	BaseDelay    time.Duration
+	MaxDelay     time.Duration
+	now          func() time.Time
+}
+
+// NewDeliverer cria o entregador, que busca entregas pendentes a cada interval
+func NewDeliverer(store Store, interval time.Duration, maxAttempts, disableAfter int) *Deliverer {
+	return &Deliverer{
+		store:        store,
+		client:       &http.Client{Timeout: 10 * time.Second},
+		interval:     interval,
+		MaxAttempts:  maxAttempts,
+		DisableAfter: disableAfter,
+		BaseDelay:    30 * time.Second,
+		MaxDelay:     time.Hour,
+		now:          time.Now,
+	}
+}
+
+// Send cria as entregas de um evento do outbox para as assinaturas
+// interessadas; permite usar o Deliverer como destino do despachante
+func (d *Deliverer) Send(ctx context.Context, event models.Event) error {
+	_, err := d.store.Enqueue(event)
+	return err
+}
+
+// Run envia as entregas pendentes a cada intervalo, até ctx ser cancelado
+func (d *Deliverer) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := d.DeliverDue(ctx); err != nil {
+				log.Printf("Erro ao enviar webhooks: %v", err)
+			}
+		}
+	}
+}
+
+// DeliverDue faz uma tentativa de cada entrega vencida e devolve quantas
+// foram bem-sucedidas
+func (d *Deliverer) DeliverDue(ctx context.Context) (int, error) {
+	deliveries, err := d.store.Claim(deliveryBatchSize, claimLease)
+	if err != nil {
+		return 0, err
+	}
+	succeeded := 0
+	for i := range deliveries {
+		if ctx.Err() != nil {
+			break
+		}
+		ok, err := d.Deliver(ctx, &deliveries[i])
+		if err != nil {
+			log.Printf("Erro ao registrar entrega de webhook %s: %v", deliveries[i].ID, err)
+			continue
+		}
+		if ok {
+			succeeded++
+		}
+	}
+	return succeeded, nil
+}
+
+// Deliver faz uma tentativa de entrega e registra o resultado
+func (d *Deliverer) Deliver(ctx context.Context, delivery *models.WebhookDelivery) (bool, error) {
+	attempt := d.post(ctx, delivery)
+	delivery.Attempts++
+	ok := attempt.Error == ""
+	switch {
+	case ok:
+		delivery.Status = models.WebhookDeliverySucceeded
+		delivery.DeliveredAt = &attempt.AttemptedAt
+		delivery.NextAttemptAt = nil
+	case delivery.Attempts >= d.MaxAttempts:
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.NextAttemptAt = nil
+	default:
+		delivery.Status = models.WebhookDeliveryPending
+		next := attempt.AttemptedAt.Add(d.retryDelay(delivery.Attempts))
+		delivery.NextAttemptAt = &next
+	}
+	disabled, err := d.store.RecordAttempt(delivery, attempt, d.DisableAfter)
+	if err != nil {
+		return false, err
+	}
+	if disabled {
+		log.Printf("Webhook %s desativado após %d falhas consecutivas", delivery.SubscriptionID, d.DisableAfter)
+	}
+	return ok, nil
+}
+
+// post envia a entrega assinada e descreve o resultado na tentativa
+func (d *Deliverer) post(ctx context.Context, delivery *models.WebhookDelivery) *models.WebhookAttempt {
+	attempt := &models.WebhookAttempt{AttemptedAt: d.now()}
+	timestamp := attempt.AttemptedAt.Unix()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "projeto-livros-webhooks/1.0")
+	req.Header.Set(HeaderDelivery, delivery.ID)
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(delivery.Secret, timestamp, delivery.Payload))
+
+	start := time.Now()
+	resp, err := d.client.Do(req)
+	attempt.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	attempt.StatusCode = &resp.StatusCode
+	attempt.ResponseBody = string(body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		attempt.Error = fmt.Sprintf("destino respondeu com status %d", resp.StatusCode)
+	}
+	return attempt
+}
+
+// retryDelay devolve a espera após a falha número attempts: BaseDelay,
+// dobrando a cada falha, até MaxDelay
+func (d *Deliverer) retryDelay(attempts int) time.Duration {
+	delay := d.BaseDelay
+	for i := 1; i < attempts && delay < d.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > d.MaxDelay {
+		delay = d.MaxDelay
+	}
+	return delay
+}
@@ -0,0 +1,61 @@
+// Package webhooks entrega os eventos de domínio às assinaturas de webhook,
+// com payload assinado, novas tentativas com espera exponencial e
+// desativação automática de destinos que falham repetidamente.
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cabeçalhos enviados em cada entrega
+const (
+	HeaderDelivery  = "X-Webhook-Delivery"  // ID da entrega; o mesmo em todas as tentativas
+	HeaderEvent     = "X-Webhook-Event"     // Tipo do evento
+	HeaderTimestamp = "X-Webhook-Timestamp" // Segundos Unix do envio, incluídos na assinatura
+	HeaderSignature = "X-Webhook-Signature" // sha256=<HMAC-SHA256 hexadecimal>
+)
+
+// Sign calcula a assinatura de uma entrega: HMAC-SHA256, com o segredo da
+// assinatura, de "<timestamp>.<corpo>", no formato do cabeçalho X-Webhook-Signature
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify confere a assinatura recebida por um destino. tolerance limita a
+// idade do timestamp, para recusar reenvios de mensagens antigas (0 não limita).
+func Verify(secret, signature, timestamp string, body []byte, tolerance time.Duration, now time.Time) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("timestamp inválido: %q", timestamp)
+	}
+	if tolerance > 0 {
+		age := now.Sub(time.Unix(ts, 0))
+		if age > tolerance || age < -tolerance {
+			return fmt.Errorf("timestamp fora da tolerância de %s", tolerance)
+		}
+	}
+	expected := Sign(secret, ts, body)
+	if !strings.HasPrefix(signature, "sha256=") || !hmac.Equal([]byte(signature), []byte(expected)) {
+		return fmt.Errorf("assinatura não confere")
+	}
+	return nil
+}
+
+// NewSecret gera um segredo aleatório para uma assinatura
+func NewSecret() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,143 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"projeto_livros/internal/domain/models"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// fakeStore guarda as tentativas e as falhas consecutivas em memória
+type fakeStore struct {
+	attempts []models.WebhookAttempt
+	failures int
+	disabled bool
+}
+
+func (s *fakeStore) Enqueue(event models.Event) (int64, error) { return 0, nil }
+
+func (s *fakeStore) Claim(limit int, lease time.Duration) ([]models.WebhookDelivery, error) {
+	return nil, nil
+}
+
+func (s *fakeStore) RecordAttempt(delivery *models.WebhookDelivery, attempt *models.WebhookAttempt, disableAfter int) (bool, error) {
+	s.attempts = append(s.attempts, *attempt)
+	if delivery.Status == models.WebhookDeliverySucceeded {
+		s.failures = 0
+		return false, nil
+	}
+	s.failures++
+	if !s.disabled && s.failures >= disableAfter {
+		s.disabled = true
+		return true, nil
+	}
+	return false, nil
+}
+
+func newTestDelivery(url string) *models.WebhookDelivery {
+	return &models.WebhookDelivery{
+		ID:             "d1",
+		SubscriptionID: "w1",
+		EventType:      models.EventStockChanged,
+		Payload:        json.RawMessage(`{"id":42,"type":"StockChanged","payload":{"book_id":"b1","from":3,"to":1}}`),
+		Status:         models.WebhookDeliveryPending,
+		URL:            url,
+		Secret:         "segredo",
+	}
+}
+
+func TestDeliverSignsPayload(t *testing.T) {
+	now := time.Now()
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		err := Verify("segredo", r.Header.Get(HeaderSignature), r.Header.Get(HeaderTimestamp), body, 5*time.Minute, now)
+		if err != nil {
+			t.Errorf("assinatura inválida: %v", err)
+		}
+		if r.Header.Get(HeaderDelivery) != "d1" || r.Header.Get(HeaderEvent) != models.EventStockChanged {
+			t.Errorf("cabeçalhos = %v", r.Header)
+		}
+		w.Write([]byte("recebido"))
+	}))
+	defer receiver.Close()
+
+	store := &fakeStore{}
+	d := NewDeliverer(store, time.Second, 5, 3)
+	d.now = func() time.Time { return now }
+	delivery := newTestDelivery(receiver.URL)
+
+	ok, err := d.Deliver(context.Background(), delivery)
+	if err != nil || !ok {
+		t.Fatalf("Deliver = %v, %v", ok, err)
+	}
+	if delivery.Status != models.WebhookDeliverySucceeded || delivery.Attempts != 1 || delivery.DeliveredAt == nil {
+		t.Errorf("entrega = %+v", delivery)
+	}
+	attempt := store.attempts[0]
+	if attempt.StatusCode == nil || *attempt.StatusCode != http.StatusOK || attempt.ResponseBody != "recebido" {
+		t.Errorf("tentativa = %+v", attempt)
+	}
+}
+
+func TestDeliverRetriesWithBackoffThenGivesUp(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer receiver.Close()
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	store := &fakeStore{}
+	d := NewDeliverer(store, time.Second, 3, 2)
+	d.BaseDelay = 10 * time.Second
+	d.now = func() time.Time { return now }
+	delivery := newTestDelivery(receiver.URL)
+
+	d.Deliver(context.Background(), delivery)
+	if delivery.Status != models.WebhookDeliveryPending || !delivery.NextAttemptAt.Equal(now.Add(10*time.Second)) {
+		t.Fatalf("após a 1ª falha: status %s, próxima tentativa %v", delivery.Status, delivery.NextAttemptAt)
+	}
+	if store.disabled {
+		t.Fatal("assinatura desativada cedo demais")
+	}
+
+	d.Deliver(context.Background(), delivery)
+	if !delivery.NextAttemptAt.Equal(now.Add(20 * time.Second)) {
+		t.Errorf("após a 2ª falha: próxima tentativa %v, esperava espera dobrada", delivery.NextAttemptAt)
+	}
+	if !store.disabled {
+		t.Error("esperava a assinatura desativada após 2 falhas consecutivas")
+	}
+
+	d.Deliver(context.Background(), delivery)
+	if delivery.Status != models.WebhookDeliveryFailed || delivery.NextAttemptAt != nil {
+		t.Errorf("após a última tentativa: status %s, próxima tentativa %v", delivery.Status, delivery.NextAttemptAt)
+	}
+	if len(store.attempts) != 3 || *store.attempts[2].StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("tentativas = %+v", store.attempts)
+	}
+}
+
+func TestVerifyRejectsTamperedBody(t *testing.T) {
+	now := time.Now()
+	body := []byte(`{"id":1}`)
+	signature := Sign("segredo", now.Unix(), body)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	if err := Verify("segredo", signature, timestamp, body, time.Minute, now); err != nil {
+		t.Fatalf("assinatura válida recusada: %v", err)
+	}
+	if err := Verify("segredo", signature, timestamp, []byte(`{"id":2}`), time.Minute, now); err == nil {
+		t.Error("esperava erro para corpo alterado")
+	}
+	if err := Verify("outro", signature, timestamp, body, time.Minute, now); err == nil {
+		t.Error("esperava erro para segredo diferente")
+	}
+	if err := Verify("segredo", signature, timestamp, body, time.Minute, now.Add(time.Hour)); err == nil {
+		t.Error("esperava erro para timestamp antigo")
+	}
+}
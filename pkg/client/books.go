@@ -0,0 +1,234 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// ListBooksOptions são os parâmetros de GET /api/books; valores zero usam os
+// padrões da API (página 1, 20 por página, ordem por nome)
+type ListBooksOptions struct {
+	Page      int
+	PerPage   int
+	SortField string // name ou quantity
+	SortDesc  bool
+}
+
+func (o ListBooksOptions) query(page int) url.Values {
+	q := url.Values{}
+	if page > 0 {
+		q.Set("page", strconv.Itoa(page))
+	}
+	if o.PerPage > 0 {
+		q.Set("per_page", strconv.Itoa(o.PerPage))
+	}
+	if o.SortField != "" {
+		q.Set("sort_field", o.SortField)
+		if o.SortDesc {
+			q.Set("sort_direction", "desc")
+		}
+	}
+	return q
+}
+
+// ListBooks busca uma página do catálogo
+func (c *Client) ListBooks(ctx context.Context, opts ListBooksOptions) (*BookPage, error) {
+	var page BookPage
+	if err := c.do(ctx, http.MethodGet, "/api/books", opts.query(opts.Page), nil, &page, true); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// Books percorre o catálogo inteiro a partir de opts.Page, buscando as
+// páginas sob demanda
+func (c *Client) Books(opts ListBooksOptions) *Iterator[Book] {
+	return newIterator(opts.Page, func(ctx context.Context, page int) ([]Book, int, error) {
+		result, err := c.ListBooks(ctx, ListBooksOptions{Page: page, PerPage: opts.PerPage, SortField: opts.SortField, SortDesc: opts.SortDesc})
+		if err != nil {
+			return nil, 0, err
+		}
+		return result.Data, result.TotalPages, nil
+	})
+}
+
+// GetBook busca um livro pelo id, com autores e gêneros
+func (c *Client) GetBook(ctx context.Context, id string) (*Book, error) {
+	var book Book
+	if err := c.do(ctx, http.MethodGet, "/api/books/"+url.PathEscape(id), nil, nil, &book, true); err != nil {
+		return nil, err
+	}
+	return &book, nil
+}
+
+// GetBookByISBN busca um livro pelo ISBN-10 ou ISBN-13, com ou sem hífens
+func (c *Client) GetBookByISBN(ctx context.Context, isbn string) (*Book, error) {
+	var book Book
+	if err := c.do(ctx, http.MethodGet, "/api/books/isbn/"+url.PathEscape(isbn), nil, nil, &book, true); err != nil {
+		return nil, err
+	}
+	return &book, nil
+}
+
+func enrichQuery(enrich bool) url.Values {
+	if !enrich {
+		return nil
+	}
+	return url.Values{"enrich": {"true"}}
+}
+
+// CreateBook cadastra um livro. Com enrich, a API completa os campos vazios
+// com os metadados do ISBN. Um ISBN já cadastrado devolve um *Error com
+// StatusCode 409 e ExistingID.
+func (c *Client) CreateBook(ctx context.Context, book Book, enrich bool) (*Book, error) {
+	var created Book
+	if err := c.do(ctx, http.MethodPost, "/api/books", enrichQuery(enrich), book, &created, false); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// CreateBooks cadastra um lote de livros. Se algum ISBN já existir ou se
+// repetir no lote, nada é criado e o *Error traz os Duplicates.
+func (c *Client) CreateBooks(ctx context.Context, books []Book, enrich bool) (*BatchResult, error) {
+	var result BatchResult
+	if err := c.do(ctx, http.MethodPost, "/api/books/batch", enrichQuery(enrich), books, &result, false); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// BookUpdate são os campos alterados por UpdateBook; nil mantém o valor atual
+type BookUpdate struct {
+	Name            *string       `json:"name,omitempty"`
+	Author          *string       `json:"author,omitempty"` // Substitui os autores (papel author)
+	Quantity        *int          `json:"quantity,omitempty"`
+	MinQuantity     *int          `json:"min_quantity,omitempty"`
+	ISBN            *string       `json:"isbn,omitempty"`
+	Publisher       *string       `json:"publisher,omitempty"`
+	PublicationYear *int          `json:"publication_year,omitempty"`
+	Edition         *string       `json:"edition,omitempty"`
+	Subjects        *[]string     `json:"subjects,omitempty"`
+	Authors         *[]BookAuthor `json:"authors,omitempty"` // Substitui todos os autores, com os papéis
+	Genres          *[]BookGenre  `json:"genres,omitempty"`  // Substitui os gêneros; o primeiro é o principal
+}
+
+// quantityOnly indica se só a quantidade é alterada
+func (u BookUpdate) quantityOnly() bool {
+	return u.Quantity != nil && u == BookUpdate{Quantity: u.Quantity}
+}
+
+// UpdateBook altera os campos informados do livro
+func (c *Client) UpdateBook(ctx context.Context, id string, update BookUpdate) (*Book, error) {
+	// A API trata o corpo {id, quantity} como atualização de quantidade e
+	// responde com o resultado dela, não com o livro
+	if update.quantityOnly() {
+		if _, err := c.UpdateQuantity(ctx, id, *update.Quantity); err != nil {
+			return nil, err
+		}
+		return c.GetBook(ctx, id)
+	}
+	body := struct {
+		ID string `json:"id"`
+		BookUpdate
+	}{id, update}
+	var book Book
+	if err := c.do(ctx, http.MethodPut, "/api/books/"+url.PathEscape(id), nil, body, &book, true); err != nil {
+		return nil, err
+	}
+	return &book, nil
+}
+
+// UpdateQuantity define o estoque do livro. Como o valor é absoluto, a
+// chamada é repetida em caso de falha.
+func (c *Client) UpdateQuantity(ctx context.Context, id string, quantity int) (*QuantityUpdate, error) {
+	body := struct {
+		ID       string `json:"id"`
+		Quantity int    `json:"quantity"`
+	}{id, quantity}
+	var result QuantityUpdate
+	if err := c.do(ctx, http.MethodPost, "/api/books/update-quantity", nil, body, &result, true); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DeleteBook move o livro para a lixeira; hard apaga definitivamente e exige
+// um token de administrador
+func (c *Client) DeleteBook(ctx context.Context, id string, hard bool) error {
+	var q url.Values
+	if hard {
+		q = url.Values{"hard": {"true"}}
+	}
+	return c.do(ctx, http.MethodDelete, "/api/books/"+url.PathEscape(id), q, nil, nil, true)
+}
+
+// RestoreBook tira o livro da lixeira
+func (c *Client) RestoreBook(ctx context.Context, id string) (*Book, error) {
+	var book Book
+	if err := c.do(ctx, http.MethodPost, "/api/books/"+url.PathEscape(id)+"/restore", nil, nil, &book, false); err != nil {
+		return nil, err
+	}
+	return &book, nil
+}
+
+// BookHistory busca uma página das revisões do livro, da mais recente para a
+// mais antiga; page e perPage zero usam os padrões da API
+func (c *Client) BookHistory(ctx context.Context, id string, page, perPage int) (*RevisionPage, error) {
+	q := url.Values{}
+	if page > 0 {
+		q.Set("page", strconv.Itoa(page))
+	}
+	if perPage > 0 {
+		q.Set("per_page", strconv.Itoa(perPage))
+	}
+	var result RevisionPage
+	if err := c.do(ctx, http.MethodGet, "/api/books/"+url.PathEscape(id)+"/history", q, nil, &result, true); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Revisions percorre todas as revisões do livro
+func (c *Client) Revisions(id string, perPage int) *Iterator[Revision] {
+	return newIterator(1, func(ctx context.Context, page int) ([]Revision, int, error) {
+		result, err := c.BookHistory(ctx, id, page, perPage)
+		if err != nil {
+			return nil, 0, err
+		}
+		return result.Data, result.TotalPages, nil
+	})
+}
+
+// GetBookRevision busca o estado do livro na revisão rev
+func (c *Client) GetBookRevision(ctx context.Context, id string, rev int) (*Revision, error) {
+	var revision Revision
+	path := "/api/books/" + url.PathEscape(id) + "/history/" + strconv.Itoa(rev)
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, &revision, true); err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// DiffBookRevisions compara as revisões from e to do livro campo a campo
+func (c *Client) DiffBookRevisions(ctx context.Context, id string, from, to int) (*RevisionDiff, error) {
+	q := url.Values{"from": {strconv.Itoa(from)}, "to": {strconv.Itoa(to)}}
+	var diff RevisionDiff
+	if err := c.do(ctx, http.MethodGet, "/api/books/"+url.PathEscape(id)+"/history/diff", q, nil, &diff, true); err != nil {
+		return nil, err
+	}
+	return &diff, nil
+}
+
+// RevertBook volta o livro ao estado da revisão rev e devolve a nova revisão
+// registrada
+func (c *Client) RevertBook(ctx context.Context, id string, rev int) (*Revision, error) {
+	var revision Revision
+	path := "/api/books/" + url.PathEscape(id) + "/revert/" + strconv.Itoa(rev)
+	if err := c.do(ctx, http.MethodPost, path, nil, nil, &revision, false); err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
@@ -0,0 +1,206 @@
+// Package client é o cliente Go da API REST do catálogo (livros e gêneros),
+// para as ferramentas que hoje repetem as mesmas chamadas HTTP.
+//
+//	c := client.New("http://localhost:3001", client.WithToken(token))
+//	book, err := c.GetBook(ctx, id)
+//	if client.IsNotFound(err) {
+//		...
+//	}
+//
+// As chamadas idempotentes (GET, PUT, DELETE e a definição da quantidade) são
+// repetidas com backoff exponencial quando a API responde 429 ou 5xx ou a
+// conexão falha; as demais são enviadas uma única vez. Um DELETE repetido que
+// recebe 404 conta como sucesso, pois a tentativa anterior pode ter removido
+// o item sem que a resposta chegasse. As respostas de erro viram *Error, com
+// o status e os campos extras de cada rota.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy define as novas tentativas das chamadas idempotentes
+type RetryPolicy struct {
+	MaxAttempts int           // Total de tentativas, incluindo a primeira; 1 desliga as repetições
+	MinBackoff  time.Duration // Espera antes da segunda tentativa; dobra a cada nova tentativa
+	MaxBackoff  time.Duration // Limite da espera entre tentativas
+}
+
+// DefaultRetryPolicy é a política usada quando WithRetry não é informado
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 3, MinBackoff: 200 * time.Millisecond, MaxBackoff: 2 * time.Second}
+
+// backoff é a espera antes da tentativa attempt+1, com variação aleatória
+// para que vários clientes não repitam ao mesmo tempo
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	wait := p.MinBackoff << (attempt - 1)
+	if wait > p.MaxBackoff || wait <= 0 {
+		wait = p.MaxBackoff
+	}
+	if wait <= 0 {
+		return 0
+	}
+	return wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+}
+
+// Client faz as chamadas à API. É seguro para uso concorrente.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	token      string
+	userAgent  string
+	language   string
+	retry      RetryPolicy
+}
+
+// Option altera a configuração do cliente em New
+type Option func(*Client)
+
+// WithHTTPClient troca o http.Client usado nas chamadas (timeouts, proxy, TLS)
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) { c.httpClient = httpClient }
+}
+
+// WithToken envia o JWT no cabeçalho Authorization ("Bearer <token>")
+func WithToken(token string) Option {
+	return func(c *Client) { c.token = token }
+}
+
+// WithRetry troca a política de novas tentativas
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) { c.retry = policy }
+}
+
+// WithUserAgent identifica a ferramenta nos logs da API
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) { c.userAgent = userAgent }
+}
+
+// WithLanguage pede as mensagens de erro no idioma indicado, como "en" ou
+// "pt-BR" (cabeçalho Accept-Language); o padrão da API é pt-BR
+func WithLanguage(language string) Option {
+	return func(c *Client) { c.language = language }
+}
+
+// New cria um cliente para a API em baseURL, como "http://localhost:3001"
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		userAgent:  "projeto_livros-client",
+		retry:      DefaultRetryPolicy,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// retryable indica as respostas que valem uma nova tentativa
+func retryable(status int) bool {
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
+// do envia a requisição e decodifica a resposta JSON em out (se não for nil).
+// Com idempotent, repete a chamada conforme a política de novas tentativas.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}, idempotent bool) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("erro ao codificar a requisição: %w", err)
+		}
+	}
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	attempts := 1
+	if idempotent && c.retry.MaxAttempts > 1 {
+		attempts = c.retry.MaxAttempts
+	}
+	for attempt := 1; ; attempt++ {
+		resp, err := c.send(ctx, method, target, payload)
+		if err != nil && ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if attempt == attempts || (err == nil && !retryable(resp.StatusCode)) {
+			if err != nil {
+				return err
+			}
+			err = decodeResponse(resp, out)
+			if method == http.MethodDelete && attempt > 1 && IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+
+		wait := c.retry.backoff(attempt)
+		if resp != nil {
+			// Retry-After (em segundos) vale mais que o backoff calculado
+			if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+				wait = time.Duration(seconds) * time.Second
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *Client) send(ctx context.Context, method, target string, payload []byte) (*http.Response, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if c.language != "" {
+		req.Header.Set("Accept-Language", c.language)
+	}
+	return c.httpClient.Do(req)
+}
+
+func decodeResponse(resp *http.Response, out interface{}) error {
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("erro ao ler a resposta: %w", err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return decodeError(resp.StatusCode, data)
+	}
+	if out == nil || len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("erro ao decodificar a resposta: %w", err)
+	}
+	return nil
+}
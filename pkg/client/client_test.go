@@ -0,0 +1,237 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"projeto_livros/internal/config"
+	handlers "projeto_livros/internal/delivery/http"
+	"projeto_livros/internal/events"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+var bookColumns = []string{"id", "name", "quantity", "genre_id", "author",
+	"isbn", "publisher", "publication_year", "edition", "subjects", "min_quantity"}
+
+// newTestServer sobe a API com o roteador real e o banco simulado. O contador
+// devolvido soma as requisições que chegaram ao servidor.
+func newTestServer(t *testing.T) (*Client, sqlmock.Sqlmock, *int32) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Erro ao criar mock do banco de dados: %v", err)
+	}
+	router, err := handlers.NewRouter(db, &config.Config{StreamHeartbeat: time.Second, GraphQLMaxDepth: 10, GraphQLMaxComplexity: 5000,
+		ValidateResponses: true},
+		handlers.RouterDeps{Broker: events.NewBroker(10)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		router.ServeHTTP(w, r)
+	}))
+	t.Cleanup(func() {
+		server.Close()
+		db.Close()
+	})
+	c := New(server.URL, WithRetry(RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}))
+	return c, mock, &requests
+}
+
+func TestBooksIteratorWalksAllPages(t *testing.T) {
+	c, mock, _ := newTestServer(t)
+	mock.ExpectQuery("SELECT (.+) FROM livros l").WithArgs(2, 0).WillReturnRows(sqlmock.NewRows(bookColumns).
+		AddRow("1", "A Hora da Estrela", 1, nil, "", "", "", nil, "", "{}", nil).
+		AddRow("2", "Dom Casmurro", 2, nil, "", "", "", nil, "", "{}", nil))
+	mock.ExpectQuery("SELECT COUNT").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectQuery("SELECT (.+) FROM livros l").WithArgs(2, 2).WillReturnRows(sqlmock.NewRows(bookColumns).
+		AddRow("3", "Vidas Secas", 3, nil, "", "", "", nil, "", "{}", nil))
+	mock.ExpectQuery("SELECT COUNT").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	var names []string
+	it := c.Books(ListBooksOptions{PerPage: 2})
+	for it.Next(context.Background()) {
+		names = append(names, it.Item().Name)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 3 || names[2] != "Vidas Secas" {
+		t.Errorf("esperava os 3 livros das 2 páginas, obteve %v", names)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas não atendidas: %s", err)
+	}
+}
+
+func TestGetBookNotFoundDecodesError(t *testing.T) {
+	c, mock, _ := newTestServer(t)
+	mock.ExpectQuery("SELECT (.+) FROM livros l WHERE l.id = \\$1").WithArgs("nao-existe").
+		WillReturnRows(sqlmock.NewRows(bookColumns))
+
+	_, err := c.GetBook(context.Background(), "nao-existe")
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || !IsNotFound(err) {
+		t.Fatalf("esperava *Error 404, obteve %v", err)
+	}
+	if apiErr.Message != "Livro não encontrado" || apiErr.Code != "BOOK_NOT_FOUND" || apiErr.Type != "/api/problems/book-not-found" {
+		t.Errorf("problema incorreto: %#v", apiErr)
+	}
+}
+
+func TestWithLanguageTranslatesErrors(t *testing.T) {
+	c, mock, _ := newTestServer(t)
+	mock.ExpectQuery("SELECT (.+) FROM livros l WHERE l.id = \\$1").WithArgs("nao-existe").
+		WillReturnRows(sqlmock.NewRows(bookColumns))
+
+	_, err := New(c.baseURL, WithLanguage("en")).GetBook(context.Background(), "nao-existe")
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.Message != "Book not found" || apiErr.Code != "BOOK_NOT_FOUND" {
+		t.Errorf("esperava a mensagem em inglês, obteve %v", err)
+	}
+}
+
+func TestValidationErrorFields(t *testing.T) {
+	c, _, _ := newTestServer(t)
+	_, err := c.CreateBook(context.Background(), Book{Name: "Dom Casmurro", Quantity: 0}, false)
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest || apiErr.Code != "VALIDATION_FAILED" {
+		t.Fatalf("esperava VALIDATION_FAILED, obteve %v", err)
+	}
+	if len(apiErr.Fields) != 1 || apiErr.Fields[0].Field != "quantity" || apiErr.Fields[0].Location != "body" {
+		t.Errorf("violações incorretas: %+v", apiErr.Fields)
+	}
+}
+
+func TestIdempotentCallIsRetried(t *testing.T) {
+	c, mock, requests := newTestServer(t)
+	mock.ExpectQuery("SELECT id, name").WillReturnError(errors.New("conexão perdida"))
+	mock.ExpectQuery("SELECT id, name").WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "parent_id"}).
+		AddRow("g1", "Romance", "", nil))
+
+	genres, err := c.ListGenres(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(genres) != 1 || genres[0].Name != "Romance" {
+		t.Errorf("gêneros incorretos: %v", genres)
+	}
+	if n := atomic.LoadInt32(requests); n != 2 {
+		t.Errorf("esperava 2 requisições (uma repetição), obteve %d", n)
+	}
+}
+
+func TestCreateIsNotRetried(t *testing.T) {
+	c, mock, requests := newTestServer(t)
+	mock.ExpectQuery("SELECT (.+) WHERE l.isbn = \\$1").WithArgs("9788535910667").
+		WillReturnError(errors.New("conexão perdida"))
+
+	_, err := c.CreateBook(context.Background(), Book{Name: "Dom Casmurro", Quantity: 1, ISBN: "85-359-1066-2"}, false)
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("esperava *Error 500, obteve %v", err)
+	}
+	if n := atomic.LoadInt32(requests); n != 1 {
+		t.Errorf("POST não deve ser repetido: %d requisições", n)
+	}
+}
+
+func TestRetriedDeleteTreatsNotFoundAsSuccess(t *testing.T) {
+	// A primeira tentativa remove o livro, mas a resposta se perde em um 503;
+	// a repetição encontra o livro já na lixeira
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"status":503,"code":"INTERNAL_ERROR"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"status":404,"code":"BOOK_NOT_FOUND"}`))
+	}))
+	defer server.Close()
+	c := New(server.URL, WithRetry(RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}))
+
+	if err := c.DeleteBook(context.Background(), "b1", false); err != nil {
+		t.Errorf("DELETE repetido com 404 deveria ser sucesso, obteve %v", err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 2 {
+		t.Errorf("esperava 2 requisições, obteve %d", n)
+	}
+
+	// Na primeira tentativa, o 404 continua sendo erro
+	atomic.StoreInt32(&requests, 1)
+	if err := c.DeleteBook(context.Background(), "b1", false); !IsNotFound(err) {
+		t.Errorf("esperava 404 na primeira tentativa, obteve %v", err)
+	}
+}
+
+func TestConflictErrors(t *testing.T) {
+	c, mock, _ := newTestServer(t)
+	mock.ExpectQuery("SELECT (.+) WHERE l.isbn = \\$1").WithArgs("9788535910667").WillReturnRows(sqlmock.NewRows(bookColumns).
+		AddRow("existente", "Dom Casmurro", 1, nil, "", "9788535910667", "", nil, "", "{}", nil))
+
+	_, err := c.CreateBook(context.Background(), Book{Name: "Dom Casmurro", Quantity: 2, ISBN: "85-359-1066-2"}, false)
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || !IsConflict(err) || apiErr.ExistingID != "existente" {
+		t.Errorf("esperava conflito com existing_id, obteve %#v", err)
+	}
+
+	// No lote, o ISBN repetido é recusado sem criar nenhum livro
+	mock.ExpectQuery("SELECT (.+) WHERE l.isbn = \\$1").WithArgs("9788535910667").WillReturnRows(sqlmock.NewRows(bookColumns))
+	_, err = c.CreateBooks(context.Background(), []Book{
+		{Name: "Dom Casmurro", Quantity: 1, ISBN: "9788535910667"},
+		{Name: "Dom Casmurro (2ª ed.)", Quantity: 1, ISBN: "85-359-1066-2"},
+	}, false)
+	if !errors.As(err, &apiErr) || len(apiErr.Duplicates) != 1 || apiErr.Duplicates[0].Index != 1 {
+		t.Errorf("esperava o livro 1 em duplicates, obteve %#v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas não atendidas: %s", err)
+	}
+}
+
+func TestDecodeErrorFormats(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		message string
+		code    string
+	}{
+		{"problem+json", `{"type":"/api/problems/genre-not-found","title":"Gênero não encontrado","status":404,
+			"detail":"Gênero não encontrado","code":"GENRE_NOT_FOUND"}`, "Gênero não encontrado", "GENRE_NOT_FOUND"},
+		{"ErrorResponse", `{"error":"Livro não encontrado","code":404}`, "Livro não encontrado", ""},
+		{"APIError", `{"code":"NOT_FOUND","message":"Gênero não encontrado"}`, "Gênero não encontrado", "NOT_FOUND"},
+		{"texto puro", "Gênero não encontrado\n", "Gênero não encontrado", ""},
+		{"corpo vazio", "", "Not Found", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := decodeError(http.StatusNotFound, []byte(tt.body))
+			if err.Message != tt.message || err.Code != tt.code {
+				t.Errorf("obteve mensagem %q e código %q", err.Message, err.Code)
+			}
+		})
+	}
+}
+
+func TestRetryStopsWhenContextIsCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	c := New(server.URL, WithRetry(RetryPolicy{MaxAttempts: 5, MinBackoff: time.Hour, MaxBackoff: time.Hour}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := c.GetBook(ctx, "1"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("esperava o erro do contexto, obteve %v", err)
+	}
+}
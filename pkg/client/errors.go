@@ -0,0 +1,109 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Error é uma resposta de erro da API, enviada em application/problem+json
+// com o código do catálogo em Code (o catálogo completo está em
+// /api/problems). Corpos no formato antigo ({"error"} ou {"message"}) e em
+// texto puro, de servidores anteriores, também são aceitos.
+type Error struct {
+	StatusCode int
+	Message    string       // detail do problema
+	Code       string       // Código do catálogo (BOOK_NOT_FOUND, GENRE_IN_USE...)
+	Type       string       // URI de tipo, como /api/problems/book-not-found
+	Title      string       // Resumo do tipo de problema
+	Fields     []FieldError // Violações campo a campo (VALIDATION_FAILED)
+
+	ExistingID string          // Livro que já usa o ISBN (409 ao criar ou atualizar um livro)
+	TotalBooks int             // Livros que impedem a remoção do gênero (409 em DeleteGenre)
+	Duplicates []DuplicateISBN // ISBNs repetidos ou já cadastrados (409 em CreateBooks)
+
+	Body []byte // Corpo original da resposta
+}
+
+// FieldError é uma violação em um campo da requisição
+type FieldError struct {
+	Field    string `json:"field"`    // Caminho do campo, como authors[0].role
+	Location string `json:"location"` // path, query ou body
+	Message  string `json:"message"`
+}
+
+// DuplicateISBN é um livro do lote recusado por CreateBooks
+type DuplicateISBN struct {
+	Index      int    `json:"index"`                 // Posição no lote enviado
+	ISBN       string `json:"isbn"`                  // ISBN-13 normalizado
+	ExistingID string `json:"existing_id,omitempty"` // Vazio quando o ISBN se repete no próprio lote
+}
+
+func (e *Error) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("api: %d %s: %s", e.StatusCode, e.Code, e.Message)
+	}
+	return fmt.Sprintf("api: %d: %s", e.StatusCode, e.Message)
+}
+
+// IsNotFound indica se err é uma resposta 404 da API
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsConflict indica se err é uma resposta 409 da API (ISBN duplicado,
+// gênero com livros...)
+func IsConflict(err error) bool {
+	return hasStatus(err, http.StatusConflict)
+}
+
+func hasStatus(err error, status int) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == status
+}
+
+// errorBody reúne os membros do problema e os campos do formato antigo, em
+// que code era o status numérico
+type errorBody struct {
+	Type       string          `json:"type"`
+	Title      string          `json:"title"`
+	Detail     string          `json:"detail"`
+	Errors     []FieldError    `json:"errors"`
+	Error      string          `json:"error"`
+	Message    string          `json:"message"`
+	Code       json.RawMessage `json:"code"`
+	ExistingID string          `json:"existing_id"`
+	TotalBooks int             `json:"total_books"`
+	Duplicates []DuplicateISBN `json:"duplicates"`
+}
+
+func decodeError(status int, data []byte) *Error {
+	apiErr := &Error{StatusCode: status, Body: data}
+	var body errorBody
+	if err := json.Unmarshal(data, &body); err != nil {
+		apiErr.Message = string(bytes.TrimSpace(data))
+	} else {
+		for _, message := range []string{body.Detail, body.Title, body.Error, body.Message} {
+			if message != "" {
+				apiErr.Message = message
+				break
+			}
+		}
+		apiErr.Type = body.Type
+		apiErr.Title = body.Title
+		apiErr.Fields = body.Errors
+		var code string
+		if json.Unmarshal(body.Code, &code) == nil {
+			apiErr.Code = code
+		}
+		apiErr.ExistingID = body.ExistingID
+		apiErr.TotalBooks = body.TotalBooks
+		apiErr.Duplicates = body.Duplicates
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(status)
+	}
+	return apiErr
+}
@@ -0,0 +1,115 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// ListGenres lista os gêneros em ordem de nome
+func (c *Client) ListGenres(ctx context.Context) ([]Genre, error) {
+	var genres []Genre
+	if err := c.do(ctx, http.MethodGet, "/api/genres", nil, nil, &genres, true); err != nil {
+		return nil, err
+	}
+	return genres, nil
+}
+
+// GetGenre busca o nome do gênero com os livros dele (id, nome e quantidade)
+func (c *Client) GetGenre(ctx context.Context, id string) (*GenreSummary, error) {
+	var genre GenreSummary
+	if err := c.do(ctx, http.MethodGet, "/api/genres", url.Values{"genre_id": {id}}, nil, &genre, true); err != nil {
+		return nil, err
+	}
+	return &genre, nil
+}
+
+// GenreTree devolve os gêneros raiz com os subgêneros aninhados
+func (c *Client) GenreTree(ctx context.Context) ([]GenreNode, error) {
+	var tree []GenreNode
+	if err := c.do(ctx, http.MethodGet, "/api/genres/tree", nil, nil, &tree, true); err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+// GenreBooks lista os livros do gênero; com includeDescendants, também os
+// dos subgêneros
+func (c *Client) GenreBooks(ctx context.Context, id string, includeDescendants bool) ([]Book, error) {
+	var q url.Values
+	if includeDescendants {
+		q = url.Values{"include_descendants": {"true"}}
+	}
+	var books []Book
+	if err := c.do(ctx, http.MethodGet, "/api/genres/"+url.PathEscape(id)+"/books", q, nil, &books, true); err != nil {
+		return nil, err
+	}
+	return books, nil
+}
+
+// CreateGenre cadastra um gênero
+func (c *Client) CreateGenre(ctx context.Context, genre Genre) (*Genre, error) {
+	var created Genre
+	if err := c.do(ctx, http.MethodPost, "/api/genres", nil, genre, &created, false); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// ReplaceGenre substitui todos os dados do gênero (PUT)
+func (c *Client) ReplaceGenre(ctx context.Context, id string, genre Genre) (*Genre, error) {
+	var updated Genre
+	if err := c.do(ctx, http.MethodPut, "/api/genres/"+url.PathEscape(id), nil, genre, &updated, true); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// PatchGenre altera só os campos informados do gênero (PATCH)
+func (c *Client) PatchGenre(ctx context.Context, id string, update GenreUpdate) (*Genre, error) {
+	var updated Genre
+	if err := c.do(ctx, http.MethodPatch, "/api/genres/"+url.PathEscape(id), nil, update, &updated, true); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteGenre move o gênero para a lixeira. Se ele tiver livros, reassignTo
+// indica o gênero que os recebe; sem ele, a API responde 409 e o *Error traz
+// TotalBooks. hard apaga definitivamente e exige um token de administrador.
+func (c *Client) DeleteGenre(ctx context.Context, id, reassignTo string, hard bool) (*GenreDeleteResult, error) {
+	q := url.Values{}
+	if reassignTo != "" {
+		q.Set("reassign_to", reassignTo)
+	}
+	if hard {
+		q.Set("hard", "true")
+	}
+	var result GenreDeleteResult
+	if err := c.do(ctx, http.MethodDelete, "/api/genres/"+url.PathEscape(id), q, nil, &result, true); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// RestoreGenre tira o gênero da lixeira
+func (c *Client) RestoreGenre(ctx context.Context, id string) (*Genre, error) {
+	var genre Genre
+	if err := c.do(ctx, http.MethodPost, "/api/genres/"+url.PathEscape(id)+"/restore", nil, nil, &genre, false); err != nil {
+		return nil, err
+	}
+	return &genre, nil
+}
+
+// MergeGenre mescla o gênero id em targetID: os livros e subgêneros passam
+// para o destino e a origem é removida
+func (c *Client) MergeGenre(ctx context.Context, id, targetID string) (*GenreMergeResult, error) {
+	body := struct {
+		TargetID string `json:"target_id"`
+	}{targetID}
+	var result GenreMergeResult
+	if err := c.do(ctx, http.MethodPost, "/api/genres/"+url.PathEscape(id)+"/merge", nil, body, &result, false); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
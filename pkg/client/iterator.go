@@ -0,0 +1,60 @@
+package client
+
+import "context"
+
+// Iterator percorre uma listagem paginada, buscando cada página quando os
+// itens da anterior acabam:
+//
+//	it := c.Books(client.ListBooksOptions{PerPage: 100})
+//	for it.Next(ctx) {
+//		book := it.Item()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator[T any] struct {
+	fetch   func(ctx context.Context, page int) (items []T, totalPages int, err error)
+	page    int // Próxima página a buscar
+	items   []T
+	current T
+	done    bool
+	err     error
+}
+
+func newIterator[T any](page int, fetch func(ctx context.Context, page int) ([]T, int, error)) *Iterator[T] {
+	if page < 1 {
+		page = 1
+	}
+	return &Iterator[T]{fetch: fetch, page: page}
+}
+
+// Next avança para o próximo item. Devolve false no fim da listagem ou
+// quando uma página falha; nesse caso Err devolve o erro.
+func (it *Iterator[T]) Next(ctx context.Context) bool {
+	for len(it.items) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		items, totalPages, err := it.fetch(ctx, it.page)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.page++
+		it.items = items
+		it.done = len(items) == 0 || it.page > totalPages
+	}
+	it.current, it.items = it.items[0], it.items[1:]
+	return true
+}
+
+// Item devolve o item atual, depois de Next devolver true
+func (it *Iterator[T]) Item() T {
+	return it.current
+}
+
+// Err devolve o erro que interrompeu a iteração, se houver
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
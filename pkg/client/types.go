@@ -0,0 +1,147 @@
+package client
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Book é um livro como a API o envia e recebe
+type Book struct {
+	ID       string  `json:"id,omitempty"`
+	Name     string  `json:"name"`
+	Title    string  `json:"title,omitempty"`
+	Author   string  `json:"author,omitempty"` // Nomes dos autores (papel author); na entrada, separados por vírgula
+	Quantity int     `json:"quantity"`
+	GenreID  *string `json:"genre_id,omitempty"` // Gênero principal: o primeiro de Genres
+
+	MinQuantity *int `json:"min_quantity,omitempty"`
+
+	ISBN            string   `json:"isbn,omitempty"` // ISBN-13 normalizado; na entrada aceita ISBN-10 ou ISBN-13
+	ISBN10          string   `json:"isbn_10,omitempty"`
+	ISBN13          string   `json:"isbn_13,omitempty"`
+	Publisher       string   `json:"publisher,omitempty"`
+	PublicationYear *int     `json:"publication_year,omitempty"`
+	Edition         string   `json:"edition,omitempty"`
+	Subjects        []string `json:"subjects,omitempty"`
+
+	Authors []BookAuthor `json:"authors,omitempty"`
+	Genres  []BookGenre  `json:"genres,omitempty"`
+}
+
+// BookAuthor é um autor do livro com o papel dele (author, translator ou illustrator)
+type BookAuthor struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name"`
+	Role string `json:"role,omitempty"`
+}
+
+// BookGenre é um dos gêneros do livro. Na entrada, basta o id.
+type BookGenre struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+// BookPage é uma página de GET /api/books
+type BookPage struct {
+	Data       []Book `json:"data"`
+	Page       int    `json:"page"`
+	PerPage    int    `json:"per_page"`
+	TotalBooks int    `json:"total_books"`
+	TotalPages int    `json:"total_pages"`
+}
+
+// BatchResult é a resposta de POST /api/books/batch. Livros inválidos do
+// lote são ignorados pela API e não aparecem em Books.
+type BatchResult struct {
+	Message string `json:"message"`
+	Books   []Book `json:"books"`
+}
+
+// QuantityUpdate é a resposta de POST /api/books/update-quantity
+type QuantityUpdate struct {
+	ID                string `json:"id"`
+	RequestedQuantity int    `json:"requested_quantity"`
+	FinalQuantity     int    `json:"final_quantity"`
+	OriginalQuantity  int    `json:"original_quantity"`
+	Success           bool   `json:"success"`
+	Message           string `json:"message"`
+}
+
+// Revision é o estado completo de um livro depois de uma alteração
+type Revision struct {
+	ID            string    `json:"id"`
+	BookID        string    `json:"book_id"`
+	Rev           int       `json:"rev"`
+	Action        string    `json:"action"` // create, update, delete, restore ou revert
+	Actor         string    `json:"actor,omitempty"`
+	ChangedFields []string  `json:"changed_fields"`
+	RevertedFrom  *int      `json:"reverted_from,omitempty"`
+	Snapshot      Book      `json:"snapshot"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// RevisionPage é uma página de GET /api/books/{id}/history
+type RevisionPage struct {
+	Data           []Revision `json:"data"`
+	Page           int        `json:"page"`
+	PerPage        int        `json:"per_page"`
+	TotalRevisions int        `json:"total_revisions"`
+	TotalPages     int        `json:"total_pages"`
+}
+
+// RevisionDiff são as diferenças entre duas revisões de um livro
+type RevisionDiff struct {
+	BookID  string        `json:"book_id"`
+	From    int           `json:"from"`
+	To      int           `json:"to"`
+	Changes []FieldChange `json:"changes"`
+}
+
+// FieldChange é a diferença de um campo entre duas revisões
+type FieldChange struct {
+	Field string          `json:"field"`
+	From  json.RawMessage `json:"from"`
+	To    json.RawMessage `json:"to"`
+}
+
+// Genre é um gênero; ParentID é nil nos gêneros raiz
+type Genre struct {
+	ID          string  `json:"id,omitempty"`
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	ParentID    *string `json:"parent_id"`
+}
+
+// GenreNode é um gênero na árvore de GET /api/genres/tree
+type GenreNode struct {
+	Genre
+	Children []GenreNode `json:"children"`
+}
+
+// GenreSummary é um gênero com os livros dele (id, nome e quantidade)
+type GenreSummary struct {
+	Name       string `json:"name"`
+	TotalBooks int    `json:"total_books"`
+	Books      []Book `json:"books"`
+}
+
+// GenreUpdate são os campos alterados por PatchGenre; nil mantém o valor atual
+type GenreUpdate struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+	// Novo gênero pai; aponte para "" para tornar o gênero raiz
+	ParentID *string `json:"parent_id,omitempty"`
+}
+
+// GenreDeleteResult é a resposta de DELETE /api/genres/{id}
+type GenreDeleteResult struct {
+	Message    string `json:"message"`
+	MovedBooks int    `json:"moved_books"`
+}
+
+// GenreMergeResult é a resposta de POST /api/genres/{id}/merge
+type GenreMergeResult struct {
+	Message    string `json:"message"`
+	Genre      Genre  `json:"genre"`
+	MovedBooks int    `json:"moved_books"`
+}
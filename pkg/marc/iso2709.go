@@ -0,0 +1,220 @@
+package marc
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	subfieldDelimiter = 0x1F
+	fieldTerminator   = 0x1E
+	recordTerminator  = 0x1D
+
+	leaderLength         = 24
+	directoryEntryLength = 12
+)
+
+// Reader lê registros ISO 2709 em sequência
+type Reader struct {
+	r *bufio.Reader
+}
+
+// NewReader cria um leitor de registros binários MARC21
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+// Read devolve o próximo registro, ou io.EOF quando não houver mais registros
+func (mr *Reader) Read() (*Record, error) {
+	// Ignora quebras de linha e espaços que alguns sistemas inserem entre registros
+	for {
+		b, err := mr.r.Peek(1)
+		if err != nil {
+			return nil, err
+		}
+		if b[0] != '\n' && b[0] != '\r' && b[0] != ' ' {
+			break
+		}
+		mr.r.ReadByte()
+	}
+
+	head := make([]byte, 5)
+	if _, err := io.ReadFull(mr.r, head); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, fmt.Errorf("marc: registro truncado")
+		}
+		return nil, err
+	}
+	length, err := digits(string(head))
+	if err != nil || length < leaderLength+1 {
+		return nil, fmt.Errorf("marc: tamanho de registro inválido %q", head)
+	}
+	data := make([]byte, length)
+	copy(data, head)
+	if _, err := io.ReadFull(mr.r, data[5:]); err != nil {
+		return nil, fmt.Errorf("marc: registro truncado: %v", err)
+	}
+	return Unmarshal(data)
+}
+
+// Unmarshal decodifica um único registro ISO 2709
+func Unmarshal(data []byte) (*Record, error) {
+	if len(data) < leaderLength+1 {
+		return nil, fmt.Errorf("marc: registro curto demais")
+	}
+	if data[len(data)-1] != recordTerminator {
+		return nil, fmt.Errorf("marc: terminador de registro ausente")
+	}
+	rec := &Record{Leader: string(data[:leaderLength])}
+	base, err := digits(rec.Leader[12:17])
+	if err != nil || base <= leaderLength || base > len(data) {
+		return nil, fmt.Errorf("marc: endereço base inválido %q", rec.Leader[12:17])
+	}
+
+	directory := data[leaderLength : base-1]
+	if len(directory)%directoryEntryLength != 0 {
+		return nil, fmt.Errorf("marc: diretório com tamanho inválido")
+	}
+	for i := 0; i < len(directory); i += directoryEntryLength {
+		entry := directory[i : i+directoryEntryLength]
+		tag := string(entry[:3])
+		fieldLength, err1 := digits(string(entry[3:7]))
+		start, err2 := digits(string(entry[7:12]))
+		if err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("marc: entrada de diretório inválida para a tag %s", tag)
+		}
+		begin := base + start
+		end := begin + fieldLength
+		if end <= begin || end > len(data) {
+			return nil, fmt.Errorf("marc: campo %s fora dos limites do registro", tag)
+		}
+		raw := bytes.TrimSuffix(data[begin:end], []byte{fieldTerminator})
+
+		field := Field{Tag: tag}
+		if field.IsControl() {
+			field.Value = string(raw)
+			rec.Fields = append(rec.Fields, field)
+			continue
+		}
+		if len(raw) < 2 {
+			return nil, fmt.Errorf("marc: campo %s sem indicadores", tag)
+		}
+		field.Ind1 = string(raw[0])
+		field.Ind2 = string(raw[1])
+		for _, part := range bytes.Split(raw[2:], []byte{subfieldDelimiter}) {
+			if len(part) == 0 {
+				continue
+			}
+			field.Subfields = append(field.Subfields, Subfield{Code: string(part[0]), Value: string(part[1:])})
+		}
+		rec.Fields = append(rec.Fields, field)
+	}
+	return rec, nil
+}
+
+// digits lê um número do leader ou do diretório, que só admitem algarismos:
+// strconv.Atoi sozinho aceitaria sinais, como em "-001"
+func digits(s string) (int, error) {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return 0, fmt.Errorf("marc: número inválido %q", s)
+		}
+	}
+	return strconv.Atoi(s)
+}
+
+// Marshal codifica o registro em ISO 2709, recalculando o tamanho do registro,
+// o endereço base e o diretório. Os bytes de estrutura (0x1D, 0x1E e 0x1F)
+// são removidos dos valores, pois cortariam o campo na leitura.
+func Marshal(rec *Record) ([]byte, error) {
+	var directory, body bytes.Buffer
+	for _, f := range rec.Fields {
+		if len(f.Tag) != 3 {
+			return nil, fmt.Errorf("marc: tag inválida %q", f.Tag)
+		}
+		start := body.Len()
+		if f.IsControl() {
+			body.WriteString(stripStructural(f.Value))
+		} else {
+			body.WriteString(indicator(f.Ind1))
+			body.WriteString(indicator(f.Ind2))
+			for _, sf := range f.Subfields {
+				body.WriteByte(subfieldDelimiter)
+				body.WriteString(stripStructural(sf.Code))
+				body.WriteString(stripStructural(sf.Value))
+			}
+		}
+		body.WriteByte(fieldTerminator)
+		fieldLength := body.Len() - start
+		if fieldLength > 9999 || start > 99999 {
+			return nil, fmt.Errorf("marc: campo %s excede o tamanho máximo", f.Tag)
+		}
+		fmt.Fprintf(&directory, "%s%04d%05d", f.Tag, fieldLength, start)
+	}
+	directory.WriteByte(fieldTerminator)
+
+	base := leaderLength + directory.Len()
+	total := base + body.Len() + 1
+	if total > 99999 {
+		return nil, fmt.Errorf("marc: registro excede o tamanho máximo")
+	}
+
+	leader := []byte(rec.Leader)
+	if len(leader) != leaderLength {
+		leader = []byte(DefaultLeader)
+	}
+	copy(leader[0:5], fmt.Sprintf("%05d", total))
+	copy(leader[12:17], fmt.Sprintf("%05d", base))
+	// Tamanhos fixos das entradas de diretório no MARC21
+	copy(leader[20:24], "4500")
+
+	out := make([]byte, 0, total)
+	out = append(out, leader...)
+	out = append(out, directory.Bytes()...)
+	out = append(out, body.Bytes()...)
+	out = append(out, recordTerminator)
+	return out, nil
+}
+
+// Writer escreve registros ISO 2709 em sequência
+type Writer struct {
+	w io.Writer
+}
+
+// NewWriter cria um escritor de registros binários MARC21
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Write codifica e escreve um registro
+func (mw *Writer) Write(rec *Record) error {
+	data, err := Marshal(rec)
+	if err != nil {
+		return err
+	}
+	_, err = mw.w.Write(data)
+	return err
+}
+
+func indicator(ind string) string {
+	if len(ind) != 1 || stripStructural(ind) == "" {
+		return " "
+	}
+	return ind
+}
+
+// stripStructural remove de um valor os delimitadores de subcampo e os
+// terminadores de campo e de registro
+func stripStructural(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case subfieldDelimiter, fieldTerminator, recordTerminator:
+			return -1
+		}
+		return r
+	}, s)
+}
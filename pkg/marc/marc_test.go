@@ -0,0 +1,137 @@
+package marc
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func sampleRecord() *Record {
+	rec := NewRecord()
+	rec.AddControlField("001", "2ABC")
+	rec.AddDataField("020", "", "", Subfield{"a", "9788535910667"})
+	rec.AddDataField("100", "1", "", Subfield{"a", "Assis, Machado de,"})
+	rec.AddDataField("245", "1", "0", Subfield{"a", "Dom Casmurro /"}, Subfield{"c", "Machado de Assis."})
+	rec.AddDataField("650", "", "4", Subfield{"a", "Ficção brasileira"})
+	return rec
+}
+
+func assertSample(t *testing.T, rec *Record) {
+	t.Helper()
+	if got := rec.ControlField("001"); got != "2ABC" {
+		t.Errorf("001 = %q", got)
+	}
+	if got := rec.SubfieldValue("245", "a"); got != "Dom Casmurro /" {
+		t.Errorf("245$a = %q", got)
+	}
+	if got := rec.SubfieldValue("650", "a"); got != "Ficção brasileira" {
+		t.Errorf("650$a = %q", got)
+	}
+	fields := rec.DataFields("245")
+	if len(fields) != 1 || fields[0].Ind1 != "1" || fields[0].Ind2 != "0" {
+		t.Errorf("indicadores do 245 incorretos: %+v", fields)
+	}
+}
+
+func TestMarshalStripsStructuralBytes(t *testing.T) {
+	rec := NewRecord()
+	rec.AddControlField("001", "2A\x1eBC")
+	rec.AddDataField("245", "\x1f", "0", Subfield{"a", "Dom\x1e Casmurro\x1d /\x1f"}, Subfield{"c", "Machado de Assis."})
+	rec.AddDataField("650", "", "4", Subfield{"a", "Ficção brasileira"})
+
+	data, err := Marshal(rec)
+	if err != nil {
+		t.Fatalf("erro ao codificar registro: %v", err)
+	}
+	got, err := Unmarshal(data)
+	if err != nil {
+		t.Fatalf("erro ao ler registro: %v", err)
+	}
+	if v := got.ControlField("001"); v != "2ABC" {
+		t.Errorf("001 = %q", v)
+	}
+	if v := got.SubfieldValue("245", "a"); v != "Dom Casmurro /" {
+		t.Errorf("245$a = %q", v)
+	}
+	if v := got.SubfieldValue("245", "c"); v != "Machado de Assis." {
+		t.Errorf("245$c = %q", v)
+	}
+	if v := got.SubfieldValue("650", "a"); v != "Ficção brasileira" {
+		t.Errorf("650$a = %q, o campo seguinte não deveria ser afetado", v)
+	}
+	if fields := got.DataFields("245"); len(fields) != 1 || fields[0].Ind1 != " " {
+		t.Errorf("245 = %+v, esperava o indicador vazio", fields)
+	}
+}
+
+func TestISO2709RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	for i := 0; i < 2; i++ {
+		if err := w.Write(sampleRecord()); err != nil {
+			t.Fatalf("erro ao escrever registro: %v", err)
+		}
+	}
+
+	r := NewReader(&buf)
+	count := 0
+	for {
+		rec, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("erro ao ler registro: %v", err)
+		}
+		if rec.Leader[20:24] != "4500" {
+			t.Errorf("líder inválido: %q", rec.Leader)
+		}
+		assertSample(t, rec)
+		count++
+	}
+	if count != 2 {
+		t.Errorf("esperava 2 registros, leu %d", count)
+	}
+}
+
+func TestMARCXMLRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewXMLWriter(&buf)
+	if err := w.Write(sampleRecord()); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	rec, err := NewXMLReader(&buf).Read()
+	if err != nil {
+		t.Fatalf("erro ao ler MARCXML: %v\n%s", err, buf.String())
+	}
+	assertSample(t, rec)
+}
+
+func TestUnmarshalRejectsTruncatedRecord(t *testing.T) {
+	data, err := Marshal(sampleRecord())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Unmarshal(data[:len(data)-10]); err == nil {
+		t.Error("esperava erro para registro truncado")
+	}
+}
+
+func TestUnmarshalRejectsMalformedDirectory(t *testing.T) {
+	data, err := Marshal(sampleRecord())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range []string{"245-00100000", "2450010+0000", "245 00100000"} {
+		broken := append([]byte(nil), data...)
+		// A primeira entrada do diretório começa logo após o leader
+		copy(broken[leaderLength:], entry)
+		if _, err := Unmarshal(broken); err == nil {
+			t.Errorf("esperava erro para a entrada de diretório %q", entry)
+		}
+	}
+}
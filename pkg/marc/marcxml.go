@@ -0,0 +1,132 @@
+package marc
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// Namespace é o namespace do esquema MARCXML
+const Namespace = "http://www.loc.gov/MARC21/slim"
+
+type xmlRecord struct {
+	XMLName       xml.Name          `xml:"record"`
+	Leader        string            `xml:"leader"`
+	ControlFields []xmlControlField `xml:"controlfield"`
+	DataFields    []xmlDataField    `xml:"datafield"`
+}
+
+type xmlControlField struct {
+	Tag   string `xml:"tag,attr"`
+	Value string `xml:",chardata"`
+}
+
+type xmlDataField struct {
+	Tag       string        `xml:"tag,attr"`
+	Ind1      string        `xml:"ind1,attr"`
+	Ind2      string        `xml:"ind2,attr"`
+	Subfields []xmlSubfield `xml:"subfield"`
+}
+
+type xmlSubfield struct {
+	Code  string `xml:"code,attr"`
+	Value string `xml:",chardata"`
+}
+
+// XMLReader lê registros de um documento MARCXML, seja uma <collection> ou um
+// único <record>, decodificando um registro por vez.
+type XMLReader struct {
+	dec *xml.Decoder
+}
+
+// NewXMLReader cria um leitor de MARCXML
+func NewXMLReader(r io.Reader) *XMLReader {
+	return &XMLReader{dec: xml.NewDecoder(r)}
+}
+
+// Read devolve o próximo registro, ou io.EOF ao fim do documento
+func (xr *XMLReader) Read() (*Record, error) {
+	for {
+		tok, err := xr.dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "record" {
+			continue
+		}
+		var xrec xmlRecord
+		if err := xr.dec.DecodeElement(&xrec, &start); err != nil {
+			return nil, fmt.Errorf("marcxml: %v", err)
+		}
+		// A ordem original entre campos de controle e de dados é preservada
+		// porque o MARC21 exige os campos de controle antes dos de dados
+		rec := &Record{Leader: xrec.Leader}
+		for _, cf := range xrec.ControlFields {
+			rec.AddControlField(cf.Tag, cf.Value)
+		}
+		for _, df := range xrec.DataFields {
+			subfields := make([]Subfield, len(df.Subfields))
+			for i, sf := range df.Subfields {
+				subfields[i] = Subfield{Code: sf.Code, Value: sf.Value}
+			}
+			rec.AddDataField(df.Tag, df.Ind1, df.Ind2, subfields...)
+		}
+		return rec, nil
+	}
+}
+
+// XMLWriter escreve uma <collection> MARCXML registro a registro.
+// Close precisa ser chamado para fechar o elemento raiz.
+type XMLWriter struct {
+	w       io.Writer
+	enc     *xml.Encoder
+	started bool
+}
+
+// NewXMLWriter cria um escritor de MARCXML
+func NewXMLWriter(w io.Writer) *XMLWriter {
+	return &XMLWriter{w: w, enc: xml.NewEncoder(w)}
+}
+
+func (xw *XMLWriter) start() error {
+	if xw.started {
+		return nil
+	}
+	xw.started = true
+	_, err := io.WriteString(xw.w, xml.Header+`<collection xmlns="`+Namespace+`">`+"\n")
+	return err
+}
+
+// Write codifica um registro dentro da coleção
+func (xw *XMLWriter) Write(rec *Record) error {
+	if err := xw.start(); err != nil {
+		return err
+	}
+	xrec := xmlRecord{Leader: rec.Leader}
+	for _, f := range rec.Fields {
+		if f.IsControl() {
+			xrec.ControlFields = append(xrec.ControlFields, xmlControlField{Tag: f.Tag, Value: f.Value})
+			continue
+		}
+		df := xmlDataField{Tag: f.Tag, Ind1: indicator(f.Ind1), Ind2: indicator(f.Ind2)}
+		for _, sf := range f.Subfields {
+			df.Subfields = append(df.Subfields, xmlSubfield{Code: sf.Code, Value: sf.Value})
+		}
+		xrec.DataFields = append(xrec.DataFields, df)
+	}
+	if err := xw.enc.Encode(xrec); err != nil {
+		return err
+	}
+	_, err := io.WriteString(xw.w, "\n")
+	return err
+}
+
+// Close fecha o elemento <collection>
+func (xw *XMLWriter) Close() error {
+	if err := xw.start(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(xw.w, "</collection>\n")
+	return err
+}
@@ -0,0 +1,99 @@
+// Package marc lê e escreve registros bibliográficos MARC21, tanto no formato
+// binário ISO 2709 quanto em MARCXML (http://www.loc.gov/standards/marcxml/).
+package marc
+
+import "strings"
+
+// Subfield é um subcampo ($a, $b, ...) de um campo de dados
+type Subfield struct {
+	Code  string
+	Value string
+}
+
+// Field é um campo de controle (tags 001 a 009, apenas Value) ou um campo de
+// dados (indicadores e subcampos).
+type Field struct {
+	Tag       string
+	Value     string
+	Ind1      string
+	Ind2      string
+	Subfields []Subfield
+}
+
+// IsControl indica se o campo é de controle (tags 00X)
+func (f Field) IsControl() bool {
+	return strings.HasPrefix(f.Tag, "00")
+}
+
+// Subfield devolve o primeiro subcampo com o código informado, ou "" se não existir
+func (f Field) Subfield(code string) string {
+	for _, sf := range f.Subfields {
+		if sf.Code == code {
+			return sf.Value
+		}
+	}
+	return ""
+}
+
+// Record é um registro MARC21
+type Record struct {
+	Leader string
+	Fields []Field
+}
+
+// DefaultLeader é o líder usado para registros bibliográficos novos: registro
+// novo ("n"), material textual ("a"), monografia ("m") e codificação UTF-8 ("a").
+// Tamanho do registro e endereço base são preenchidos na escrita.
+const DefaultLeader = "00000nam a2200000 i 4500"
+
+// NewRecord cria um registro bibliográfico vazio
+func NewRecord() *Record {
+	return &Record{Leader: DefaultLeader}
+}
+
+// ControlField devolve o valor do primeiro campo de controle com a tag informada
+func (r *Record) ControlField(tag string) string {
+	for _, f := range r.Fields {
+		if f.Tag == tag && f.IsControl() {
+			return f.Value
+		}
+	}
+	return ""
+}
+
+// DataFields devolve todos os campos de dados com a tag informada
+func (r *Record) DataFields(tag string) []Field {
+	var fields []Field
+	for _, f := range r.Fields {
+		if f.Tag == tag && !f.IsControl() {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// SubfieldValue devolve o subcampo do primeiro campo de dados com a tag informada
+func (r *Record) SubfieldValue(tag, code string) string {
+	for _, f := range r.DataFields(tag) {
+		if v := f.Subfield(code); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// AddControlField acrescenta um campo de controle
+func (r *Record) AddControlField(tag, value string) {
+	r.Fields = append(r.Fields, Field{Tag: tag, Value: value})
+}
+
+// AddDataField acrescenta um campo de dados. Indicadores vazios viram espaço.
+func (r *Record) AddDataField(tag, ind1, ind2 string, subfields ...Subfield) {
+	if ind1 == "" {
+		ind1 = " "
+	}
+	if ind2 == "" {
+		ind2 = " "
+	}
+	r.Fields = append(r.Fields, Field{Tag: tag, Ind1: ind1, Ind2: ind2, Subfields: subfields})
+}
@@ -0,0 +1,158 @@
+// Package xlsx gera planilhas XLSX de uma única aba de forma incremental,
+// escrevendo cada linha diretamente no io.Writer de destino sem manter a
+// planilha inteira em memória.
+package xlsx
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const contentTypesXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+</Types>`
+
+const rootRelsXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+
+const workbookRelsXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+</Relationships>`
+
+const workbookXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets>
+</workbook>`
+
+const sheetHeaderXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+
+const sheetFooterXML = `</sheetData></worksheet>`
+
+// StreamWriter escreve uma planilha linha a linha. As partes fixas do pacote
+// são gravadas na criação; a aba é gravada por último, conforme WriteRow é
+// chamado, e o arquivo só fica válido após Close.
+type StreamWriter struct {
+	zw   *zip.Writer
+	buf  *bufio.Writer
+	row  int
+	done bool
+}
+
+// NewStreamWriter inicia uma planilha com uma única aba chamada sheetName.
+func NewStreamWriter(w io.Writer, sheetName string) (*StreamWriter, error) {
+	zw := zip.NewWriter(w)
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", contentTypesXML},
+		{"_rels/.rels", rootRelsXML},
+		{"xl/workbook.xml", fmt.Sprintf(workbookXML, escape(sheetName))},
+		{"xl/_rels/workbook.xml.rels", workbookRelsXML},
+	}
+	for _, part := range parts {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return nil, err
+		}
+	}
+	sheet, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	buf := bufio.NewWriter(sheet)
+	if _, err := buf.WriteString(sheetHeaderXML); err != nil {
+		return nil, err
+	}
+	return &StreamWriter{zw: zw, buf: buf}, nil
+}
+
+// WriteRow acrescenta uma linha à aba. Valores inteiros e de ponto flutuante
+// viram células numéricas; nil vira célula vazia; o resto é gravado como texto.
+func (s *StreamWriter) WriteRow(values []interface{}) error {
+	if s.done {
+		return fmt.Errorf("xlsx: escrita após Close")
+	}
+	s.row++
+	if _, err := fmt.Fprintf(s.buf, `<row r="%d">`, s.row); err != nil {
+		return err
+	}
+	for i, value := range values {
+		ref := ColumnName(i) + strconv.Itoa(s.row)
+		var cell string
+		switch v := value.(type) {
+		case nil:
+			continue
+		case int:
+			cell = fmt.Sprintf(`<c r="%s"><v>%d</v></c>`, ref, v)
+		case int64:
+			cell = fmt.Sprintf(`<c r="%s"><v>%d</v></c>`, ref, v)
+		case float64:
+			cell = fmt.Sprintf(`<c r="%s"><v>%s</v></c>`, ref, strconv.FormatFloat(v, 'f', -1, 64))
+		default:
+			cell = fmt.Sprintf(`<c r="%s" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`,
+				ref, escape(fmt.Sprint(v)))
+		}
+		if _, err := s.buf.WriteString(cell); err != nil {
+			return err
+		}
+	}
+	_, err := s.buf.WriteString(`</row>`)
+	return err
+}
+
+// Flush envia ao destino o que já foi escrito, sem encerrar a planilha.
+func (s *StreamWriter) Flush() error {
+	if err := s.buf.Flush(); err != nil {
+		return err
+	}
+	return s.zw.Flush()
+}
+
+// Close finaliza a aba e o pacote zip.
+func (s *StreamWriter) Close() error {
+	if s.done {
+		return nil
+	}
+	s.done = true
+	if _, err := s.buf.WriteString(sheetFooterXML); err != nil {
+		return err
+	}
+	if err := s.buf.Flush(); err != nil {
+		return err
+	}
+	return s.zw.Close()
+}
+
+// ColumnName converte um índice de coluna começando em zero para a notação
+// de letras da planilha (0 → A, 25 → Z, 26 → AA).
+func ColumnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
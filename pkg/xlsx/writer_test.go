@@ -0,0 +1,59 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestStreamWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewStreamWriter(&buf, "Livros")
+	if err != nil {
+		t.Fatalf("erro ao criar planilha: %v", err)
+	}
+	if err := w.WriteRow([]interface{}{"nome", "quantidade"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteRow([]interface{}{"A & B <C>", 7}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("arquivo gerado não é um zip válido: %v", err)
+	}
+	var sheet string
+	for _, f := range zr.File {
+		if f.Name != "xl/worksheets/sheet1.xml" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		sheet = string(data)
+	}
+	if !strings.Contains(sheet, `<c r="B2"><v>7</v></c>`) {
+		t.Errorf("célula numérica ausente: %s", sheet)
+	}
+	if !strings.Contains(sheet, "A &amp; B &lt;C&gt;") {
+		t.Errorf("texto não foi escapado: %s", sheet)
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	cases := map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"}
+	for index, want := range cases {
+		if got := ColumnName(index); got != want {
+			t.Errorf("ColumnName(%d) = %s, esperava %s", index, got, want)
+		}
+	}
+}